@@ -146,7 +146,7 @@ function Build-Application {
         $version = "dev"
     }
     
-    $ldflags = "-w -s -X main.version=$version"
+    $ldflags = "-w -s -X portal64api/internal/version.Version=$version"
     $env:CGO_ENABLED = "0"
     
     # Build for Windows (amd64)
@@ -447,7 +447,7 @@ function Build-Release {
         $version = "1.0.0"
     }
     
-    $ldflags = "-w -s -X main.version=$version"
+    $ldflags = "-w -s -X portal64api/internal/version.Version=$version"
     
     # Build for different platforms
     Write-ColorOutput "Building for Linux (amd64)..." $WHITE
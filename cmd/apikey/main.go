@@ -0,0 +1,173 @@
+// API key management for Portal64 API
+// Keys are stored hashed in the key file configured with AUTH_KEYS_FILE; the plaintext
+// key is shown once on creation. The running server picks up changes automatically.
+//
+// Usage:
+//
+//	apikey create -name "Nightly import" -scopes admin:import,admin:cache [-expires 2026-12-31]
+//	apikey list
+//	apikey revoke <id>
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"portal64api/internal/auth"
+	"portal64api/internal/config"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
+		os.Exit(1)
+	}
+
+	command, args := os.Args[1], os.Args[2:]
+	switch command {
+	case "create":
+		err = runCreate(cfg.Auth.KeysFile, args)
+	case "list":
+		err = runList(cfg.Auth.KeysFile, args)
+	case "revoke":
+		err = runRevoke(cfg.Auth.KeysFile, args)
+	case "help", "-h", "--help":
+		usage()
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown command %q\n\n", command)
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Manage Portal64 API keys
+
+Usage:
+  apikey create -name <name> -scopes <scope,...> [-expires YYYY-MM-DD] [-file path]
+  apikey list [-file path]
+  apikey revoke [-file path] <id>
+
+Scopes: %s
+`, strings.Join(auth.AllScopes, ", "))
+}
+
+// runCreate creates a key and prints the plaintext key once
+func runCreate(keysFile string, args []string) error {
+	flags := flag.NewFlagSet("create", flag.ExitOnError)
+	file := flags.String("file", keysFile, "API key file")
+	name := flags.String("name", "", "Name describing the key owner or purpose")
+	scopes := flags.String("scopes", "", "Comma-separated scopes")
+	expires := flags.String("expires", "", "Expiry date (YYYY-MM-DD), key is valid until the end of this day")
+	flags.Parse(args)
+
+	if *name == "" {
+		return fmt.Errorf("-name is required")
+	}
+
+	var scopeList []string
+	for _, scope := range strings.Split(*scopes, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopeList = append(scopeList, scope)
+		}
+	}
+
+	var expiresAt *time.Time
+	if *expires != "" {
+		day, err := time.ParseInLocation("2006-01-02", *expires, time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid -expires date %q (expected YYYY-MM-DD)", *expires)
+		}
+		end := day.AddDate(0, 0, 1).UTC()
+		expiresAt = &end
+	}
+
+	store, err := auth.NewKeyStore(*file)
+	if err != nil {
+		return err
+	}
+	key, apiKey, err := store.Create(*name, scopeList, expiresAt)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Created API key %s (%s) with scopes %s\n", apiKey.ID, apiKey.Name, strings.Join(apiKey.Scopes, ","))
+	fmt.Println("Store this key now, it cannot be shown again:")
+	fmt.Println(key)
+	return nil
+}
+
+// runList prints all keys without secrets
+func runList(keysFile string, args []string) error {
+	flags := flag.NewFlagSet("list", flag.ExitOnError)
+	file := flags.String("file", keysFile, "API key file")
+	flags.Parse(args)
+
+	store, err := auth.NewKeyStore(*file)
+	if err != nil {
+		return err
+	}
+
+	keys := store.List()
+	if len(keys) == 0 {
+		fmt.Printf("No API keys in %s\n", *file)
+		return nil
+	}
+
+	now := time.Now()
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSCOPES\tCREATED\tEXPIRES\tSTATUS")
+	for _, apiKey := range keys {
+		expires := "-"
+		if apiKey.ExpiresAt != nil {
+			expires = apiKey.ExpiresAt.Local().Format("2006-01-02 15:04")
+		}
+		status := "active"
+		if apiKey.RevokedAt != nil {
+			status = "revoked"
+		} else if !apiKey.IsActive(now) {
+			status = "expired"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", apiKey.ID, apiKey.Name, strings.Join(apiKey.Scopes, ","),
+			apiKey.CreatedAt.Local().Format("2006-01-02 15:04"), expires, status)
+	}
+	return w.Flush()
+}
+
+// runRevoke revokes a key by ID
+func runRevoke(keysFile string, args []string) error {
+	flags := flag.NewFlagSet("revoke", flag.ExitOnError)
+	file := flags.String("file", keysFile, "API key file")
+	flags.Parse(args)
+
+	if flags.NArg() != 1 {
+		return fmt.Errorf("revoke requires exactly one key ID")
+	}
+
+	store, err := auth.NewKeyStore(*file)
+	if err != nil {
+		return err
+	}
+	if err := store.Revoke(flags.Arg(0)); err != nil {
+		return err
+	}
+
+	fmt.Printf("Revoked API key %s\n", flags.Arg(0))
+	return nil
+}
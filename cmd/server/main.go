@@ -11,11 +11,15 @@ import (
 	"time"
 
 	"portal64api/internal/api"
+	"portal64api/internal/auth"
 	"portal64api/internal/cache"
 	"portal64api/internal/config"
 	"portal64api/internal/database"
+	"portal64api/internal/graphql"
 	"portal64api/internal/logging"
+	"portal64api/internal/ratelimit"
 	"portal64api/internal/services"
+	"portal64api/internal/tracing"
 
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
@@ -38,6 +42,11 @@ import (
 
 // @schemes http https
 
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key with the scope required by the endpoint (read, admin:import, admin:analysis, admin:cache, admin:webhooks, export)
+
 // @tag.name players
 // @tag.description Player and rating operations
 
@@ -60,6 +69,15 @@ func main() {
 	}
 	log.Println("Logging system initialized")
 
+	// Setup tracing
+	shutdownTracing, err := tracing.Setup(cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Failed to setup tracing: %v", err)
+	}
+	if cfg.Tracing.Enabled {
+		log.Printf("Tracing enabled, exporting spans via %s", cfg.Tracing.Exporter)
+	}
+
 	// Set Gin mode based on environment
 	if cfg.Server.Environment == "production" {
 		gin.SetMode(gin.ReleaseMode)
@@ -96,6 +114,20 @@ func main() {
 		log.Println("Cache service disabled")
 	}
 
+	// Initialize rate limiting, state is shared through Redis if the cache is enabled
+	var limiter *ratelimit.Limiter
+	if cfg.RateLimit.Enabled {
+		groupRules, err := ratelimit.ParseRules(cfg.RateLimit.Groups)
+		if err != nil {
+			log.Fatalf("Failed to parse rate limit configuration: %v", err)
+		}
+		defaultRule := ratelimit.Rule{RequestsPerMinute: cfg.RateLimit.RequestsPerMinute, Burst: cfg.RateLimit.Burst}
+		limiter = ratelimit.NewLimiter(ratelimit.NewStore(cacheService), defaultRule, groupRules)
+		log.Printf("Rate limiting enabled (%d requests/minute, burst %d)", defaultRule.RequestsPerMinute, defaultRule.Burst)
+	} else {
+		log.Println("Rate limiting disabled")
+	}
+
 	// Initialize import service if enabled
 	var importService *services.ImportService
 	if cfg.Import.Enabled {
@@ -106,6 +138,9 @@ func main() {
 		}
 
 		importService = services.NewImportService(&cfg.Import, &cfg.Database, cacheService, importLogger)
+		if limiter != nil {
+			importService.SetLoadMonitor(limiter)
+		}
 		
 		// Start import service
 		if err := importService.Start(); err != nil {
@@ -162,8 +197,119 @@ func main() {
 		log.Println("Kader-Planung service disabled")
 	}
 
+	// Initialize bulk export service if enabled, exports are generated after each import
+	var exportService *services.ExportService
+	if cfg.Export.Enabled {
+		exportService = services.NewExportService(&cfg.Export, dbs, logging.Log)
+		if importService != nil {
+			exportService.SetLastImportTime(importService.GetLastImportTime)
+		}
+
+		if err := exportService.Start(); err != nil {
+			log.Printf("Warning: Failed to start export service: %v", err)
+			exportService = nil
+		} else {
+			log.Println("Export service started successfully")
+		}
+
+		// Register with import service if both are enabled
+		if importService != nil && exportService != nil {
+			importService.AddCompletionCallback(exportService)
+			log.Println("Export service registered for post-import generation")
+		}
+
+		// Ensure a running generation is cancelled on shutdown
+		if exportService != nil {
+			defer func() {
+				if stopErr := exportService.Stop(); stopErr != nil {
+					log.Printf("Error stopping export service: %v", stopErr)
+				}
+			}()
+		}
+	} else {
+		log.Println("Export service disabled")
+	}
+
+	// Initialize webhook service if enabled, events are published by the import and analysis services
+	var webhookService *services.WebhookService
+	if cfg.Webhooks.Enabled {
+		webhookService, err = services.NewWebhookService(&cfg.Webhooks, dbs, logging.Log)
+		if err != nil {
+			log.Fatalf("Failed to load webhook subscriptions: %v", err)
+		}
+		if importService != nil {
+			webhookService.SetImportStatus(importService.GetStatus)
+		}
+
+		if err := webhookService.Start(); err != nil {
+			log.Printf("Warning: Failed to start webhook service: %v", err)
+			webhookService = nil
+		} else {
+			log.Println("Webhook service started successfully")
+		}
+
+		// Register with import and Kader-Planung services for their events
+		if importService != nil && webhookService != nil {
+			importService.AddCompletionCallback(webhookService)
+		}
+		if kaderPlanungService != nil && webhookService != nil {
+			kaderPlanungService.AddCompletionCallback(webhookService)
+		}
+
+		// Ensure deliveries are stopped on shutdown
+		if webhookService != nil {
+			defer func() {
+				if stopErr := webhookService.Stop(); stopErr != nil {
+					log.Printf("Error stopping webhook service: %v", stopErr)
+				}
+			}()
+		}
+	} else {
+		log.Println("Webhook service disabled")
+	}
+
+	// Load API keys for administrative endpoints
+	var keyStore *auth.KeyStore
+	if cfg.Auth.Enabled {
+		keyStore, err = auth.NewKeyStore(cfg.Auth.KeysFile)
+		if err != nil {
+			log.Fatalf("Failed to load API keys: %v", err)
+		}
+		if len(keyStore.List()) == 0 {
+			log.Printf("Warning: No API keys in %s, administrative endpoints are inaccessible (create one with 'apikey create')", cfg.Auth.KeysFile)
+		}
+	} else {
+		log.Println("Warning: API key authentication disabled, administrative endpoints are publicly accessible")
+	}
+
+	// Build the GraphQL schema, an invalid one stops the startup like any other configuration error
+	var graphQLSchema *graphql.Schema
+	if cfg.GraphQL.Enabled {
+		graphQLSchema, err = graphql.NewSchema()
+		if err != nil {
+			log.Fatalf("Failed to build GraphQL schema: %v", err)
+		}
+	}
+
 	// Setup routes
-	router := api.SetupRoutes(dbs, cacheService, importService, kaderPlanungService)
+	router, err := api.SetupRoutes(api.RouterOptions{
+		Databases:           dbs,
+		Cache:               cacheService,
+		ImportService:       importService,
+		KaderPlanungService: kaderPlanungService,
+		ExportService:       exportService,
+		WebhookService:      webhookService,
+		KeyStore:            keyStore,
+		RequireReadKey:      cfg.Auth.RequireReadKey,
+		Limiter:             limiter,
+		Compression:         cfg.Compression,
+		GraphQL:             cfg.GraphQL,
+		GraphQLSchema:       graphQLSchema,
+		TrustedProxies:      cfg.Server.TrustedProxies,
+	})
+	if err != nil {
+		log.Fatalf("Failed to setup routes: %v", err)
+	}
 
 	// Create HTTP server
 	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
@@ -208,5 +354,10 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
+	// Flush the spans of the last requests
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("Warning: failed to flush traces: %v", err)
+	}
+
 	log.Println("Server exited")
 }
@@ -1,7 +1,11 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	"portal64api/internal/models"
 	"portal64api/internal/services"
@@ -11,6 +15,12 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Limits for the cross-region address search
+const (
+	defaultAddressSearchLimit = 50
+	maxAddressSearchLimit     = 500
+)
+
 // AddressHandler handles address-related HTTP requests
 type AddressHandler struct {
 	addressService *services.AddressService
@@ -26,13 +36,15 @@ func NewAddressHandler(addressService *services.AddressService) *AddressHandler
 // @Description Get addresses of officials/functionaries for a specific region
 // @Tags addresses
 // @Accept json
-// @Produce json,text/csv
+// @Produce json,text/csv,text/vcard
 // @Param region path string true "Region code (e.g., C, B, W)"
 // @Param type query string false "Address type (e.g., praesidium, vorstand)"
-// @Param format query string false "Response format (json or csv)" Enums(json,csv)
+// @Param format query string false "Response format (json, csv or vcf)" Enums(json,csv,vcf)
+// @Param layout query string false "CSV layout: one row per contact detail (default) or one row per official with a column per contact type" Enums(rows,mailmerge)
+// @Param id query int false "Address ID, returns a single vCard (format=vcf only)"
 // @Success 200 {object} models.Response{data=[]models.RegionAddressResponse}
-// @Failure 400 {object} models.Response
-// @Failure 404 {object} models.Response
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
 // @Router /api/v1/addresses/{region} [get]
 func (h *AddressHandler) GetRegionAddresses(c *gin.Context) {
 	region := c.Param("region")
@@ -46,7 +58,7 @@ func (h *AddressHandler) GetRegionAddresses(c *gin.Context) {
 	}
 
 	// Get addresses from service
-	addresses, err := h.addressService.GetRegionAddresses(region, addressType)
+	addresses, err := h.addressService.WithContext(c.Request.Context()).GetRegionAddresses(region, addressType)
 	if err != nil {
 		if apiErr, ok := err.(errors.APIError); ok {
 			utils.SendJSONResponse(c, apiErr.Code, apiErr)
@@ -57,14 +69,7 @@ func (h *AddressHandler) GetRegionAddresses(c *gin.Context) {
 		return
 	}
 
-	// Check for CSV format
-	format := c.Query("format")
-	if format == "csv" {
-		h.sendAddressesCSV(c, addresses)
-		return
-	}
-
-	utils.SendJSONResponse(c, http.StatusOK, addresses)
+	h.sendAddresses(c, addresses, region, addressType)
 }
 
 // GetRegionAddressesByType godoc
@@ -72,13 +77,15 @@ func (h *AddressHandler) GetRegionAddresses(c *gin.Context) {
 // @Description Get addresses of officials/functionaries for a specific region and address type
 // @Tags addresses
 // @Accept json
-// @Produce json,text/csv
+// @Produce json,text/csv,text/vcard
 // @Param region path string true "Region code (e.g., C, B, W)"
 // @Param type path string true "Address type (e.g., praesidium, vorstand)"
-// @Param format query string false "Response format (json or csv)" Enums(json,csv)
+// @Param format query string false "Response format (json, csv or vcf)" Enums(json,csv,vcf)
+// @Param layout query string false "CSV layout: one row per contact detail (default) or one row per official with a column per contact type" Enums(rows,mailmerge)
+// @Param id query int false "Address ID, returns a single vCard (format=vcf only)"
 // @Success 200 {object} models.Response{data=[]models.RegionAddressResponse}
-// @Failure 400 {object} models.Response
-// @Failure 404 {object} models.Response
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
 // @Router /api/v1/addresses/{region}/{type} [get]
 func (h *AddressHandler) GetRegionAddressesByType(c *gin.Context) {
 	region := c.Param("region")
@@ -97,7 +104,7 @@ func (h *AddressHandler) GetRegionAddressesByType(c *gin.Context) {
 	}
 
 	// Get addresses from service
-	addresses, err := h.addressService.GetRegionAddresses(region, addressType)
+	addresses, err := h.addressService.WithContext(c.Request.Context()).GetRegionAddresses(region, addressType)
 	if err != nil {
 		if apiErr, ok := err.(errors.APIError); ok {
 			utils.SendJSONResponse(c, apiErr.Code, apiErr)
@@ -108,14 +115,114 @@ func (h *AddressHandler) GetRegionAddressesByType(c *gin.Context) {
 		return
 	}
 
-	// Check for CSV format
-	format := c.Query("format")
-	if format == "csv" {
-		h.sendAddressesCSV(c, addresses)
+	h.sendAddresses(c, addresses, region, addressType)
+}
+
+// SearchAddresses godoc
+// @Summary Search addresses across all regions
+// @Description Search addresses of officials and organisations by name and function across all regions. Matching is case- and umlaut-insensitive ("Mueller" finds "Müller").
+// @Tags addresses
+// @Accept json
+// @Produce json,text/csv,text/vcard
+// @Param q query string false "Name of the person or organisation (every word has to match)"
+// @Param function query string false "Function (e.g., Jugendwart)"
+// @Param region query string false "Region code (e.g., C, B, W)"
+// @Param limit query int false "Maximum results (default 50, max 500)"
+// @Param format query string false "Response format (json, csv or vcf)" Enums(json,csv,vcf)
+// @Param layout query string false "CSV layout: one row per contact detail (default) or one row per official with a column per contact type" Enums(rows,mailmerge)
+// @Success 200 {object} models.Response{data=[]models.RegionAddressResponse}
+// @Failure 400 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /api/v1/addresses/search [get]
+func (h *AddressHandler) SearchAddresses(c *gin.Context) {
+	limit := defaultAddressSearchLimit
+	if limitParam := c.Query("limit"); limitParam != "" {
+		parsed, err := strconv.Atoi(limitParam)
+		if err != nil || parsed < 1 {
+			utils.SendJSONResponse(c, http.StatusBadRequest,
+				errors.NewBadRequestError("Invalid limit parameter"))
+			return
+		}
+		if parsed > maxAddressSearchLimit {
+			parsed = maxAddressSearchLimit
+		}
+		limit = parsed
+	}
+
+	region := strings.ToUpper(strings.TrimSpace(c.Query("region")))
+
+	addresses, err := h.addressService.WithContext(c.Request.Context()).SearchAddresses(c.Query("q"), c.Query("function"), region, limit)
+	if err != nil {
+		if apiErr, ok := err.(errors.APIError); ok {
+			utils.SendJSONResponse(c, apiErr.Code, apiErr)
+			return
+		}
+		utils.SendJSONResponse(c, http.StatusInternalServerError,
+			errors.NewInternalServerError("Failed to search addresses"))
 		return
 	}
 
-	utils.SendJSONResponse(c, http.StatusOK, addresses)
+	h.sendAddresses(c, addresses, region, "")
+}
+
+// GetPlayerFunctions godoc
+// @Summary Get functions held by a player
+// @Description Get all functions (offices) a player holds in clubs and federations, with GDPR-filtered contact details
+// @Tags players
+// @Accept json
+// @Produce json,text/csv
+// @Param id path string true "Player ID (e.g., C0101-1014)"
+// @Param format query string false "Response format (json or csv)" Enums(json,csv)
+// @Success 200 {object} models.Response{data=models.PersonFunctionsResponse}
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /api/v1/players/{id}/functions [get]
+func (h *AddressHandler) GetPlayerFunctions(c *gin.Context) {
+	playerID := c.Param("id")
+
+	functions, err := h.addressService.WithContext(c.Request.Context()).GetPlayerFunctions(playerID)
+	h.sendPersonFunctions(c, functions, err)
+}
+
+// GetPersonFunctions godoc
+// @Summary Get functions held by a person
+// @Description Get all functions (offices) a person holds in clubs and federations by person UUID, with GDPR-filtered contact details
+// @Tags addresses
+// @Accept json
+// @Produce json,text/csv
+// @Param uuid path string true "Person UUID"
+// @Param format query string false "Response format (json or csv)" Enums(json,csv)
+// @Success 200 {object} models.Response{data=models.PersonFunctionsResponse}
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Router /api/v1/persons/{uuid}/functions [get]
+func (h *AddressHandler) GetPersonFunctions(c *gin.Context) {
+	uuid := c.Param("uuid")
+
+	functions, err := h.addressService.WithContext(c.Request.Context()).GetPersonFunctionsByUUID(uuid)
+	h.sendPersonFunctions(c, functions, err)
+}
+
+// sendPersonFunctions sends the functions of a person, CSV lists one row per contact detail
+func (h *AddressHandler) sendPersonFunctions(c *gin.Context, functions *models.PersonFunctionsResponse, err error) {
+	if err != nil {
+		if apiErr, ok := err.(errors.APIError); ok {
+			utils.SendJSONResponse(c, apiErr.Code, apiErr)
+			return
+		}
+		utils.SendJSONResponse(c, http.StatusInternalServerError,
+			errors.NewInternalServerError("Failed to get person functions"))
+		return
+	}
+
+	if utils.RequestedFormat(c).IsCSV() {
+		h.sendAddressesCSV(c, functions.Functions)
+		return
+	}
+
+	utils.SendJSONResponse(c, http.StatusOK, functions)
 }
 
 // GetAvailableRegions godoc
@@ -125,10 +232,10 @@ func (h *AddressHandler) GetRegionAddressesByType(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} models.Response{data=[]models.RegionInfo}
-// @Failure 500 {object} models.Response
+// @Failure 500 {object} errors.Problem
 // @Router /api/v1/addresses/regions [get]
 func (h *AddressHandler) GetAvailableRegions(c *gin.Context) {
-	regions, err := h.addressService.GetAvailableRegions()
+	regions, err := h.addressService.WithContext(c.Request.Context()).GetAvailableRegions()
 	if err != nil {
 		utils.SendJSONResponse(c, http.StatusInternalServerError, err)
 		return
@@ -145,8 +252,8 @@ func (h *AddressHandler) GetAvailableRegions(c *gin.Context) {
 // @Produce json
 // @Param region path string true "Region code (e.g., C, B, W)"
 // @Success 200 {object} models.Response{data=[]models.AddressTypeInfo}
-// @Failure 400 {object} models.Response
-// @Failure 500 {object} models.Response
+// @Failure 400 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
 // @Router /api/v1/addresses/{region}/types [get]
 func (h *AddressHandler) GetAddressTypes(c *gin.Context) {
 	region := c.Param("region")
@@ -158,7 +265,7 @@ func (h *AddressHandler) GetAddressTypes(c *gin.Context) {
 		return
 	}
 
-	types, err := h.addressService.GetAddressTypes(region)
+	types, err := h.addressService.WithContext(c.Request.Context()).GetAddressTypes(region)
 	if err != nil {
 		utils.SendJSONResponse(c, http.StatusInternalServerError, err)
 		return
@@ -167,6 +274,188 @@ func (h *AddressHandler) GetAddressTypes(c *gin.Context) {
 	utils.SendJSONResponse(c, http.StatusOK, types)
 }
 
+// sendAddresses sends addresses in the requested format (JSON, CSV, mail-merge CSV or vCard)
+func (h *AddressHandler) sendAddresses(c *gin.Context, addresses []models.RegionAddressResponse, region, addressType string) {
+	if utils.WantsVCard(c) {
+		h.sendAddressesVCard(c, addresses, region, addressType)
+		return
+	}
+
+	// Check for CSV format
+	if utils.RequestedFormat(c).IsCSV() {
+		if c.Query("layout") == "mailmerge" {
+			h.sendAddressesMailMerge(c, addresses)
+			return
+		}
+		h.sendAddressesCSV(c, addresses)
+		return
+	}
+
+	utils.SendJSONResponse(c, http.StatusOK, addresses)
+}
+
+// sendAddressesVCard sends addresses as a multi-card vCard file, or a single card if an ID is given
+func (h *AddressHandler) sendAddressesVCard(c *gin.Context, addresses []models.RegionAddressResponse, region, addressType string) {
+	if idParam := c.Query("id"); idParam != "" {
+		id, err := strconv.ParseUint(idParam, 10, 32)
+		if err != nil {
+			utils.SendJSONResponse(c, http.StatusBadRequest,
+				errors.NewBadRequestError("Invalid address ID"))
+			return
+		}
+		for _, addr := range addresses {
+			if addr.ID == uint(id) {
+				utils.SendVCardResponse(c, fmt.Sprintf("address_%d.vcf", addr.ID), []utils.VCard{addressToVCard(addr)})
+				return
+			}
+		}
+		utils.SendJSONResponse(c, http.StatusNotFound,
+			errors.New(errors.CodeAddressNotFound, "Address not found"))
+		return
+	}
+
+	cards := make([]utils.VCard, 0, len(addresses))
+	for _, addr := range addresses {
+		cards = append(cards, addressToVCard(addr))
+	}
+
+	filename := "addresses"
+	if region != "" {
+		filename += "_" + strings.ToUpper(region)
+	}
+	if addressType != "" {
+		filename += "_" + addressType
+	}
+	utils.SendVCardResponse(c, url.PathEscape(filename)+".vcf", cards)
+}
+
+// addressToVCard maps an official and its contact details to a vCard
+func addressToVCard(addr models.RegionAddressResponse) utils.VCard {
+	card := utils.VCard{
+		Kind:         "individual",
+		FullName:     addr.Name,
+		FamilyName:   addr.PersonName,
+		GivenName:    addr.PersonFirstname,
+		Organization: addr.OrganisationName,
+		Title:        addr.FunctionName,
+		UID:          fmt.Sprintf("urn:portal64:address:%d", addr.ID),
+	}
+	if addr.PersonID == 0 {
+		card.Kind = "org"
+	}
+	if addr.UUID != "" {
+		card.UID = "urn:uuid:" + strings.ToLower(addr.UUID)
+	}
+	if addr.FunctionName != "" {
+		card.Categories = []string{addr.FunctionName}
+	}
+
+	var notes []string
+	for _, contact := range addr.ContactDetails {
+		switch contact.TypeID {
+		case models.AdrArtName:
+			if card.FullName == "" {
+				card.FullName = contact.Value
+			}
+		case models.AdrArtStrasse:
+			card.Street = contact.Value
+		case models.AdrArtZusatz:
+			card.Extended = contact.Value
+		case models.AdrArtPLZ:
+			card.PostalCode = contact.Value
+		case models.AdrArtOrt:
+			card.Locality = contact.Value
+		case models.AdrArtLand:
+			card.Country = contact.Value
+		case models.AdrArtTelefon1, models.AdrArtTelefon2, models.AdrArtTelefon3:
+			card.Phones = append(card.Phones, contact.Value)
+		case models.AdrArtFax:
+			card.Faxes = append(card.Faxes, contact.Value)
+		case models.AdrArtEmail1, models.AdrArtEmail2:
+			card.Emails = append(card.Emails, contact.Value)
+		case models.AdrArtHomepage:
+			homepage := contact.Value
+			if !strings.Contains(homepage, "://") {
+				homepage = "https://" + homepage
+			}
+			card.URLs = append(card.URLs, homepage)
+		case models.AdrArtBreite:
+			card.Latitude = contact.Value
+		case models.AdrArtLaenge:
+			card.Longitude = contact.Value
+		case models.AdrArtBemerkung, models.AdrArtUebungsabend:
+			notes = append(notes, contact.Type+": "+contact.Value)
+		}
+	}
+	card.Note = strings.Join(notes, "\n")
+
+	return card
+}
+
+// sendAddressesMailMerge sends addresses as CSV with one row per official and one column per contact type
+func (h *AddressHandler) sendAddressesMailMerge(c *gin.Context, addresses []models.RegionAddressResponse) {
+	type MailMergeAddress struct {
+		ID           uint   `json:"id"`
+		Name         string `json:"name"`
+		Nachname     string `json:"nachname"`
+		Vorname      string `json:"vorname"`
+		Funktion     string `json:"funktion"`
+		Organisation string `json:"organisation"`
+		Region       string `json:"region"`
+		Strasse      string `json:"strasse"`
+		Zusatz       string `json:"zusatz"`
+		PLZ          string `json:"plz"`
+		Ort          string `json:"ort"`
+		Land         string `json:"land"`
+		Telefon1     string `json:"telefon_1"`
+		Telefon2     string `json:"telefon_2"`
+		Telefon3     string `json:"telefon_3"`
+		Fax          string `json:"fax"`
+		Email1       string `json:"email_1"`
+		Email2       string `json:"email_2"`
+		Homepage     string `json:"homepage"`
+		Bemerkung    string `json:"bemerkung"`
+	}
+
+	rows := make([]MailMergeAddress, 0, len(addresses))
+	for _, addr := range addresses {
+		row := MailMergeAddress{
+			ID:           addr.ID,
+			Name:         addr.Name,
+			Nachname:     addr.PersonName,
+			Vorname:      addr.PersonFirstname,
+			Funktion:     addr.FunctionName,
+			Organisation: addr.OrganisationName,
+			Region:       addr.Region,
+		}
+
+		columns := map[uint]*string{
+			models.AdrArtStrasse:   &row.Strasse,
+			models.AdrArtZusatz:    &row.Zusatz,
+			models.AdrArtPLZ:       &row.PLZ,
+			models.AdrArtOrt:       &row.Ort,
+			models.AdrArtLand:      &row.Land,
+			models.AdrArtTelefon1:  &row.Telefon1,
+			models.AdrArtTelefon2:  &row.Telefon2,
+			models.AdrArtTelefon3:  &row.Telefon3,
+			models.AdrArtFax:       &row.Fax,
+			models.AdrArtEmail1:    &row.Email1,
+			models.AdrArtEmail2:    &row.Email2,
+			models.AdrArtHomepage:  &row.Homepage,
+			models.AdrArtBemerkung: &row.Bemerkung,
+		}
+		for _, contact := range addr.ContactDetails {
+			if column, ok := columns[contact.TypeID]; ok {
+				*column = contact.Value
+			}
+		}
+
+		rows = append(rows, row)
+	}
+
+	utils.SendCSVResponse(c, "addresses_mailmerge.csv", rows)
+}
+
 // sendAddressesCSV sends addresses in CSV format
 func (h *AddressHandler) sendAddressesCSV(c *gin.Context, addresses []models.RegionAddressResponse) {
 	// Transform addresses to a flat structure for CSV
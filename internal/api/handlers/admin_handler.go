@@ -30,6 +30,7 @@ func NewAdminHandler(cacheService cache.CacheService) *AdminHandler {
 // @Produce json
 // @Success 200 {object} models.CacheStatsResponse
 // @Failure 503 {object} map[string]string
+// @Security ApiKeyAuth
 // @Router /api/v1/admin/cache/stats [get]
 func (h *AdminHandler) GetCacheStats(c *gin.Context) {
 	stats := h.cacheService.GetStats()
@@ -67,6 +68,7 @@ func (h *AdminHandler) GetCacheStats(c *gin.Context) {
 // @Produce json
 // @Success 200 {object} models.CacheHealthResponse
 // @Failure 503 {object} models.CacheHealthResponse
+// @Security ApiKeyAuth
 // @Router /api/v1/admin/cache/health [get]
 func (h *AdminHandler) GetCacheHealth(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+
+	"portal64api/internal/models"
+	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// batchResponse is the body of a batch lookup: one result per distinct ID and a summary
+type batchResponse struct {
+	Data interface{}      `json:"data"`
+	Meta models.BatchMeta `json:"meta"`
+}
+
+// bindBatchRequest reads the IDs of a batch lookup
+// Invalid bodies, empty and oversized batches are answered with a bad request error.
+func bindBatchRequest(c *gin.Context) ([]string, bool) {
+	var request models.BatchRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendJSONResponse(c, http.StatusBadRequest,
+			errors.New(errors.CodeInvalidRequestBody, `Invalid request format (expected: {"ids": [...]})`))
+		return nil, false
+	}
+	if len(request.IDs) == 0 {
+		utils.SendJSONResponse(c, http.StatusBadRequest,
+			errors.NewBadRequestError("At least one ID is required"))
+		return nil, false
+	}
+	if len(request.IDs) > models.MaxBatchIDs {
+		utils.SendJSONResponse(c, http.StatusBadRequest,
+			errors.NewBadRequestError(fmt.Sprintf("Too many IDs (maximum: %d)", models.MaxBatchIDs)))
+		return nil, false
+	}
+	return request.IDs, true
+}
+
+// sendBatchResponse sends the results of a batch lookup
+// Per-ID errors are part of the results, so the response status is 200 unless the lookup itself failed.
+func sendBatchResponse(c *gin.Context, results interface{}, requested, failed int) {
+	utils.SendJSONResponse(c, http.StatusOK, batchResponse{
+		Data: results,
+		Meta: models.BatchMeta{Requested: requested, Found: requested - failed, Failed: failed},
+	})
+}
+
+// sendBatchError answers a batch lookup that failed as a whole
+func sendBatchError(c *gin.Context, err error, message string) {
+	if apiErr, ok := err.(errors.APIError); ok {
+		utils.SendJSONResponse(c, apiErr.Code, apiErr)
+		return
+	}
+	utils.SendJSONResponse(c, http.StatusInternalServerError, errors.NewInternalServerError(message))
+}
@@ -13,12 +13,13 @@ import (
 
 // ClubHandler handles club-related HTTP requests
 type ClubHandler struct {
-	clubService *services.ClubService
+	clubService   *services.ClubService
+	playerService *services.PlayerService // Embeds players with ?include=players
 }
 
 // NewClubHandler creates a new club handler
-func NewClubHandler(clubService *services.ClubService) *ClubHandler {
-	return &ClubHandler{clubService: clubService}
+func NewClubHandler(clubService *services.ClubService, playerService *services.PlayerService) *ClubHandler {
+	return &ClubHandler{clubService: clubService, playerService: playerService}
 }
 
 // GetClub godoc
@@ -26,12 +27,14 @@ func NewClubHandler(clubService *services.ClubService) *ClubHandler {
 // @Description Get a club by its VKZ/ID (format: C0101)
 // @Tags clubs
 // @Accept json
-// @Produce json,text/csv
+// @Produce json,text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/xml
 // @Param id path string true "Club ID (format: C0101)"
-// @Param format query string false "Response format (json or csv)" Enums(json,csv)
-// @Success 200 {object} models.ClubResponse
-// @Failure 400 {object} models.Response
-// @Failure 404 {object} models.Response
+// @Param fields query string false "Comma separated fields to return (e.g. id,name,member_count)"
+// @Param include query string false "Related resources to embed (players: up to 500 active players by DWZ, players_truncated marks larger clubs)"
+// @Param format query string false "Response format, instead of the Accept header" Enums(json,csv,csv-excel,ndjson,xlsx,xml)
+// @Success 200 {object} models.ClubWithIncludes
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
 // @Router /api/v1/clubs/{id} [get]
 func (h *ClubHandler) GetClub(c *gin.Context) {
 	clubID := c.Param("id")
@@ -40,7 +43,7 @@ func (h *ClubHandler) GetClub(c *gin.Context) {
 	// This handles the case where someone requests /api/v1/clubs/ instead of /api/v1/clubs
 	if clubID == "" {
 		utils.SendJSONResponse(c, http.StatusNotFound,
-			errors.NewNotFoundError("Club not found"))
+			errors.New(errors.CodeClubNotFound, "Club not found"))
 		return
 	}
 
@@ -50,7 +53,13 @@ func (h *ClubHandler) GetClub(c *gin.Context) {
 		return
 	}
 
-	club, err := h.clubService.GetClubByID(clubID)
+	includes, err := utils.ParseIncludes(c, clubIncludes...)
+	if err != nil {
+		utils.SendJSONResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	club, err := h.clubService.WithContext(c.Request.Context()).GetClubByID(clubID)
 	if err != nil {
 		if apiErr, ok := err.(errors.APIError); ok {
 			utils.SendJSONResponse(c, apiErr.Code, apiErr)
@@ -61,7 +70,63 @@ func (h *ClubHandler) GetClub(c *gin.Context) {
 		return
 	}
 
-	utils.HandleResponse(c, club, "club.csv")
+	if len(includes) == 0 {
+		utils.HandleResponse(c, club, "club.csv")
+		return
+	}
+
+	// players is the only club include
+	req := models.SearchRequest{Limit: clubIncludePlayersLimit, SortBy: "current_dwz", SortOrder: "desc"}
+	players, meta, err := h.playerService.WithContext(c.Request.Context()).GetPlayersByClub(clubID, req, true)
+	if err != nil {
+		utils.SendJSONResponse(c, http.StatusInternalServerError,
+			errors.NewInternalServerError("Failed to get related resources"))
+		return
+	}
+
+	included := &models.ClubIncludes{Players: players, PlayersTotal: len(players)}
+	if meta != nil && meta.Total > len(players) {
+		included.PlayersTotal = meta.Total
+		included.PlayersTruncated = true
+	}
+
+	response := models.ClubWithIncludes{
+		ClubResponse: *club,
+		Included:     included,
+	}
+
+	utils.HandleResponse(c, response, "club.csv")
+}
+
+// GetClubsBatch godoc
+// @Summary Get several clubs by ID
+// @Description Get up to 500 clubs in one request. There is one result per distinct ID, IDs that are invalid or not found carry an error instead of data.
+// @Tags clubs
+// @Accept json
+// @Produce json
+// @Param request body models.BatchRequest true "Club IDs (format: C0101)"
+// @Success 200 {object} models.Response{data=[]models.ClubBatchResult}
+// @Failure 400 {object} errors.Problem
+// @Router /api/v1/clubs/batch [post]
+func (h *ClubHandler) GetClubsBatch(c *gin.Context) {
+	ids, ok := bindBatchRequest(c)
+	if !ok {
+		return
+	}
+
+	results, err := h.clubService.WithContext(c.Request.Context()).GetClubsByIDs(ids)
+	if err != nil {
+		sendBatchError(c, err, "Failed to get clubs")
+		return
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != nil {
+			failed++
+		}
+	}
+	sendBatchResponse(c, results, len(results), failed)
 }
 
 // SearchClubs godoc
@@ -69,7 +134,7 @@ func (h *ClubHandler) GetClub(c *gin.Context) {
 // @Description Search clubs by name, VKZ, or other criteria
 // @Tags clubs
 // @Accept json
-// @Produce json,text/csv
+// @Produce json,text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/xml
 // @Param query query string false "Search query"
 // @Param limit query int false "Limit (max 500)" default(20)
 // @Param offset query int false "Offset" default(0)
@@ -77,9 +142,10 @@ func (h *ClubHandler) GetClub(c *gin.Context) {
 // @Param sort_order query string false "Sort order (asc/desc)" default(asc)
 // @Param filter_by query string false "Filter by field (region, district)"
 // @Param filter_value query string false "Filter value"
-// @Param format query string false "Response format (json or csv)" Enums(json,csv)
+// @Param fields query string false "Comma separated fields to return (e.g. id,name,member_count)"
+// @Param format query string false "Response format, instead of the Accept header" Enums(json,csv,csv-excel,ndjson,xlsx,xml)
 // @Success 200 {object} models.Response{data=[]models.ClubResponse,meta=models.Meta}
-// @Failure 400 {object} models.Response
+// @Failure 400 {object} errors.Problem
 // @Router /api/v1/clubs [get]
 func (h *ClubHandler) SearchClubs(c *gin.Context) {
 	req, err := utils.ParseSearchParamsWithDefaults(c, "vkz", "asc")
@@ -92,7 +158,7 @@ func (h *ClubHandler) SearchClubs(c *gin.Context) {
 		return
 	}
 
-	clubs, meta, err := h.clubService.SearchClubs(req)
+	clubs, meta, err := h.clubService.WithContext(c.Request.Context()).SearchClubs(req)
 	if err != nil {
 		if apiErr, ok := err.(errors.APIError); ok {
 			utils.SendJSONResponse(c, apiErr.Code, apiErr)
@@ -116,16 +182,42 @@ func (h *ClubHandler) SearchClubs(c *gin.Context) {
 
 // GetAllClubs godoc
 // @Summary Get all clubs
-// @Description Get all active clubs
+// @Description Get all active clubs. JSON responses are streamed while the clubs are read from the database.
 // @Tags clubs
 // @Accept json
-// @Produce json,text/csv
-// @Param format query string false "Response format (json or csv)" Enums(json,csv)
+// @Produce json,text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/xml
+// @Param fields query string false "Comma separated fields to return (e.g. id,name,member_count)"
+// @Param format query string false "Response format, instead of the Accept header" Enums(json,csv,csv-excel,ndjson,xlsx,xml)
 // @Success 200 {object} models.Response{data=[]models.ClubResponse}
-// @Failure 500 {object} models.Response
+// @Failure 500 {object} errors.Problem
 // @Router /api/v1/clubs/all [get]
 func (h *ClubHandler) GetAllClubs(c *gin.Context) {
-	clubs, err := h.clubService.GetAllClubs()
+	clubService := h.clubService.WithContext(c.Request.Context())
+
+	// JSON is written club by club as the rows are read, other formats need the complete list
+	if utils.RequestedFormat(c) == utils.FormatJSON {
+		fields := utils.ParseFields(c)
+		err := utils.StreamJSONRows(c, http.StatusOK, func(emit func(interface{}) error) error {
+			return clubService.StreamAllClubs(func(club models.ClubResponse) error {
+				selected, err := utils.SelectFields(club, fields)
+				if err != nil {
+					return err
+				}
+				return emit(selected)
+			})
+		})
+		if err != nil {
+			if apiErr, ok := err.(errors.APIError); ok {
+				utils.SendJSONResponse(c, apiErr.Code, apiErr)
+				return
+			}
+			utils.SendJSONResponse(c, http.StatusInternalServerError,
+				errors.NewInternalServerError("Failed to get all clubs"))
+		}
+		return
+	}
+
+	clubs, err := clubService.GetAllClubs()
 	if err != nil {
 		if apiErr, ok := err.(errors.APIError); ok {
 			utils.SendJSONResponse(c, apiErr.Code, apiErr)
@@ -144,12 +236,13 @@ func (h *ClubHandler) GetAllClubs(c *gin.Context) {
 // @Description Get a comprehensive club profile with players, statistics, and other details
 // @Tags clubs
 // @Accept json
-// @Produce json,text/csv
+// @Produce json,text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/xml
 // @Param id path string true "Club ID (format: C0101)"
-// @Param format query string false "Response format (json or csv)" Enums(json,csv)
+// @Param fields query string false "Comma separated fields to return"
+// @Param format query string false "Response format, instead of the Accept header" Enums(json,csv,csv-excel,ndjson,xlsx,xml)
 // @Success 200 {object} models.ClubProfileResponse
-// @Failure 400 {object} models.Response
-// @Failure 404 {object} models.Response
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
 // @Router /api/v1/clubs/{id}/profile [get]
 func (h *ClubHandler) GetClubProfile(c *gin.Context) {
 	clubID := c.Param("id")
@@ -157,7 +250,7 @@ func (h *ClubHandler) GetClubProfile(c *gin.Context) {
 	// If clubID is empty, this should be a 404 (not found)
 	if clubID == "" {
 		utils.SendJSONResponse(c, http.StatusNotFound,
-			errors.NewNotFoundError("Club not found"))
+			errors.New(errors.CodeClubNotFound, "Club not found"))
 		return
 	}
 
@@ -167,7 +260,7 @@ func (h *ClubHandler) GetClubProfile(c *gin.Context) {
 		return
 	}
 
-	profile, err := h.clubService.GetClubProfile(clubID)
+	profile, err := h.clubService.WithContext(c.Request.Context()).GetClubProfile(clubID)
 	if err != nil {
 		if apiErr, ok := err.(errors.APIError); ok {
 			utils.SendJSONResponse(c, apiErr.Code, apiErr)
@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"strings"
+
+	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetErrorCatalogue godoc
+// @Summary List error codes
+// @Description List the error codes of error responses with their HTTP status and meaning. Codes are stable, messages may change.
+// @Tags errors
+// @Produce json
+// @Success 200 {object} models.Response{data=[]errors.CatalogueEntry}
+// @Router /api/v1/errors [get]
+func GetErrorCatalogue(c *gin.Context) {
+	utils.SendJSONResponse(c, http.StatusOK, errors.Catalogue())
+}
+
+// GetErrorCode godoc
+// @Summary Describe an error code
+// @Description Describe an error code; the type URI of problem details points here
+// @Tags errors
+// @Produce json
+// @Param code path string true "Error code (e.g. PLAYER_NOT_FOUND)"
+// @Success 200 {object} models.Response{data=errors.CatalogueEntry}
+// @Failure 404 {object} errors.Problem
+// @Router /api/v1/errors/{code} [get]
+func GetErrorCode(c *gin.Context) {
+	entry, ok := errors.Lookup(errors.ErrorCode(strings.ToUpper(c.Param("code"))))
+	if !ok {
+		utils.SendJSONResponse(c, http.StatusNotFound, errors.NewNotFoundError("Error code"))
+		return
+	}
+	utils.SendJSONResponse(c, http.StatusOK, entry)
+}
+
+// RouteNotFound answers requests for paths without endpoint
+func RouteNotFound(c *gin.Context) {
+	utils.SendJSONResponse(c, http.StatusNotFound,
+		errors.New(errors.CodeRouteNotFound, "No endpoint for "+c.Request.Method+" "+c.Request.URL.Path))
+}
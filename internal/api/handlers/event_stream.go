@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"portal64api/internal/events"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// eventStreamHeartbeat is the interval of comments that keep idle streams open through proxies
+	eventStreamHeartbeat = 15 * time.Second
+	// eventStreamRetry is the reconnection delay suggested to clients
+	eventStreamRetry = 3 * time.Second
+)
+
+// streamEvents sends events as a Server-Sent Events stream until the client disconnects
+// The initial events describe the current state, so clients need no further request after (re)connecting.
+// The stream ends when the subscriber falls behind and is dropped; clients then reconnect.
+func streamEvents(c *gin.Context, ch <-chan events.Event, initial ...events.Event) {
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no") // Disable response buffering of nginx
+	c.Status(http.StatusOK)
+
+	// The stream outlives the write timeout of the server
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	if _, err := fmt.Fprintf(c.Writer, "retry: %d\n\n", eventStreamRetry.Milliseconds()); err != nil {
+		return
+	}
+	for _, event := range initial {
+		if err := writeEvent(c.Writer, event); err != nil {
+			return
+		}
+	}
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case event, ok := <-ch:
+			if !ok {
+				return
+			}
+			if err := writeEvent(c.Writer, event); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if _, err := io.WriteString(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		c.Writer.Flush()
+	}
+}
+
+// writeEvent writes an event in the Server-Sent Events format, the data as a single line of JSON
+func writeEvent(w io.Writer, event events.Event) error {
+	data, err := json.Marshal(event.Data)
+	if err != nil {
+		return err
+	}
+
+	if event.ID > 0 {
+		if _, err := fmt.Fprintf(w, "id: %d\n", event.ID); err != nil {
+			return err
+		}
+	}
+	_, err = fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+	return err
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"time"
+
+	"portal64api/internal/models"
+	"portal64api/internal/services"
+	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ExportHandler serves the bulk export
+type ExportHandler struct {
+	exportService *services.ExportService
+}
+
+// NewExportHandler creates a new export handler
+func NewExportHandler(exportService *services.ExportService) *ExportHandler {
+	return &ExportHandler{exportService: exportService}
+}
+
+// GetManifest returns the manifest of the bulk export
+// @Summary Get bulk export manifest
+// @Description Get the files of the newest bulk export generation with row counts and SHA-256 checksums.
+// @Description All files of a generation are read from the same database snapshot, taken after an import.
+// @Tags export
+// @Produce json
+// @Param generation query string false "Generation, e.g. 20261018T031502Z (default: newest)"
+// @Success 200 {object} models.Response{data=models.ExportManifest}
+// @Failure 400 {object} errors.Problem
+// @Failure 503 {object} errors.Problem "No export generated yet"
+// @Security ApiKeyAuth
+// @Router /api/v1/export [get]
+func (h *ExportHandler) GetManifest(c *gin.Context) {
+	manifest, err := h.exportService.GetManifest(c.Query("generation"))
+	if err != nil {
+		sendExportError(c, err)
+		return
+	}
+
+	utils.SendJSONResponse(c, http.StatusOK, manifest)
+}
+
+// DownloadDataset downloads a dataset of the bulk export
+// @Summary Download bulk export dataset
+// @Description Download a complete dataset of the bulk export as NDJSON or gzipped CSV (semicolon separated).
+// @Description NDJSON is sent gzip encoded to clients accepting it and can be resumed with Range requests.
+// @Description The ETag is the SHA-256 checksum of the manifest, X-Export-Generation names the generation.
+// @Tags export
+// @Produce application/x-ndjson,application/gzip
+// @Param dataset path string true "Dataset" Enums(clubs, players, memberships, evaluations)
+// @Param format query string false "File format" Enums(ndjson, csv) default(ndjson)
+// @Param generation query string false "Generation, e.g. 20261018T031502Z (default: newest)"
+// @Success 200 {file} file
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem "Unknown dataset"
+// @Failure 406 {object} errors.Problem "Unknown format"
+// @Failure 503 {object} errors.Problem "No export generated yet"
+// @Security ApiKeyAuth
+// @Router /api/v1/export/{dataset} [get]
+func (h *ExportHandler) DownloadDataset(c *gin.Context) {
+	format := c.DefaultQuery("format", models.ExportFormatNDJSON)
+	if format != models.ExportFormatNDJSON && format != models.ExportFormatCSV {
+		utils.SendJSONResponse(c, http.StatusNotAcceptable, errors.New(errors.CodeNotAcceptable,
+			fmt.Sprintf("Unknown export format '%s' (supported: %s, %s)", format, models.ExportFormatNDJSON, models.ExportFormatCSV)))
+		return
+	}
+
+	path, manifest, file, err := h.exportService.GetFile(c.Query("generation"), c.Param("dataset"), format)
+	if err != nil {
+		sendExportError(c, err)
+		return
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		// The generation was pruned after the manifest was read
+		utils.SendJSONResponse(c, http.StatusServiceUnavailable,
+			errors.New(errors.CodeExportNotReady, fmt.Sprintf("Export generation %s is not available", manifest.Generation)))
+		return
+	}
+	defer f.Close()
+
+	// Datasets are large, the download outlives the write timeout of the server
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("X-Export-Generation", manifest.Generation)
+	c.Header("Cache-Control", "private, no-cache")
+
+	if format == models.ExportFormatCSV {
+		c.Header("Content-Type", "application/gzip")
+		c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", file.Name))
+		c.Header("ETag", `"`+file.SHA256+`"`)
+		http.ServeContent(c.Writer, c.Request, file.Name, manifest.GeneratedAt, f)
+		return
+	}
+
+	c.Header("Content-Type", utils.FormatNDJSON.ContentType())
+	c.Header("Vary", "Accept-Encoding")
+	if utils.AcceptsGzip(c.GetHeader("Accept-Encoding")) {
+		// The file is sent as stored, the compression middleware leaves encoded responses alone
+		c.Header("Content-Encoding", "gzip")
+		c.Header("ETag", `"`+file.SHA256+`"`)
+		http.ServeContent(c.Writer, c.Request, file.Name, manifest.GeneratedAt, f)
+		return
+	}
+
+	etag := `"` + file.ContentSHA256 + `"`
+	c.Header("ETag", etag)
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		utils.SendJSONResponse(c, http.StatusInternalServerError,
+			errors.NewInternalServerError("Failed to read export: "+err.Error()))
+		return
+	}
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, gz); err != nil {
+		// Headers are sent, the client sees a truncated stream
+		c.Error(err)
+	}
+}
+
+// GenerateExport starts generating a new bulk export
+// @Summary Generate bulk export
+// @Description Start generating a new bulk export generation in the background. Exports are generated
+// @Description automatically after each import; the current generation stays available meanwhile.
+// @Tags export
+// @Produce json
+// @Success 202 {object} models.Response
+// @Failure 409 {object} errors.Problem "Export already running"
+// @Security ApiKeyAuth
+// @Router /api/v1/export/generate [post]
+func (h *ExportHandler) GenerateExport(c *gin.Context) {
+	if err := h.exportService.StartGeneration(); err != nil {
+		sendExportError(c, err)
+		return
+	}
+
+	utils.SendJSONResponse(c, http.StatusAccepted, gin.H{"message": "Export generation started"})
+}
+
+// sendExportError sends an error of the export service
+func sendExportError(c *gin.Context, err error) {
+	if apiErr, ok := err.(errors.APIError); ok {
+		utils.SendJSONResponse(c, apiErr.Code, apiErr)
+		return
+	}
+	utils.SendJSONResponse(c, http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+}
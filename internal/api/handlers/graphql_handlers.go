@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+
+	"portal64api/internal/graphql"
+	"portal64api/internal/ratelimit"
+	"portal64api/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+)
+
+// GraphQLHandler handles GraphQL requests
+type GraphQLHandler struct {
+	server *graphql.Server
+}
+
+// NewGraphQLHandler creates a new GraphQL handler
+func NewGraphQLHandler(server *graphql.Server) *GraphQLHandler {
+	return &GraphQLHandler{server: server}
+}
+
+// Query godoc
+// @Summary GraphQL query
+// @Description Read-only GraphQL endpoint over players, clubs, tournaments and addresses. Load the schema with an introspection query. Queries exceeding the configured depth or complexity are rejected with 400. Complex queries take one rate limit token per started GRAPHQL_COMPLEXITY_PER_TOKEN of complexity and are rejected with 429 if the client has too few left.
+// @Tags graphql
+// @Accept json
+// @Produce json
+// @Param request body graphql.Request true "GraphQL request"
+// @Success 200 {object} map[string]interface{} "data and errors of the query"
+// @Failure 400 {object} map[string]interface{} "errors of an invalid query"
+// @Failure 429 {object} map[string]interface{} "rate limit exceeded by the complexity of the query"
+// @Router /api/v1/graphql [post]
+func (h *GraphQLHandler) Query(c *gin.Context) {
+	var request graphql.Request
+	if c.Request.Method == http.MethodGet {
+		// GET requests pass the query and JSON encoded variables as query parameters
+		request.Query = c.Query("query")
+		request.OperationName = c.Query("operationName")
+		if variables := c.Query("variables"); variables != "" {
+			if err := json.Unmarshal([]byte(variables), &request.Variables); err != nil {
+				sendGraphQLError(c, "Invalid variables parameter")
+				return
+			}
+		}
+	} else if err := c.ShouldBindJSON(&request); err != nil {
+		sendGraphQLError(c, `Invalid request format (expected: {"query": "...", "variables": {...}})`)
+		return
+	}
+
+	if request.Query == "" {
+		sendGraphQLError(c, "Query cannot be empty")
+		return
+	}
+
+	operation, invalid := h.server.Prepare(request)
+	if invalid != nil {
+		c.JSON(http.StatusBadRequest, invalid)
+		return
+	}
+
+	// The rate limit middleware took one token for the request, complex queries pay the rest
+	if extra := operation.Tokens - 1; extra > 0 {
+		result, err := ratelimit.ChargeRequest(c.Request.Context(), extra)
+		if err == nil && result.Limit > 0 {
+			c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			c.Header("RateLimit-Reset", strconv.Itoa(int(math.Ceil(result.Reset.Seconds()))))
+		}
+		if err == nil && !result.Allowed {
+			retryAfter := int(math.Ceil(result.RetryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			c.JSON(http.StatusTooManyRequests, gql.Result{Errors: []gqlerrors.FormattedError{{
+				Message: fmt.Sprintf("Rate limit exceeded, the query costs %d requests (complexity %d); retry after %d seconds",
+					operation.Tokens, operation.Complexity, retryAfter),
+				Extensions: map[string]interface{}{"code": http.StatusTooManyRequests, "error_code": string(errors.CodeRateLimited)},
+			}}})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, h.server.Run(c.Request.Context(), operation))
+}
+
+// sendGraphQLError answers a request that is no valid GraphQL request, in the GraphQL response format
+func sendGraphQLError(c *gin.Context, message string) {
+	c.JSON(http.StatusBadRequest, gql.Result{Errors: []gqlerrors.FormattedError{{Message: message}}})
+}
@@ -3,12 +3,26 @@ package handlers
 import (
 	"net/http"
 
+	"portal64api/internal/services"
+
 	"github.com/gin-gonic/gin"
 )
 
+// HealthHandler handles liveness and readiness probes
+type HealthHandler struct {
+	healthService *services.HealthService
+}
+
+// NewHealthHandler creates a new health handler
+func NewHealthHandler(healthService *services.HealthService) *HealthHandler {
+	return &HealthHandler{
+		healthService: healthService,
+	}
+}
+
 // HealthCheck godoc
 // @Summary Health check
-// @Description Check if the API is running and healthy
+// @Description Check if the API is running. Use /health/ready to check the dependencies.
 // @Tags health
 // @Accept json
 // @Produce json
@@ -17,6 +31,39 @@ import (
 func HealthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status":  "healthy",
-		"version": "1.0.0",
+		"version": services.BuildInfo().Version,
 	})
 }
+
+// Live godoc
+// @Summary Liveness check
+// @Description Check if the process is running, without checking its dependencies
+// @Tags health
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.LivenessResponse
+// @Router /health/live [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, h.healthService.Live())
+}
+
+// Ready godoc
+// @Summary Readiness check
+// @Description Check the databases, the cache, running imports and the Kader-Planung binary.
+// @Description Returns 503 while a database is unreachable or an import is replacing the databases.
+// @Tags health
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.ReadinessResponse
+// @Failure 503 {object} models.ReadinessResponse
+// @Router /health/ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	readiness := h.healthService.Ready(c.Request.Context())
+
+	status := http.StatusOK
+	if !readiness.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	c.Header("Cache-Control", "no-store")
+	c.JSON(status, readiness)
+}
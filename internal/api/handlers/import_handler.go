@@ -3,8 +3,11 @@ package handlers
 import (
 	"fmt"
 	"net/http"
+	"portal64api/internal/events"
 	"portal64api/internal/models"
 	"portal64api/internal/services"
+	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
 	"strconv"
 	"time"
 
@@ -30,12 +33,12 @@ func NewImportHandler(importService services.ImportServiceInterface) *ImportHand
 // @Accept json
 // @Produce json
 // @Success 200 {object} models.ImportStatus
+// @Security ApiKeyAuth
 // @Router /api/v1/import/status [get]
 func (ih *ImportHandler) GetImportStatus(c *gin.Context) {
 	if ih.importService == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"error": "Import service is not available",
-		})
+		utils.SendJSONResponse(c, http.StatusServiceUnavailable,
+			errors.New(errors.CodeServiceUnavailable, "Import service is not available"))
 		return
 	}
 
@@ -50,35 +53,32 @@ func (ih *ImportHandler) GetImportStatus(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} models.ImportStartResponse
-// @Failure 400 {object} gin.H
-// @Failure 409 {object} gin.H
-// @Failure 500 {object} gin.H
+// @Failure 400 {object} errors.Problem
+// @Failure 409 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/import/start [post]
 func (ih *ImportHandler) StartManualImport(c *gin.Context) {
 	if ih.importService == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"error": "Import service is not available",
-		})
+		utils.SendJSONResponse(c, http.StatusServiceUnavailable,
+			errors.New(errors.CodeServiceUnavailable, "Import service is not available"))
 		return
 	}
 
 	err := ih.importService.TriggerManualImport()
 	if err != nil {
 		if err.Error() == "import is already running" {
-			c.JSON(http.StatusConflict, gin.H{
-				"error": "Import is already running",
-			})
+			utils.SendJSONResponse(c, http.StatusConflict,
+				errors.New(errors.CodeImportInProgress, "Import is already running"))
 			return
 		}
 		if err.Error() == "import service is disabled" {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": "Import service is disabled",
-			})
+			utils.SendJSONResponse(c, http.StatusBadRequest,
+				errors.New(errors.CodeImportDisabled, "Import service is disabled"))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": err.Error(),
-		})
+		utils.SendJSONResponse(c, http.StatusInternalServerError,
+			errors.NewAPIError(http.StatusInternalServerError, err.Error()))
 		return
 	}
 
@@ -98,12 +98,12 @@ func (ih *ImportHandler) StartManualImport(c *gin.Context) {
 // @Produce json
 // @Param limit query int false "Maximum number of log entries to return (default: 500)"
 // @Success 200 {object} models.ImportLogsResponse
+// @Security ApiKeyAuth
 // @Router /api/v1/import/logs [get]
 func (ih *ImportHandler) GetImportLogs(c *gin.Context) {
 	if ih.importService == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"error": "Import service is not available",
-		})
+		utils.SendJSONResponse(c, http.StatusServiceUnavailable,
+			errors.New(errors.CodeServiceUnavailable, "Import service is not available"))
 		return
 	}
 
@@ -127,6 +127,31 @@ func (ih *ImportHandler) GetImportLogs(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// StreamImportEvents streams import progress as Server-Sent Events
+// @Summary Stream import progress
+// @Description Stream status changes and log entries of imports as Server-Sent Events instead of polling.
+// @Description "status" events carry the full import status (step and progress), "log" events a log entry.
+// @Description The current status is sent on connect. Idle streams receive a heartbeat comment every 15 seconds.
+// @Tags import
+// @Produce text/event-stream
+// @Success 200 {string} string "Event stream"
+// @Failure 503 {object} errors.Problem
+// @Security ApiKeyAuth
+// @Router /api/v1/import/events [get]
+func (ih *ImportHandler) StreamImportEvents(c *gin.Context) {
+	if ih.importService == nil {
+		utils.SendJSONResponse(c, http.StatusServiceUnavailable,
+			errors.New(errors.CodeServiceUnavailable, "Import service is not available"))
+		return
+	}
+
+	// Subscribe before reading the status, so no change in between is missed
+	ch, unsubscribe := ih.importService.SubscribeEvents()
+	defer unsubscribe()
+
+	streamEvents(c, ch, events.Event{Type: events.TypeStatus, Data: ih.importService.GetStatus()})
+}
+
 // TestImportConnection tests the SCP connection
 // @Summary Test import connection
 // @Description Test the SCP connection for import operations
@@ -134,21 +159,20 @@ func (ih *ImportHandler) GetImportLogs(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} gin.H
-// @Failure 500 {object} gin.H
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/import/test-connection [post]
 func (ih *ImportHandler) TestImportConnection(c *gin.Context) {
 	if ih.importService == nil {
-		c.JSON(http.StatusServiceUnavailable, gin.H{
-			"error": "Import service is not available",
-		})
+		utils.SendJSONResponse(c, http.StatusServiceUnavailable,
+			errors.New(errors.CodeServiceUnavailable, "Import service is not available"))
 		return
 	}
 
 	err := ih.importService.TestConnection()
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": fmt.Sprintf("Connection test failed: %v", err),
-		})
+		utils.SendJSONResponse(c, http.StatusInternalServerError,
+			errors.NewAPIError(http.StatusInternalServerError, fmt.Sprintf("Connection test failed: %v", err)))
 		return
 	}
 
@@ -165,6 +189,7 @@ func (ih *ImportHandler) TestImportConnection(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} gin.H
+// @Security ApiKeyAuth
 // @Router /api/v1/import/health [get]
 func (ih *ImportHandler) GetImportHealth(c *gin.Context) {
 	if ih.importService == nil {
@@ -205,6 +230,7 @@ func (ih *ImportHandler) GetImportHealth(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} gin.H
+// @Security ApiKeyAuth
 // @Router /api/v1/import/config [get]
 func (ih *ImportHandler) GetImportConfig(c *gin.Context) {
 	// This would typically be restricted to admin users
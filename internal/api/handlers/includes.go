@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"portal64api/internal/models"
+	"portal64api/internal/services"
+)
+
+// Related resources that can be embedded with ?include= per resource type
+var (
+	playerIncludes     = []string{models.IncludeClub, models.IncludeRatingHistory, models.IncludeMemberships}
+	clubIncludes       = []string{models.IncludePlayers}
+	tournamentIncludes = []string{models.IncludePerformance, models.IncludeTeamMatches}
+)
+
+// clubIncludePlayersLimit caps the number of players embedded in a club
+const clubIncludePlayersLimit = 500
+
+// embedPlayerIncludes loads the requested related resources of the players
+// Each include is loaded for all players at once with the batch lookups of the services.
+// Related resources that are unavailable for a player are left out instead of failing the request.
+func embedPlayerIncludes(playerService *services.PlayerService, clubService *services.ClubService, players []models.PlayerResponse, includes []string) ([]models.PlayerWithIncludes, error) {
+	results := make([]models.PlayerWithIncludes, len(players))
+	playerIDs := make([]string, 0, len(players))
+	clubIDs := make([]string, 0, len(players))
+	for i, player := range players {
+		results[i] = models.PlayerWithIncludes{PlayerResponse: player, Included: &models.PlayerIncludes{}}
+		playerIDs = append(playerIDs, player.ID)
+		if player.ClubID != "" {
+			clubIDs = append(clubIDs, player.ClubID)
+		}
+	}
+	if len(players) == 0 {
+		return results, nil
+	}
+
+	for _, include := range includes {
+		switch include {
+		case models.IncludeClub:
+			if len(clubIDs) == 0 {
+				continue
+			}
+			batch, err := clubService.GetClubsByIDs(clubIDs)
+			if err != nil {
+				return nil, err
+			}
+			clubs := make(map[string]*models.ClubResponse, len(batch))
+			for _, result := range batch {
+				if result.Error == nil {
+					clubs[result.ID] = result.Data
+				}
+			}
+			for i := range results {
+				results[i].Included.Club = clubs[results[i].ClubID]
+			}
+
+		case models.IncludeRatingHistory:
+			batch, err := playerService.GetPlayersRatingHistories(playerIDs)
+			if err != nil {
+				return nil, err
+			}
+			histories := make(map[string][]models.RatingHistoryResponse, len(batch))
+			for _, result := range batch {
+				if result.Error == nil {
+					histories[result.ID] = result.Data
+				}
+			}
+			for i := range results {
+				results[i].Included.RatingHistory = histories[results[i].ID]
+			}
+
+		case models.IncludeMemberships:
+			batch, err := playerService.GetPlayersMemberships(playerIDs)
+			if err != nil {
+				return nil, err
+			}
+			memberships := make(map[string][]models.MembershipResponse, len(batch))
+			for _, result := range batch {
+				if result.Error == nil {
+					memberships[result.ID] = result.Data
+				}
+			}
+			for i := range results {
+				results[i].Included.Memberships = memberships[results[i].ID]
+			}
+		}
+	}
+
+	return results, nil
+}
@@ -5,6 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"portal64api/internal/events"
 	"portal64api/internal/services"
 	"portal64api/pkg/errors"
 	"portal64api/pkg/utils"
@@ -31,7 +32,8 @@ func NewKaderPlanungHandler(service *services.KaderPlanungService) *KaderPlanung
 // @Accept json
 // @Produce json
 // @Success 200 {object} services.ExecutionStatus
-// @Failure 500 {object} errors.APIError
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/kader-planung/status [get]
 func (h *KaderPlanungHandler) GetKaderPlanungStatus(c *gin.Context) {
 	status := h.service.GetStatus()
@@ -52,6 +54,24 @@ func (h *KaderPlanungHandler) GetKaderPlanungStatus(c *gin.Context) {
 	utils.SendJSONResponse(c, http.StatusOK, response)
 }
 
+// StreamAnalysisEvents streams analysis progress as Server-Sent Events
+// @Summary Stream analysis progress
+// @Description Stream the progress of Kader-Planung, statistical and hybrid analysis runs as Server-Sent Events instead of polling.
+// @Description "status" events carry the execution status with job and progress, "log" events a line of output
+// @Description and "completed" events the finished run. The current status is sent on connect.
+// @Tags kader-planung
+// @Produce text/event-stream
+// @Success 200 {string} string "Event stream"
+// @Security ApiKeyAuth
+// @Router /api/v1/kader-planung/events [get]
+func (h *KaderPlanungHandler) StreamAnalysisEvents(c *gin.Context) {
+	// Subscribe before reading the status, so no change in between is missed
+	ch, unsubscribe := h.service.SubscribeEvents()
+	defer unsubscribe()
+
+	streamEvents(c, ch, events.Event{Type: events.TypeStatus, Data: h.service.GetStatus()})
+}
+
 // StartKaderPlanungExecution starts manual Kader-Planung execution
 // @Summary Start manual Kader-Planung execution  
 // @Description Starts manual execution of Kader-Planung with optional parameters
@@ -60,15 +80,16 @@ func (h *KaderPlanungHandler) GetKaderPlanungStatus(c *gin.Context) {
 // @Produce json
 // @Param request body KaderPlanungRequest false "Execution parameters"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} errors.APIError
-// @Failure 409 {object} errors.APIError "Already running"
-// @Failure 500 {object} errors.APIError
+// @Failure 400 {object} errors.Problem
+// @Failure 409 {object} errors.Problem "Already running"
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/kader-planung/start [post]
 func (h *KaderPlanungHandler) StartKaderPlanungExecution(c *gin.Context) {
 	var request KaderPlanungRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		utils.SendJSONResponse(c, http.StatusBadRequest, 
-			errors.NewBadRequestError("Invalid request format"))
+			errors.New(errors.CodeInvalidRequestBody, "Invalid request format"))
 		return
 	}
 	
@@ -93,7 +114,7 @@ func (h *KaderPlanungHandler) StartKaderPlanungExecution(c *gin.Context) {
 	if err := h.service.ExecuteManually(params); err != nil {
 		if strings.Contains(err.Error(), "already running") {
 			utils.SendJSONResponse(c, http.StatusConflict, 
-				errors.NewAPIError(409, "EXECUTION_ALREADY_RUNNING", "Execution already in progress"))
+				errors.New(errors.CodeExecutionAlreadyRunning, "Execution already in progress"))
 			return
 		}
 		
@@ -114,7 +135,8 @@ func (h *KaderPlanungHandler) StartKaderPlanungExecution(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {array} services.FileInfo
-// @Failure 500 {object} errors.APIError
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/kader-planung/files [get]
 func (h *KaderPlanungHandler) ListKaderPlanungFiles(c *gin.Context) {
 	files, err := h.service.ListAvailableFiles()
@@ -135,9 +157,10 @@ func (h *KaderPlanungHandler) ListKaderPlanungFiles(c *gin.Context) {
 // @Produce application/octet-stream
 // @Param filename path string true "Filename to download"
 // @Success 200 {file} file "CSV file content"
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/kader-planung/download/{filename} [get]
 func (h *KaderPlanungHandler) DownloadKaderPlanungFile(c *gin.Context) {
 	filename := c.Param("filename")
@@ -172,7 +195,7 @@ func (h *KaderPlanungHandler) DownloadKaderPlanungFile(c *gin.Context) {
 	
 	if targetFile == nil {
 		utils.SendJSONResponse(c, http.StatusNotFound, 
-			errors.NewNotFoundError("The requested file does not exist"))
+			errors.New(errors.CodeFileNotFound, "The requested file does not exist"))
 		return
 	}
 	
@@ -225,15 +248,16 @@ type HybridAnalysisRequest struct {
 // @Produce json
 // @Param request body StatisticalAnalysisRequest false "Statistical analysis parameters"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} errors.APIError
-// @Failure 409 {object} errors.APIError "Already running"
-// @Failure 500 {object} errors.APIError
+// @Failure 400 {object} errors.Problem
+// @Failure 409 {object} errors.Problem "Already running"
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/kader-planung/statistical [post]
 func (h *KaderPlanungHandler) ExecuteStatisticalAnalysis(c *gin.Context) {
 	var request StatisticalAnalysisRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		utils.SendJSONResponse(c, http.StatusBadRequest,
-			errors.NewBadRequestError("Invalid request format"))
+			errors.New(errors.CodeInvalidRequestBody, "Invalid request format"))
 		return
 	}
 
@@ -258,7 +282,7 @@ func (h *KaderPlanungHandler) ExecuteStatisticalAnalysis(c *gin.Context) {
 	if err := h.service.ExecuteStatisticalAnalysis(params); err != nil {
 		if strings.Contains(err.Error(), "already running") {
 			utils.SendJSONResponse(c, http.StatusConflict,
-				errors.NewAPIError(409, "EXECUTION_ALREADY_RUNNING", "Statistical analysis already in progress"))
+				errors.New(errors.CodeExecutionAlreadyRunning, "Statistical analysis already in progress"))
 			return
 		}
 
@@ -280,15 +304,16 @@ func (h *KaderPlanungHandler) ExecuteStatisticalAnalysis(c *gin.Context) {
 // @Produce json
 // @Param request body HybridAnalysisRequest false "Hybrid analysis parameters"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} errors.APIError
-// @Failure 409 {object} errors.APIError "Already running"
-// @Failure 500 {object} errors.APIError
+// @Failure 400 {object} errors.Problem
+// @Failure 409 {object} errors.Problem "Already running"
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/kader-planung/hybrid [post]
 func (h *KaderPlanungHandler) ExecuteHybridAnalysis(c *gin.Context) {
 	var request HybridAnalysisRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		utils.SendJSONResponse(c, http.StatusBadRequest,
-			errors.NewBadRequestError("Invalid request format"))
+			errors.New(errors.CodeInvalidRequestBody, "Invalid request format"))
 		return
 	}
 
@@ -319,7 +344,7 @@ func (h *KaderPlanungHandler) ExecuteHybridAnalysis(c *gin.Context) {
 	if err := h.service.ExecuteHybridAnalysis(params); err != nil {
 		if strings.Contains(err.Error(), "already running") {
 			utils.SendJSONResponse(c, http.StatusConflict,
-				errors.NewAPIError(409, "EXECUTION_ALREADY_RUNNING", "Hybrid analysis already in progress"))
+				errors.New(errors.CodeExecutionAlreadyRunning, "Hybrid analysis already in progress"))
 			return
 		}
 
@@ -340,7 +365,8 @@ func (h *KaderPlanungHandler) ExecuteHybridAnalysis(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {array} services.FileInfo
-// @Failure 500 {object} errors.APIError
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/kader-planung/statistical/files [get]
 func (h *KaderPlanungHandler) GetStatisticalResults(c *gin.Context) {
 	files, err := h.service.ListAvailableFiles()
@@ -368,7 +394,8 @@ func (h *KaderPlanungHandler) GetStatisticalResults(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {object} map[string]interface{}
-// @Failure 500 {object} errors.APIError
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/kader-planung/capabilities [get]
 func (h *KaderPlanungHandler) GetAnalysisCapabilities(c *gin.Context) {
 	capabilities := h.service.GetAnalysisCapabilities()
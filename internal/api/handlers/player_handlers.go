@@ -15,11 +15,12 @@ import (
 // PlayerHandler handles player-related HTTP requests
 type PlayerHandler struct {
 	playerService *services.PlayerService
+	clubService   *services.ClubService // Embeds clubs with ?include=club
 }
 
 // NewPlayerHandler creates a new player handler
-func NewPlayerHandler(playerService *services.PlayerService) *PlayerHandler {
-	return &PlayerHandler{playerService: playerService}
+func NewPlayerHandler(playerService *services.PlayerService, clubService *services.ClubService) *PlayerHandler {
+	return &PlayerHandler{playerService: playerService, clubService: clubService}
 }
 
 // GetPlayer godoc
@@ -27,12 +28,14 @@ func NewPlayerHandler(playerService *services.PlayerService) *PlayerHandler {
 // @Description Get a player by their ID (format: C0101-1014)
 // @Tags players
 // @Accept json
-// @Produce json,text/csv
+// @Produce json,text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/xml
 // @Param id path string true "Player ID (format: C0101-1014)"
-// @Param format query string false "Response format (json or csv)" Enums(json,csv)
-// @Success 200 {object} models.PlayerResponse
-// @Failure 400 {object} models.Response
-// @Failure 404 {object} models.Response
+// @Param fields query string false "Comma separated fields to return (e.g. id,name,current_dwz)"
+// @Param include query string false "Related resources to embed (club, rating_history, memberships)"
+// @Param format query string false "Response format, instead of the Accept header" Enums(json,csv,csv-excel,ndjson,xlsx,xml)
+// @Success 200 {object} models.PlayerWithIncludes
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
 // @Router /api/v1/players/{id} [get]
 func (h *PlayerHandler) GetPlayer(c *gin.Context) {
 	playerID := c.Param("id")
@@ -43,7 +46,13 @@ func (h *PlayerHandler) GetPlayer(c *gin.Context) {
 		return
 	}
 
-	player, err := h.playerService.GetPlayerByID(playerID)
+	includes, err := utils.ParseIncludes(c, playerIncludes...)
+	if err != nil {
+		utils.SendJSONResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	player, err := h.playerService.WithContext(c.Request.Context()).GetPlayerByID(playerID)
 	if err != nil {
 		if apiErr, ok := err.(errors.APIError); ok {
 			utils.SendJSONResponse(c, apiErr.Code, apiErr)
@@ -54,7 +63,19 @@ func (h *PlayerHandler) GetPlayer(c *gin.Context) {
 		return
 	}
 
-	utils.HandleResponse(c, player, "player.csv")
+	if len(includes) == 0 {
+		utils.HandleResponse(c, player, "player.csv")
+		return
+	}
+
+	embedded, err := h.embedIncludes(c, []models.PlayerResponse{*player}, includes)
+	if err != nil {
+		utils.SendJSONResponse(c, http.StatusInternalServerError,
+			errors.NewInternalServerError("Failed to get related resources"))
+		return
+	}
+
+	utils.HandleResponse(c, embedded[0], "player.csv")
 }
 
 // SearchPlayers godoc
@@ -62,16 +83,18 @@ func (h *PlayerHandler) GetPlayer(c *gin.Context) {
 // @Description Search players by name with pagination
 // @Tags players
 // @Accept json
-// @Produce json,text/csv
+// @Produce json,text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/xml
 // @Param query query string false "Search query"
 // @Param limit query int false "Limit (max 500)" default(20)
 // @Param offset query int false "Offset" default(0)
 // @Param sort_by query string false "Sort by field" default(name)
 // @Param sort_order query string false "Sort order (asc/desc)" default(asc)
 // @Param active query bool false "Show only active players with valid club memberships" default(true)
-// @Param format query string false "Response format (json or csv)" Enums(json,csv)
-// @Success 200 {object} models.Response{data=[]models.PlayerResponse,meta=models.Meta}
-// @Failure 400 {object} models.Response
+// @Param fields query string false "Comma separated fields to return (e.g. id,name,current_dwz)"
+// @Param include query string false "Related resources to embed (club, rating_history, memberships)"
+// @Param format query string false "Response format, instead of the Accept header" Enums(json,csv,csv-excel,ndjson,xlsx,xml)
+// @Success 200 {object} models.Response{data=[]models.PlayerWithIncludes,meta=models.Meta}
+// @Failure 400 {object} errors.Problem
 // @Router /api/v1/players [get]
 func (h *PlayerHandler) SearchPlayers(c *gin.Context) {
 	req, err := utils.ParseSearchParams(c)
@@ -93,7 +116,13 @@ func (h *PlayerHandler) SearchPlayers(c *gin.Context) {
 		return
 	}
 
-	players, meta, err := h.playerService.SearchPlayers(req, showActive)
+	includes, err := utils.ParseIncludes(c, playerIncludes...)
+	if err != nil {
+		utils.SendJSONResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	players, meta, err := h.playerService.WithContext(c.Request.Context()).SearchPlayers(req, showActive)
 	if err != nil {
 		if apiErr, ok := err.(errors.APIError); ok {
 			utils.SendJSONResponse(c, apiErr.Code, apiErr)
@@ -104,6 +133,26 @@ func (h *PlayerHandler) SearchPlayers(c *gin.Context) {
 		return
 	}
 
+	if len(includes) > 0 {
+		embedded, err := h.embedIncludes(c, players, includes)
+		if err != nil {
+			utils.SendJSONResponse(c, http.StatusInternalServerError,
+				errors.NewInternalServerError("Failed to get related resources"))
+			return
+		}
+
+		response := struct {
+			Data []models.PlayerWithIncludes `json:"data"`
+			Meta interface{}                 `json:"meta"`
+		}{
+			Data: embedded,
+			Meta: meta,
+		}
+
+		utils.HandleResponse(c, response, "players.csv")
+		return
+	}
+
 	response := struct {
 		Data []models.PlayerResponse `json:"data"`
 		Meta interface{}             `json:"meta"`
@@ -120,12 +169,13 @@ func (h *PlayerHandler) SearchPlayers(c *gin.Context) {
 // @Description Get DWZ rating history for a player
 // @Tags players
 // @Accept json
-// @Produce json,text/csv
+// @Produce json,text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/xml
 // @Param id path string true "Player ID (format: C0101-1014)"
-// @Param format query string false "Response format (json or csv)" Enums(json,csv)
+// @Param fields query string false "Comma separated fields to return"
+// @Param format query string false "Response format, instead of the Accept header" Enums(json,csv,csv-excel,ndjson,xlsx,xml)
 // @Success 200 {object} models.Response{data=[]models.RatingHistoryResponse}
-// @Failure 400 {object} models.Response
-// @Failure 404 {object} models.Response
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
 // @Router /api/v1/players/{id}/rating-history [get]
 func (h *PlayerHandler) GetPlayerRatingHistory(c *gin.Context) {
 	playerID := c.Param("id")
@@ -141,7 +191,7 @@ func (h *PlayerHandler) GetPlayerRatingHistory(c *gin.Context) {
 		return
 	}
 
-	history, err := h.playerService.GetPlayerRatingHistory(playerID)
+	history, err := h.playerService.WithContext(c.Request.Context()).GetPlayerRatingHistory(playerID)
 	if err != nil {
 		if apiErr, ok := err.(errors.APIError); ok {
 			utils.SendJSONResponse(c, apiErr.Code, apiErr)
@@ -155,12 +205,74 @@ func (h *PlayerHandler) GetPlayerRatingHistory(c *gin.Context) {
 	utils.HandleResponse(c, history, "rating_history.csv")
 }
 
+// GetPlayersBatch godoc
+// @Summary Get several players by ID
+// @Description Get up to 500 players in one request. There is one result per distinct ID, IDs that are invalid or not found carry an error instead of data.
+// @Tags players
+// @Accept json
+// @Produce json
+// @Param request body models.BatchRequest true "Player IDs (format: C0101-1014)"
+// @Success 200 {object} models.Response{data=[]models.PlayerBatchResult}
+// @Failure 400 {object} errors.Problem
+// @Router /api/v1/players/batch [post]
+func (h *PlayerHandler) GetPlayersBatch(c *gin.Context) {
+	ids, ok := bindBatchRequest(c)
+	if !ok {
+		return
+	}
+
+	results, err := h.playerService.WithContext(c.Request.Context()).GetPlayersByIDs(ids)
+	if err != nil {
+		sendBatchError(c, err, "Failed to get players")
+		return
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != nil {
+			failed++
+		}
+	}
+	sendBatchResponse(c, results, len(results), failed)
+}
+
+// GetPlayersRatingHistoryBatch godoc
+// @Summary Get the rating histories of several players
+// @Description Get the DWZ rating histories of up to 500 players in one request. There is one result per distinct ID, IDs that are invalid or not found carry an error instead of data.
+// @Tags players
+// @Accept json
+// @Produce json
+// @Param request body models.BatchRequest true "Player IDs (format: C0101-1014)"
+// @Success 200 {object} models.Response{data=[]models.RatingHistoryBatchResult}
+// @Failure 400 {object} errors.Problem
+// @Router /api/v1/players/rating-history/batch [post]
+func (h *PlayerHandler) GetPlayersRatingHistoryBatch(c *gin.Context) {
+	ids, ok := bindBatchRequest(c)
+	if !ok {
+		return
+	}
+
+	results, err := h.playerService.WithContext(c.Request.Context()).GetPlayersRatingHistories(ids)
+	if err != nil {
+		sendBatchError(c, err, "Failed to get rating history")
+		return
+	}
+
+	failed := 0
+	for _, result := range results {
+		if result.Error != nil {
+			failed++
+		}
+	}
+	sendBatchResponse(c, results, len(results), failed)
+}
+
 // GetPlayersByClub godoc
 // @Summary Get players by club
 // @Description Get all players in a specific club
 // @Tags players
 // @Accept json
-// @Produce json,text/csv
+// @Produce json,text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/xml
 // @Param id path string true "Club ID (format: C0101)"
 // @Param query query string false "Search query"
 // @Param limit query int false "Limit (max 500)" default(20)
@@ -168,10 +280,12 @@ func (h *PlayerHandler) GetPlayerRatingHistory(c *gin.Context) {
 // @Param sort_by query string false "Sort by field" default(current_dwz)
 // @Param sort_order query string false "Sort order (asc/desc)" default(desc)
 // @Param active query bool false "Show only active players with valid club memberships" default(true)
-// @Param format query string false "Response format (json or csv)" Enums(json,csv)
-// @Success 200 {object} models.Response{data=[]models.PlayerResponse,meta=models.Meta}
-// @Failure 400 {object} models.Response
-// @Failure 404 {object} models.Response
+// @Param fields query string false "Comma separated fields to return (e.g. id,name,current_dwz)"
+// @Param include query string false "Related resources to embed (club, rating_history, memberships)"
+// @Param format query string false "Response format, instead of the Accept header" Enums(json,csv,csv-excel,ndjson,xlsx,xml)
+// @Success 200 {object} models.Response{data=[]models.PlayerWithIncludes,meta=models.Meta}
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
 // @Router /api/v1/clubs/{id}/players [get]
 func (h *PlayerHandler) GetPlayersByClub(c *gin.Context) {
 	clubID := c.Param("id")
@@ -201,7 +315,13 @@ func (h *PlayerHandler) GetPlayersByClub(c *gin.Context) {
 		return
 	}
 
-	players, meta, err := h.playerService.GetPlayersByClub(clubID, req, showActive)
+	includes, err := utils.ParseIncludes(c, playerIncludes...)
+	if err != nil {
+		utils.SendJSONResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	players, meta, err := h.playerService.WithContext(c.Request.Context()).GetPlayersByClub(clubID, req, showActive)
 	if err != nil {
 		if apiErr, ok := err.(errors.APIError); ok {
 			utils.SendJSONResponse(c, apiErr.Code, apiErr)
@@ -212,6 +332,26 @@ func (h *PlayerHandler) GetPlayersByClub(c *gin.Context) {
 		return
 	}
 
+	if len(includes) > 0 {
+		embedded, err := h.embedIncludes(c, players, includes)
+		if err != nil {
+			utils.SendJSONResponse(c, http.StatusInternalServerError,
+				errors.NewInternalServerError("Failed to get related resources"))
+			return
+		}
+
+		response := struct {
+			Data []models.PlayerWithIncludes `json:"data"`
+			Meta interface{}                 `json:"meta"`
+		}{
+			Data: embedded,
+			Meta: meta,
+		}
+
+		utils.HandleResponse(c, response, "club_players.csv")
+		return
+	}
+
 	response := struct {
 		Data []models.PlayerResponse `json:"data"`
 		Meta interface{}             `json:"meta"`
@@ -222,3 +362,9 @@ func (h *PlayerHandler) GetPlayersByClub(c *gin.Context) {
 
 	utils.HandleResponse(c, response, "club_players.csv")
 }
+
+// embedIncludes embeds the requested related resources into players
+func (h *PlayerHandler) embedIncludes(c *gin.Context, players []models.PlayerResponse, includes []string) ([]models.PlayerWithIncludes, error) {
+	ctx := c.Request.Context()
+	return embedPlayerIncludes(h.playerService.WithContext(ctx), h.clubService.WithContext(ctx), players, includes)
+}
@@ -40,7 +40,8 @@ func NewSomatogrammCompatibilityHandler(kaderPlanungService *services.KaderPlanu
 // @Accept json
 // @Produce json
 // @Success 200 {object} services.ExecutionStatus
-// @Failure 500 {object} errors.APIError
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/somatogramm/status [get]
 // @Deprecated
 func (h *SomatogrammCompatibilityHandler) GetSomatogrammStatus(c *gin.Context) {
@@ -73,9 +74,10 @@ func (h *SomatogrammCompatibilityHandler) GetSomatogrammStatus(c *gin.Context) {
 // @Produce json
 // @Param request body SomatogrammCompatibilityRequest false "Execution parameters"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} errors.APIError
-// @Failure 409 {object} errors.APIError "Already running"
-// @Failure 500 {object} errors.APIError
+// @Failure 400 {object} errors.Problem
+// @Failure 409 {object} errors.Problem "Already running"
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/somatogramm/start [post]
 // @Deprecated
 func (h *SomatogrammCompatibilityHandler) StartSomatogrammExecution(c *gin.Context) {
@@ -84,7 +86,7 @@ func (h *SomatogrammCompatibilityHandler) StartSomatogrammExecution(c *gin.Conte
 	var request SomatogrammCompatibilityRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		utils.SendJSONResponse(c, http.StatusBadRequest,
-			errors.NewBadRequestError("Invalid request format"))
+			errors.New(errors.CodeInvalidRequestBody, "Invalid request format"))
 		return
 	}
 
@@ -109,7 +111,7 @@ func (h *SomatogrammCompatibilityHandler) StartSomatogrammExecution(c *gin.Conte
 	if err := h.adapter.ExecuteManually(params); err != nil {
 		if strings.Contains(err.Error(), "already running") {
 			utils.SendJSONResponse(c, http.StatusConflict,
-				errors.NewAPIError(409, "EXECUTION_ALREADY_RUNNING", "Somatogramm-compatible execution already in progress"))
+				errors.New(errors.CodeExecutionAlreadyRunning, "Somatogramm-compatible execution already in progress"))
 			return
 		}
 
@@ -131,7 +133,8 @@ func (h *SomatogrammCompatibilityHandler) StartSomatogrammExecution(c *gin.Conte
 // @Accept json
 // @Produce json
 // @Success 200 {array} services.FileInfo
-// @Failure 500 {object} errors.APIError
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/somatogramm/files [get]
 // @Deprecated
 func (h *SomatogrammCompatibilityHandler) ListSomatogrammFiles(c *gin.Context) {
@@ -161,9 +164,10 @@ func (h *SomatogrammCompatibilityHandler) ListSomatogrammFiles(c *gin.Context) {
 // @Produce application/octet-stream
 // @Param filename path string true "Filename to download"
 // @Success 200 {file} file "CSV/JSON file content"
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/somatogramm/download/{filename} [get]
 // @Deprecated
 func (h *SomatogrammCompatibilityHandler) DownloadSomatogrammFile(c *gin.Context) {
@@ -201,7 +205,7 @@ func (h *SomatogrammCompatibilityHandler) DownloadSomatogrammFile(c *gin.Context
 
 	if targetFile == nil {
 		utils.SendJSONResponse(c, http.StatusNotFound,
-			errors.NewNotFoundError("The requested file does not exist"))
+			errors.New(errors.CodeFileNotFound, "The requested file does not exist"))
 		return
 	}
 
@@ -35,7 +35,8 @@ func NewSomatogrammHandler(service *services.SomatogrammService) *SomatogrammHan
 // @Accept json
 // @Produce json
 // @Success 200 {object} services.ExecutionStatus
-// @Failure 500 {object} errors.APIError
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/somatogramm/status [get]
 func (h *SomatogrammHandler) GetSomatogrammStatus(c *gin.Context) {
 	status := h.service.GetStatus()
@@ -64,15 +65,16 @@ func (h *SomatogrammHandler) GetSomatogrammStatus(c *gin.Context) {
 // @Produce json
 // @Param request body SomatogrammRequest false "Execution parameters"
 // @Success 200 {object} map[string]string
-// @Failure 400 {object} errors.APIError
-// @Failure 409 {object} errors.APIError "Already running"
-// @Failure 500 {object} errors.APIError
+// @Failure 400 {object} errors.Problem
+// @Failure 409 {object} errors.Problem "Already running"
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/somatogramm/start [post]
 func (h *SomatogrammHandler) StartSomatogrammExecution(c *gin.Context) {
 	var request SomatogrammRequest
 	if err := c.ShouldBindJSON(&request); err != nil {
 		utils.SendJSONResponse(c, http.StatusBadRequest,
-			errors.NewBadRequestError("Invalid request format"))
+			errors.New(errors.CodeInvalidRequestBody, "Invalid request format"))
 		return
 	}
 
@@ -97,7 +99,7 @@ func (h *SomatogrammHandler) StartSomatogrammExecution(c *gin.Context) {
 	if err := h.service.ExecuteManually(params); err != nil {
 		if strings.Contains(err.Error(), "already running") {
 			utils.SendJSONResponse(c, http.StatusConflict,
-				errors.NewAPIError(409, "EXECUTION_ALREADY_RUNNING", "Execution already in progress"))
+				errors.New(errors.CodeExecutionAlreadyRunning, "Execution already in progress"))
 			return
 		}
 
@@ -118,7 +120,8 @@ func (h *SomatogrammHandler) StartSomatogrammExecution(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Success 200 {array} services.FileInfo
-// @Failure 500 {object} errors.APIError
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/somatogramm/files [get]
 func (h *SomatogrammHandler) ListSomatogrammFiles(c *gin.Context) {
 	files, err := h.service.ListAvailableFiles()
@@ -139,9 +142,10 @@ func (h *SomatogrammHandler) ListSomatogrammFiles(c *gin.Context) {
 // @Produce application/octet-stream
 // @Param filename path string true "Filename to download"
 // @Success 200 {file} file "CSV or JSON file content"
-// @Failure 400 {object} ErrorResponse
-// @Failure 404 {object} ErrorResponse
-// @Failure 500 {object} ErrorResponse
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Failure 500 {object} errors.Problem
+// @Security ApiKeyAuth
 // @Router /api/v1/somatogramm/download/{filename} [get]
 func (h *SomatogrammHandler) DownloadSomatogrammFile(c *gin.Context) {
 	filename := c.Param("filename")
@@ -176,7 +180,7 @@ func (h *SomatogrammHandler) DownloadSomatogrammFile(c *gin.Context) {
 
 	if targetFile == nil {
 		utils.SendJSONResponse(c, http.StatusNotFound,
-			errors.NewNotFoundError("The requested file does not exist"))
+			errors.New(errors.CodeFileNotFound, "The requested file does not exist"))
 		return
 	}
 
@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"portal64api/internal/models"
@@ -28,12 +31,14 @@ func NewTournamentHandler(tournamentService *services.TournamentService) *Tourna
 // @Description Get a tournament by its ID/code (format: C529-K00-HT1) with comprehensive details
 // @Tags tournaments
 // @Accept json
-// @Produce json,text/csv
+// @Produce json,text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/xml
 // @Param id path string true "Tournament ID (format: C529-K00-HT1)"
-// @Param format query string false "Response format (json or csv)" Enums(json,csv)
-// @Success 200 {object} models.EnhancedTournamentResponse
-// @Failure 400 {object} models.Response
-// @Failure 404 {object} models.Response
+// @Param fields query string false "Comma separated fields to return (e.g. id,name,start_date)"
+// @Param include query string false "Optional sections (performance) and related resources to embed (team_matches)"
+// @Param format query string false "Response format, instead of the Accept header" Enums(json,csv,csv-excel,ndjson,xlsx,xml)
+// @Success 200 {object} models.TournamentWithIncludes
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
 // @Router /api/v1/tournaments/{id} [get]
 func (h *TournamentHandler) GetTournament(c *gin.Context) {
 	tournamentID := c.Param("id")
@@ -44,7 +49,19 @@ func (h *TournamentHandler) GetTournament(c *gin.Context) {
 		return
 	}
 
-	tournament, err := h.tournamentService.GetTournamentByID(tournamentID)
+	// Reject unknown includes, the supported ones are checked with HasInclude below
+	if _, err := utils.ParseIncludes(c, tournamentIncludes...); err != nil {
+		utils.SendJSONResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	var tournament *models.EnhancedTournamentResponse
+	var err error
+	if utils.HasInclude(c, models.IncludePerformance) {
+		tournament, err = h.tournamentService.WithContext(c.Request.Context()).GetTournamentWithPerformance(tournamentID)
+	} else {
+		tournament, err = h.tournamentService.WithContext(c.Request.Context()).GetTournamentByID(tournamentID)
+	}
 	if err != nil {
 		if apiErr, ok := err.(errors.APIError); ok {
 			utils.SendJSONResponse(c, apiErr.Code, apiErr)
@@ -55,7 +72,54 @@ func (h *TournamentHandler) GetTournament(c *gin.Context) {
 		return
 	}
 
-	utils.HandleResponse(c, tournament, "tournament.csv")
+	if !utils.HasInclude(c, models.IncludeTeamMatches) {
+		utils.HandleResponse(c, tournament, "tournament.csv")
+		return
+	}
+
+	// Team matches are built from the loaded pairings, they are nil for individual tournaments
+	response := models.TournamentWithIncludes{
+		EnhancedTournamentResponse: *tournament,
+		Included:                   &models.TournamentIncludes{TeamMatches: services.BuildTeamMatches(tournament)},
+	}
+
+	utils.HandleResponse(c, response, "tournament.csv")
+}
+
+// GetTournamentTeamMatches godoc
+// @Summary Get team matches of a league tournament
+// @Description Get team fixtures per round with board pairings, match and board points and the league table. Team competitions are recognised by their league settings (boards per team); other tournaments return 404.
+// @Tags tournaments
+// @Accept json
+// @Produce json,text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/xml
+// @Param id path string true "Tournament ID (format: C529-K00-HT1)"
+// @Param fields query string false "Comma separated fields to return"
+// @Param format query string false "Response format, instead of the Accept header" Enums(json,csv,csv-excel,ndjson,xlsx,xml)
+// @Success 200 {object} models.TeamMatchesResponse
+// @Failure 400 {object} errors.Problem
+// @Failure 404 {object} errors.Problem
+// @Router /api/v1/tournaments/{id}/team-matches [get]
+func (h *TournamentHandler) GetTournamentTeamMatches(c *gin.Context) {
+	tournamentID := c.Param("id")
+
+	// Validate tournament ID format
+	if err := utils.ValidateTournamentID(tournamentID); err != nil {
+		utils.SendJSONResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	teamMatches, err := h.tournamentService.WithContext(c.Request.Context()).GetTournamentTeamMatches(tournamentID)
+	if err != nil {
+		if apiErr, ok := err.(errors.APIError); ok {
+			utils.SendJSONResponse(c, apiErr.Code, apiErr)
+			return
+		}
+		utils.SendJSONResponse(c, http.StatusInternalServerError,
+			errors.NewInternalServerError("Failed to get team matches"))
+		return
+	}
+
+	utils.HandleResponse(c, teamMatches, "team_matches.csv")
 }
 
 // SearchTournaments godoc
@@ -63,7 +127,7 @@ func (h *TournamentHandler) GetTournament(c *gin.Context) {
 // @Description Search tournaments by name, code, or other criteria
 // @Tags tournaments
 // @Accept json
-// @Produce json,text/csv
+// @Produce json,text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/xml
 // @Param query query string false "Search query"
 // @Param limit query int false "Limit (max 500)" default(20)
 // @Param offset query int false "Offset" default(0)
@@ -71,9 +135,18 @@ func (h *TournamentHandler) GetTournament(c *gin.Context) {
 // @Param sort_order query string false "Sort order (asc/desc)" default(desc)
 // @Param filter_by query string false "Filter by field (year)"
 // @Param filter_value query string false "Filter value"
-// @Param format query string false "Response format (json or csv)" Enums(json,csv)
+// @Param organisation_id query int false "Organising club (organisation ID)"
+// @Param region query string false "VKZ prefix of the organising club (e.g. C03)"
+// @Param type query string false "Tournament type acronym (e.g. HT1)"
+// @Param rounds query int false "Number of rounds"
+// @Param computed query bool false "Only computed (true) or uncomputed (false) tournaments"
+// @Param assessor_id query int false "Assessor (person ID)"
+// @Param finished_from query string false "Finished on or after (YYYY-MM-DD)"
+// @Param finished_to query string false "Finished on or before (YYYY-MM-DD)"
+// @Param fields query string false "Comma separated fields to return"
+// @Param format query string false "Response format, instead of the Accept header" Enums(json,csv,csv-excel,ndjson,xlsx,xml)
 // @Success 200 {object} models.Response{data=[]models.TournamentResponse,meta=models.Meta}
-// @Failure 400 {object} models.Response
+// @Failure 400 {object} errors.Problem
 // @Router /api/v1/tournaments [get]
 func (h *TournamentHandler) SearchTournaments(c *gin.Context) {
 	req, err := utils.ParseSearchParamsWithDefaults(c, "finishedOn", "desc")
@@ -86,7 +159,17 @@ func (h *TournamentHandler) SearchTournaments(c *gin.Context) {
 		return
 	}
 
-	tournaments, meta, err := h.tournamentService.SearchTournaments(req)
+	filter, err := utils.ParseTournamentSearchFilter(c)
+	if err != nil {
+		if apiErr, ok := err.(errors.APIError); ok {
+			utils.SendJSONResponse(c, apiErr.Code, apiErr)
+			return
+		}
+		utils.SendJSONResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	tournaments, meta, err := h.tournamentService.WithContext(c.Request.Context()).SearchTournaments(req, filter)
 	if err != nil {
 		if apiErr, ok := err.(errors.APIError); ok {
 			utils.SendJSONResponse(c, apiErr.Code, apiErr)
@@ -110,15 +193,18 @@ func (h *TournamentHandler) SearchTournaments(c *gin.Context) {
 
 // GetRecentTournaments godoc
 // @Summary Get recent tournaments
-// @Description Get recently finished tournaments
+// @Description Get recently finished tournaments, optionally as iCalendar feed
 // @Tags tournaments
 // @Accept json
-// @Produce json,text/csv
+// @Produce json,text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/xml,text/calendar
 // @Param days query int false "Number of days to look back" default(30)
 // @Param limit query int false "Maximum number of tournaments to return" default(20)
-// @Param format query string false "Response format (json or csv)" Enums(json,csv)
+// @Param region query string false "VKZ prefix of the organising club (e.g. C03)"
+// @Param type query string false "Tournament type acronym (e.g. HT1)"
+// @Param fields query string false "Comma separated fields to return (json and csv)"
+// @Param format query string false "Response format, instead of the Accept header" Enums(json,csv,csv-excel,ndjson,xlsx,xml,ics)
 // @Success 200 {object} models.Response{data=[]models.TournamentResponse}
-// @Failure 500 {object} models.Response
+// @Failure 500 {object} errors.Problem
 // @Router /api/v1/tournaments/recent [get]
 func (h *TournamentHandler) GetRecentTournaments(c *gin.Context) {
 	daysStr := c.DefaultQuery("days", "30")
@@ -134,7 +220,17 @@ func (h *TournamentHandler) GetRecentTournaments(c *gin.Context) {
 		limit = 20
 	}
 
-	tournaments, err := h.tournamentService.GetRecentTournaments(days, limit)
+	filter, err := utils.ParseTournamentSearchFilter(c)
+	if err != nil {
+		if apiErr, ok := err.(errors.APIError); ok {
+			utils.SendJSONResponse(c, apiErr.Code, apiErr)
+			return
+		}
+		utils.SendJSONResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	tournaments, err := h.tournamentService.WithContext(c.Request.Context()).GetRecentTournaments(days, limit, filter)
 	if err != nil {
 		if apiErr, ok := err.(errors.APIError); ok {
 			utils.SendJSONResponse(c, apiErr.Code, apiErr)
@@ -145,15 +241,21 @@ func (h *TournamentHandler) GetRecentTournaments(c *gin.Context) {
 		return
 	}
 
+	if utils.WantsICalendar(c) {
+		utils.SendICalendarResponse(c, "recent_tournaments.ics", "Portal64 Turniere",
+			tournamentsToICalEvents(c, tournaments))
+		return
+	}
+
 	utils.HandleResponse(c, tournaments, "recent_tournaments.csv")
 }
 
 // GetTournamentsByDateRange godoc
 // @Summary Get tournaments by date range
-// @Description Get tournaments within a specific date range
+// @Description Get tournaments within a specific date range, optionally as iCalendar feed
 // @Tags tournaments
 // @Accept json
-// @Produce json,text/csv
+// @Produce json,text/csv,application/x-ndjson,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,application/xml,text/calendar
 // @Param start_date query string true "Start date (YYYY-MM-DD)"
 // @Param end_date query string true "End date (YYYY-MM-DD)"
 // @Param query query string false "Search query"
@@ -161,9 +263,12 @@ func (h *TournamentHandler) GetRecentTournaments(c *gin.Context) {
 // @Param offset query int false "Offset" default(0)
 // @Param sort_by query string false "Sort by field" default(finishedOn)
 // @Param sort_order query string false "Sort order (asc/desc)" default(desc)
-// @Param format query string false "Response format (json or csv)" Enums(json,csv)
+// @Param region query string false "VKZ prefix of the organising club (e.g. C03)"
+// @Param type query string false "Tournament type acronym (e.g. HT1)"
+// @Param fields query string false "Comma separated fields to return (json and csv)"
+// @Param format query string false "Response format, instead of the Accept header" Enums(json,csv,csv-excel,ndjson,xlsx,xml,ics)
 // @Success 200 {object} models.Response{data=[]models.TournamentResponse,meta=models.Meta}
-// @Failure 400 {object} models.Response
+// @Failure 400 {object} errors.Problem
 // @Router /api/v1/tournaments/date-range [get]
 func (h *TournamentHandler) GetTournamentsByDateRange(c *gin.Context) {
 	startDateStr := c.Query("start_date")
@@ -199,7 +304,17 @@ func (h *TournamentHandler) GetTournamentsByDateRange(c *gin.Context) {
 		return
 	}
 
-	tournaments, meta, err := h.tournamentService.GetTournamentsByDateRange(startDate, endDate, req)
+	filter, err := utils.ParseTournamentSearchFilter(c)
+	if err != nil {
+		if apiErr, ok := err.(errors.APIError); ok {
+			utils.SendJSONResponse(c, apiErr.Code, apiErr)
+			return
+		}
+		utils.SendJSONResponse(c, http.StatusBadRequest, err)
+		return
+	}
+
+	tournaments, meta, err := h.tournamentService.WithContext(c.Request.Context()).GetTournamentsByDateRange(startDate, endDate, req, filter)
 	if err != nil {
 		if apiErr, ok := err.(errors.APIError); ok {
 			utils.SendJSONResponse(c, apiErr.Code, apiErr)
@@ -210,6 +325,12 @@ func (h *TournamentHandler) GetTournamentsByDateRange(c *gin.Context) {
 		return
 	}
 
+	if utils.WantsICalendar(c) {
+		utils.SendICalendarResponse(c, "tournaments.ics", "Portal64 Turniere",
+			tournamentsToICalEvents(c, tournaments))
+		return
+	}
+
 	response := struct {
 		Data []models.TournamentResponse `json:"data"`
 		Meta interface{}                 `json:"meta"`
@@ -220,3 +341,59 @@ func (h *TournamentHandler) GetTournamentsByDateRange(c *gin.Context) {
 
 	utils.HandleResponse(c, response, "tournaments_by_date.csv")
 }
+
+// tournamentsToICalEvents converts tournaments into all-day calendar events
+// Tournaments without any known date are skipped
+func tournamentsToICalEvents(c *gin.Context, tournaments []models.TournamentResponse) []utils.ICalEvent {
+	baseURL := requestBaseURL(c)
+	events := make([]utils.ICalEvent, 0, len(tournaments))
+
+	for _, tournament := range tournaments {
+		if tournament.StartDate == nil {
+			continue
+		}
+		end := *tournament.StartDate
+		if tournament.EndDate != nil && tournament.EndDate.After(end) {
+			end = *tournament.EndDate
+		}
+
+		detailURL := fmt.Sprintf("%s/api/v1/tournaments/%s", baseURL, url.PathEscape(tournament.Code))
+		description := fmt.Sprintf("Turnier: %s\nCode: %s\nRunden: %d", tournament.Name, tournament.Code, tournament.Rounds)
+		if tournament.Organization != "" {
+			description += fmt.Sprintf("\nAusrichter: %s", tournament.Organization)
+		}
+		description += "\n" + detailURL
+
+		events = append(events, utils.ICalEvent{
+			UID:         fmt.Sprintf("%s@portal64", tournament.Code),
+			Summary:     tournament.Name,
+			Description: description,
+			Location:    tournament.Organization,
+			URL:         detailURL,
+			Start:       *tournament.StartDate,
+			End:         end,
+		})
+	}
+
+	return events
+}
+
+// requestBaseURL reconstructs the externally visible base URL of the request
+// X-Forwarded-Proto and X-Forwarded-Host are only honoured for requests of trusted proxies.
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	host := c.Request.Host
+
+	if utils.FromTrustedProxy(c) {
+		if proto := strings.ToLower(c.GetHeader("X-Forwarded-Proto")); proto == "http" || proto == "https" {
+			scheme = proto
+		}
+		if forwardedHost := c.GetHeader("X-Forwarded-Host"); forwardedHost != "" {
+			host = forwardedHost
+		}
+	}
+	return fmt.Sprintf("%s://%s", scheme, host)
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"portal64api/internal/services"
+	"portal64api/internal/webhooks"
+	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookHandler handles webhook subscription requests
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(webhookService *services.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService}
+}
+
+// ListSubscriptions lists the webhook subscriptions
+// @Summary List webhook subscriptions
+// @Description List all webhook subscriptions. Secrets are only returned on creation.
+// @Tags webhooks
+// @Produce json
+// @Success 200 {object} models.Response{data=[]webhooks.Subscription}
+// @Security ApiKeyAuth
+// @Router /api/v1/webhooks [get]
+func (h *WebhookHandler) ListSubscriptions(c *gin.Context) {
+	utils.SendJSONResponse(c, http.StatusOK, h.webhookService.ListSubscriptions())
+}
+
+// CreateSubscription creates a webhook subscription
+// @Summary Create webhook subscription
+// @Description Subscribe a URL to events: import.completed, import.failed, analysis.completed, player.rating_changed.
+// @Description Filters by club_ids or player_ids restrict player.rating_changed events. Deliveries are signed with
+// @Description the secret, which is generated if omitted and only returned in this response.
+// @Tags webhooks
+// @Accept json
+// @Produce json
+// @Param subscription body webhooks.Subscription true "URL, events, filters and optional secret"
+// @Success 201 {object} models.Response{data=webhooks.Subscription}
+// @Failure 400 {object} errors.Problem
+// @Security ApiKeyAuth
+// @Router /api/v1/webhooks [post]
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	var request webhooks.Subscription
+	if err := c.ShouldBindJSON(&request); err != nil {
+		utils.SendJSONResponse(c, http.StatusBadRequest,
+			errors.New(errors.CodeInvalidRequestBody, "Invalid request body: "+err.Error()))
+		return
+	}
+
+	subscription, err := h.webhookService.CreateSubscription(request)
+	if err != nil {
+		sendWebhookError(c, err)
+		return
+	}
+
+	utils.SendJSONResponse(c, http.StatusCreated, subscription)
+}
+
+// GetSubscription gets a webhook subscription
+// @Summary Get webhook subscription
+// @Description Get a webhook subscription by ID
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 200 {object} models.Response{data=webhooks.Subscription}
+// @Failure 404 {object} errors.Problem
+// @Security ApiKeyAuth
+// @Router /api/v1/webhooks/{id} [get]
+func (h *WebhookHandler) GetSubscription(c *gin.Context) {
+	subscription, err := h.webhookService.GetSubscription(c.Param("id"))
+	if err != nil {
+		sendWebhookError(c, err)
+		return
+	}
+
+	utils.SendJSONResponse(c, http.StatusOK, subscription)
+}
+
+// DeleteSubscription deletes a webhook subscription
+// @Summary Delete webhook subscription
+// @Description Delete a webhook subscription, scheduled retries of its deliveries are still sent
+// @Tags webhooks
+// @Param id path string true "Subscription ID"
+// @Success 204
+// @Failure 404 {object} errors.Problem
+// @Security ApiKeyAuth
+// @Router /api/v1/webhooks/{id} [delete]
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	if err := h.webhookService.DeleteSubscription(c.Param("id")); err != nil {
+		sendWebhookError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetDeliveries gets the delivery log of a webhook subscription
+// @Summary Get webhook deliveries
+// @Description Get the recent deliveries of a subscription with status, attempts and the last response, newest first.
+// @Description The delivery log is kept in memory.
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Param limit query int false "Maximum number of deliveries" default(100)
+// @Success 200 {object} models.Response{data=[]webhooks.Delivery}
+// @Failure 404 {object} errors.Problem
+// @Security ApiKeyAuth
+// @Router /api/v1/webhooks/{id}/deliveries [get]
+func (h *WebhookHandler) GetDeliveries(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 {
+		utils.SendJSONResponse(c, http.StatusBadRequest, errors.NewBadRequestError("Invalid limit parameter"))
+		return
+	}
+
+	deliveries, err := h.webhookService.GetDeliveries(c.Param("id"), limit)
+	if err != nil {
+		sendWebhookError(c, err)
+		return
+	}
+
+	utils.SendJSONResponse(c, http.StatusOK, deliveries)
+}
+
+// PingSubscription sends a ping event to a webhook subscription
+// @Summary Ping webhook subscription
+// @Description Send a signed ping event to test a subscription; the delivery appears in its delivery log
+// @Tags webhooks
+// @Produce json
+// @Param id path string true "Subscription ID"
+// @Success 202 {object} models.Response{data=webhooks.Delivery}
+// @Failure 404 {object} errors.Problem
+// @Security ApiKeyAuth
+// @Router /api/v1/webhooks/{id}/ping [post]
+func (h *WebhookHandler) PingSubscription(c *gin.Context) {
+	delivery, err := h.webhookService.Ping(c.Param("id"))
+	if err != nil {
+		sendWebhookError(c, err)
+		return
+	}
+
+	utils.SendJSONResponse(c, http.StatusAccepted, delivery)
+}
+
+// sendWebhookError sends an error of the webhook service
+func sendWebhookError(c *gin.Context, err error) {
+	if apiErr, ok := err.(errors.APIError); ok {
+		utils.SendJSONResponse(c, apiErr.Code, apiErr)
+		return
+	}
+	utils.SendJSONResponse(c, http.StatusInternalServerError, errors.NewInternalServerError(err.Error()))
+}
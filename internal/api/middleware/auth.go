@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"portal64api/internal/auth"
+	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyContextKey is the gin context key of the authenticated *auth.APIKey
+const APIKeyContextKey = "api_key"
+
+// RequireScope returns a middleware that requires an API key granting the given scope
+// Keys are accepted as "Authorization: Bearer <key>" or "X-API-Key: <key>".
+// A nil key store disables authentication.
+func RequireScope(store *auth.KeyStore, scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if store == nil {
+			c.Next()
+			return
+		}
+
+		key := APIKeyFromRequest(c)
+		if key == "" {
+			c.Header("WWW-Authenticate", `Bearer realm="portal64api"`)
+			utils.SendJSONResponse(c, http.StatusUnauthorized,
+				errors.New(errors.CodeAPIKeyRequired, "API key required"))
+			c.Abort()
+			return
+		}
+
+		apiKey, err := store.Authenticate(key)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer realm="portal64api", error="invalid_token"`)
+			utils.SendJSONResponse(c, http.StatusUnauthorized,
+				errors.New(errors.CodeInvalidAPIKey, "Invalid API key", err.Error()))
+			c.Abort()
+			return
+		}
+
+		if !apiKey.HasScope(scope) {
+			utils.SendJSONResponse(c, http.StatusForbidden,
+				errors.New(errors.CodeInsufficientScope, "API key lacks required scope "+scope))
+			c.Abort()
+			return
+		}
+
+		c.Set(APIKeyContextKey, apiKey)
+		c.Next()
+	}
+}
+
+// APIKeyFromRequest extracts the API key from the request headers
+func APIKeyFromRequest(c *gin.Context) string {
+	if key := strings.TrimSpace(c.GetHeader("X-API-Key")); key != "" {
+		return key
+	}
+	authorization := c.GetHeader("Authorization")
+	if len(authorization) > 7 && strings.EqualFold(authorization[:7], "Bearer ") {
+		return strings.TrimSpace(authorization[7:])
+	}
+	return ""
+}
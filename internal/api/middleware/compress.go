@@ -0,0 +1,242 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"portal64api/internal/config"
+	"portal64api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Compression returns a middleware that gzip-compresses responses for clients accepting gzip
+// Only responses with a configured content type and a body of at least MinSize bytes are
+// compressed; streamed responses are compressed as soon as they are flushed.
+func Compression(cfg config.CompressionConfig) gin.HandlerFunc {
+	level := cfg.Level
+	if level < gzip.BestSpeed || level > gzip.BestCompression {
+		level = gzip.DefaultCompression
+	}
+	pool := &sync.Pool{
+		New: func() interface{} {
+			gz, _ := gzip.NewWriterLevel(io.Discard, level)
+			return gz
+		},
+	}
+
+	return func(c *gin.Context) {
+		if !cfg.Enabled {
+			c.Next()
+			return
+		}
+
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		if !utils.AcceptsGzip(c.GetHeader("Accept-Encoding")) || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+
+		writer := &compressWriter{
+			ResponseWriter: c.Writer,
+			pool:           pool,
+			minSize:        cfg.MinSize,
+			contentTypes:   cfg.ContentTypes,
+			status:         http.StatusOK,
+		}
+		c.Writer = writer
+		defer func() {
+			c.Writer = writer.ResponseWriter
+			if err := recover(); err != nil {
+				// Leave the response to the error handling middleware
+				writer.release()
+				panic(err)
+			}
+			writer.finish()
+		}()
+
+		c.Next()
+	}
+}
+
+// compressWriter buffers the start of a response until it is clear whether to compress it
+type compressWriter struct {
+	gin.ResponseWriter
+	pool         *sync.Pool
+	minSize      int
+	contentTypes []string
+
+	status   int
+	buf      []byte
+	decided  bool
+	compress bool
+	gz       *gzip.Writer
+}
+
+// WriteHeader records the status code until the compression is decided
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code > 0 {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow is deferred until the compression is decided
+func (w *compressWriter) WriteHeaderNow() {
+	if w.decided {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// Write buffers the body until MinSize is reached, then compresses it
+func (w *compressWriter) Write(data []byte) (int, error) {
+	if !w.decided {
+		if !w.compressible() {
+			w.decide(false)
+		} else {
+			w.buf = append(w.buf, data...)
+			if len(w.buf) >= w.minSize {
+				if err := w.decide(true); err != nil {
+					return 0, err
+				}
+			}
+			return len(data), nil
+		}
+	}
+
+	if w.compress {
+		return w.gz.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+// WriteString buffers or compresses the body
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Status returns the response status code
+func (w *compressWriter) Status() int {
+	if w.decided {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+// Size returns the number of bytes written to the client
+func (w *compressWriter) Size() int {
+	if w.decided {
+		return w.ResponseWriter.Size()
+	}
+	if len(w.buf) == 0 {
+		return -1
+	}
+	return len(w.buf)
+}
+
+// Written reports whether the response has been started
+func (w *compressWriter) Written() bool {
+	return w.decided || len(w.buf) > 0
+}
+
+// Flush decides on the compression and flushes compressed data to the client
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(w.compressible())
+	}
+	if w.compress {
+		w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap returns the underlying writer, used by http.ResponseController
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// compressible reports whether the status and content type allow compression
+func (w *compressWriter) compressible() bool {
+	switch {
+	case w.status < http.StatusOK, w.status == http.StatusNoContent,
+		w.status == http.StatusPartialContent, w.status == http.StatusNotModified:
+		return false
+	}
+
+	header := w.Header()
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	if contentType == "" || strings.HasPrefix(contentType, "text/event-stream") {
+		// Events are sent one by one as they happen, there is little to gain from compressing them
+		return false
+	}
+	for _, prefix := range w.contentTypes {
+		if prefix = strings.ToLower(strings.TrimSpace(prefix)); prefix != "" && strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// decide writes the headers and the buffered body, compressed or not
+func (w *compressWriter) decide(compress bool) error {
+	w.decided = true
+	w.compress = compress
+
+	if compress {
+		header := w.Header()
+		header.Del("Content-Length")
+		header.Set("Content-Encoding", "gzip")
+		// The compressed representation differs byte by byte, so its validator is weak
+		if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+			header.Set("ETag", "W/"+etag)
+		}
+
+		w.gz = w.pool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+	}
+
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+
+	buf := w.buf
+	w.buf = nil
+	if len(buf) == 0 {
+		return nil
+	}
+	if compress {
+		_, err := w.gz.Write(buf)
+		return err
+	}
+	_, err := w.ResponseWriter.Write(buf)
+	return err
+}
+
+// finish writes small responses uncompressed and completes the gzip stream
+func (w *compressWriter) finish() {
+	if !w.decided {
+		w.decide(false)
+	}
+	if w.compress {
+		w.gz.Close()
+	}
+	w.release()
+}
+
+// release returns the gzip writer to the pool
+func (w *compressWriter) release() {
+	if w.gz != nil {
+		w.gz.Reset(io.Discard)
+		w.pool.Put(w.gz)
+		w.gz = nil
+	}
+}
@@ -0,0 +1,236 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LastModifiedFunc returns the time the served data last changed
+// The second result is false if the time is unknown.
+type LastModifiedFunc func() (time.Time, bool)
+
+// ConditionalGet returns a middleware that adds ETag and Last-Modified headers to successful
+// GET and HEAD responses and answers 304 Not Modified to matching If-None-Match or
+// If-Modified-Since requests. The ETag is a hash of the response body; Last-Modified comes from
+// lastModified, which may be nil. Both conditions are only evaluated once the handler has
+// produced a 200 response, so unknown resources and errors keep their status. Responses that are
+// flushed while being written (streamed) only carry Last-Modified; If-Modified-Since is evaluated
+// at the first flush and the rest of a not modified stream is discarded.
+func ConditionalGet(lastModified LastModifiedFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method != http.MethodGet && c.Request.Method != http.MethodHead {
+			c.Next()
+			return
+		}
+
+		var modified time.Time
+		if lastModified != nil {
+			if t, ok := lastModified(); ok {
+				modified = t.UTC().Truncate(time.Second)
+			}
+		}
+
+		header := c.Writer.Header()
+		setValidators := func() {
+			if !modified.IsZero() {
+				header.Set("Last-Modified", modified.Format(http.TimeFormat))
+			}
+			if header.Get("Cache-Control") == "" {
+				// Caches may store responses but have to revalidate them, data changes with each import
+				header.Set("Cache-Control", "no-cache")
+			}
+		}
+
+		// Streams have no ETag, only If-Modified-Since can be answered for them
+		streamNotModified := func() bool {
+			setValidators()
+			return c.GetHeader("If-None-Match") == "" && notModified(c.Request, "", modified)
+		}
+
+		writer := &bufferedWriter{ResponseWriter: c.Writer, status: http.StatusOK, onStream: streamNotModified}
+		c.Writer = writer
+		defer func() { c.Writer = writer.ResponseWriter }()
+		c.Next()
+
+		if writer.passthrough {
+			return
+		}
+		if writer.status != http.StatusOK {
+			writer.flushBuffer()
+			return
+		}
+
+		if header.Get("ETag") == "" {
+			header.Set("ETag", bodyETag(writer.body.Bytes()))
+		}
+		setValidators()
+
+		if notModified(c.Request, header.Get("ETag"), modified) {
+			writer.writeNotModified()
+			return
+		}
+
+		writer.flushBuffer()
+	}
+}
+
+// notModified evaluates If-None-Match and, only if it is absent, If-Modified-Since (RFC 9110)
+func notModified(r *http.Request, etag string, modified time.Time) bool {
+	if ifNoneMatch := r.Header.Get("If-None-Match"); ifNoneMatch != "" {
+		return etagMatches(ifNoneMatch, etag)
+	}
+
+	ifModifiedSince := r.Header.Get("If-Modified-Since")
+	if ifModifiedSince == "" || modified.IsZero() {
+		return false
+	}
+	since, err := http.ParseTime(ifModifiedSince)
+	if err != nil {
+		return false
+	}
+	return !modified.After(since)
+}
+
+// etagMatches reports whether an If-None-Match list matches the ETag using weak comparison
+func etagMatches(ifNoneMatch, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bodyETag returns a strong ETag derived from the response body
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`
+}
+
+// bufferedWriter holds back the response so headers can be added after the handler ran
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body        bytes.Buffer
+	status      int
+	passthrough bool
+	discard     bool        // Body writes are dropped after 304 Not Modified
+	onStream    func() bool // Called before a flushed 200 response is passed through, true if not modified
+}
+
+// WriteHeader records the status code until the response is written
+func (w *bufferedWriter) WriteHeader(code int) {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	if code > 0 {
+		w.status = code
+	}
+}
+
+// WriteHeaderNow is a no-op while buffering, the status is written with the body
+func (w *bufferedWriter) WriteHeaderNow() {
+	if w.passthrough {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
+
+// Write buffers the body
+func (w *bufferedWriter) Write(data []byte) (int, error) {
+	if w.discard {
+		return len(data), nil
+	}
+	if w.passthrough {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.body.Write(data)
+}
+
+// WriteString buffers the body
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	if w.discard {
+		return len(s), nil
+	}
+	if w.passthrough {
+		return w.ResponseWriter.WriteString(s)
+	}
+	return w.body.WriteString(s)
+}
+
+// Status returns the response status code
+func (w *bufferedWriter) Status() int {
+	if w.passthrough {
+		return w.ResponseWriter.Status()
+	}
+	return w.status
+}
+
+// Size returns the number of body bytes written so far
+func (w *bufferedWriter) Size() int {
+	if w.passthrough {
+		return w.ResponseWriter.Size()
+	}
+	if w.body.Len() == 0 {
+		return -1
+	}
+	return w.body.Len()
+}
+
+// Written reports whether the response has been started
+func (w *bufferedWriter) Written() bool {
+	if w.passthrough {
+		return w.ResponseWriter.Written()
+	}
+	return w.body.Len() > 0
+}
+
+// Flush switches to passthrough, streamed responses are not buffered
+func (w *bufferedWriter) Flush() {
+	if w.discard {
+		return
+	}
+	if !w.passthrough {
+		if w.status == http.StatusOK && w.onStream != nil && w.onStream() {
+			w.writeNotModified()
+			return
+		}
+		w.flushBuffer()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// Unwrap returns the underlying writer, used by http.ResponseController
+func (w *bufferedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// writeNotModified answers 304 without body, later writes of the handler are discarded
+func (w *bufferedWriter) writeNotModified() {
+	w.passthrough = true
+	w.discard = true
+	w.body.Reset()
+	header := w.ResponseWriter.Header()
+	header.Del("Content-Type")
+	header.Del("Content-Length")
+	w.ResponseWriter.WriteHeader(http.StatusNotModified)
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// flushBuffer writes the recorded status and buffered body and stops buffering
+func (w *bufferedWriter) flushBuffer() {
+	w.passthrough = true
+	w.ResponseWriter.WriteHeader(w.status)
+	w.ResponseWriter.WriteHeaderNow()
+	if w.body.Len() > 0 {
+		w.ResponseWriter.Write(w.body.Bytes())
+		w.body.Reset()
+	}
+}
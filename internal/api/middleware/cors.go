@@ -28,14 +28,29 @@ func CORSMiddleware() gin.HandlerFunc {
 			"Accept-Encoding",
 			"X-CSRF-Token",
 			"Authorization",
+			"X-API-Key",
 			"Accept",
 			"Cache-Control",
 			"X-Requested-With",
+			"If-None-Match",
+			"If-Modified-Since",
+			"X-Request-ID",
+			"traceparent",
+			"tracestate",
 		},
 		ExposeHeaders: []string{
 			"Content-Length",
 			"Content-Type",
 			"Content-Disposition",
+			"WWW-Authenticate",
+			"ETag",
+			"Last-Modified",
+			"RateLimit-Limit",
+			"RateLimit-Remaining",
+			"RateLimit-Reset",
+			"RateLimit-Policy",
+			"Retry-After",
+			"X-Request-ID",
 		},
 		AllowCredentials: false,
 		MaxAge:          12 * time.Hour,
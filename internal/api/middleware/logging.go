@@ -1,61 +1,76 @@
 package middleware
 
 import (
-	"log"
+	"fmt"
+	"net/http"
 	"time"
 
+	"portal64api/internal/logging"
+	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
+
 	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
 )
 
-// LoggingMiddleware returns a logging middleware
+// LoggingMiddleware returns a middleware writing one structured access log entry per request
+// Entries carry the request ID, route template, status, latency and the cache and database
+// work recorded for the request.
 func LoggingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		start := time.Now()
-		path := c.Request.URL.Path
-		raw := c.Request.URL.RawQuery
 
 		// Process request
 		c.Next()
 
-		// Calculate request processing time
-		latency := time.Since(start)
-
-		// Get client IP
-		clientIP := c.ClientIP()
-
-		// Get status code
-		statusCode := c.Writer.Status()
-
-		// Get method
-		method := c.Request.Method
-
-		// Build full path
-		if raw != "" {
-			path = path + "?" + raw
+		fields := logrus.Fields{
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+			"route":      c.FullPath(),
+			"status":     c.Writer.Status(),
+			"latency_ms": durationMillis(time.Since(start)),
+			"bytes":      c.Writer.Size(),
+			"client_ip":  c.ClientIP(),
+			"user_agent": c.Request.UserAgent(),
+		}
+		if raw := c.Request.URL.RawQuery; raw != "" {
+			fields["query"] = raw
+		}
+		if info := logging.RequestInfoFromContext(c.Request.Context()); info != nil {
+			stats := info.Stats()
+			fields["cache_hits"] = stats.CacheHits
+			fields["cache_misses"] = stats.CacheMisses
+			fields["db_queries"] = stats.DBQueries
+			fields["db_time_ms"] = durationMillis(stats.DBTime)
+		}
+		if len(c.Errors) > 0 {
+			fields["errors"] = c.Errors.String()
 		}
 
-		// Log request
-		log.Printf("[%s] %d %s %s %v %s",
-			method,
-			statusCode,
-			clientIP,
-			path,
-			latency,
-			c.Request.UserAgent(),
-		)
+		entry := logging.FromContext(c.Request.Context()).WithFields(fields)
+		switch status := c.Writer.Status(); {
+		case status >= 500:
+			entry.Error("request")
+		case status >= 400:
+			entry.Warn("request")
+		default:
+			entry.Info("request")
+		}
 	}
 }
 
+// durationMillis returns a duration in milliseconds with microsecond precision
+func durationMillis(d time.Duration) float64 {
+	return float64(d.Microseconds()) / 1000
+}
+
 // ErrorHandlingMiddleware handles panics and errors
 func ErrorHandlingMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		defer func() {
 			if err := recover(); err != nil {
-				log.Printf("Panic recovered: %v", err)
-				c.JSON(500, gin.H{
-					"success": false,
-					"error":   "Internal server error",
-				})
+				logging.FromContext(c.Request.Context()).WithField("panic", fmt.Sprint(err)).Error("Panic recovered")
+				utils.SendProblemResponse(c, http.StatusInternalServerError, errors.NewInternalServerError(""))
 				c.Abort()
 			}
 		}()
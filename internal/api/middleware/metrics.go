@@ -0,0 +1,28 @@
+package middleware
+
+import (
+	"time"
+
+	"portal64api/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Metrics returns a middleware recording the latency of each request by route template
+// The route template (e.g. /api/v1/players/:id) keeps the number of label values bounded.
+func Metrics(httpMetrics *metrics.HTTPMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if httpMetrics == nil {
+			c.Next()
+			return
+		}
+
+		start := time.Now()
+		httpMetrics.Started()
+		defer func() {
+			httpMetrics.Finished(c.Request.Method, c.FullPath(), c.Writer.Status(), time.Since(start))
+		}()
+
+		c.Next()
+	}
+}
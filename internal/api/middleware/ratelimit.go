@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"portal64api/internal/auth"
+	"portal64api/internal/ratelimit"
+	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiPathPrefix is the prefix of rate limited routes, the next path segment is the route group
+const apiPathPrefix = "/api/v1/"
+
+// RateLimit returns a middleware that limits requests per client and route group
+// Clients are identified by a valid API key, otherwise by IP address. Responses carry the
+// RateLimit-Limit, RateLimit-Remaining, RateLimit-Reset and RateLimit-Policy headers; denied
+// requests receive 429 with Retry-After. If the limit state cannot be read the request is
+// allowed. Handlers can take further tokens with ratelimit.ChargeRequest. A nil limiter disables
+// rate limiting.
+func RateLimit(limiter *ratelimit.Limiter, keyStore *auth.KeyStore) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if limiter == nil {
+			c.Next()
+			return
+		}
+
+		group := RouteGroup(c.Request.URL.Path)
+		if group == "" {
+			c.Next()
+			return
+		}
+
+		client := clientKey(c, keyStore)
+		result, err := limiter.Allow(c.Request.Context(), group, client)
+		if err != nil {
+			log.Printf("Rate limit check failed, allowing request: %v", err)
+			c.Next()
+			return
+		}
+
+		if result.Limit > 0 {
+			rule := limiter.RuleFor(group)
+			c.Header("RateLimit-Limit", strconv.Itoa(result.Limit))
+			c.Header("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+			c.Header("RateLimit-Reset", strconv.Itoa(ceilSeconds(result.Reset)))
+			c.Header("RateLimit-Policy", fmt.Sprintf("%d;w=60;burst=%d", rule.RequestsPerMinute, result.Limit))
+		}
+
+		if !result.Allowed {
+			retryAfter := ceilSeconds(result.RetryAfter)
+			c.Header("Retry-After", strconv.Itoa(retryAfter))
+			utils.SendJSONResponse(c, http.StatusTooManyRequests,
+				errors.New(errors.CodeRateLimited, "Rate limit exceeded",
+					fmt.Sprintf("Retry after %d seconds", retryAfter)))
+			c.Abort()
+			return
+		}
+
+		// Handlers of expensive requests take further tokens from the same bucket
+		ctx := c.Request.Context()
+		c.Request = c.Request.WithContext(ratelimit.WithCharge(ctx, func(tokens int) (ratelimit.Result, error) {
+			return limiter.Charge(ctx, group, client, tokens)
+		}))
+
+		c.Next()
+	}
+}
+
+// RouteGroup returns the route group of an API path, e.g. "players" for /api/v1/players/123
+func RouteGroup(path string) string {
+	if !strings.HasPrefix(path, apiPathPrefix) {
+		return ""
+	}
+	group, _, _ := strings.Cut(strings.TrimPrefix(path, apiPathPrefix), "/")
+	return group
+}
+
+// clientKey identifies the client by API key if a valid one is sent, by IP address otherwise
+// gin only takes the address from X-Forwarded-For for requests of the trusted proxies set with
+// SetTrustedProxies, clients cannot get a new bucket per request by sending a different address.
+func clientKey(c *gin.Context, keyStore *auth.KeyStore) string {
+	if keyStore != nil {
+		if key := APIKeyFromRequest(c); key != "" {
+			if apiKey, err := keyStore.Authenticate(key); err == nil {
+				return "key:" + apiKey.ID
+			}
+		}
+	}
+	return "ip:" + c.ClientIP()
+}
+
+// ceilSeconds rounds a duration up to whole seconds
+func ceilSeconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
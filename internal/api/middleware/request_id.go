@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"portal64api/internal/logging"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader is the header carrying the request ID
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength limits request IDs accepted from clients
+const maxRequestIDLength = 128
+
+// RequestID returns a middleware that assigns each request an ID
+// A valid X-Request-ID sent by the client or a proxy is kept, otherwise a UUID is generated.
+// The ID is echoed in the response header and attached to the request context, where
+// logging.FromContext picks it up in services and repositories.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !isValidRequestID(id) {
+			id = newRequestID()
+		}
+
+		info := &logging.RequestInfo{ID: id}
+		c.Request = c.Request.WithContext(logging.WithRequestInfo(c.Request.Context(), info))
+		c.Header(RequestIDHeader, id)
+
+		c.Next()
+	}
+}
+
+// isValidRequestID accepts IDs made of letters, digits and . _ - : up to maxRequestIDLength
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '.', r == '_', r == '-', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID generates a random (version 4) UUID
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "unknown"
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -5,61 +5,143 @@ import (
 	
 	"portal64api/internal/api/handlers"
 	"portal64api/internal/api/middleware"
+	"portal64api/internal/auth"
 	"portal64api/internal/cache"
+	"portal64api/internal/config"
 	"portal64api/internal/database"
+	"portal64api/internal/graphql"
+	"portal64api/internal/interfaces"
+	"portal64api/internal/metrics"
+	"portal64api/internal/ratelimit"
 	"portal64api/internal/repositories"
 	"portal64api/internal/services"
 	"portal64api/internal/static"
+	"portal64api/pkg/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	
 	docs "portal64api/docs/generated" // swagger docs
 )
 
+// RouterOptions holds the dependencies and settings of the router
+// Optional services and features are left out with their zero value.
+type RouterOptions struct {
+	Databases           *database.Databases
+	Cache               cache.CacheService
+	ImportService       *services.ImportService       // Import routes are served if set
+	KaderPlanungService *services.KaderPlanungService // Kader-Planung and Somatogramm routes are served if set
+	ExportService       *services.ExportService       // Export routes are served if set
+	WebhookService      *services.WebhookService      // Webhook routes are served if set
+	KeyStore            *auth.KeyStore                // Administrative routes require API keys with the matching scope if set
+	RequireReadKey      bool                          // Data routes require an API key with the read scope
+	Limiter             *ratelimit.Limiter            // API routes are rate limited per client and route group if set
+	Compression         config.CompressionConfig
+	GraphQL             config.GraphQLConfig
+	GraphQLSchema       *graphql.Schema // GraphQL is served if set
+	TrustedProxies      []string        // X-Forwarded-* headers are only honoured for requests of these proxies
+}
+
 // SetupRoutes configures all API routes
-func SetupRoutes(dbs *database.Databases, cacheService cache.CacheService, importService *services.ImportService, kaderPlanungService *services.KaderPlanungService) *gin.Engine {
+func SetupRoutes(opts RouterOptions) (*gin.Engine, error) {
 	// Ensure swagger docs are loaded
 	_ = docs.SwaggerInfo
 	
 	// Create repositories
-	playerRepo := repositories.NewPlayerRepository(dbs)
-	clubRepo := repositories.NewClubRepository(dbs)
-	tournamentRepo := repositories.NewTournamentRepository(dbs)
-	addressRepo := repositories.NewAddressRepository(dbs)
+	playerRepo := repositories.NewPlayerRepository(opts.Databases)
+	clubRepo := repositories.NewClubRepository(opts.Databases)
+	tournamentRepo := repositories.NewTournamentRepository(opts.Databases)
+	addressRepo := repositories.NewAddressRepository(opts.Databases)
 
 	// Create services
-	playerService := services.NewPlayerService(playerRepo, clubRepo, tournamentRepo, cacheService)
-	clubService := services.NewClubService(clubRepo, cacheService)
+	playerService := services.NewPlayerService(interfaces.NewPlayerRepository(playerRepo),
+		interfaces.NewClubRepository(clubRepo), interfaces.NewTournamentRepository(tournamentRepo), opts.Cache)
+	clubService := services.NewClubService(clubRepo, opts.Cache)
 	clubService.SetPlayerRepository(playerRepo) // Set player repo for club profile functionality
-	tournamentService := services.NewTournamentService(tournamentRepo, cacheService)
-	addressService := services.NewAddressService(addressRepo, cacheService)
+	tournamentService := services.NewTournamentService(tournamentRepo, opts.Cache)
+	addressService := services.NewAddressService(addressRepo, opts.Cache)
+	addressService.SetPlayerRepository(playerRepo) // Resolve player IDs for person functions
 
 	// Create handlers
-	playerHandler := handlers.NewPlayerHandler(playerService)
-	clubHandler := handlers.NewClubHandler(clubService)
+	playerHandler := handlers.NewPlayerHandler(playerService, clubService)
+	clubHandler := handlers.NewClubHandler(clubService, playerService)
 	tournamentHandler := handlers.NewTournamentHandler(tournamentService)
 	addressHandler := handlers.NewAddressHandler(addressService)
-	adminHandler := handlers.NewAdminHandler(cacheService)
+	adminHandler := handlers.NewAdminHandler(opts.Cache)
+	healthHandler := handlers.NewHealthHandler(services.NewHealthService(opts.Databases, opts.Cache, opts.ImportService, opts.KaderPlanungService))
+
+	// Create GraphQL handler if the schema was built
+	var graphQLHandler *handlers.GraphQLHandler
+	if opts.GraphQLSchema != nil {
+		server := graphql.NewServer(opts.GraphQLSchema, graphql.Services{
+			Players:     playerService,
+			Clubs:       clubService,
+			Tournaments: tournamentService,
+			Addresses:   addressService,
+		}, graphql.Limits{
+			MaxDepth:           opts.GraphQL.MaxDepth,
+			MaxComplexity:      opts.GraphQL.MaxComplexity,
+			ComplexityPerToken: opts.GraphQL.ComplexityPerToken,
+		})
+		graphQLHandler = handlers.NewGraphQLHandler(server)
+	}
 	
 	// Create import handler if import service is available
 	var importHandler *handlers.ImportHandler
-	if importService != nil {
-		importHandler = handlers.NewImportHandler(importService)
+	if opts.ImportService != nil {
+		importHandler = handlers.NewImportHandler(opts.ImportService)
 	}
 	
 	// Create Kader-Planung handler if service is available
 	var kaderPlanungHandler *handlers.KaderPlanungHandler
-	if kaderPlanungService != nil {
-		kaderPlanungHandler = handlers.NewKaderPlanungHandler(kaderPlanungService)
+	if opts.KaderPlanungService != nil {
+		kaderPlanungHandler = handlers.NewKaderPlanungHandler(opts.KaderPlanungService)
+	}
+
+	// Create export handler if export service is available
+	var exportHandler *handlers.ExportHandler
+	if opts.ExportService != nil {
+		exportHandler = handlers.NewExportHandler(opts.ExportService)
+	}
+
+	// Create webhook handler if webhook service is available
+	var webhookHandler *handlers.WebhookHandler
+	if opts.WebhookService != nil {
+		webhookHandler = handlers.NewWebhookHandler(opts.WebhookService)
+	}
+
+	// Prometheus metrics, served with the Go runtime and process metrics of the default registry
+	httpMetrics := metrics.NewHTTPMetrics()
+	collectors := []prometheus.Collector{httpMetrics, metrics.DatabaseCollector(opts.Databases.Stats)}
+	if opts.Cache != nil {
+		collectors = append(collectors, metrics.CacheCollector(opts.Cache))
+	}
+	if opts.ImportService != nil {
+		collectors = append(collectors, metrics.ImportCollector(opts.ImportService))
+	}
+	if opts.KaderPlanungService != nil {
+		collectors = append(collectors, metrics.AnalysisCollector(opts.KaderPlanungService))
+	}
+	if err := metrics.Register(collectors...); err != nil {
+		return nil, err
 	}
 
 	// Create router
 	router := gin.New()
+	if err := router.SetTrustedProxies(opts.TrustedProxies); err != nil {
+		return nil, err
+	}
 
 	// Apply middleware
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Metrics(httpMetrics))
+	router.Use(middleware.Tracing())
 	router.Use(middleware.CORSMiddleware())
 	router.Use(middleware.LoggingMiddleware())
 	router.Use(middleware.ErrorHandlingMiddleware())
+	router.Use(middleware.RateLimit(opts.Limiter, opts.KeyStore))
+	router.Use(middleware.Compression(opts.Compression))
 
 	// Swagger documentation - Embedded implementation
 	// Serve the swagger JSON docs
@@ -92,65 +174,144 @@ func SetupRoutes(dbs *database.Databases, cacheService cache.CacheService, impor
 		c.Redirect(http.StatusMovedPermanently, "/demo/")
 	})
 
-	// Health check endpoint
+	// Health check endpoints, /health/ready is meant for load balancers
 	router.GET("/health", handlers.HealthCheck)
+	router.GET("/health/live", healthHandler.Live)
+	router.GET("/health/ready", healthHandler.Ready)
+
+	// Metrics endpoint for Prometheus
+	router.GET("/metrics", middleware.RequireScope(opts.KeyStore, auth.ScopeMetrics), gin.WrapH(promhttp.Handler()))
+
+	// Data endpoints are public unless a key with the read scope is required
+	var dataMiddleware []gin.HandlerFunc
+	if opts.RequireReadKey {
+		dataMiddleware = append(dataMiddleware, middleware.RequireScope(opts.KeyStore, auth.ScopeRead))
+	}
+
+	// Data only changes with an import, so responses are validated against the last import time
+	var lastImport middleware.LastModifiedFunc
+	if opts.ImportService != nil {
+		lastImport = opts.ImportService.GetLastImportTime
+	}
+	dataMiddleware = append(dataMiddleware, middleware.ConditionalGet(lastImport))
+
+	// Formats of the data routes, requests for other formats are answered with 406 Not Acceptable
+	dataFormats := middleware.ResponseFormat(utils.DataFormats...)
+	calendarFormats := middleware.ResponseFormat(utils.FormatJSON, utils.FormatCSV, utils.FormatExcelCSV,
+		utils.FormatNDJSON, utils.FormatXLSX, utils.FormatXML, utils.FormatICalendar)
+	tableFormats := middleware.ResponseFormat(utils.FormatJSON, utils.FormatCSV, utils.FormatExcelCSV)
+	contactFormats := middleware.ResponseFormat(utils.FormatJSON, utils.FormatCSV, utils.FormatExcelCSV, utils.FormatVCard)
+	jsonFormat := middleware.ResponseFormat(utils.FormatJSON)
+
+	// Error responses are problem details (RFC 7807), unknown paths included
+	router.NoRoute(handlers.RouteNotFound)
 
 	// API v1 routes
 	v1 := router.Group("/api/v1")
 	{
+		// Catalogue of error codes, the type URIs of problem details point here
+		v1.GET("/errors", handlers.GetErrorCatalogue)
+		v1.GET("/errors/:code", handlers.GetErrorCode)
+
 		// Player routes
-		players := v1.Group("/players")
+		players := v1.Group("/players", dataMiddleware...)
 		{
-			players.GET("", playerHandler.SearchPlayers)
-			players.GET("/:id", playerHandler.GetPlayer)
-			players.GET("/:id/rating-history", playerHandler.GetPlayerRatingHistory)
+			players.GET("", dataFormats, playerHandler.SearchPlayers)
+			players.GET("/:id", dataFormats, playerHandler.GetPlayer)
+			players.GET("/:id/rating-history", dataFormats, playerHandler.GetPlayerRatingHistory)
+			players.GET("/:id/functions", tableFormats, addressHandler.GetPlayerFunctions)
+			players.POST("/batch", jsonFormat, playerHandler.GetPlayersBatch)
+			players.POST("/rating-history/batch", jsonFormat, playerHandler.GetPlayersRatingHistoryBatch)
+		}
+
+		// Person routes
+		persons := v1.Group("/persons", dataMiddleware...)
+		{
+			persons.GET("/:uuid/functions", tableFormats, addressHandler.GetPersonFunctions)
 		}
 
 		// Club routes
-		clubs := v1.Group("/clubs")
+		clubs := v1.Group("/clubs", dataMiddleware...)
 		{
-			clubs.GET("", clubHandler.SearchClubs)
-			clubs.GET("/all", clubHandler.GetAllClubs)
-			clubs.GET("/:id", clubHandler.GetClub)
-			clubs.GET("/:id/players", playerHandler.GetPlayersByClub)
-			clubs.GET("/:id/profile", clubHandler.GetClubProfile)
+			clubs.GET("", dataFormats, clubHandler.SearchClubs)
+			clubs.GET("/all", dataFormats, clubHandler.GetAllClubs)
+			clubs.GET("/:id", dataFormats, clubHandler.GetClub)
+			clubs.GET("/:id/players", dataFormats, playerHandler.GetPlayersByClub)
+			clubs.GET("/:id/profile", dataFormats, clubHandler.GetClubProfile)
+			clubs.POST("/batch", jsonFormat, clubHandler.GetClubsBatch)
 		}
 
 		// Tournament routes
-		tournaments := v1.Group("/tournaments")
+		tournaments := v1.Group("/tournaments", dataMiddleware...)
 		{
-			tournaments.GET("", tournamentHandler.SearchTournaments)
-			tournaments.GET("/recent", tournamentHandler.GetRecentTournaments)
-			tournaments.GET("/date-range", tournamentHandler.GetTournamentsByDateRange)
-			tournaments.GET("/:id", tournamentHandler.GetTournament)
+			tournaments.GET("", dataFormats, tournamentHandler.SearchTournaments)
+			tournaments.GET("/recent", calendarFormats, tournamentHandler.GetRecentTournaments)
+			tournaments.GET("/date-range", calendarFormats, tournamentHandler.GetTournamentsByDateRange)
+			tournaments.GET("/:id", dataFormats, tournamentHandler.GetTournament)
+			tournaments.GET("/:id/team-matches", dataFormats, tournamentHandler.GetTournamentTeamMatches)
+		}
+
+		// GraphQL over the data of the routes above, read-only
+		if graphQLHandler != nil {
+			graphQLRoutes := v1.Group("/graphql", dataMiddleware...)
+			{
+				graphQLRoutes.GET("", jsonFormat, graphQLHandler.Query)
+				graphQLRoutes.POST("", jsonFormat, graphQLHandler.Query)
+			}
 		}
 
 		// Address routes
-		addresses := v1.Group("/addresses")
+		addresses := v1.Group("/addresses", dataMiddleware...)
 		{
-			addresses.GET("/regions", addressHandler.GetAvailableRegions)
-			addresses.GET("/:region", addressHandler.GetRegionAddresses)
-			addresses.GET("/:region/types", addressHandler.GetAddressTypes)
-			addresses.GET("/:region/:type", addressHandler.GetRegionAddressesByType)
+			addresses.GET("/regions", jsonFormat, addressHandler.GetAvailableRegions)
+			addresses.GET("/search", contactFormats, addressHandler.SearchAddresses)
+			addresses.GET("/:region", contactFormats, addressHandler.GetRegionAddresses)
+			addresses.GET("/:region/types", jsonFormat, addressHandler.GetAddressTypes)
+			addresses.GET("/:region/:type", contactFormats, addressHandler.GetRegionAddressesByType)
 		}
 
 		// Admin routes
 		admin := v1.Group("/admin")
 		{
-			cache := admin.Group("/cache")
+			cache := admin.Group("/cache", middleware.RequireScope(opts.KeyStore, auth.ScopeAdminCache))
 			{
 				cache.GET("/stats", adminHandler.GetCacheStats)
 				cache.GET("/health", adminHandler.GetCacheHealth)
 			}
 		}
 
+		// Bulk export routes (if export service is available)
+		// Files are served from disk with their own ETags, so data middleware does not apply.
+		if exportHandler != nil {
+			exportRoutes := v1.Group("/export")
+			{
+				exportRoutes.GET("", middleware.RequireScope(opts.KeyStore, auth.ScopeExport), exportHandler.GetManifest)
+				exportRoutes.GET("/:dataset", middleware.RequireScope(opts.KeyStore, auth.ScopeExport), exportHandler.DownloadDataset)
+				exportRoutes.POST("/generate", middleware.RequireScope(opts.KeyStore, auth.ScopeAdminImport), exportHandler.GenerateExport)
+			}
+		}
+
+		// Webhook subscription routes (if webhook service is available)
+		if webhookHandler != nil {
+			webhookRoutes := v1.Group("/webhooks", middleware.RequireScope(opts.KeyStore, auth.ScopeAdminWebhooks))
+			{
+				webhookRoutes.GET("", webhookHandler.ListSubscriptions)
+				webhookRoutes.POST("", webhookHandler.CreateSubscription)
+				webhookRoutes.GET("/:id", webhookHandler.GetSubscription)
+				webhookRoutes.DELETE("/:id", webhookHandler.DeleteSubscription)
+				webhookRoutes.GET("/:id/deliveries", webhookHandler.GetDeliveries)
+				webhookRoutes.POST("/:id/ping", webhookHandler.PingSubscription)
+			}
+		}
+
 		// Import routes (if import service is available)
 		if importHandler != nil {
-			importRoutes := v1.Group("/import")
+			importRoutes := v1.Group("/import", middleware.RequireScope(opts.KeyStore, auth.ScopeAdminImport))
 			{
 				importRoutes.GET("/status", importHandler.GetImportStatus)
 				importRoutes.POST("/start", importHandler.StartManualImport)
 				importRoutes.GET("/logs", importHandler.GetImportLogs)
+				importRoutes.GET("/events", importHandler.StreamImportEvents)
 				importRoutes.POST("/test-connection", importHandler.TestImportConnection)
 				importRoutes.GET("/health", importHandler.GetImportHealth)
 				importRoutes.GET("/config", importHandler.GetImportConfig)
@@ -159,10 +320,11 @@ func SetupRoutes(dbs *database.Databases, cacheService cache.CacheService, impor
 		
 		// Kader-Planung routes (if service is available)
 		if kaderPlanungHandler != nil {
-			kaderPlanungRoutes := v1.Group("/kader-planung")
+			kaderPlanungRoutes := v1.Group("/kader-planung", middleware.RequireScope(opts.KeyStore, auth.ScopeAdminAnalysis))
 			{
 				// Legacy routes (unchanged for backward compatibility)
 				kaderPlanungRoutes.GET("/status", kaderPlanungHandler.GetKaderPlanungStatus)
+				kaderPlanungRoutes.GET("/events", kaderPlanungHandler.StreamAnalysisEvents)
 				kaderPlanungRoutes.POST("/start", kaderPlanungHandler.StartKaderPlanungExecution)
 				kaderPlanungRoutes.GET("/files", kaderPlanungHandler.ListKaderPlanungFiles)
 				kaderPlanungRoutes.GET("/download/:filename", kaderPlanungHandler.DownloadKaderPlanungFile)
@@ -180,9 +342,9 @@ func SetupRoutes(dbs *database.Databases, cacheService cache.CacheService, impor
 		// by redirecting them to the unified Kader-Planung service in statistical mode
 		if kaderPlanungHandler != nil {
 			// Create compatibility adapter handler
-			somatogrammCompatibilityHandler := handlers.NewSomatogrammCompatibilityHandler(kaderPlanungService)
+			somatogrammCompatibilityHandler := handlers.NewSomatogrammCompatibilityHandler(opts.KaderPlanungService)
 
-			somatogrammRoutes := v1.Group("/somatogramm")
+			somatogrammRoutes := v1.Group("/somatogramm", middleware.RequireScope(opts.KeyStore, auth.ScopeAdminAnalysis))
 			{
 				somatogrammRoutes.GET("/status", somatogrammCompatibilityHandler.GetSomatogrammStatus)
 				somatogrammRoutes.POST("/start", somatogrammCompatibilityHandler.StartSomatogrammExecution)
@@ -192,5 +354,5 @@ func SetupRoutes(dbs *database.Databases, cacheService cache.CacheService, impor
 		}
 	}
 
-	return router
+	return router, nil
 }
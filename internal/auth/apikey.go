@@ -0,0 +1,98 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Scopes granted to API keys
+const (
+	ScopeRead          = "read"           // Read access to data endpoints (if required by configuration)
+	ScopeAdminImport   = "admin:import"   // Database import status and control
+	ScopeAdminAnalysis = "admin:analysis" // Kader-Planung and statistical analysis runs and downloads
+	ScopeAdminCache    = "admin:cache"    // Cache statistics and health
+	ScopeAdminWebhooks = "admin:webhooks" // Webhook subscriptions and delivery log
+	ScopeMetrics       = "metrics"        // Prometheus metrics scraping
+	ScopeExport        = "export"         // Bulk export downloads
+)
+
+// AllScopes lists every scope that can be granted
+var AllScopes = []string{ScopeRead, ScopeAdminImport, ScopeAdminAnalysis, ScopeAdminCache, ScopeAdminWebhooks, ScopeMetrics, ScopeExport}
+
+// keyPrefix marks Portal64 API keys, e.g. p64_1a2b3c4d_<secret>
+const keyPrefix = "p64"
+
+// APIKey is a stored API key. Only the SHA-256 hash of the key is kept.
+type APIKey struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Hash      string     `json:"hash"`
+	Scopes    []string   `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// HasScope reports whether the key grants the given scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// IsActive reports whether the key is neither revoked nor expired
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
+
+// IsValidScope reports whether a scope is known
+func IsValidScope(scope string) bool {
+	for _, s := range AllScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// HashKey returns the hex encoded SHA-256 hash of a key
+// Keys carry 256 bits of randomness, so a fast hash is sufficient.
+func HashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// generateKey creates a new key ID and the plaintext key containing it
+func generateKey() (string, string, error) {
+	idBytes := make([]byte, 4)
+	if _, err := rand.Read(idBytes); err != nil {
+		return "", "", err
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+
+	id := hex.EncodeToString(idBytes)
+	key := keyPrefix + "_" + id + "_" + base64.RawURLEncoding.EncodeToString(secret)
+	return id, key, nil
+}
+
+// parseKeyID extracts the key ID from a plaintext key
+func parseKeyID(key string) (string, bool) {
+	parts := strings.SplitN(key, "_", 3)
+	if len(parts) != 3 || parts[0] != keyPrefix || parts[1] == "" || parts[2] == "" {
+		return "", false
+	}
+	return parts[1], true
+}
@@ -0,0 +1,245 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Errors returned by the key store
+var (
+	ErrInvalidKey  = errors.New("invalid API key")
+	ErrKeyExpired  = errors.New("API key expired")
+	ErrKeyRevoked  = errors.New("API key revoked")
+	ErrKeyNotFound = errors.New("API key not found")
+)
+
+// keyFile is the on-disk format of the key store
+type keyFile struct {
+	Keys []*APIKey `json:"keys"`
+}
+
+// KeyStore keeps API keys in a JSON file
+// The file is reloaded when it changes, so keys managed with the CLI take effect
+// without restarting the server.
+type KeyStore struct {
+	path    string
+	mu      sync.RWMutex
+	keys    map[string]*APIKey
+	modTime time.Time
+}
+
+// NewKeyStore opens the key store at path, a missing file is an empty store
+func NewKeyStore(path string) (*KeyStore, error) {
+	store := &KeyStore{
+		path: path,
+		keys: make(map[string]*APIKey),
+	}
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Authenticate looks up a plaintext key and checks that it is active
+func (s *KeyStore) Authenticate(key string) (*APIKey, error) {
+	id, ok := parseKeyID(key)
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+
+	s.reloadIfChanged()
+
+	s.mu.RLock()
+	var stored APIKey
+	entry, exists := s.keys[id]
+	if exists {
+		stored = *entry
+	}
+	s.mu.RUnlock()
+
+	if !exists || subtle.ConstantTimeCompare([]byte(stored.Hash), []byte(HashKey(key))) != 1 {
+		return nil, ErrInvalidKey
+	}
+	if stored.RevokedAt != nil {
+		return nil, ErrKeyRevoked
+	}
+	if !stored.IsActive(time.Now()) {
+		return nil, ErrKeyExpired
+	}
+
+	return &stored, nil
+}
+
+// Create creates and persists a new key. The plaintext key is returned once and never stored.
+func (s *KeyStore) Create(name string, scopes []string, expiresAt *time.Time) (string, *APIKey, error) {
+	if len(scopes) == 0 {
+		return "", nil, fmt.Errorf("at least one scope is required")
+	}
+	for _, scope := range scopes {
+		if !IsValidScope(scope) {
+			return "", nil, fmt.Errorf("unknown scope %q", scope)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadLocked(); err != nil {
+		return "", nil, err
+	}
+
+	id, key, err := generateKey()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	for s.keys[id] != nil {
+		if id, key, err = generateKey(); err != nil {
+			return "", nil, fmt.Errorf("failed to generate key: %w", err)
+		}
+	}
+
+	apiKey := &APIKey{
+		ID:        id,
+		Name:      name,
+		Hash:      HashKey(key),
+		Scopes:    scopes,
+		CreatedAt: time.Now().UTC(),
+		ExpiresAt: expiresAt,
+	}
+	s.keys[id] = apiKey
+
+	if err := s.saveLocked(); err != nil {
+		delete(s.keys, id)
+		return "", nil, err
+	}
+
+	result := *apiKey
+	return key, &result, nil
+}
+
+// Revoke marks a key as revoked, revoked keys stay listed for auditing
+func (s *KeyStore) Revoke(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.loadLocked(); err != nil {
+		return err
+	}
+
+	apiKey, exists := s.keys[id]
+	if !exists {
+		return ErrKeyNotFound
+	}
+	if apiKey.RevokedAt == nil {
+		now := time.Now().UTC()
+		apiKey.RevokedAt = &now
+	}
+
+	return s.saveLocked()
+}
+
+// List returns all keys ordered by creation time
+func (s *KeyStore) List() []APIKey {
+	s.reloadIfChanged()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]APIKey, 0, len(s.keys))
+	for _, apiKey := range s.keys {
+		keys = append(keys, *apiKey)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+	return keys
+}
+
+// reloadIfChanged reloads the key file if it was modified since the last load
+func (s *KeyStore) reloadIfChanged() {
+	info, err := os.Stat(s.path)
+	if err != nil {
+		return
+	}
+
+	s.mu.RLock()
+	changed := !info.ModTime().Equal(s.modTime)
+	s.mu.RUnlock()
+
+	if changed {
+		// Keep the previous keys if the file is being rewritten
+		_ = s.load()
+	}
+}
+
+// load reads the key file
+func (s *KeyStore) load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.loadLocked()
+}
+
+// loadLocked reads the key file, the caller holds the write lock
+func (s *KeyStore) loadLocked() error {
+	info, err := os.Stat(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read API key file: %w", err)
+	}
+
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		return fmt.Errorf("failed to read API key file: %w", err)
+	}
+
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse API key file %s: %w", s.path, err)
+	}
+
+	keys := make(map[string]*APIKey, len(file.Keys))
+	for _, apiKey := range file.Keys {
+		keys[apiKey.ID] = apiKey
+	}
+	s.keys = keys
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// saveLocked writes the key file atomically, the caller holds the write lock
+func (s *KeyStore) saveLocked() error {
+	file := keyFile{Keys: make([]*APIKey, 0, len(s.keys))}
+	for _, apiKey := range s.keys {
+		file.Keys = append(file.Keys, apiKey)
+	}
+	sort.Slice(file.Keys, func(i, j int) bool { return file.Keys[i].CreatedAt.Before(file.Keys[j].CreatedAt) })
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode API keys: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create API key directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write API key file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write API key file: %w", err)
+	}
+
+	if info, err := os.Stat(s.path); err == nil {
+		s.modTime = info.ModTime()
+	}
+	return nil
+}
@@ -55,6 +55,16 @@ type CacheStats struct {
 	// Connection Stats
 	ActiveConnections int `json:"active_connections"`
 	IdleConnections   int `json:"idle_connections"`
+	
+	// Hit/Miss Statistics per key prefix (e.g. "player", "search:club")
+	PrefixStats map[string]PrefixStats `json:"prefix_stats,omitempty"`
+}
+
+// PrefixStats holds the hits and misses of the keys sharing a prefix
+type PrefixStats struct {
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
 }
 
 // CacheError represents cache-specific errors
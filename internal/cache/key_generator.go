@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"portal64api/internal/models"
 	"strings"
+	"time"
 )
 
 // Key generation constants
@@ -16,6 +17,16 @@ const (
 	SearchKeyPrefix      = "search"
 )
 
+// KeyPrefix returns the prefix grouping a key in the cache statistics
+// This is the entity of the key, for search keys the searched entity as well ("search:player").
+func KeyPrefix(key string) string {
+	parts := strings.SplitN(key, ":", 3)
+	if parts[0] == SearchKeyPrefix && len(parts) > 1 {
+		return parts[0] + ":" + parts[1]
+	}
+	return parts[0]
+}
+
 // KeyGenerator provides cache key generation utilities
 type KeyGenerator struct{}
 
@@ -33,6 +44,10 @@ func (kg *KeyGenerator) PlayerRatingHistoryKey(playerID string) string {
 	return fmt.Sprintf("%s:%s:rating-history", PlayerKeyPrefix, playerID)
 }
 
+func (kg *KeyGenerator) PlayerMembershipsKey(playerID string) string {
+	return fmt.Sprintf("%s:%s:memberships", PlayerKeyPrefix, playerID)
+}
+
 // Club-related keys
 func (kg *KeyGenerator) ClubKey(clubID string) string {
 	return fmt.Sprintf("%s:%s", ClubKeyPrefix, clubID)
@@ -79,6 +94,10 @@ func (kg *KeyGenerator) AddressTypesKey(region string) string {
 	return fmt.Sprintf("%s:region:%s:types", AddressKeyPrefix, region)
 }
 
+func (kg *KeyGenerator) AddressPersonFunctionsKey(personID string) string {
+	return fmt.Sprintf("%s:person:%s:functions", AddressKeyPrefix, personID)
+}
+
 // Search-related keys
 func (kg *KeyGenerator) SearchKey(entityType string, hash string) string {
 	return fmt.Sprintf("%s:%s:hash:%s", SearchKeyPrefix, entityType, hash)
@@ -101,14 +120,34 @@ func (kg *KeyGenerator) GenerateClubSearchHash(req models.SearchRequest) string
 	return fmt.Sprintf("%x", md5.Sum([]byte(data)))
 }
 
-// Tournament search hash
-func (kg *KeyGenerator) GenerateTournamentSearchHash(req models.SearchRequest) string {
+// Tournament search hash with filter parameters
+func (kg *KeyGenerator) GenerateTournamentSearchHash(req models.SearchRequest, filter models.TournamentSearchFilter) string {
 	sortKey := fmt.Sprintf("%s:%s", req.SortBy, req.SortOrder)
-	data := fmt.Sprintf("%s:%d:%d:%s", 
-		strings.ToLower(req.Query), req.Limit, req.Offset, sortKey)
+	computed := ""
+	if filter.Computed != nil {
+		computed = fmt.Sprintf("%t", *filter.Computed)
+	}
+	data := fmt.Sprintf("%s:%d:%d:%s:%s:%s:%d:%s:%s:%d:%s:%d:%s:%s",
+		strings.ToLower(req.Query), req.Limit, req.Offset, sortKey, req.FilterBy, req.FilterValue,
+		filter.OrganisationID, filter.Region, filter.TypeAcronym, filter.Rounds, computed, filter.AssessorID,
+		formatDateKey(filter.FinishedFrom), formatDateKey(filter.FinishedTo))
+	return fmt.Sprintf("%x", md5.Sum([]byte(data)))
+}
+
+// Address search hash
+func (kg *KeyGenerator) GenerateAddressSearchHash(query, function, region string, limit int) string {
+	data := fmt.Sprintf("%s:%s:%s:%d", strings.ToLower(query), strings.ToLower(function), region, limit)
 	return fmt.Sprintf("%x", md5.Sum([]byte(data)))
 }
 
+// formatDateKey formats an optional date for use in hash data
+func formatDateKey(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+	return date.Format("2006-01-02")
+}
+
 // Key validation
 func (kg *KeyGenerator) ValidateKey(key string) bool {
 	if key == "" {
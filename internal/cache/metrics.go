@@ -30,6 +30,9 @@ type MetricsCollector struct {
 	activeConnections  int
 	idleConnections   int
 	
+	// Hits and misses per key prefix, see KeyPrefix
+	prefixStats map[string]*PrefixStats
+	
 	// Configuration
 	maxResponseTimesSamples int
 }
@@ -39,6 +42,7 @@ func NewMetricsCollector() *MetricsCollector {
 	return &MetricsCollector{
 		maxResponseTimesSamples: 1000, // Keep last 1000 response times
 		responseTimes:          make([]time.Duration, 0, 1000),
+		prefixStats:            make(map[string]*PrefixStats),
 	}
 }
 
@@ -57,6 +61,40 @@ func (mc *MetricsCollector) RecordMiss() {
 	atomic.AddInt64(&mc.cacheMisses, 1)
 }
 
+// RecordKeyHit records a cache hit for a key, counted overall and for its prefix
+func (mc *MetricsCollector) RecordKeyHit(key string) {
+	mc.RecordHit()
+	mc.recordPrefix(key, true)
+}
+
+// RecordKeyMiss records a cache miss for a key, counted overall and for its prefix
+func (mc *MetricsCollector) RecordKeyMiss(key string) {
+	mc.RecordMiss()
+	mc.recordPrefix(key, false)
+}
+
+// recordPrefix counts a hit or miss for the prefix of a key
+func (mc *MetricsCollector) recordPrefix(key string, hit bool) {
+	prefix := KeyPrefix(key)
+	
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+	
+	if mc.prefixStats == nil {
+		mc.prefixStats = make(map[string]*PrefixStats)
+	}
+	stats, ok := mc.prefixStats[prefix]
+	if !ok {
+		stats = &PrefixStats{}
+		mc.prefixStats[prefix] = stats
+	}
+	if hit {
+		stats.Hits++
+	} else {
+		stats.Misses++
+	}
+}
+
 // RecordOperation records a cache operation
 func (mc *MetricsCollector) RecordOperation() {
 	atomic.AddInt64(&mc.cacheOperations, 1)
@@ -146,7 +184,25 @@ func (mc *MetricsCollector) GetStats() CacheStats {
 		KeyCount:           atomic.LoadInt64(&mc.keyCount),
 		ActiveConnections:  mc.activeConnections,
 		IdleConnections:    mc.idleConnections,
+		PrefixStats:        mc.prefixStatsUnsafe(),
+	}
+}
+
+// prefixStatsUnsafe returns a copy of the per prefix statistics, the caller holds the lock
+func (mc *MetricsCollector) prefixStatsUnsafe() map[string]PrefixStats {
+	if len(mc.prefixStats) == 0 {
+		return nil
+	}
+	result := make(map[string]PrefixStats, len(mc.prefixStats))
+	for prefix, stats := range mc.prefixStats {
+		total := stats.Hits + stats.Misses
+		copied := *stats
+		if total > 0 {
+			copied.HitRatio = float64(stats.Hits) / float64(total)
+		}
+		result[prefix] = copied
 	}
+	return result
 }
 
 // Reset resets all metrics (useful for testing)
@@ -167,6 +223,7 @@ func (mc *MetricsCollector) Reset() {
 	atomic.StoreInt64(&mc.keyCount, 0)
 	
 	mc.responseTimes = mc.responseTimes[:0]
+	mc.prefixStats = make(map[string]*PrefixStats)
 	mc.activeConnections = 0
 	mc.idleConnections = 0
 }
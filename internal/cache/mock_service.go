@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"portal64api/internal/logging"
 )
 
 // MockCacheService provides an in-memory cache implementation for testing
@@ -69,7 +71,8 @@ func (mcs *MockCacheService) Get(ctx context.Context, key string, dest interface
 	}
 	
 	if !mcs.enabled {
-		mcs.metrics.RecordMiss()
+		mcs.metrics.RecordKeyMiss(key)
+		logging.RecordCacheMiss(ctx)
 		return ErrCacheNotEnabled
 	}
 	
@@ -78,13 +81,15 @@ func (mcs *MockCacheService) Get(ctx context.Context, key string, dest interface
 	
 	item, exists := mcs.data[key]
 	if !exists {
-		mcs.metrics.RecordMiss()
+		mcs.metrics.RecordKeyMiss(key)
+		logging.RecordCacheMiss(ctx)
 		return &CacheError{Operation: "get", Key: key, Err: ErrKeyNotFound.Err}
 	}
 	
 	// Check expiration
 	if time.Now().After(item.expiresAt) {
-		mcs.metrics.RecordMiss()
+		mcs.metrics.RecordKeyMiss(key)
+		logging.RecordCacheMiss(ctx)
 		delete(mcs.data, key) // Clean up expired item
 		return &CacheError{Operation: "get", Key: key, Err: ErrKeyNotFound.Err}
 	}
@@ -95,7 +100,8 @@ func (mcs *MockCacheService) Get(ctx context.Context, key string, dest interface
 		return &CacheError{Operation: "get", Key: key, Err: ErrDeserialization.Err}
 	}
 	
-	mcs.metrics.RecordHit()
+	mcs.metrics.RecordKeyHit(key)
+	logging.RecordCacheHit(ctx)
 	return nil
 }
 
@@ -7,7 +7,10 @@ import (
 	"time"
 
 	"portal64api/internal/config"
+	"portal64api/internal/logging"
+
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // RedisService implements CacheService using Redis
@@ -68,8 +71,20 @@ func NewRedisService(config config.CacheConfig) (*RedisService, error) {
 	return service, nil
 }
 
+// Client returns the Redis client, nil if the cache is disabled
+// Other Redis users share its connection pool instead of opening their own.
+func (rs *RedisService) Client() *redis.Client {
+	return rs.client
+}
+
 // Get retrieves a value from cache and deserializes it
-func (rs *RedisService) Get(ctx context.Context, key string, dest interface{}) error {
+func (rs *RedisService) Get(ctx context.Context, key string, dest interface{}) (err error) {
+	ctx, span := rs.startSpan(ctx, "get", key)
+	defer func() {
+		span.SetAttributes(attribute.Bool("cache.hit", err == nil))
+		endSpan(span, err)
+	}()
+
 	start := time.Now()
 	defer func() {
 		rs.metrics.RecordResponseTime(time.Since(start))
@@ -79,7 +94,8 @@ func (rs *RedisService) Get(ctx context.Context, key string, dest interface{}) e
 	rs.metrics.RecordOperation()
 	
 	if !rs.enabled {
-		rs.metrics.RecordMiss()
+		rs.metrics.RecordKeyMiss(key)
+		logging.RecordCacheMiss(ctx)
 		return ErrCacheNotEnabled
 	}
 	
@@ -93,7 +109,8 @@ func (rs *RedisService) Get(ctx context.Context, key string, dest interface{}) e
 	val, err := rs.client.Get(ctx, key).Result()
 	if err != nil {
 		if err == redis.Nil {
-			rs.metrics.RecordMiss()
+			rs.metrics.RecordKeyMiss(key)
+			logging.RecordCacheMiss(ctx)
 			return &CacheError{Operation: "get", Key: key, Err: ErrKeyNotFound.Err}
 		}
 		rs.metrics.RecordError()
@@ -106,12 +123,16 @@ func (rs *RedisService) Get(ctx context.Context, key string, dest interface{}) e
 		return &CacheError{Operation: "get", Key: key, Err: ErrDeserialization.Err}
 	}
 	
-	rs.metrics.RecordHit()
+	rs.metrics.RecordKeyHit(key)
+	logging.RecordCacheHit(ctx)
 	return nil
 }
 
 // Set stores a value in cache with TTL
-func (rs *RedisService) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+func (rs *RedisService) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) (err error) {
+	ctx, span := rs.startSpan(ctx, "set", key)
+	defer func() { endSpan(span, err) }()
+
 	start := time.Now()
 	defer func() {
 		rs.metrics.RecordResponseTime(time.Since(start))
@@ -146,7 +167,10 @@ func (rs *RedisService) Set(ctx context.Context, key string, value interface{},
 }
 
 // Delete removes a key from cache
-func (rs *RedisService) Delete(ctx context.Context, key string) error {
+func (rs *RedisService) Delete(ctx context.Context, key string) (err error) {
+	ctx, span := rs.startSpan(ctx, "delete", key)
+	defer func() { endSpan(span, err) }()
+
 	rs.metrics.RecordOperation()
 	
 	if !rs.enabled {
@@ -162,7 +186,10 @@ func (rs *RedisService) Delete(ctx context.Context, key string) error {
 }
 
 // Exists checks if a key exists in cache
-func (rs *RedisService) Exists(ctx context.Context, key string) (bool, error) {
+func (rs *RedisService) Exists(ctx context.Context, key string) (exists bool, err error) {
+	ctx, span := rs.startSpan(ctx, "exists", key)
+	defer func() { endSpan(span, err) }()
+
 	rs.metrics.RecordOperation()
 	
 	if !rs.enabled {
@@ -179,7 +206,10 @@ func (rs *RedisService) Exists(ctx context.Context, key string) (bool, error) {
 }
 
 // FlushAll clears all keys from the cache
-func (rs *RedisService) FlushAll(ctx context.Context) error {
+func (rs *RedisService) FlushAll(ctx context.Context) (err error) {
+	ctx, span := rs.startSpan(ctx, "flush_all", "")
+	defer func() { endSpan(span, err) }()
+
 	rs.metrics.RecordOperation()
 	
 	if !rs.enabled {
@@ -217,6 +247,7 @@ func (rs *RedisService) GetWithRefresh(ctx context.Context, key string, dest int
 	if setErr := rs.Set(ctx, key, value, ttl); setErr != nil {
 		// Log error but don't fail the request
 		// The caller still gets their data
+		logging.FromContext(ctx).WithError(setErr).WithField("key", key).Warn("Failed to store value in cache")
 	}
 	
 	// Return the freshly fetched data
@@ -230,7 +261,10 @@ func (rs *RedisService) GetWithRefresh(ctx context.Context, key string, dest int
 }
 
 // MGet retrieves multiple keys at once
-func (rs *RedisService) MGet(ctx context.Context, keys []string) (map[string]interface{}, error) {
+func (rs *RedisService) MGet(ctx context.Context, keys []string) (_ map[string]interface{}, err error) {
+	ctx, span := rs.startSpan(ctx, "mget", "")
+	defer func() { endSpan(span, err) }()
+
 	rs.metrics.RecordOperation()
 	
 	if !rs.enabled {
@@ -262,12 +296,14 @@ func (rs *RedisService) MGet(ctx context.Context, keys []string) (map[string]int
 			var jsonData interface{}
 			if err := json.Unmarshal([]byte(val.(string)), &jsonData); err == nil {
 				result[keys[i]] = jsonData
-				rs.metrics.RecordHit()
+				rs.metrics.RecordKeyHit(keys[i])
+				logging.RecordCacheHit(ctx)
 			} else {
 				rs.metrics.RecordError()
 			}
 		} else {
-			rs.metrics.RecordMiss()
+			rs.metrics.RecordKeyMiss(keys[i])
+			logging.RecordCacheMiss(ctx)
 		}
 	}
 	
@@ -275,7 +311,10 @@ func (rs *RedisService) MGet(ctx context.Context, keys []string) (map[string]int
 }
 
 // MSet stores multiple key-value pairs with the same TTL
-func (rs *RedisService) MSet(ctx context.Context, items map[string]interface{}, ttl time.Duration) error {
+func (rs *RedisService) MSet(ctx context.Context, items map[string]interface{}, ttl time.Duration) (err error) {
+	ctx, span := rs.startSpan(ctx, "mset", "")
+	defer func() { endSpan(span, err) }()
+
 	rs.metrics.RecordOperation()
 	
 	if !rs.enabled {
@@ -307,7 +346,7 @@ func (rs *RedisService) MSet(ctx context.Context, items map[string]interface{},
 	}
 	
 	// Execute pipeline
-	_, err := pipe.Exec(ctx)
+	_, err = pipe.Exec(ctx)
 	if err != nil {
 		rs.metrics.RecordError()
 		return &CacheError{Operation: "mset", Err: err}
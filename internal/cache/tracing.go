@@ -0,0 +1,38 @@
+package cache
+
+import (
+	"context"
+	"errors"
+
+	"portal64api/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// startSpan starts the span of a Redis operation as a child of the span in ctx
+func (rs *RedisService) startSpan(ctx context.Context, operation, key string) (context.Context, trace.Span) {
+	attributes := []attribute.KeyValue{
+		attribute.String("db.system", "redis"),
+		attribute.String("db.operation", operation),
+	}
+	if key != "" {
+		attributes = append(attributes, attribute.String("cache.key_prefix", KeyPrefix(key)))
+	}
+	return tracing.Start(ctx, "cache."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...),
+	)
+}
+
+// endSpan ends the span of a Redis operation, misses and a disabled cache are not errors
+func endSpan(span trace.Span, err error) {
+	var cacheErr *CacheError
+	switch {
+	case err == nil, err == ErrCacheNotEnabled:
+	case errors.As(err, &cacheErr) && cacheErr.Err == ErrKeyNotFound.Err:
+	default:
+		tracing.RecordError(span, err)
+	}
+	span.End()
+}
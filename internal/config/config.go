@@ -19,6 +19,13 @@ type Config struct {
 	Cache               CacheConfig
 	Import              ImportConfig
 	Logging             LoggingConfig
+	Auth                AuthConfig
+	RateLimit           RateLimitConfig
+	Compression         CompressionConfig
+	Tracing             TracingConfig
+	GraphQL             GraphQLConfig
+	Export              ExportConfig
+	Webhooks            WebhooksConfig
 	KaderPlanung        KaderPlanungConfig        // Legacy config for backward compatibility
 	Somatogramm         SomatogrammConfig         // Legacy config for backward compatibility
 	UnifiedKaderPlanung UnifiedKaderPlanungConfig // New unified config
@@ -34,6 +41,10 @@ type ServerConfig struct {
 	KeyFile      string
 	ReadTimeout  int
 	WriteTimeout int
+
+	// TrustedProxies lists the IP addresses and CIDR ranges of reverse proxies whose
+	// X-Forwarded-* headers are honoured, none by default
+	TrustedProxies []string
 }
 
 // DatabaseConfig holds database connection configuration
@@ -152,6 +163,7 @@ type RetryConfig struct {
 type LoggingConfig struct {
 	Enabled        bool
 	Level          string
+	Format         string // "json" for structured logs, "text" for human readable logs
 	MainLogFile    string
 	ImportLogFile  string
 	MaxSizeMB      int
@@ -160,6 +172,69 @@ type LoggingConfig struct {
 	Compress       bool
 }
 
+// AuthConfig holds API key authentication configuration
+type AuthConfig struct {
+	Enabled        bool
+	KeysFile       string
+	RequireReadKey bool // Require a key with the read scope for data endpoints
+}
+
+// RateLimitConfig holds per-client rate limiting configuration
+type RateLimitConfig struct {
+	Enabled           bool
+	RequestsPerMinute int      // Default sustained rate per client and route group
+	Burst             int      // Default burst per client and route group
+	Groups            []string // Per route group overrides, "group=requestsPerMinute:burst"
+}
+
+// TracingConfig holds OpenTelemetry tracing configuration
+type TracingConfig struct {
+	Enabled      bool
+	Exporter     string  // "otlp", "stdout" or "file"
+	OTLPEndpoint string  // OTLP/HTTP collector URL, e.g. http://localhost:4318
+	FilePath     string  // Span output of the "file" exporter, one JSON document per span
+	SampleRatio  float64 // Share of new traces recorded, 0 to 1; sampled parents are always followed
+	ServiceName  string
+}
+
+// GraphQLConfig holds GraphQL endpoint configuration
+type GraphQLConfig struct {
+	Enabled       bool
+	MaxDepth      int // Maximum nesting of selections, introspection excluded
+	MaxComplexity int // Maximum estimated number of resolved fields, lists count with their limit
+
+	ComplexityPerToken int // Complexity paid by one rate limit token, 0 to charge one token per query
+}
+
+// ExportConfig holds bulk export configuration
+type ExportConfig struct {
+	Enabled           bool
+	Directory         string // Generations are written to subdirectories named after their generation
+	Keep              int    // Number of generations kept on disk, the newest is served by default
+	GenerateOnStartup bool   // Generate an export on startup if none exists yet
+}
+
+// WebhooksConfig holds webhook delivery configuration
+type WebhooksConfig struct {
+	Enabled         bool
+	Directory       string        // Subscriptions and the DWZ baseline for rating changes are stored here
+	MaxAttempts     int           // Attempts per delivery including the first
+	RetryBackoff    time.Duration // Delay before the first retry, doubled for each further retry
+	Timeout         time.Duration // Timeout of a delivery request
+	Workers         int           // Concurrent delivery requests
+	DeliveryLogSize int           // Deliveries kept in the delivery log
+
+	AllowPrivateNetworks bool // Deliver to loopback, private and link-local receiver addresses
+}
+
+// CompressionConfig holds response compression configuration
+type CompressionConfig struct {
+	Enabled      bool
+	Level        int      // gzip level 1 (fastest) to 9 (smallest)
+	MinSize      int      // Minimum body size in bytes to compress
+	ContentTypes []string // Compressed content type prefixes, e.g. "text/" matches all text types
+}
+
 // KaderPlanungConfig holds configuration for integrated Kader-Planung functionality
 type KaderPlanungConfig struct {
 	Enabled       bool
@@ -312,14 +387,15 @@ func manualLoadEnvFile(filename string) bool {
 func loadConfig() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Port:         getIntEnv("SERVER_PORT", 8080),
-			Host:         getStringEnv("SERVER_HOST", "0.0.0.0"),
-			Environment:  getStringEnv("ENVIRONMENT", "development"),
-			EnableHTTPS:  getBoolEnv("ENABLE_HTTPS", false),
-			CertFile:     getStringEnv("CERT_FILE", ""),
-			KeyFile:      getStringEnv("KEY_FILE", ""),
-			ReadTimeout:  getIntEnv("READ_TIMEOUT", 10),
-			WriteTimeout: getIntEnv("WRITE_TIMEOUT", 10),
+			Port:           getIntEnv("SERVER_PORT", 8080),
+			Host:           getStringEnv("SERVER_HOST", "0.0.0.0"),
+			Environment:    getStringEnv("ENVIRONMENT", "development"),
+			EnableHTTPS:    getBoolEnv("ENABLE_HTTPS", false),
+			CertFile:       getStringEnv("CERT_FILE", ""),
+			KeyFile:        getStringEnv("KEY_FILE", ""),
+			ReadTimeout:    getIntEnv("READ_TIMEOUT", 10),
+			WriteTimeout:   getIntEnv("WRITE_TIMEOUT", 10),
+			TrustedProxies: getStringSliceEnv("TRUSTED_PROXIES", []string{}),
 		},		
 		Database: DatabaseConfig{
 			MVDSB: DatabaseConnection{
@@ -409,6 +485,7 @@ func loadConfig() *Config {
 		Logging: LoggingConfig{
 			Enabled:       getBoolEnv("LOG_ENABLED", true),
 			Level:         getStringEnv("LOG_LEVEL", "info"),
+			Format:        getStringEnv("LOG_FORMAT", "json"),
 			MainLogFile:   getStringEnv("LOG_FILE_PATH", "./logs/portal64api.log"),
 			ImportLogFile: getStringEnv("LOG_IMPORT_FILE_PATH", "./logs/portal64api-import.log"),
 			MaxSizeMB:     getIntEnv("LOG_MAX_SIZE_MB", 100),
@@ -416,6 +493,57 @@ func loadConfig() *Config {
 			MaxAgeDays:    getIntEnv("LOG_MAX_AGE_DAYS", 30),
 			Compress:      getBoolEnv("LOG_COMPRESS", true),
 		},
+		Auth: AuthConfig{
+			Enabled:        getBoolEnv("AUTH_ENABLED", true),
+			KeysFile:       getStringEnv("AUTH_KEYS_FILE", "./data/api_keys.json"),
+			RequireReadKey: getBoolEnv("AUTH_REQUIRE_READ_KEY", false),
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:           getBoolEnv("RATE_LIMIT_ENABLED", false),
+			RequestsPerMinute: getIntEnv("RATE_LIMIT_REQUESTS_PER_MINUTE", 300),
+			Burst:             getIntEnv("RATE_LIMIT_BURST", 60),
+			Groups:            getStringSliceEnv("RATE_LIMIT_GROUPS", []string{}),
+		},
+		Compression: CompressionConfig{
+			Enabled: getBoolEnv("COMPRESSION_ENABLED", true),
+			Level:   getIntEnv("COMPRESSION_LEVEL", 5),
+			MinSize: getIntEnv("COMPRESSION_MIN_SIZE", 1024),
+			ContentTypes: getStringSliceEnv("COMPRESSION_CONTENT_TYPES", []string{
+				"application/json", "application/javascript", "application/xml", "image/svg+xml", "text/",
+			}),
+		},
+		Tracing: TracingConfig{
+			Enabled:      getBoolEnv("TRACING_ENABLED", false),
+			Exporter:     getStringEnv("TRACING_EXPORTER", "otlp"),
+			OTLPEndpoint: getStringEnv("TRACING_OTLP_ENDPOINT", "http://localhost:4318"),
+			FilePath:     getStringEnv("TRACING_FILE_PATH", "./logs/traces.json"),
+			SampleRatio:  getFloat64Env("TRACING_SAMPLE_RATIO", 1.0),
+			ServiceName:  getStringEnv("TRACING_SERVICE_NAME", "portal64api"),
+		},
+		GraphQL: GraphQLConfig{
+			Enabled:       getBoolEnv("GRAPHQL_ENABLED", true),
+			MaxDepth:      getIntEnv("GRAPHQL_MAX_DEPTH", 8),
+			MaxComplexity: getIntEnv("GRAPHQL_MAX_COMPLEXITY", 5000),
+
+			ComplexityPerToken: getIntEnv("GRAPHQL_COMPLEXITY_PER_TOKEN", 100),
+		},
+		Export: ExportConfig{
+			Enabled:           getBoolEnv("EXPORT_ENABLED", true),
+			Directory:         getStringEnv("EXPORT_DIR", "./data/export"),
+			Keep:              getIntEnv("EXPORT_KEEP_GENERATIONS", 2),
+			GenerateOnStartup: getBoolEnv("EXPORT_GENERATE_ON_STARTUP", true),
+		},
+		Webhooks: WebhooksConfig{
+			Enabled:         getBoolEnv("WEBHOOKS_ENABLED", true),
+			Directory:       getStringEnv("WEBHOOKS_DIR", "./data/webhooks"),
+			MaxAttempts:     getIntEnv("WEBHOOKS_MAX_ATTEMPTS", 6),
+			RetryBackoff:    getDurationEnv("WEBHOOKS_RETRY_BACKOFF", 30*time.Second),
+			Timeout:         getDurationEnv("WEBHOOKS_TIMEOUT", 10*time.Second),
+			Workers:         getIntEnv("WEBHOOKS_WORKERS", 4),
+			DeliveryLogSize: getIntEnv("WEBHOOKS_DELIVERY_LOG_SIZE", 1000),
+
+			AllowPrivateNetworks: getBoolEnv("WEBHOOKS_ALLOW_PRIVATE_NETWORKS", false),
+		},
 		KaderPlanung: KaderPlanungConfig{
 			Enabled:       getBoolEnv("KADER_PLANUNG_ENABLED", true),
 			BinaryPath:    getStringEnv("KADER_PLANUNG_BINARY_PATH", "kader-planung/bin/kader-planung.exe"),
@@ -1,6 +1,8 @@
 package database
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"log"
 	"time"
@@ -21,9 +23,9 @@ type Databases struct {
 // Connect establishes connections to all databases
 func Connect(cfg *config.Config) (*Databases, error) {
 	// Configure GORM logger
-	gormLogger := logger.Default.LogMode(logger.Info)
+	gormLogger := newQueryLogger(logger.Info)
 	if cfg.Server.Environment == "production" {
-		gormLogger = logger.Default.LogMode(logger.Error)
+		gormLogger = newQueryLogger(logger.Error)
 	}
 
 	// Connect to MVDSB database
@@ -57,6 +59,11 @@ func connectToDatabase(dbConfig config.DatabaseConnection, dbName string, gormLo
 		return nil, fmt.Errorf("failed to connect to %s: %w", dbName, err)
 	}
 
+	// Trace each statement as part of the request it belongs to
+	if err := registerTracing(db, dbName); err != nil {
+		return nil, fmt.Errorf("failed to register tracing callbacks for %s: %w", dbName, err)
+	}
+
 	// Get underlying sql.DB to configure connection pool
 	sqlDB, err := db.DB()
 	if err != nil {
@@ -77,6 +84,72 @@ func connectToDatabase(dbConfig config.DatabaseConnection, dbName string, gormLo
 	return db, nil
 }
 
+// WithContext returns a copy of the connections bound to a context
+// Queries run through the copy are attributed to the request of the context in the logs.
+func (dbs *Databases) WithContext(ctx context.Context) *Databases {
+	return &Databases{
+		MVDSB:       dbs.MVDSB.WithContext(ctx),
+		Portal64BDW: dbs.Portal64BDW.WithContext(ctx),
+	}
+}
+
+// Snapshot calls fn with connections reading consistent snapshots of both databases
+// The queries of fn run in read-only REPEATABLE READ transactions, so changes committed meanwhile
+// are not seen; each snapshot is taken with the first query of its database.
+func (dbs *Databases) Snapshot(ctx context.Context, fn func(snapshot *Databases) error) error {
+	options := &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	return dbs.MVDSB.WithContext(ctx).Transaction(func(mvdsb *gorm.DB) error {
+		return dbs.Portal64BDW.WithContext(ctx).Transaction(func(portal64BDW *gorm.DB) error {
+			return fn(&Databases{MVDSB: mvdsb, Portal64BDW: portal64BDW})
+		}, options)
+	}, options)
+}
+
+// Names of the databases in statistics and health checks
+const (
+	MVDSBName       = "mvdsb"
+	Portal64BDWName = "portal64_bdw"
+)
+
+// Stats returns the connection pool statistics of each database
+func (dbs *Databases) Stats() map[string]sql.DBStats {
+	stats := make(map[string]sql.DBStats, 2)
+	for name, db := range dbs.connections() {
+		if sqlDB, err := db.DB(); err == nil {
+			stats[name] = sqlDB.Stats()
+		}
+	}
+	return stats
+}
+
+// Ping checks the connectivity of the database with the given name
+func (dbs *Databases) Ping(ctx context.Context, name string) error {
+	db, ok := dbs.connections()[name]
+	if !ok {
+		return fmt.Errorf("database %s is not connected", name)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get underlying sql.DB for %s: %w", name, err)
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// connections returns the open connections by database name
+func (dbs *Databases) connections() map[string]*gorm.DB {
+	connections := make(map[string]*gorm.DB, 2)
+	if dbs == nil {
+		return connections
+	}
+	if dbs.MVDSB != nil {
+		connections[MVDSBName] = dbs.MVDSB
+	}
+	if dbs.Portal64BDW != nil {
+		connections[Portal64BDWName] = dbs.Portal64BDW
+	}
+	return connections
+}
+
 // Close closes all database connections
 func (dbs *Databases) Close() error {
 	var errors []error
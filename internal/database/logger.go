@@ -0,0 +1,86 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"portal64api/internal/logging"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// slowQueryThreshold is the duration above which queries are logged as slow
+const slowQueryThreshold = 200 * time.Millisecond
+
+// queryLogger is a GORM logger writing to the structured application log
+// Each query is counted for the request of its context and logged with the request ID.
+type queryLogger struct {
+	level logger.LogLevel
+}
+
+// newQueryLogger creates a query logger with the given level
+func newQueryLogger(level logger.LogLevel) logger.Interface {
+	return &queryLogger{level: level}
+}
+
+// LogMode returns a copy of the logger with the given level
+func (l *queryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &queryLogger{level: level}
+}
+
+// Info logs a GORM info message
+func (l *queryLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Info {
+		logging.FromContext(ctx).Info(fmt.Sprintf(msg, args...))
+	}
+}
+
+// Warn logs a GORM warning
+func (l *queryLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		logging.FromContext(ctx).Warn(fmt.Sprintf(msg, args...))
+	}
+}
+
+// Error logs a GORM error
+func (l *queryLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Error {
+		logging.FromContext(ctx).Error(fmt.Sprintf(msg, args...))
+	}
+}
+
+// Trace records the duration of a query and logs failed, slow or, at Info level, all queries
+func (l *queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	elapsed := time.Since(begin)
+	logging.RecordDBQuery(ctx, elapsed)
+
+	if l.level <= logger.Silent {
+		return
+	}
+
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+	slow := elapsed > slowQueryThreshold
+	if !(failed && l.level >= logger.Error) && !(slow && l.level >= logger.Warn) && l.level < logger.Info {
+		return
+	}
+
+	sql, rows := fc()
+	entry := logging.FromContext(ctx).WithFields(logrus.Fields{
+		"sql":         sql,
+		"rows":        rows,
+		"duration_ms": float64(elapsed.Microseconds()) / 1000,
+	})
+
+	switch {
+	case failed && l.level >= logger.Error:
+		entry.WithError(err).Error("Database query failed")
+	case slow && l.level >= logger.Warn:
+		entry.Warn("Slow database query")
+	default:
+		entry.Info("Database query")
+	}
+}
@@ -0,0 +1,87 @@
+package database
+
+import (
+	"context"
+	"errors"
+
+	"portal64api/internal/tracing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// Instance keys holding the span of a statement between the before and after callbacks
+const (
+	spanInstanceKey          = "portal64api:span"
+	parentContextInstanceKey = "portal64api:parent_context"
+)
+
+// registerTracing adds GORM callbacks creating a span for each statement run on a database
+// The spans are children of the span in the statement context (set with db.WithContext).
+func registerTracing(db *gorm.DB, dbName string) error {
+	callbacks := db.Callback()
+	errs := []error{
+		callbacks.Create().Before("gorm:create").Register("tracing:before_create", startStatementSpan(dbName, "create")),
+		callbacks.Create().After("gorm:create").Register("tracing:after_create", endStatementSpan),
+		callbacks.Query().Before("gorm:query").Register("tracing:before_query", startStatementSpan(dbName, "query")),
+		callbacks.Query().After("gorm:query").Register("tracing:after_query", endStatementSpan),
+		callbacks.Update().Before("gorm:update").Register("tracing:before_update", startStatementSpan(dbName, "update")),
+		callbacks.Update().After("gorm:update").Register("tracing:after_update", endStatementSpan),
+		callbacks.Delete().Before("gorm:delete").Register("tracing:before_delete", startStatementSpan(dbName, "delete")),
+		callbacks.Delete().After("gorm:delete").Register("tracing:after_delete", endStatementSpan),
+		callbacks.Row().Before("gorm:row").Register("tracing:before_row", startStatementSpan(dbName, "row")),
+		callbacks.Row().After("gorm:row").Register("tracing:after_row", endStatementSpan),
+		callbacks.Raw().Before("gorm:raw").Register("tracing:before_raw", startStatementSpan(dbName, "raw")),
+		callbacks.Raw().After("gorm:raw").Register("tracing:after_raw", endStatementSpan),
+	}
+	return errors.Join(errs...)
+}
+
+// startStatementSpan returns a callback starting the span of a statement
+func startStatementSpan(dbName, operation string) func(*gorm.DB) {
+	return func(tx *gorm.DB) {
+		parent := tx.Statement.Context
+		ctx, span := tracing.Start(parent, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system", "mysql"),
+				attribute.String("db.name", dbName),
+				attribute.String("db.operation", operation),
+			),
+		)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(spanInstanceKey, span)
+		tx.InstanceSet(parentContextInstanceKey, parent)
+	}
+}
+
+// endStatementSpan ends the span of a statement, recording the SQL and the outcome
+func endStatementSpan(tx *gorm.DB) {
+	value, ok := tx.InstanceGet(spanInstanceKey)
+	if !ok {
+		return
+	}
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+
+	span.SetAttributes(
+		attribute.String("db.statement", tx.Statement.SQL.String()),
+		attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+	)
+	if tx.Statement.Table != "" {
+		span.SetAttributes(attribute.String("db.sql.table", tx.Statement.Table))
+	}
+	if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+		tracing.RecordError(span, tx.Error)
+	}
+	span.End()
+
+	if parent, ok := tx.InstanceGet(parentContextInstanceKey); ok {
+		if ctx, ok := parent.(context.Context); ok {
+			tx.Statement.Context = ctx
+		}
+	}
+}
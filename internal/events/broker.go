@@ -0,0 +1,92 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// Event types shared by the progress streams
+const (
+	TypeStatus    = "status"    // Full current status
+	TypeLog       = "log"       // Log entry
+	TypeCompleted = "completed" // A run finished
+)
+
+// DefaultBuffer is the number of events a subscriber may fall behind before it is dropped
+const DefaultBuffer = 256
+
+// Event is a progress event sent to subscribers
+type Event struct {
+	ID   uint64      // Sequence number, increasing per broker
+	Type string      // Event type, e.g. "status" or "log"
+	Time time.Time   // Time of publishing
+	Data interface{} // Payload, encoded as JSON
+}
+
+// Broker fans out events to subscribers without blocking the publisher
+// A subscriber that falls more than its buffer behind is dropped by closing its channel,
+// so it can reconnect and start over from the current state instead of missing events silently.
+type Broker struct {
+	mutex       sync.Mutex
+	subscribers map[chan Event]struct{}
+	buffer      int
+	lastID      uint64
+}
+
+// NewBroker creates a new broker with the given subscriber buffer
+func NewBroker(buffer int) *Broker {
+	if buffer <= 0 {
+		buffer = DefaultBuffer
+	}
+	return &Broker{
+		subscribers: make(map[chan Event]struct{}),
+		buffer:      buffer,
+	}
+}
+
+// Publish sends an event to all subscribers
+func (b *Broker) Publish(eventType string, data interface{}) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.lastID++
+	event := Event{ID: b.lastID, Type: eventType, Time: time.Now(), Data: data}
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			delete(b.subscribers, ch)
+			close(ch)
+		}
+	}
+}
+
+// Subscribe registers a subscriber, the returned function unsubscribes it
+// The channel is closed when the subscriber is dropped or unsubscribed.
+func (b *Broker) Subscribe() (<-chan Event, func()) {
+	ch := make(chan Event, b.buffer)
+
+	b.mutex.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mutex.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mutex.Lock()
+			defer b.mutex.Unlock()
+			if _, ok := b.subscribers[ch]; ok {
+				delete(b.subscribers, ch)
+				close(ch)
+			}
+		})
+	}
+	return ch, unsubscribe
+}
+
+// Subscribers returns the number of subscribers
+func (b *Broker) Subscribers() int {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	return len(b.subscribers)
+}
@@ -0,0 +1,50 @@
+package graphql
+
+import (
+	"net/http"
+
+	"portal64api/internal/models"
+	"portal64api/pkg/errors"
+)
+
+// fieldError is the error of a field, reported with the status and error code a REST lookup would return
+type fieldError struct {
+	message   string
+	code      int
+	errorCode string
+}
+
+// Error returns the message of the error
+func (e fieldError) Error() string {
+	return e.message
+}
+
+// Extensions adds the status and error code to the error in the response
+func (e fieldError) Extensions() map[string]interface{} {
+	return map[string]interface{}{"code": e.code, "error_code": e.errorCode}
+}
+
+// resolveError converts a service error into a field error
+func resolveError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if apiErr, ok := err.(errors.APIError); ok {
+		return fieldError{message: apiErr.Message, code: apiErr.Code, errorCode: string(apiErr.ErrorCode)}
+	}
+	return fieldError{message: "Internal server error", code: http.StatusInternalServerError, errorCode: string(errors.CodeInternal)}
+}
+
+// batchError converts the error of a batch result into a field error
+func batchError(err *models.BatchError) error {
+	if err == nil {
+		return nil
+	}
+	return fieldError{message: err.Message, code: err.Code, errorCode: err.ErrorCode}
+}
+
+// isNotFound reports whether a field error is a not found error
+func isNotFound(err error) bool {
+	fieldErr, ok := err.(fieldError)
+	return ok && fieldErr.code == http.StatusNotFound
+}
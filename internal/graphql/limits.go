@@ -0,0 +1,186 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// defaultListSize is the estimated length of list fields without a limit
+const defaultListSize = 25
+
+// Limits bound the cost of a query; queries exceeding them are rejected before execution
+type Limits struct {
+	MaxDepth           int // Maximum nesting of selections, 0 for no limit
+	MaxComplexity      int // Maximum estimated number of resolved fields, 0 for no limit
+	ComplexityPerToken int // Complexity paid by one rate limit token, 0 to charge one token per query
+}
+
+// tokens returns the rate limit tokens of a query, at least one
+func (l Limits) tokens(complexity int) int {
+	if l.ComplexityPerToken <= 0 || complexity <= l.ComplexityPerToken {
+		return 1
+	}
+	return (complexity + l.ComplexityPerToken - 1) / l.ComplexityPerToken
+}
+
+// costWalker estimates depth and complexity of an operation
+// Each field costs 1 plus the cost of its selections, multiplied by the estimated length for
+// list fields: the limit argument (or its default), the number of ids, or defaultListSize.
+// Introspection fields are free, so tools can load the schema.
+type costWalker struct {
+	schema    *graphql.Schema
+	fragments map[string]*ast.FragmentDefinition
+	variables map[string]interface{}
+	visiting  map[string]bool
+}
+
+// checkLimits returns the complexity of an operation of a validated document, or an error if the
+// operation exceeds the limits
+func checkLimits(schema *graphql.Schema, doc *ast.Document, operation *ast.OperationDefinition, variables map[string]interface{}, limits Limits) (int, error) {
+	w := &costWalker{
+		schema:    schema,
+		fragments: make(map[string]*ast.FragmentDefinition),
+		variables: variables,
+		visiting:  make(map[string]bool),
+	}
+	for _, definition := range doc.Definitions {
+		if fragment, ok := definition.(*ast.FragmentDefinition); ok {
+			w.fragments[fragment.Name.Value] = fragment
+		}
+	}
+
+	depth, complexity := w.selectionSet(schema.QueryType(), operation.SelectionSet, 1)
+	if limits.MaxDepth > 0 && depth > limits.MaxDepth {
+		return 0, fmt.Errorf("Query depth %d exceeds the maximum of %d", depth, limits.MaxDepth)
+	}
+	if limits.MaxComplexity > 0 && complexity > limits.MaxComplexity {
+		return 0, fmt.Errorf("Query complexity %d exceeds the maximum of %d", complexity, limits.MaxComplexity)
+	}
+	return complexity, nil
+}
+
+// selectionSet returns the depth of the deepest field and the cost of a selection set
+func (w *costWalker) selectionSet(parent *graphql.Object, set *ast.SelectionSet, depth int) (int, int) {
+	if parent == nil || set == nil {
+		return 0, 0
+	}
+
+	maxDepth, cost := 0, 0
+	merge := func(d, c int) {
+		if d > maxDepth {
+			maxDepth = d
+		}
+		cost += c
+	}
+
+	for _, selection := range set.Selections {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			name := selection.Name.Value
+			if strings.HasPrefix(name, "__") {
+				continue
+			}
+			definition, ok := parent.Fields()[name]
+			if !ok {
+				continue
+			}
+
+			fieldDepth, childCost := depth, 0
+			if selection.SelectionSet != nil {
+				child, _ := graphql.GetNamed(definition.Type).(*graphql.Object)
+				if d, c := w.selectionSet(child, selection.SelectionSet, depth+1); d > 0 {
+					fieldDepth, childCost = d, c
+				}
+			}
+			merge(fieldDepth, 1+w.listSize(definition, selection)*childCost)
+
+		case *ast.InlineFragment:
+			fragmentType := parent
+			if selection.TypeCondition != nil {
+				fragmentType, _ = w.schema.Type(selection.TypeCondition.Name.Value).(*graphql.Object)
+			}
+			merge(w.selectionSet(fragmentType, selection.SelectionSet, depth))
+
+		case *ast.FragmentSpread:
+			name := selection.Name.Value
+			fragment, ok := w.fragments[name]
+			if !ok || w.visiting[name] {
+				continue
+			}
+			fragmentType, _ := w.schema.Type(fragment.TypeCondition.Name.Value).(*graphql.Object)
+			w.visiting[name] = true
+			merge(w.selectionSet(fragmentType, fragment.SelectionSet, depth))
+			w.visiting[name] = false
+		}
+	}
+	return maxDepth, cost
+}
+
+// listSize estimates the number of elements a field resolves to, 1 for fields that are no list
+func (w *costWalker) listSize(definition *graphql.FieldDefinition, field *ast.Field) int {
+	if !isList(definition.Type) {
+		return 1
+	}
+
+	for _, argument := range field.Arguments {
+		switch argument.Name.Value {
+		case "limit":
+			if limit, ok := w.intValue(argument.Value); ok && limit > 0 {
+				return limit
+			}
+		case "ids":
+			if ids, ok := w.listLength(argument.Value); ok {
+				return ids
+			}
+		}
+	}
+	for _, argument := range definition.Args {
+		if limit, ok := argument.DefaultValue.(int); ok && argument.Name() == "limit" && limit > 0 {
+			return limit
+		}
+	}
+	return defaultListSize
+}
+
+// intValue returns the value of an Int literal or variable
+func (w *costWalker) intValue(value ast.Value) (int, bool) {
+	switch value := value.(type) {
+	case *ast.IntValue:
+		n, err := strconv.Atoi(value.Value)
+		return n, err == nil
+	case *ast.Variable:
+		switch n := w.variables[value.Name.Value].(type) {
+		case int:
+			return n, true
+		case float64: // Variables decoded from JSON
+			return int(n), true
+		}
+	}
+	return 0, false
+}
+
+// listLength returns the length of a list literal or variable
+func (w *costWalker) listLength(value ast.Value) (int, bool) {
+	switch value := value.(type) {
+	case *ast.ListValue:
+		return len(value.Values), true
+	case *ast.Variable:
+		if list, ok := w.variables[value.Name.Value].([]interface{}); ok {
+			return len(list), true
+		}
+	}
+	return 0, false
+}
+
+// isList reports whether a field type is a list, ignoring non-null wrappers
+func isList(t graphql.Type) bool {
+	if nonNull, ok := t.(*graphql.NonNull); ok {
+		t = nonNull.OfType
+	}
+	_, ok := t.(*graphql.List)
+	return ok
+}
@@ -0,0 +1,180 @@
+package graphql
+
+import (
+	"context"
+	"sync"
+
+	"portal64api/internal/models"
+	"portal64api/internal/services"
+)
+
+// loadResult is the value or error loaded for one ID
+type loadResult struct {
+	value interface{}
+	err   error
+}
+
+// batchFunc loads several IDs at once and returns a result per ID
+type batchFunc func(ids []string) (map[string]loadResult, error)
+
+// loader batches the lookups of one resource type within a request, dataloader style
+// Resolvers register IDs with load and return a thunk. The executor resolves thunks breadth
+// first, so all IDs of a level are registered before the first thunk loads them in one batch.
+type loader struct {
+	mu      sync.Mutex
+	fetch   batchFunc
+	pending []string
+	queued  map[string]bool
+	results map[string]loadResult
+}
+
+func newLoader(fetch batchFunc) *loader {
+	return &loader{fetch: fetch, queued: make(map[string]bool), results: make(map[string]loadResult)}
+}
+
+// load registers an ID and returns a thunk resolving to its value
+func (l *loader) load(id string) func() (interface{}, error) {
+	l.mu.Lock()
+	if _, loaded := l.results[id]; !loaded && !l.queued[id] {
+		l.queued[id] = true
+		l.pending = append(l.pending, id)
+	}
+	l.mu.Unlock()
+
+	return func() (interface{}, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if _, loaded := l.results[id]; !loaded {
+			l.dispatch()
+		}
+		result := l.results[id]
+		return result.value, result.err
+	}
+}
+
+// dispatch loads all pending IDs in batches of at most models.MaxBatchIDs
+func (l *loader) dispatch() {
+	pending := l.pending
+	l.pending = nil
+	for start := 0; start < len(pending); start += models.MaxBatchIDs {
+		end := start + models.MaxBatchIDs
+		if end > len(pending) {
+			end = len(pending)
+		}
+		ids := pending[start:end]
+
+		results, err := l.fetch(ids)
+		for _, id := range ids {
+			delete(l.queued, id)
+			if err != nil {
+				l.results[id] = loadResult{err: resolveError(err)}
+				continue
+			}
+			l.results[id] = results[id]
+		}
+	}
+}
+
+// loaders holds the loaders and services of a request
+type loaders struct {
+	services    Services
+	players     *loader
+	clubs       *loader
+	histories   *loader
+	tournaments *loader
+	rosters     *loader
+}
+
+// newLoaders creates the loaders of a request backed by the batch lookups of the services
+func newLoaders(ctx context.Context, svc Services) *loaders {
+	svc = svc.withContext(ctx)
+	return &loaders{
+		services: svc,
+		players: newLoader(func(ids []string) (map[string]loadResult, error) {
+			results, err := svc.Players.GetPlayersByIDs(ids)
+			if err != nil {
+				return nil, err
+			}
+			loaded := make(map[string]loadResult, len(results))
+			for _, result := range results {
+				loaded[result.ID] = loadResult{value: result.Data, err: batchError(result.Error)}
+			}
+			return loaded, nil
+		}),
+		clubs: newLoader(func(ids []string) (map[string]loadResult, error) {
+			results, err := svc.Clubs.GetClubsByIDs(ids)
+			if err != nil {
+				return nil, err
+			}
+			loaded := make(map[string]loadResult, len(results))
+			for _, result := range results {
+				loaded[result.ID] = loadResult{value: result.Data, err: batchError(result.Error)}
+			}
+			return loaded, nil
+		}),
+		histories: newLoader(func(ids []string) (map[string]loadResult, error) {
+			results, err := svc.Players.GetPlayersRatingHistories(ids)
+			if err != nil {
+				return nil, err
+			}
+			loaded := make(map[string]loadResult, len(results))
+			for _, result := range results {
+				loaded[result.ID] = loadResult{value: result.Data, err: batchError(result.Error)}
+			}
+			return loaded, nil
+		}),
+		tournaments: newLoader(func(ids []string) (map[string]loadResult, error) {
+			results, err := svc.Tournaments.GetTournamentsByIDs(ids)
+			if err != nil {
+				return nil, err
+			}
+			loaded := make(map[string]loadResult, len(results))
+			for _, result := range results {
+				loaded[result.ID] = loadResult{value: result.Data, err: batchError(result.Error)}
+			}
+			return loaded, nil
+		}),
+		rosters: newLoader(func(ids []string) (map[string]loadResult, error) {
+			results, err := svc.Players.GetClubsPlayers(ids)
+			if err != nil {
+				return nil, err
+			}
+			loaded := make(map[string]loadResult, len(results))
+			for _, result := range results {
+				loaded[result.ID] = loadResult{value: result.Data, err: batchError(result.Error)}
+			}
+			return loaded, nil
+		}),
+	}
+}
+
+type loadersKey struct{}
+
+// withLoaders returns a context carrying the loaders of a request
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// loadersFrom returns the loaders of the request a resolver runs in
+func loadersFrom(ctx context.Context) *loaders {
+	l, _ := ctx.Value(loadersKey{}).(*loaders)
+	return l
+}
+
+// Services are the services the schema is resolved with
+type Services struct {
+	Players     *services.PlayerService
+	Clubs       *services.ClubService
+	Tournaments *services.TournamentService
+	Addresses   *services.AddressService
+}
+
+// withContext returns the services handling a request with the given context
+func (s Services) withContext(ctx context.Context) Services {
+	return Services{
+		Players:     s.Players.WithContext(ctx),
+		Clubs:       s.Clubs.WithContext(ctx),
+		Tournaments: s.Tournaments.WithContext(ctx),
+		Addresses:   s.Addresses.WithContext(ctx),
+	}
+}
@@ -0,0 +1,225 @@
+package graphql
+
+import (
+	"portal64api/internal/models"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Lookups by ID go through the loaders of the request, so the lookups of a level of the query
+// are batched. Related resources that do not exist resolve to null instead of failing.
+
+func resolvePlayer(p graphql.ResolveParams) (interface{}, error) {
+	id, _ := p.Args["id"].(string)
+	return loadersFrom(p.Context).players.load(id), nil
+}
+
+func resolvePlayers(p graphql.ResolveParams) (interface{}, error) {
+	ids, err := idsArg(p)
+	if err != nil {
+		return nil, err
+	}
+	l := loadersFrom(p.Context)
+	players := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		players = append(players, optional(l.players.load(id)))
+	}
+	return players, nil
+}
+
+func resolveSearchPlayers(p graphql.ResolveParams) (interface{}, error) {
+	req, err := searchRequest(p, "name", "asc")
+	if err != nil {
+		return nil, err
+	}
+	active, _ := p.Args["active"].(bool)
+	players, _, err := loadersFrom(p.Context).services.Players.SearchPlayers(req, active)
+	return players, resolveError(err)
+}
+
+func resolvePlayerClub(p graphql.ResolveParams) (interface{}, error) {
+	player := asPlayer(p.Source)
+	if player == nil || player.ClubID == "" {
+		return nil, nil
+	}
+	return optional(loadersFrom(p.Context).clubs.load(player.ClubID)), nil
+}
+
+func resolvePlayerRatingHistory(p graphql.ResolveParams) (interface{}, error) {
+	player := asPlayer(p.Source)
+	if player == nil {
+		return nil, nil
+	}
+	limit, err := limitArg(p, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	load := loadersFrom(p.Context).histories.load(player.ID)
+	return func() (interface{}, error) {
+		value, err := load()
+		if err != nil {
+			return nil, err
+		}
+		history, _ := value.([]models.RatingHistoryResponse)
+		if limit > 0 && len(history) > limit {
+			history = history[:limit]
+		}
+		return history, nil
+	}, nil
+}
+
+func resolveClub(p graphql.ResolveParams) (interface{}, error) {
+	id, _ := p.Args["id"].(string)
+	return loadersFrom(p.Context).clubs.load(id), nil
+}
+
+func resolveClubs(p graphql.ResolveParams) (interface{}, error) {
+	ids, err := idsArg(p)
+	if err != nil {
+		return nil, err
+	}
+	l := loadersFrom(p.Context)
+	clubs := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		clubs = append(clubs, optional(l.clubs.load(id)))
+	}
+	return clubs, nil
+}
+
+func resolveSearchClubs(p graphql.ResolveParams) (interface{}, error) {
+	req, err := searchRequest(p, "name", "asc")
+	if err != nil {
+		return nil, err
+	}
+	clubs, _, err := loadersFrom(p.Context).services.Clubs.SearchClubs(req)
+	return clubs, resolveError(err)
+}
+
+func resolveClubPlayers(p graphql.ResolveParams) (interface{}, error) {
+	club := asClub(p.Source)
+	if club == nil {
+		return nil, nil
+	}
+	req, err := searchRequest(p, "current_dwz", "desc")
+	if err != nil {
+		return nil, err
+	}
+
+	// The rosters of all clubs of a level are loaded in one batch and paginated here. They only
+	// hold players with a current membership, so active=false returns the same players.
+	load := loadersFrom(p.Context).rosters.load(club.ID)
+	return func() (interface{}, error) {
+		value, err := load()
+		if err != nil {
+			return nil, err
+		}
+		players, _ := value.([]models.PlayerResponse)
+		if req.Offset >= len(players) {
+			return []models.PlayerResponse{}, nil
+		}
+		players = players[req.Offset:]
+		if len(players) > req.Limit {
+			players = players[:req.Limit]
+		}
+		return players, nil
+	}, nil
+}
+
+func resolveTournament(p graphql.ResolveParams) (interface{}, error) {
+	id, _ := p.Args["id"].(string)
+	return loadersFrom(p.Context).tournaments.load(id), nil
+}
+
+func resolveSearchTournaments(p graphql.ResolveParams) (interface{}, error) {
+	req, err := searchRequest(p, "finishedOn", "desc")
+	if err != nil {
+		return nil, err
+	}
+	tournaments, _, err := loadersFrom(p.Context).services.Tournaments.SearchTournaments(req, models.TournamentSearchFilter{})
+	return tournaments, resolveError(err)
+}
+
+func resolveRecentTournaments(p graphql.ResolveParams) (interface{}, error) {
+	limit, err := limitArg(p, defaultLimit)
+	if err != nil {
+		return nil, err
+	}
+	days, _ := p.Args["days"].(int)
+	tournaments, err := loadersFrom(p.Context).services.Tournaments.GetRecentTournaments(days, limit, models.TournamentSearchFilter{})
+	return tournaments, resolveError(err)
+}
+
+func resolveRatingHistoryTournament(p graphql.ResolveParams) (interface{}, error) {
+	var tournamentID string
+	switch entry := p.Source.(type) {
+	case models.RatingHistoryResponse:
+		tournamentID = entry.TournamentID
+	case *models.RatingHistoryResponse:
+		tournamentID = entry.TournamentID
+	}
+	if tournamentID == "" {
+		return nil, nil
+	}
+	return optional(loadersFrom(p.Context).tournaments.load(tournamentID)), nil
+}
+
+func resolveAddressRegions(p graphql.ResolveParams) (interface{}, error) {
+	regions, err := loadersFrom(p.Context).services.Addresses.GetAvailableRegions()
+	return regions, resolveError(err)
+}
+
+func resolveAddresses(p graphql.ResolveParams) (interface{}, error) {
+	region, _ := p.Args["region"].(string)
+	addressType, _ := p.Args["type"].(string)
+	addresses, err := loadersFrom(p.Context).services.Addresses.GetRegionAddresses(region, addressType)
+	return addresses, resolveError(err)
+}
+
+func resolveAddressClub(p graphql.ResolveParams) (interface{}, error) {
+	var address models.RegionAddressResponse
+	switch source := p.Source.(type) {
+	case models.RegionAddressResponse:
+		address = source
+	case *models.RegionAddressResponse:
+		address = *source
+	}
+	if address.OrganisationLevel != "club" || address.OrganisationVKZ == "" {
+		return nil, nil
+	}
+	return optional(loadersFrom(p.Context).clubs.load(address.OrganisationVKZ)), nil
+}
+
+// optional resolves resources that do not exist to null instead of an error
+func optional(load func() (interface{}, error)) func() (interface{}, error) {
+	return func() (interface{}, error) {
+		value, err := load()
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return value, err
+	}
+}
+
+// asPlayer returns the player a field is resolved on
+// Players loaded by ID are pointers, players of lists are values.
+func asPlayer(source interface{}) *models.PlayerResponse {
+	switch player := source.(type) {
+	case *models.PlayerResponse:
+		return player
+	case models.PlayerResponse:
+		return &player
+	}
+	return nil
+}
+
+// asClub returns the club a field is resolved on
+func asClub(source interface{}) *models.ClubResponse {
+	switch club := source.(type) {
+	case *models.ClubResponse:
+		return club
+	case models.ClubResponse:
+		return &club
+	}
+	return nil
+}
@@ -0,0 +1,253 @@
+package graphql
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"portal64api/internal/models"
+	"portal64api/pkg/errors"
+
+	"github.com/graphql-go/graphql"
+)
+
+// Limits of list fields, matching the REST endpoints
+const (
+	defaultLimit = 20
+	maxLimit     = 500
+)
+
+// schemaBuilder generates the object types of the schema from the response models
+type schemaBuilder struct {
+	objects map[reflect.Type]*graphql.Object
+}
+
+// object returns the object type of a response model, named after the model without the
+// Response suffix. Its fields are the json tagged fields of the model, resolved by the default
+// resolver which reads struct fields by their json tag. related adds fields resolving related
+// resources; it is called lazily, so related types may refer to each other.
+func (b *schemaBuilder) object(model interface{}, description string, related func() graphql.Fields) *graphql.Object {
+	t := reflect.TypeOf(model)
+	if object, exists := b.objects[t]; exists {
+		return object
+	}
+
+	object := graphql.NewObject(graphql.ObjectConfig{
+		Name:        strings.TrimSuffix(t.Name(), "Response"),
+		Description: description,
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			fields := b.modelFields(t)
+			if related != nil {
+				for name, field := range related() {
+					fields[name] = field
+				}
+			}
+			return fields
+		}),
+	})
+	b.objects[t] = object
+	return object
+}
+
+// modelFields generates the fields of a response model
+func (b *schemaBuilder) modelFields(t reflect.Type) graphql.Fields {
+	fields := graphql.Fields{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" || name == "-" || !field.IsExported() {
+			continue
+		}
+		if output := b.outputType(field.Type); output != nil {
+			fields[name] = &graphql.Field{Type: output}
+		}
+	}
+	return fields
+}
+
+// outputType maps a Go type to a GraphQL type
+// Pointers and slices are nullable, other values are not. Unsupported types return nil.
+func (b *schemaBuilder) outputType(t reflect.Type) graphql.Output {
+	if t.Kind() == reflect.Ptr {
+		output := b.outputType(t.Elem())
+		if nonNull, ok := output.(*graphql.NonNull); ok {
+			return nonNull.OfType
+		}
+		return output
+	}
+	if t == reflect.TypeOf(time.Time{}) {
+		return graphql.NewNonNull(graphql.DateTime)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return graphql.NewNonNull(graphql.String)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return graphql.NewNonNull(graphql.Int)
+	case reflect.Float32, reflect.Float64:
+		return graphql.NewNonNull(graphql.Float)
+	case reflect.Bool:
+		return graphql.NewNonNull(graphql.Boolean)
+	case reflect.Slice:
+		if elem := b.outputType(t.Elem()); elem != nil {
+			return graphql.NewList(elem)
+		}
+	case reflect.Struct:
+		return graphql.NewNonNull(b.object(reflect.New(t).Elem().Interface(), "", nil))
+	}
+	return nil
+}
+
+// newSchema builds the read-only schema over players, clubs, tournaments and addresses
+func newSchema() (graphql.Schema, error) {
+	b := &schemaBuilder{objects: make(map[reflect.Type]*graphql.Object)}
+
+	var player, club, ratingHistory, tournament, address *graphql.Object
+	player = b.object(models.PlayerResponse{}, "A player, identified by club and membership number (C0101-1014)", func() graphql.Fields {
+		return graphql.Fields{
+			"current_club": {
+				Type:        club,
+				Description: "The club of the player ID",
+				Resolve:     resolvePlayerClub,
+			},
+			"rating_history": {
+				Type:        graphql.NewList(graphql.NewNonNull(ratingHistory)),
+				Description: "DWZ evaluations, most recent first",
+				Args: graphql.FieldConfigArgument{
+					"limit": {Type: graphql.Int, Description: "Number of most recent evaluations to return"},
+				},
+				Resolve: resolvePlayerRatingHistory,
+			},
+		}
+	})
+	club = b.object(models.ClubResponse{}, "A club, identified by its VKZ (C0101)", func() graphql.Fields {
+		return graphql.Fields{
+			"players": {
+				Type:        graphql.NewList(graphql.NewNonNull(player)),
+				Description: "Players of the club, highest DWZ first",
+				Args: graphql.FieldConfigArgument{
+					"limit":  {Type: graphql.Int, DefaultValue: 100},
+					"offset": {Type: graphql.Int, DefaultValue: 0},
+					"active": {Type: graphql.Boolean, DefaultValue: true, Description: "Kept for compatibility, rosters only hold players with a current membership"},
+				},
+				Resolve: resolveClubPlayers,
+			},
+		}
+	})
+	tournament = b.object(models.TournamentResponse{}, "A tournament, identified by its code (C529-K00-HT1)", nil)
+	ratingHistory = b.object(models.RatingHistoryResponse{}, "A DWZ evaluation of a player in a tournament", func() graphql.Fields {
+		return graphql.Fields{
+			"tournament": {
+				Type:    tournament,
+				Resolve: resolveRatingHistoryTournament,
+			},
+		}
+	})
+	address = b.object(models.RegionAddressResponse{}, "An official of a club or federation", func() graphql.Fields {
+		return graphql.Fields{
+			"club": {
+				Type:        club,
+				Description: "The club of club officials",
+				Resolve:     resolveAddressClub,
+			},
+		}
+	})
+	region := b.object(models.RegionInfo{}, "A region with address information", nil)
+
+	searchArgs := func() graphql.FieldConfigArgument {
+		return graphql.FieldConfigArgument{
+			"query":  {Type: graphql.String, DefaultValue: ""},
+			"limit":  {Type: graphql.Int, DefaultValue: defaultLimit},
+			"offset": {Type: graphql.Int, DefaultValue: 0},
+		}
+	}
+	idArgs := graphql.FieldConfigArgument{
+		"id": {Type: graphql.NewNonNull(graphql.String)},
+	}
+	idsArgs := graphql.FieldConfigArgument{
+		"ids": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(graphql.String)))},
+	}
+
+	playerSearchArgs := searchArgs()
+	playerSearchArgs["active"] = &graphql.ArgumentConfig{Type: graphql.Boolean, DefaultValue: true}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"player":         {Type: player, Args: idArgs, Resolve: resolvePlayer},
+			"players":        {Type: graphql.NewList(player), Args: idsArgs, Resolve: resolvePlayers, Description: "Players by ID, null for IDs that are not found"},
+			"search_players": {Type: graphql.NewList(graphql.NewNonNull(player)), Args: playerSearchArgs, Resolve: resolveSearchPlayers},
+			"club":           {Type: club, Args: idArgs, Resolve: resolveClub},
+			"clubs":          {Type: graphql.NewList(club), Args: idsArgs, Resolve: resolveClubs, Description: "Clubs by ID, null for IDs that are not found"},
+			"search_clubs":   {Type: graphql.NewList(graphql.NewNonNull(club)), Args: searchArgs(), Resolve: resolveSearchClubs},
+			"tournament":     {Type: tournament, Args: idArgs, Resolve: resolveTournament},
+			"search_tournaments": {
+				Type:    graphql.NewList(graphql.NewNonNull(tournament)),
+				Args:    searchArgs(),
+				Resolve: resolveSearchTournaments,
+			},
+			"recent_tournaments": {
+				Type: graphql.NewList(graphql.NewNonNull(tournament)),
+				Args: graphql.FieldConfigArgument{
+					"days":  {Type: graphql.Int, DefaultValue: 30},
+					"limit": {Type: graphql.Int, DefaultValue: defaultLimit},
+				},
+				Resolve: resolveRecentTournaments,
+			},
+			"address_regions": {Type: graphql.NewList(graphql.NewNonNull(region)), Resolve: resolveAddressRegions},
+			"addresses": {
+				Type: graphql.NewList(graphql.NewNonNull(address)),
+				Args: graphql.FieldConfigArgument{
+					"region": {Type: graphql.NewNonNull(graphql.String), Description: "Region code, e.g. C"},
+					"type":   {Type: graphql.String, DefaultValue: "", Description: "Function, e.g. Präsident"},
+				},
+				Resolve: resolveAddresses,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query})
+}
+
+// searchRequest builds the search request of a list field, validating limit and offset like the REST endpoints
+func searchRequest(p graphql.ResolveParams, sortBy, sortOrder string) (models.SearchRequest, error) {
+	query, _ := p.Args["query"].(string)
+	offset, _ := p.Args["offset"].(int)
+	limit, err := limitArg(p, defaultLimit)
+	if err != nil {
+		return models.SearchRequest{}, err
+	}
+	if offset < 0 {
+		return models.SearchRequest{}, resolveError(errors.NewBadRequestError("Offset cannot be negative"))
+	}
+	return models.SearchRequest{Query: query, Limit: limit, Offset: offset, SortBy: sortBy, SortOrder: sortOrder}, nil
+}
+
+// limitArg returns the limit argument of a list field, or fallback if it is not set
+func limitArg(p graphql.ResolveParams, fallback int) (int, error) {
+	limit, ok := p.Args["limit"].(int)
+	if !ok {
+		return fallback, nil
+	}
+	if limit < 1 || limit > maxLimit {
+		return 0, resolveError(errors.NewBadRequestError(fmt.Sprintf("Limit must be between 1 and %d", maxLimit)))
+	}
+	return limit, nil
+}
+
+// idsArg returns the ids argument of a batch field
+func idsArg(p graphql.ResolveParams) ([]string, error) {
+	values, _ := p.Args["ids"].([]interface{})
+	if len(values) > models.MaxBatchIDs {
+		return nil, resolveError(errors.NewBadRequestError(fmt.Sprintf("Too many IDs (maximum: %d)", models.MaxBatchIDs)))
+	}
+	ids := make([]string, 0, len(values))
+	for _, value := range values {
+		if id, ok := value.(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,163 @@
+// Package graphql provides a read-only GraphQL API over players, clubs, tournaments and addresses.
+// The object types are generated from the REST response models and resolved with the services,
+// lookups by ID are batched per request.
+package graphql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Request is a GraphQL request as sent by clients
+type Request struct {
+	Query         string                 `json:"query" form:"query" example:"{ club(id: \"C0101\") { name players(limit: 5) { name current_dwz } } }"`
+	OperationName string                 `json:"operationName" form:"operationName"`
+	Variables     map[string]interface{} `json:"variables"`
+}
+
+// Schema is the read-only GraphQL schema, built once at startup
+type Schema struct {
+	schema graphql.Schema
+}
+
+// NewSchema builds the schema from the REST response models
+// An error means the models cannot be mapped to GraphQL types, the server should not start.
+func NewSchema() (*Schema, error) {
+	schema, err := newSchema()
+	if err != nil {
+		return nil, err
+	}
+	return &Schema{schema: schema}, nil
+}
+
+// Server executes GraphQL requests against the schema
+type Server struct {
+	schema   graphql.Schema
+	services Services
+	limits   Limits
+}
+
+// NewServer creates a server resolving the schema with the given services
+func NewServer(schema *Schema, svc Services, limits Limits) *Server {
+	return &Server{schema: schema.schema, services: svc, limits: limits}
+}
+
+// Operation is a request that passed validation and the limits, ready to be executed
+type Operation struct {
+	Complexity int // Estimated number of resolved fields
+	Tokens     int // Rate limit tokens the operation costs, see Limits.ComplexityPerToken
+
+	request Request
+	doc     *ast.Document
+}
+
+// Prepare parses and validates a request and checks it against the limits
+// Requests that cannot be parsed, are invalid for the schema or exceed the limits are not
+// prepared; the result only carries their errors.
+func (s *Server) Prepare(req Request) (*Operation, *graphql.Result) {
+	doc, err := parser.Parse(parser.ParseParams{
+		Source: source.NewSource(&source.Source{Body: []byte(req.Query), Name: "GraphQL request"}),
+	})
+	if err != nil {
+		return nil, &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	validation := graphql.ValidateDocument(&s.schema, doc, nil)
+	if !validation.IsValid {
+		return nil, &graphql.Result{Errors: validation.Errors}
+	}
+
+	operation, err := selectOperation(doc, req.OperationName)
+	if err != nil {
+		return nil, &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+	complexity, err := checkLimits(&s.schema, doc, operation, req.Variables, s.limits)
+	if err != nil {
+		return nil, &graphql.Result{Errors: gqlerrors.FormatErrors(err)}
+	}
+
+	return &Operation{Complexity: complexity, Tokens: s.limits.tokens(complexity), request: req, doc: doc}, nil
+}
+
+// Run executes a prepared operation
+func (s *Server) Run(ctx context.Context, operation *Operation) *graphql.Result {
+	result := graphql.Execute(graphql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           operation.doc,
+		OperationName: operation.request.OperationName,
+		Args:          operation.request.Variables,
+		Context:       withLoaders(ctx, newLoaders(ctx, s.services)),
+	})
+	restoreExtensions(result.Errors)
+	return result
+}
+
+// Execute prepares and runs a request and reports whether it was executed
+// The result of a request that is not executed only carries errors, see Prepare.
+func (s *Server) Execute(ctx context.Context, req Request) (*graphql.Result, bool) {
+	operation, invalid := s.Prepare(req)
+	if invalid != nil {
+		return invalid, false
+	}
+	return s.Run(ctx, operation), true
+}
+
+// selectOperation returns the operation of a document to execute
+// Only queries are supported, the API is read-only.
+func selectOperation(doc *ast.Document, operationName string) (*ast.OperationDefinition, error) {
+	var operation *ast.OperationDefinition
+	for _, definition := range doc.Definitions {
+		definition, ok := definition.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+		if operationName == "" && operation != nil {
+			return nil, fmt.Errorf("Must provide operation name if query contains multiple operations")
+		}
+		if operationName == "" || (definition.Name != nil && definition.Name.Value == operationName) {
+			operation = definition
+		}
+	}
+
+	if operation == nil {
+		return nil, fmt.Errorf("Unknown operation named \"%s\"", operationName)
+	}
+	if operation.Operation != ast.OperationTypeQuery {
+		return nil, fmt.Errorf("Only queries are supported, the API is read-only")
+	}
+	return operation, nil
+}
+
+// restoreExtensions adds the status codes of field errors to the response errors
+// Errors of deferred (batched) resolvers lose their extensions in the executor; they are
+// recovered from the original error.
+func restoreExtensions(errs []gqlerrors.FormattedError) {
+	for i := range errs {
+		if errs[i].Extensions == nil {
+			errs[i].Extensions = extensionsOf(errs[i].OriginalError())
+		}
+	}
+}
+
+// extensionsOf returns the extensions of the error wrapped by executor errors
+func extensionsOf(err error) map[string]interface{} {
+	for err != nil {
+		switch e := err.(type) {
+		case gqlerrors.ExtendedError:
+			return e.Extensions()
+		case gqlerrors.FormattedError:
+			err = e.OriginalError()
+		case *gqlerrors.Error:
+			err = e.OriginalError
+		default:
+			return nil
+		}
+	}
+	return nil
+}
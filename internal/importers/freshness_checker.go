@@ -109,7 +109,7 @@ func (fc *FreshnessChecker) SaveImportMetadata(files []models.FileMetadata) erro
 // loadLastImportMetadata loads the last import metadata from file
 func (fc *FreshnessChecker) loadLastImportMetadata() (*models.LastImportMetadata, error) {
 	if _, err := os.Stat(fc.metadataFile); os.IsNotExist(err) {
-		return nil, fmt.Errorf("metadata file does not exist: %s", fc.metadataFile)
+		return nil, fmt.Errorf("metadata file does not exist: %s: %w", fc.metadataFile, os.ErrNotExist)
 	}
 
 	data, err := os.ReadFile(fc.metadataFile)
@@ -187,6 +187,7 @@ func (fc *FreshnessChecker) compareFiles(remote models.FileMetadata, last *model
 }
 
 // GetLastImportInfo returns information about the last successful import
+// The error wraps os.ErrNotExist if no import has been recorded yet.
 func (fc *FreshnessChecker) GetLastImportInfo() (*models.ImportRecord, error) {
 	metadata, err := fc.loadLastImportMetadata()
 	if err != nil {
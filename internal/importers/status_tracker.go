@@ -3,18 +3,29 @@ package importers
 import (
 	"fmt"
 	"log"
+	"portal64api/internal/events"
 	"portal64api/internal/models"
 	"sync"
 	"time"
 )
 
 // StatusTracker manages import status and logging in memory
+// Status changes and log entries are published to subscribers as they happen.
 type StatusTracker struct {
 	status    *models.ImportStatus
 	logs      []models.ImportLogEntry
 	mutex     sync.RWMutex
 	maxLogs   int
 	logger    *log.Logger
+	events    *events.Broker
+
+	// Monitoring: outcomes of finished imports and step durations of the current or last import
+	outcomes       map[string]int64
+	phase          string
+	phaseStarted   time.Time
+	phaseDurations map[string]time.Duration
+	runStarted     time.Time
+	lastDuration   time.Duration
 }
 
 // NewStatusTracker creates a new status tracker instance
@@ -24,6 +35,10 @@ func NewStatusTracker(maxLogs int, logger *log.Logger) *StatusTracker {
 		logs:    make([]models.ImportLogEntry, 0),
 		maxLogs: maxLogs,
 		logger:  logger,
+		events:  events.NewBroker(events.DefaultBuffer),
+
+		outcomes:       make(map[string]int64),
+		phaseDurations: make(map[string]time.Duration),
 	}
 }
 
@@ -32,6 +47,22 @@ func (st *StatusTracker) GetStatus() *models.ImportStatus {
 	st.mutex.RLock()
 	defer st.mutex.RUnlock()
 
+	return st.copyStatusUnsafe()
+}
+
+// Subscribe subscribes to status changes and log entries, the returned function unsubscribes
+// Status events carry a *models.ImportStatus, log events a models.ImportLogEntry.
+func (st *StatusTracker) Subscribe() (<-chan events.Event, func()) {
+	return st.events.Subscribe()
+}
+
+// publishStatusUnsafe publishes a copy of the current status (must be called with mutex locked)
+func (st *StatusTracker) publishStatusUnsafe() {
+	st.events.Publish(events.TypeStatus, st.copyStatusUnsafe())
+}
+
+// copyStatusUnsafe returns a copy of the current status (must be called with mutex locked)
+func (st *StatusTracker) copyStatusUnsafe() *models.ImportStatus {
 	// Create a deep copy to avoid race conditions
 	statusCopy := *st.status
 	if st.status.StartedAt != nil {
@@ -63,6 +94,12 @@ func (st *StatusTracker) UpdateStatus(status, step string, progress int) {
 	st.mutex.Lock()
 	defer st.mutex.Unlock()
 
+	if status == models.StatusRunning && st.status.Status != models.StatusRunning {
+		st.runStarted = time.Now()
+		st.phaseDurations = make(map[string]time.Duration)
+		st.phase = ""
+	}
+
 	st.status.Status = status
 	st.status.CurrentStep = step
 	st.status.Progress = progress
@@ -74,7 +111,11 @@ func (st *StatusTracker) UpdateStatus(status, step string, progress int) {
 		st.status.Error = ""
 		st.status.SkipReason = ""
 	}
+	if status == models.StatusRunning {
+		st.enterPhaseUnsafe(step)
+	}
 
+	st.publishStatusUnsafe()
 	st.logEventUnsafe("INFO", step, fmt.Sprintf("Status updated: %s (%d%%)", status, progress), "", 0)
 }
 
@@ -84,6 +125,8 @@ func (st *StatusTracker) UpdateProgress(step string, progress int) {
 	defer st.mutex.Unlock()
 
 	st.status.UpdateProgress(step, progress)
+	st.enterPhaseUnsafe(step)
+	st.publishStatusUnsafe()
 	
 	// Log every 25% progress or important steps
 	if progress%25 == 0 || progress == 100 {
@@ -97,6 +140,8 @@ func (st *StatusTracker) MarkSuccess() {
 	defer st.mutex.Unlock()
 
 	st.status.MarkSuccess()
+	st.finishRunUnsafe(models.StatusSuccess)
+	st.publishStatusUnsafe()
 	st.logEventUnsafe("INFO", models.StepCompleted, "Import completed successfully", "", 0)
 }
 
@@ -106,10 +151,12 @@ func (st *StatusTracker) MarkFailed(err error, step string) {
 	defer st.mutex.Unlock()
 
 	st.status.MarkFailed(err)
+	st.finishRunUnsafe(models.StatusFailed)
 	errorMsg := ""
 	if err != nil {
 		errorMsg = err.Error()
 	}
+	st.publishStatusUnsafe()
 	st.logEventUnsafe("ERROR", step, "Import failed", errorMsg, 0)
 }
 
@@ -119,6 +166,8 @@ func (st *StatusTracker) MarkSkipped(reason, step string) {
 	defer st.mutex.Unlock()
 
 	st.status.MarkSkipped(reason)
+	st.finishRunUnsafe(models.StatusSkipped)
+	st.publishStatusUnsafe()
 	st.logEventUnsafe("INFO", step, fmt.Sprintf("Import skipped: %s", reason), "", 0)
 }
 
@@ -129,6 +178,7 @@ func (st *StatusTracker) SetRetryInfo(retryCount, maxRetries int) {
 
 	st.status.RetryCount = retryCount
 	st.status.MaxRetries = maxRetries
+	st.publishStatusUnsafe()
 }
 
 // SetNextScheduled sets the next scheduled import time
@@ -137,6 +187,7 @@ func (st *StatusTracker) SetNextScheduled(nextTime time.Time) {
 	defer st.mutex.Unlock()
 
 	st.status.NextScheduled = &nextTime
+	st.publishStatusUnsafe()
 }
 
 // SetFilesInfo sets the files information
@@ -145,6 +196,62 @@ func (st *StatusTracker) SetFilesInfo(filesInfo *models.ImportFilesInfo) {
 	defer st.mutex.Unlock()
 
 	st.status.FilesInfo = filesInfo
+	st.publishStatusUnsafe()
+}
+
+// GetMetrics returns the import outcomes and step durations for monitoring
+func (st *StatusTracker) GetMetrics() models.ImportMetrics {
+	st.mutex.RLock()
+	defer st.mutex.RUnlock()
+
+	metrics := models.ImportMetrics{
+		Running:        st.status.Status == models.StatusRunning,
+		Outcomes:       make(map[string]int64, len(st.outcomes)),
+		PhaseDurations: make(map[string]time.Duration, len(st.phaseDurations)),
+		LastDuration:   st.lastDuration,
+	}
+	for outcome, count := range st.outcomes {
+		metrics.Outcomes[outcome] = count
+	}
+	for phase, duration := range st.phaseDurations {
+		metrics.PhaseDurations[phase] = duration
+	}
+	if st.status.LastSuccess != nil {
+		lastSuccess := *st.status.LastSuccess
+		metrics.LastSuccess = &lastSuccess
+	}
+	return metrics
+}
+
+// enterPhaseUnsafe records the duration of the previous step when the step changes
+func (st *StatusTracker) enterPhaseUnsafe(step string) {
+	if step == st.phase {
+		return
+	}
+	now := time.Now()
+	st.endPhaseUnsafe(now)
+	st.phase = step
+	st.phaseStarted = now
+}
+
+// endPhaseUnsafe adds the time spent in the current step to its duration
+func (st *StatusTracker) endPhaseUnsafe(now time.Time) {
+	if st.phase != "" && !st.phaseStarted.IsZero() {
+		st.phaseDurations[st.phase] += now.Sub(st.phaseStarted)
+	}
+	st.phase = ""
+}
+
+// finishRunUnsafe counts a finished import and records its duration
+func (st *StatusTracker) finishRunUnsafe(outcome string) {
+	now := time.Now()
+	st.endPhaseUnsafe(now)
+	st.outcomes[outcome]++
+	st.lastDuration = 0
+	if !st.runStarted.IsZero() {
+		st.lastDuration = now.Sub(st.runStarted)
+		st.runStarted = time.Time{}
+	}
 }
 
 // LogInfo logs an informational message
@@ -225,6 +332,7 @@ func (st *StatusTracker) Reset() {
 
 	st.status = models.NewImportStatus()
 	st.logs = st.logs[:0]
+	st.publishStatusUnsafe()
 }
 
 // IsRunning returns true if import is currently running
@@ -299,6 +407,7 @@ func (st *StatusTracker) addLogEntryUnsafe(entry models.ImportLogEntry) {
 
 	// Add to in-memory logs
 	st.logs = append(st.logs, entry)
+	st.events.Publish(events.TypeLog, entry)
 
 	// Trim logs if exceeding max size
 	if len(st.logs) > st.maxLogs {
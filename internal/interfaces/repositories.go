@@ -1,6 +1,8 @@
 package interfaces
 
 import (
+	"context"
+
 	"portal64api/internal/models"
 	"portal64api/internal/repositories"
 )
@@ -13,6 +15,14 @@ type PlayerRepositoryInterface interface {
 	GetPlayerRatingHistory(personID uint) ([]repositories.EvaluationWithTournament, error)
 	GetPlayerCurrentClub(personID uint) (*models.Organisation, error)
 	GetPlayerCurrentMembership(personID uint) (*models.Mitgliedschaft, error)
+	GetPlayerMemberships(personID uint) ([]repositories.MembershipWithOrganisation, error)
+	GetPlayersByIDs(keys []repositories.PlayerKey) (map[repositories.PlayerKey]repositories.PlayerRecord, error)
+	GetPlayersRatingHistories(personIDs []uint) (map[uint][]repositories.EvaluationWithTournament, error)
+	GetPlayersMemberships(personIDs []uint) (map[uint][]repositories.MembershipWithOrganisation, error)
+	GetPlayersByClubs(vkzs []string) (map[repositories.PlayerKey]repositories.PlayerRecord, error)
+
+	// WithContext returns a copy of the repository running its queries with the context
+	WithContext(ctx context.Context) PlayerRepositoryInterface
 }
 
 // ClubRepositoryInterface defines the interface for club repository operations
@@ -22,9 +32,58 @@ type ClubRepositoryInterface interface {
 	GetClubMemberCount(organizationID uint) (int64, error)
 	GetClubAverageDWZ(organizationID uint) (float64, error)
 	GetAllClubs() ([]models.Organisation, error)
+	StreamAllClubs(fn func(models.Organisation) error) error
+
+	// WithContext returns a copy of the repository running its queries with the context
+	WithContext(ctx context.Context) ClubRepositoryInterface
 }
 
 // TournamentRepositoryInterface defines the interface for tournament repository operations
 type TournamentRepositoryInterface interface {
 	GetTournamentCodeByID(tournamentID uint) (string, error)
+
+	// WithContext returns a copy of the repository running its queries with the context
+	WithContext(ctx context.Context) TournamentRepositoryInterface
+}
+
+// The concrete repositories return their own type from WithContext, the adapters below return
+// the interfaces instead.
+
+// NewPlayerRepository adapts a player repository to PlayerRepositoryInterface
+func NewPlayerRepository(repo *repositories.PlayerRepository) PlayerRepositoryInterface {
+	return playerRepository{repo}
+}
+
+type playerRepository struct {
+	*repositories.PlayerRepository
+}
+
+func (r playerRepository) WithContext(ctx context.Context) PlayerRepositoryInterface {
+	return playerRepository{r.PlayerRepository.WithContext(ctx)}
+}
+
+// NewClubRepository adapts a club repository to ClubRepositoryInterface
+func NewClubRepository(repo *repositories.ClubRepository) ClubRepositoryInterface {
+	return clubRepository{repo}
+}
+
+type clubRepository struct {
+	*repositories.ClubRepository
+}
+
+func (r clubRepository) WithContext(ctx context.Context) ClubRepositoryInterface {
+	return clubRepository{r.ClubRepository.WithContext(ctx)}
+}
+
+// NewTournamentRepository adapts a tournament repository to TournamentRepositoryInterface
+func NewTournamentRepository(repo *repositories.TournamentRepository) TournamentRepositoryInterface {
+	return tournamentRepository{repo}
+}
+
+type tournamentRepository struct {
+	*repositories.TournamentRepository
+}
+
+func (r tournamentRepository) WithContext(ctx context.Context) TournamentRepositoryInterface {
+	return tournamentRepository{r.TournamentRepository.WithContext(ctx)}
 }
@@ -0,0 +1,112 @@
+package logging
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Log is the structured application logger, configured by SetupLogging
+var Log = logrus.New()
+
+// requestInfoKey is the context key of the *RequestInfo of a request
+type requestInfoKey struct{}
+
+// RequestInfo identifies a request and collects the work done for it
+// It is shared by all layers handling the request; the counters are safe for concurrent use.
+type RequestInfo struct {
+	ID string
+
+	mu          sync.Mutex
+	cacheHits   int
+	cacheMisses int
+	dbQueries   int
+	dbTime      time.Duration
+}
+
+// RequestStats is a snapshot of the counters of a request
+type RequestStats struct {
+	CacheHits   int
+	CacheMisses int
+	DBQueries   int
+	DBTime      time.Duration
+}
+
+// Stats returns the current counters
+func (r *RequestInfo) Stats() RequestStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return RequestStats{
+		CacheHits:   r.cacheHits,
+		CacheMisses: r.cacheMisses,
+		DBQueries:   r.dbQueries,
+		DBTime:      r.dbTime,
+	}
+}
+
+// WithRequestInfo returns a context carrying the request info
+func WithRequestInfo(ctx context.Context, info *RequestInfo) context.Context {
+	return context.WithValue(ctx, requestInfoKey{}, info)
+}
+
+// RequestInfoFromContext returns the request info of a context, or nil outside of requests
+func RequestInfoFromContext(ctx context.Context) *RequestInfo {
+	if ctx == nil {
+		return nil
+	}
+	info, _ := ctx.Value(requestInfoKey{}).(*RequestInfo)
+	return info
+}
+
+// RequestID returns the ID of the request a context belongs to, or ""
+func RequestID(ctx context.Context) string {
+	if info := RequestInfoFromContext(ctx); info != nil {
+		return info.ID
+	}
+	return ""
+}
+
+// FromContext returns a log entry tagged with the request ID and trace ID of the context
+func FromContext(ctx context.Context) *logrus.Entry {
+	entry := logrus.NewEntry(Log)
+	if id := RequestID(ctx); id != "" {
+		entry = entry.WithField("request_id", id)
+	}
+	if ctx != nil {
+		if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+			entry = entry.WithField("trace_id", spanContext.TraceID().String())
+		}
+	}
+	return entry
+}
+
+// RecordCacheHit counts a cache hit for the request of the context
+func RecordCacheHit(ctx context.Context) {
+	if info := RequestInfoFromContext(ctx); info != nil {
+		info.mu.Lock()
+		info.cacheHits++
+		info.mu.Unlock()
+	}
+}
+
+// RecordCacheMiss counts a cache miss for the request of the context
+func RecordCacheMiss(ctx context.Context) {
+	if info := RequestInfoFromContext(ctx); info != nil {
+		info.mu.Lock()
+		info.cacheMisses++
+		info.mu.Unlock()
+	}
+}
+
+// RecordDBQuery adds a database query and its duration to the request of the context
+func RecordDBQuery(ctx context.Context, elapsed time.Duration) {
+	if info := RequestInfoFromContext(ctx); info != nil {
+		info.mu.Lock()
+		info.dbQueries++
+		info.dbTime += elapsed
+		info.mu.Unlock()
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"portal64api/internal/config"
 	"strings"
 
+	"github.com/sirupsen/logrus"
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
@@ -122,6 +123,7 @@ func SetupLogging(cfg *config.LoggingConfig) error {
 	if !cfg.Enabled {
 		// Disable logging by setting output to discard
 		log.SetOutput(io.Discard)
+		Log.SetOutput(io.Discard)
 		return nil
 	}
 
@@ -148,9 +150,28 @@ func SetupLogging(cfg *config.LoggingConfig) error {
 	log.SetOutput(output)
 	log.SetFlags(log.LstdFlags | log.Lmicroseconds)
 
+	// Structured logger for access logs and request scoped logging
+	Log.SetOutput(output)
+	configureStructuredLogger(Log, cfg)
+
 	return nil
 }
 
+// configureStructuredLogger sets the format and level of a structured logger
+func configureStructuredLogger(logger *logrus.Logger, cfg *config.LoggingConfig) {
+	if strings.EqualFold(cfg.Format, "text") {
+		logger.SetFormatter(&logrus.TextFormatter{FullTimestamp: true, TimestampFormat: "2006-01-02T15:04:05.000Z07:00"})
+	} else {
+		logger.SetFormatter(&logrus.JSONFormatter{TimestampFormat: "2006-01-02T15:04:05.000Z07:00"})
+	}
+
+	level, err := logrus.ParseLevel(cfg.Level)
+	if err != nil {
+		level = logrus.InfoLevel
+	}
+	logger.SetLevel(level)
+}
+
 // CreateImportLogger creates a separate logger for import service
 func CreateImportLogger(cfg *config.LoggingConfig) (*log.Logger, error) {
 	if !cfg.Enabled {
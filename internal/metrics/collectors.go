@@ -0,0 +1,147 @@
+package metrics
+
+import (
+	"database/sql"
+	"time"
+
+	"portal64api/internal/cache"
+	"portal64api/internal/models"
+	"portal64api/internal/services"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// DatabaseCollector exposes the connection pool statistics of each database
+// stats is typically database.Databases.Stats.
+func DatabaseCollector(stats func() map[string]sql.DBStats) prometheus.Collector {
+	type poolMetric struct {
+		desc      *prometheus.Desc
+		valueType prometheus.ValueType
+		value     func(sql.DBStats) float64
+	}
+	pool := func(name, help string, valueType prometheus.ValueType, value func(sql.DBStats) float64) poolMetric {
+		return poolMetric{desc: newDesc("db_"+name, help, "database"), valueType: valueType, value: value}
+	}
+
+	poolMetrics := []poolMetric{
+		pool("max_open_connections", "Maximum number of open connections to the database.", prometheus.GaugeValue,
+			func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }),
+		pool("open_connections", "Number of established connections, in use and idle.", prometheus.GaugeValue,
+			func(s sql.DBStats) float64 { return float64(s.OpenConnections) }),
+		pool("in_use_connections", "Number of connections currently in use.", prometheus.GaugeValue,
+			func(s sql.DBStats) float64 { return float64(s.InUse) }),
+		pool("idle_connections", "Number of idle connections.", prometheus.GaugeValue,
+			func(s sql.DBStats) float64 { return float64(s.Idle) }),
+		pool("wait_count_total", "Total number of connections waited for.", prometheus.CounterValue,
+			func(s sql.DBStats) float64 { return float64(s.WaitCount) }),
+		pool("wait_duration_seconds_total", "Total time blocked waiting for a new connection.", prometheus.CounterValue,
+			func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }),
+		pool("max_idle_closed_total", "Total number of connections closed due to SetMaxIdleConns.", prometheus.CounterValue,
+			func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }),
+		pool("max_lifetime_closed_total", "Total number of connections closed due to SetConnMaxLifetime.", prometheus.CounterValue,
+			func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }),
+	}
+
+	c := &collector{collect: func(ch chan<- prometheus.Metric) {
+		for db, dbStats := range stats() {
+			for _, m := range poolMetrics {
+				ch <- prometheus.MustNewConstMetric(m.desc, m.valueType, m.value(dbStats), db)
+			}
+		}
+	}}
+	for _, m := range poolMetrics {
+		c.descs = append(c.descs, m.desc)
+	}
+	return c
+}
+
+// CacheCollector exposes cache hits and misses per key prefix and the cache error counters
+func CacheCollector(cacheService cache.CacheService) prometheus.Collector {
+	requests := newDesc("cache_requests_total", "Cache lookups by key prefix and result.", "prefix", "result")
+	hitRatio := newDesc("cache_hit_ratio", "Share of cache lookups answered from the cache, by key prefix.", "prefix")
+	cacheErrors := newDesc("cache_errors_total", "Total number of failed cache operations.")
+	refreshes := newDesc("cache_background_refreshes_total", "Total number of background cache refreshes.")
+	refreshErrors := newDesc("cache_refresh_errors_total", "Total number of failed cache refreshes.")
+
+	return &collector{
+		descs: []*prometheus.Desc{requests, hitRatio, cacheErrors, refreshes, refreshErrors},
+		collect: func(ch chan<- prometheus.Metric) {
+			stats := cacheService.GetStats()
+			for prefix, prefixStats := range stats.PrefixStats {
+				ch <- prometheus.MustNewConstMetric(requests, prometheus.CounterValue, float64(prefixStats.Hits), prefix, "hit")
+				ch <- prometheus.MustNewConstMetric(requests, prometheus.CounterValue, float64(prefixStats.Misses), prefix, "miss")
+				ch <- prometheus.MustNewConstMetric(hitRatio, prometheus.GaugeValue, prefixStats.HitRatio, prefix)
+			}
+			ch <- prometheus.MustNewConstMetric(cacheErrors, prometheus.CounterValue, float64(stats.CacheErrors))
+			ch <- prometheus.MustNewConstMetric(refreshes, prometheus.CounterValue, float64(stats.BackgroundRefreshes))
+			ch <- prometheus.MustNewConstMetric(refreshErrors, prometheus.CounterValue, float64(stats.RefreshErrors))
+		},
+	}
+}
+
+// ImportCollector exposes import outcomes, step durations and the time of the last successful import
+func ImportCollector(importService *services.ImportService) prometheus.Collector {
+	runs := newDesc("import_runs_total", "Finished imports by outcome.", "outcome")
+	phaseDuration := newDesc("import_phase_duration_seconds", "Duration of each step of the most recent import.", "phase")
+	lastDuration := newDesc("import_last_duration_seconds", "Total duration of the most recent import.")
+	running := newDesc("import_running", "Whether an import is in progress (1) or not (0).")
+	lastSuccess := newDesc("import_last_success_timestamp_seconds", "Unix time of the last successful import.")
+
+	return &collector{
+		descs: []*prometheus.Desc{runs, phaseDuration, lastDuration, running, lastSuccess},
+		collect: func(ch chan<- prometheus.Metric) {
+			importMetrics := importService.GetMetrics()
+			for _, outcome := range []string{models.StatusSuccess, models.StatusFailed, models.StatusSkipped} {
+				ch <- prometheus.MustNewConstMetric(runs, prometheus.CounterValue, float64(importMetrics.Outcomes[outcome]), outcome)
+			}
+			for phase, duration := range importMetrics.PhaseDurations {
+				ch <- prometheus.MustNewConstMetric(phaseDuration, prometheus.GaugeValue, duration.Seconds(), phase)
+			}
+			ch <- prometheus.MustNewConstMetric(lastDuration, prometheus.GaugeValue, importMetrics.LastDuration.Seconds())
+			ch <- prometheus.MustNewConstMetric(running, prometheus.GaugeValue, boolValue(importMetrics.Running))
+
+			// The last import time survives restarts, unlike the counters above
+			lastImport, ok := importService.GetLastImportTime()
+			if !ok && importMetrics.LastSuccess != nil {
+				lastImport, ok = *importMetrics.LastSuccess, true
+			}
+			if ok {
+				ch <- prometheus.MustNewConstMetric(lastSuccess, prometheus.GaugeValue, unixSeconds(lastImport))
+			}
+		},
+	}
+}
+
+// AnalysisCollector exposes the outcomes of Kader-Planung and statistical analysis runs
+func AnalysisCollector(kaderPlanungService *services.KaderPlanungService) prometheus.Collector {
+	runs := newDesc("analysis_runs_total", "Finished analysis runs by job type and outcome.", "job", "outcome")
+	lastDuration := newDesc("analysis_last_duration_seconds", "Duration of the most recent run by job type.", "job")
+	lastSuccess := newDesc("analysis_last_success_timestamp_seconds", "Unix time of the last successful run by job type.", "job")
+	running := newDesc("analysis_running", "Whether an analysis is in progress (1) or not (0).")
+
+	return &collector{
+		descs: []*prometheus.Desc{runs, lastDuration, lastSuccess, running},
+		collect: func(ch chan<- prometheus.Metric) {
+			for job, stats := range kaderPlanungService.GetJobStats() {
+				ch <- prometheus.MustNewConstMetric(runs, prometheus.CounterValue, float64(stats.Succeeded), job, "success")
+				ch <- prometheus.MustNewConstMetric(runs, prometheus.CounterValue, float64(stats.Failed), job, "failed")
+				ch <- prometheus.MustNewConstMetric(lastDuration, prometheus.GaugeValue, stats.LastDuration.Seconds(), job)
+				if !stats.LastSuccess.IsZero() {
+					ch <- prometheus.MustNewConstMetric(lastSuccess, prometheus.GaugeValue, unixSeconds(stats.LastSuccess), job)
+				}
+			}
+			ch <- prometheus.MustNewConstMetric(running, prometheus.GaugeValue, boolValue(kaderPlanungService.GetStatus().Running))
+		},
+	}
+}
+
+func boolValue(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / 1e9
+}
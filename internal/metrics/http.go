@@ -0,0 +1,58 @@
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HTTPMetrics tracks request latencies per route and the number of requests in flight
+type HTTPMetrics struct {
+	duration *prometheus.HistogramVec
+	inFlight prometheus.Gauge
+}
+
+// NewHTTPMetrics creates the HTTP request metrics
+func NewHTTPMetrics() *HTTPMetrics {
+	return &HTTPMetrics{
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: Namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "Duration of HTTP requests by method, route template and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		inFlight: prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: Namespace,
+			Name:      "http_requests_in_flight",
+			Help:      "Number of HTTP requests currently being served.",
+		}),
+	}
+}
+
+// Started counts a request as in flight
+func (m *HTTPMetrics) Started() {
+	m.inFlight.Inc()
+}
+
+// Finished records a completed request
+// Requests not matching a route are recorded with the route "unmatched" to bound the label values.
+func (m *HTTPMetrics) Finished(method, route string, status int, elapsed time.Duration) {
+	m.inFlight.Dec()
+	if route == "" {
+		route = "unmatched"
+	}
+	m.duration.WithLabelValues(method, route, strconv.Itoa(status)).Observe(elapsed.Seconds())
+}
+
+// Describe sends the descriptors of the request metrics
+func (m *HTTPMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.duration.Describe(ch)
+	m.inFlight.Describe(ch)
+}
+
+// Collect sends the request metrics
+func (m *HTTPMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.duration.Collect(ch)
+	m.inFlight.Collect(ch)
+}
@@ -0,0 +1,46 @@
+// Package metrics exposes the service metrics to Prometheus.
+// The collectors are registered with the default registry of client_golang next to its Go runtime
+// and process collectors; promhttp.Handler serves them.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Namespace prefixes all metric names of the service
+const Namespace = "portal64"
+
+// Register registers collectors with the default registry
+// A collector of the same metrics registered before, e.g. by an earlier router in tests, is replaced.
+func Register(collectors ...prometheus.Collector) error {
+	for _, c := range collectors {
+		prometheus.Unregister(c)
+		if err := prometheus.Register(c); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collector reads its values from the services when scraped
+type collector struct {
+	descs   []*prometheus.Desc
+	collect func(ch chan<- prometheus.Metric)
+}
+
+// Describe sends the descriptors of all metrics the collector may send
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range c.descs {
+		ch <- desc
+	}
+}
+
+// Collect sends the current values
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	c.collect(ch)
+}
+
+// newDesc describes a metric of the service namespace
+func newDesc(name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(Namespace+"_"+name, help, labels, nil)
+}
@@ -0,0 +1,65 @@
+package models
+
+// MaxBatchIDs is the maximum number of IDs accepted by a batch lookup
+const MaxBatchIDs = 500
+
+// BatchRequest represents the body of a batch lookup
+type BatchRequest struct {
+	IDs []string `json:"ids" binding:"required" example:"C0101-1014,C0101-1015"`
+}
+
+// BatchError describes why a single ID of a batch lookup failed
+type BatchError struct {
+	Code      int    `json:"code"`       // HTTP status code a single lookup would have returned
+	ErrorCode string `json:"error_code"` // Error code of the catalogue, e.g. PLAYER_NOT_FOUND
+	Message   string `json:"message"`
+}
+
+// BatchMeta summarises the results of a batch lookup
+type BatchMeta struct {
+	Requested int `json:"requested"` // Distinct IDs requested
+	Found     int `json:"found"`
+	Failed    int `json:"failed"`
+}
+
+// PlayerBatchResult is the result of a batch lookup for one player ID
+type PlayerBatchResult struct {
+	ID    string          `json:"id"`
+	Data  *PlayerResponse `json:"data"`
+	Error *BatchError     `json:"error,omitempty"`
+}
+
+// ClubBatchResult is the result of a batch lookup for one club ID
+type ClubBatchResult struct {
+	ID    string        `json:"id"`
+	Data  *ClubResponse `json:"data"`
+	Error *BatchError   `json:"error,omitempty"`
+}
+
+// ClubPlayersBatchResult is the result of a batch lookup for the players of one club ID
+type ClubPlayersBatchResult struct {
+	ID    string           `json:"id"`
+	Data  []PlayerResponse `json:"data"`
+	Error *BatchError      `json:"error,omitempty"`
+}
+
+// RatingHistoryBatchResult is the result of a batch lookup for the rating history of one player ID
+type RatingHistoryBatchResult struct {
+	ID    string                  `json:"id"`
+	Data  []RatingHistoryResponse `json:"data"`
+	Error *BatchError             `json:"error,omitempty"`
+}
+
+// MembershipsBatchResult is the result of a batch lookup for the memberships of one player ID
+type MembershipsBatchResult struct {
+	ID    string               `json:"id"`
+	Data  []MembershipResponse `json:"data"`
+	Error *BatchError          `json:"error,omitempty"`
+}
+
+// TournamentBatchResult is the result of a batch lookup for one tournament ID
+type TournamentBatchResult struct {
+	ID    string              `json:"id"`
+	Data  *TournamentResponse `json:"data"`
+	Error *BatchError         `json:"error,omitempty"`
+}
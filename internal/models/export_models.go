@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+// Datasets of the bulk export, in the order they are generated
+const (
+	ExportDatasetClubs       = "clubs"
+	ExportDatasetPlayers     = "players"
+	ExportDatasetMemberships = "memberships"
+	ExportDatasetEvaluations = "evaluations"
+)
+
+// Formats of the bulk export files
+const (
+	ExportFormatNDJSON = "ndjson"
+	ExportFormatCSV    = "csv"
+)
+
+// ExportPlayer is a row of the players export, one per current membership like in club player lists
+type ExportPlayer struct {
+	PlayerResponse
+	PersonUUID string `json:"person_uuid"`
+}
+
+// ExportMembership is a row of the memberships export, current and past memberships
+type ExportMembership struct {
+	PersonUUID string `json:"person_uuid"`
+	MembershipResponse
+}
+
+// ExportEvaluation is a row of the evaluations export, a DWZ evaluation of a computed tournament
+type ExportEvaluation struct {
+	PersonUUID string `json:"person_uuid"`
+	RatingHistoryResponse
+}
+
+// ExportManifest describes a generation of the bulk export
+// All files of a generation are read from the same database snapshot.
+type ExportManifest struct {
+	Generation  string       `json:"generation" example:"20261018T031502Z"`
+	GeneratedAt time.Time    `json:"generated_at"`
+	ImportedAt  *time.Time   `json:"imported_at,omitempty"` // Last import the snapshot reflects, if known
+	DurationMs  int64        `json:"duration_ms"`
+	Files       []ExportFile `json:"files"`
+}
+
+// ExportFile describes a gzip compressed file of the bulk export
+type ExportFile struct {
+	Dataset       string `json:"dataset" example:"players"`
+	Format        string `json:"format" example:"ndjson"`
+	Name          string `json:"name" example:"players.ndjson.gz"`
+	URL           string `json:"url" example:"/api/v1/export/players?format=ndjson"`
+	Rows          int64  `json:"rows"`
+	Size          int64  `json:"size"`           // Bytes of the gzip file
+	SHA256        string `json:"sha256"`         // Checksum of the gzip file
+	ContentSHA256 string `json:"content_sha256"` // Checksum of the uncompressed content
+}
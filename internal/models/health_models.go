@@ -0,0 +1,43 @@
+package models
+
+// Health status of the service and its components
+const (
+	HealthStatusHealthy   = "healthy"
+	HealthStatusDegraded  = "degraded"
+	HealthStatusUnhealthy = "unhealthy"
+
+	ComponentStatusUp       = "up"
+	ComponentStatusDown     = "down"
+	ComponentStatusDisabled = "disabled"
+)
+
+// BuildInfo describes the running build
+type BuildInfo struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	BuildTime string `json:"build_time,omitempty"`
+}
+
+// LivenessResponse represents the response of the liveness check
+type LivenessResponse struct {
+	Status        string    `json:"status"`
+	Build         BuildInfo `json:"build"`
+	UptimeSeconds int64     `json:"uptime_seconds"`
+}
+
+// ReadinessResponse represents the response of the readiness check
+// Status is unhealthy if a critical component is down, degraded if an optional one is.
+type ReadinessResponse struct {
+	Status     string                     `json:"status"`
+	Ready      bool                       `json:"ready"`
+	Build      BuildInfo                  `json:"build"`
+	Components map[string]ComponentHealth `json:"components"`
+}
+
+// ComponentHealth represents the state of a dependency of the service
+type ComponentHealth struct {
+	Status    string  `json:"status"`   // up, down, disabled
+	Critical  bool    `json:"critical"` // whether the service is unready without it
+	LatencyMs float64 `json:"latency_ms"`
+	Message   string  `json:"message,omitempty"`
+}
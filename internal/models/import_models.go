@@ -101,6 +101,15 @@ type LoadMetrics struct {
 	CPUUsagePercent     float64 `json:"cpu_usage_percent"`
 }
 
+// ImportMetrics summarizes the imports run since the server started, for monitoring
+type ImportMetrics struct {
+	Running        bool                     // An import is in progress
+	Outcomes       map[string]int64         // Finished imports by status (success, failed, skipped)
+	PhaseDurations map[string]time.Duration // Duration of each step of the most recent import
+	LastDuration   time.Duration            // Total duration of the most recent import
+	LastSuccess    *time.Time               // Completion of the last successful import
+}
+
 // ImportSteps contains constants for import step names
 const (
 	StepInitialization       = "initialization"
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+// Related resources that can be embedded with ?include=
+const (
+	IncludeClub          = "club"
+	IncludeRatingHistory = "rating_history"
+	IncludeMemberships   = "memberships"
+	IncludePlayers       = "players"
+	IncludePerformance   = "performance"
+	IncludeTeamMatches   = "team_matches"
+)
+
+// MembershipResponse represents a club membership of a player in API responses
+type MembershipResponse struct {
+	PlayerID string     `json:"player_id"` // Format: C0101-123, the player ID within this club
+	ClubID   string     `json:"club_id"`   // Format: C0101
+	Club     string     `json:"club"`
+	From     *time.Time `json:"from"`
+	Until    *time.Time `json:"until"` // nil for ongoing memberships
+	Status   string     `json:"status"`
+	Current  bool       `json:"current"`
+}
+
+// PlayerIncludes holds the related resources embedded in a player
+type PlayerIncludes struct {
+	Club          *ClubResponse           `json:"club,omitempty"`
+	RatingHistory []RatingHistoryResponse `json:"rating_history,omitempty"`
+	Memberships   []MembershipResponse    `json:"memberships,omitempty"`
+}
+
+// PlayerWithIncludes represents a player with embedded related resources
+type PlayerWithIncludes struct {
+	PlayerResponse
+	Included *PlayerIncludes `json:"included,omitempty"`
+}
+
+// ClubIncludes holds the related resources embedded in a club
+// Players is capped, PlayersTruncated tells whether the club has more active players than listed.
+type ClubIncludes struct {
+	Players          []PlayerResponse `json:"players,omitempty"`
+	PlayersTotal     int              `json:"players_total,omitempty"`     // Number of active players of the club
+	PlayersTruncated bool             `json:"players_truncated,omitempty"` // Players lists the strongest ones only
+}
+
+// ClubWithIncludes represents a club with embedded related resources
+type ClubWithIncludes struct {
+	ClubResponse
+	Included *ClubIncludes `json:"included,omitempty"`
+}
+
+// TournamentIncludes holds the related resources embedded in a tournament
+type TournamentIncludes struct {
+	TeamMatches *TeamMatchesResponse `json:"team_matches,omitempty"`
+}
+
+// TournamentWithIncludes represents a tournament with embedded related resources
+type TournamentWithIncludes struct {
+	EnhancedTournamentResponse
+	Included *TournamentIncludes `json:"included,omitempty"`
+}
@@ -139,7 +139,7 @@ func (Participant) TableName() string {
 	return "participant"
 }
 
-// Turnier represents a tournament (legacy model - no longer in use)
+// Turnier holds the league settings of a team competition, linked by MID to tournamentmaster.id
 type Turnier struct {
 	TID                int        `json:"tid" gorm:"primaryKey;column:TID"`
 	TName              string     `json:"tname" gorm:"column:TName"`
@@ -280,6 +280,26 @@ type TournamentResponse struct {
 	EndDate         *time.Time `json:"end_date"`
 	Status          string     `json:"status"`
 	ParticipantCount int       `json:"participant_count"`
+	CodeInfo        *TournamentCodeInfo `json:"code_info,omitempty"`
+}
+
+// TournamentCodeInfo represents the fields encoded in a tournament code
+// Codes like C529-K00-HT1 consist of decade letter (C=2020s), year digit (5),
+// week number (29), sub-code (K00) and type acronym (HT1)
+type TournamentCodeInfo struct {
+	Year            int        `json:"year"`
+	Week            int        `json:"week"`
+	ApproxStartDate *time.Time `json:"approx_start_date"` // Monday of the encoded ISO week
+	SubCode         string     `json:"sub_code"`
+	TypeAcronym     string     `json:"type_acronym"`
+}
+
+// TeamCompetitionInfo represents the league settings of a team competition
+type TeamCompetitionInfo struct {
+	Boards      int  `json:"boards"`           // Regular players (boards) per team
+	Substitutes int  `json:"substitutes"`      // Substitute players per team
+	BoardSwap   bool `json:"board_swap"`       // Players may swap boards between rounds
+	Season      *int `json:"season,omitempty"` // Season of the league
 }
 
 // Enhanced TournamentResponse with comprehensive tournament data
@@ -298,6 +318,8 @@ type EnhancedTournamentResponse struct {
 	RecomputedOn    *time.Time `json:"recomputed_on"`
 	Status          string     `json:"status"`
 	Note            string     `json:"note,omitempty"`
+	CodeInfo        *TournamentCodeInfo `json:"code_info,omitempty"`
+	TeamCompetition *TeamCompetitionInfo `json:"team_competition,omitempty"` // Only set for team competitions
 	
 	// Assessors/Officials
 	Assessors       []PersonInfo `json:"assessors,omitempty"`
@@ -311,6 +333,81 @@ type EnhancedTournamentResponse struct {
 	
 	// Evaluation data (if computed)
 	Evaluations     []EvaluationInfo `json:"evaluations,omitempty"`
+
+	// Performance ratings (only with ?include=performance)
+	Performance     *TournamentPerformance `json:"performance,omitempty"`
+}
+
+// TournamentPerformance represents performance ratings and rating changes of a tournament
+type TournamentPerformance struct {
+	RatingAverage int                 `json:"rating_average"` // Average pre-tournament rating of rated participants
+	Category      int                 `json:"category"`       // FIDE tournament category, 0 below category 1
+	Players       []PlayerPerformance `json:"players"`
+	TopGainers    []PlayerPerformance `json:"top_gainers"`
+	TopLosers     []PlayerPerformance `json:"top_losers"`
+}
+
+// PlayerPerformance represents the performance of a single participant
+type PlayerPerformance struct {
+	PersonID              uint     `json:"person_id"`
+	No                    int      `json:"no"`
+	Name                  string   `json:"name"`
+	Rating                *int     `json:"rating"`                  // Pre-tournament rating
+	Games                 int      `json:"games"`
+	Points                float64  `json:"points"`
+	RatedGames            int      `json:"rated_games"`             // Games against rated opponents
+	RatedPoints           float64  `json:"rated_points"`            // Points against rated opponents
+	AverageOpponentRating *int     `json:"average_opponent_rating"`
+	ExpectedScore         *float64 `json:"expected_score"`          // Expected points against rated opponents
+	Performance           *int     `json:"performance"`             // Tournament performance rating (TPR)
+	DWZOld                *int     `json:"dwz_old"`
+	DWZNew                *int     `json:"dwz_new"`
+	DWZDelta              *int     `json:"dwz_delta"`
+}
+
+// TeamMatchesResponse represents the team view of a league tournament
+type TeamMatchesResponse struct {
+	TournamentID string             `json:"tournament_id"`
+	Name         string             `json:"name"`
+	Rounds       []TeamRoundInfo    `json:"rounds"`
+	Table        []LeagueTableEntry `json:"table"`
+}
+
+// TeamInfo identifies a team and its club
+type TeamInfo struct {
+	ID     string `json:"id"`      // Club ID and team number, format: C0101-2
+	ClubID string `json:"club_id"` // Format: C0101
+	Name   string `json:"name"`
+}
+
+// TeamRoundInfo represents the team fixtures of a round
+type TeamRoundInfo struct {
+	Round       int             `json:"round"`
+	Appointment string          `json:"appointment"`
+	Matches     []TeamMatchInfo `json:"matches"`
+}
+
+// TeamMatchInfo represents a single team match with its board pairings
+type TeamMatchInfo struct {
+	Home            TeamInfo   `json:"home"` // Team with white on the even boards
+	Away            TeamInfo   `json:"away"`
+	HomeBoardPoints float64    `json:"home_board_points"`
+	AwayBoardPoints float64    `json:"away_board_points"`
+	HomeMatchPoints int        `json:"home_match_points"`
+	AwayMatchPoints int        `json:"away_match_points"`
+	Boards          []GameInfo `json:"boards"`
+}
+
+// LeagueTableEntry represents a team's line in the league table
+type LeagueTableEntry struct {
+	Rank        int      `json:"rank"`
+	Team        TeamInfo `json:"team"`
+	Matches     int      `json:"matches"`
+	Wins        int      `json:"wins"`
+	Draws       int      `json:"draws"`
+	Losses      int      `json:"losses"`
+	MatchPoints int      `json:"match_points"`
+	BoardPoints float64  `json:"board_points"`
 }
 
 // OrganizationInfo represents tournament organizing club/federation
@@ -422,13 +519,26 @@ type SearchRequest struct {
 	FilterValue  string `json:"filter_value" form:"filter_value"`
 }
 
+// TournamentSearchFilter represents optional, combinable tournament search filters
+type TournamentSearchFilter struct {
+	OrganisationID uint       `json:"organisation_id,omitempty" form:"organisation_id"`
+	Region         string     `json:"region,omitempty" form:"region"` // VKZ prefix of the organising club, e.g. C03
+	TypeAcronym    string     `json:"type,omitempty" form:"type"`     // Last part of the tournament code, e.g. HT1
+	Rounds         int        `json:"rounds,omitempty" form:"rounds"`
+	Computed       *bool      `json:"computed,omitempty" form:"computed"`
+	AssessorID     uint       `json:"assessor_id,omitempty" form:"assessor_id"`
+	FinishedFrom   *time.Time `json:"finished_from,omitempty" form:"finished_from"`
+	FinishedTo     *time.Time `json:"finished_to,omitempty" form:"finished_to"`
+}
+
 // Response represents a generic API response
 type Response struct {
-	Success bool        `json:"success"`
-	Message string      `json:"message,omitempty"`
-	Data    interface{} `json:"data,omitempty"`
-	Error   string      `json:"error,omitempty"`
-	Meta    *Meta       `json:"meta,omitempty"`
+	Success   bool        `json:"success"`
+	Message   string      `json:"message,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	RequestID string      `json:"request_id,omitempty"` // Set on errors, quote it when reporting problems
+	Meta      *Meta       `json:"meta,omitempty"`
 }
 
 // Meta represents response metadata
@@ -589,6 +699,8 @@ type RegionAddressResponse struct {
 	PersonFirstname       string          `json:"person_firstname,omitempty"`
 	OrganisationName      string          `json:"organisation_name,omitempty"`
 	OrganisationShortname string          `json:"organisation_shortname,omitempty"`
+	OrganisationVKZ       string          `json:"organisation_vkz,omitempty"`
+	OrganisationLevel     string          `json:"organisation_level,omitempty"` // "club" or "federation"
 	Region                string          `json:"region"`
 	FunctionName          string          `json:"function_name"`
 	FunctionID            uint            `json:"function_id"`
@@ -599,8 +711,17 @@ type RegionAddressResponse struct {
 
 // ContactDetail represents a single contact detail (phone, email, etc.)
 type ContactDetail struct {
-	Type  string `json:"type"`   // e.g., "Email 1", "Telefon 1", "Homepage"
-	Value string `json:"value"`  // The actual contact value
+	Type   string `json:"type"`    // e.g., "Email 1", "Telefon 1", "Homepage"
+	TypeID uint   `json:"type_id"` // adr_art ID, see AdrArt constants
+	Value  string `json:"value"`   // The actual contact value
+}
+
+// PersonFunctionsResponse lists the functions a person holds in clubs and federations
+type PersonFunctionsResponse struct {
+	UUID      string                  `json:"uuid"`
+	Name      string                  `json:"name"`
+	Firstname string                  `json:"firstname"`
+	Functions []RegionAddressResponse `json:"functions"`
 }
 
 // RegionInfo represents information about a region
@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// WebhookImportData is the data of import.completed and import.failed events
+type WebhookImportData struct {
+	Status      string     `json:"status" example:"success"`
+	StartedAt   *time.Time `json:"started_at"`
+	CompletedAt *time.Time `json:"completed_at"`
+	Step        string     `json:"step,omitempty"`  // Step that failed
+	Error       string     `json:"error,omitempty"` // Error of a failed import
+	Files       []string   `json:"files,omitempty"` // Imported files
+}
+
+// WebhookRatingChangesData is the data of player.rating_changed events
+// Long lists of changes are split into several events, numbered by part.
+type WebhookRatingChangesData struct {
+	ImportedAt *time.Time            `json:"imported_at,omitempty"`
+	Part       int                   `json:"part" example:"1"`
+	Parts      int                   `json:"parts" example:"1"`
+	Changes    []WebhookRatingChange `json:"changes"`
+}
+
+// WebhookRatingChange is the DWZ change of a player, one per current membership of the person
+type WebhookRatingChange struct {
+	PlayerID    string `json:"player_id" example:"C0101-1014"`
+	PersonUUID  string `json:"person_uuid"`
+	ClubID      string `json:"club_id" example:"C0101"`
+	Name        string `json:"name"`
+	Firstname   string `json:"firstname"`
+	DWZOld      *int   `json:"dwz_old"` // nil for a first rating
+	DWZOldIndex *int   `json:"dwz_old_index"`
+	DWZNew      int    `json:"dwz_new" example:"1856"`
+	DWZNewIndex int    `json:"dwz_new_index" example:"42"`
+}
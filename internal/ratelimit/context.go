@@ -0,0 +1,24 @@
+package ratelimit
+
+import "context"
+
+// chargeKey is the context key of the Charge of a request
+type chargeKey struct{}
+
+// Charge takes further tokens from the bucket a request was counted in
+type Charge func(tokens int) (Result, error)
+
+// WithCharge returns a context carrying the charge of a rate limited request
+func WithCharge(ctx context.Context, charge Charge) context.Context {
+	return context.WithValue(ctx, chargeKey{}, charge)
+}
+
+// ChargeRequest takes further tokens for the request of the context
+// Requests that are not rate limited are allowed without taking tokens.
+func ChargeRequest(ctx context.Context, tokens int) (Result, error) {
+	charge, ok := ctx.Value(chargeKey{}).(Charge)
+	if !ok || tokens <= 0 {
+		return Result{Allowed: true}, nil
+	}
+	return charge(tokens)
+}
@@ -0,0 +1,174 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule is a token bucket limit: sustained requests per minute and burst size
+type Rule struct {
+	RequestsPerMinute int
+	Burst             int
+}
+
+// Unlimited reports whether the rule disables limiting
+func (r Rule) Unlimited() bool {
+	return r.RequestsPerMinute <= 0
+}
+
+// ratePerSecond returns the token refill rate
+func (r Rule) ratePerSecond() float64 {
+	return float64(r.RequestsPerMinute) / 60
+}
+
+// capacity returns the bucket size, at least one token
+func (r Rule) capacity() float64 {
+	if r.Burst < 1 {
+		return 1
+	}
+	return float64(r.Burst)
+}
+
+// Result describes the state of a bucket after taking tokens
+type Result struct {
+	Allowed    bool
+	Limit      int           // Bucket capacity
+	Remaining  int           // Tokens left after this request
+	Reset      time.Duration // Time until the bucket is full again
+	RetryAfter time.Duration // Time until enough tokens are available if the request was denied
+}
+
+// Store keeps token buckets and the request counters used for load checks
+type Store interface {
+	// Take takes tokens from the bucket with the given key, none if the bucket holds too few
+	Take(ctx context.Context, key string, rule Rule, tokens int, now time.Time) (Result, error)
+	// CountRequest counts a request for the overall request rate
+	CountRequest(ctx context.Context, now time.Time) error
+	// RequestsPerMinute returns the overall request rate over the last minute
+	RequestsPerMinute(ctx context.Context, now time.Time) (float64, error)
+}
+
+// Limiter applies per route group rules to clients
+type Limiter struct {
+	store       Store
+	defaultRule Rule
+	groups      map[string]Rule
+}
+
+// NewLimiter creates a limiter, groups override the default rule per route group
+func NewLimiter(store Store, defaultRule Rule, groups map[string]Rule) *Limiter {
+	if groups == nil {
+		groups = make(map[string]Rule)
+	}
+	return &Limiter{
+		store:       store,
+		defaultRule: defaultRule,
+		groups:      groups,
+	}
+}
+
+// RuleFor returns the rule of a route group
+func (l *Limiter) RuleFor(group string) Rule {
+	if rule, ok := l.groups[group]; ok {
+		return rule
+	}
+	return l.defaultRule
+}
+
+// Allow counts the request and takes a token from the bucket of the client in the route group
+func (l *Limiter) Allow(ctx context.Context, group, client string) (Result, error) {
+	now := time.Now()
+	if err := l.store.CountRequest(ctx, now); err != nil {
+		return Result{}, err
+	}
+
+	rule := l.RuleFor(group)
+	if rule.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+	return l.store.Take(ctx, group+":"+client, rule, 1, now)
+}
+
+// Charge takes further tokens from the bucket of the client in the route group, for requests that
+// turn out to be more expensive than one token. The tokens are capped at the bucket capacity so an
+// expensive request is allowed once the bucket is full.
+func (l *Limiter) Charge(ctx context.Context, group, client string, tokens int) (Result, error) {
+	rule := l.RuleFor(group)
+	if rule.Unlimited() || tokens <= 0 {
+		return Result{Allowed: true}, nil
+	}
+	if capacity := int(rule.capacity()); tokens > capacity {
+		tokens = capacity
+	}
+	return l.store.Take(ctx, group+":"+client, rule, tokens, time.Now())
+}
+
+// RequestsPerMinute returns the overall request rate, used by the import load check
+func (l *Limiter) RequestsPerMinute(ctx context.Context) (float64, error) {
+	return l.store.RequestsPerMinute(ctx, time.Now())
+}
+
+// ParseRules parses route group rules of the form "group=requestsPerMinute:burst"
+func ParseRules(specs []string) (map[string]Rule, error) {
+	rules := make(map[string]Rule, len(specs))
+	for _, spec := range specs {
+		spec = strings.TrimSpace(spec)
+		if spec == "" {
+			continue
+		}
+
+		group, limits, ok := strings.Cut(spec, "=")
+		if !ok || strings.TrimSpace(group) == "" {
+			return nil, fmt.Errorf("invalid rate limit rule %q (expected group=requestsPerMinute:burst)", spec)
+		}
+
+		rpmValue, burstValue, hasBurst := strings.Cut(limits, ":")
+		rpm, err := strconv.Atoi(strings.TrimSpace(rpmValue))
+		if err != nil {
+			return nil, fmt.Errorf("invalid requests per minute in rate limit rule %q", spec)
+		}
+		burst := rpm
+		if hasBurst {
+			if burst, err = strconv.Atoi(strings.TrimSpace(burstValue)); err != nil {
+				return nil, fmt.Errorf("invalid burst in rate limit rule %q", spec)
+			}
+		}
+
+		rules[strings.TrimSpace(group)] = Rule{RequestsPerMinute: rpm, Burst: burst}
+	}
+	return rules, nil
+}
+
+// bucketResult calculates the result for a bucket holding tokens after a request for cost tokens
+func bucketResult(allowed bool, tokens float64, cost int, rule Rule) Result {
+	capacity := rule.capacity()
+	rate := rule.ratePerSecond()
+
+	result := Result{
+		Allowed:   allowed,
+		Limit:     int(capacity),
+		Remaining: int(math.Floor(tokens)),
+		Reset:     secondsToDuration((capacity - tokens) / rate),
+	}
+	if !allowed {
+		result.RetryAfter = secondsToDuration((float64(cost) - tokens) / rate)
+	}
+	return result
+}
+
+// slidingWindowRate approximates the requests of the last minute from two fixed minute windows
+func slidingWindowRate(previous, current int64, now time.Time) float64 {
+	elapsed := float64(now.Second())/60 + float64(now.Nanosecond())/60e9
+	return float64(previous)*(1-elapsed) + float64(current)
+}
+
+func secondsToDuration(seconds float64) time.Duration {
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(seconds * float64(time.Second)))
+}
@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// cleanupInterval is how often idle buckets are removed from the memory store
+const cleanupInterval = 5 * time.Minute
+
+// bucket is the state of a single token bucket
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	fullAt  time.Time // When the bucket is refilled completely
+}
+
+// MemoryStore keeps rate limit state in process memory
+// It is used when Redis is disabled; limits are then enforced per server instance.
+type MemoryStore struct {
+	mu          sync.Mutex
+	buckets     map[string]*bucket
+	lastCleanup time.Time
+
+	minute        int64
+	currentCount  int64
+	previousCount int64
+}
+
+// NewMemoryStore creates an in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// Take takes tokens from the bucket with the given key, none if the bucket holds too few
+func (s *MemoryStore) Take(ctx context.Context, key string, rule Rule, tokens int, now time.Time) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.cleanupLocked(now)
+
+	capacity := rule.capacity()
+	b, exists := s.buckets[key]
+	if !exists {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+
+	elapsed := now.Sub(b.updated).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(capacity, b.tokens+elapsed*rule.ratePerSecond())
+		b.updated = now
+	}
+
+	allowed := b.tokens >= float64(tokens)
+	if allowed {
+		b.tokens -= float64(tokens)
+	}
+
+	result := bucketResult(allowed, b.tokens, tokens, rule)
+	b.fullAt = now.Add(result.Reset)
+	return result, nil
+}
+
+// CountRequest counts a request for the overall request rate
+func (s *MemoryStore) CountRequest(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advanceLocked(now)
+	s.currentCount++
+	return nil
+}
+
+// RequestsPerMinute returns the overall request rate over the last minute
+func (s *MemoryStore) RequestsPerMinute(ctx context.Context, now time.Time) (float64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.advanceLocked(now)
+	return slidingWindowRate(s.previousCount, s.currentCount, now), nil
+}
+
+// advanceLocked moves the request counters to the minute of now
+func (s *MemoryStore) advanceLocked(now time.Time) {
+	minute := now.Unix() / 60
+	switch {
+	case minute == s.minute:
+		return
+	case minute == s.minute+1:
+		s.previousCount = s.currentCount
+	default:
+		s.previousCount = 0
+	}
+	s.currentCount = 0
+	s.minute = minute
+}
+
+// cleanupLocked removes buckets that have been refilled completely
+func (s *MemoryStore) cleanupLocked(now time.Time) {
+	if now.Sub(s.lastCleanup) < cleanupInterval {
+		return
+	}
+	s.lastCleanup = now
+
+	for key, b := range s.buckets {
+		// A full bucket is recreated in the same state on the next request
+		if !now.Before(b.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+}
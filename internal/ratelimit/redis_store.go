@@ -0,0 +1,121 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"portal64api/internal/cache"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Redis key prefixes of the rate limit state
+const (
+	redisBucketPrefix = "ratelimit:bucket:"
+	redisLoadPrefix   = "ratelimit:load:"
+)
+
+// tokenBucketScript refills and takes from a bucket atomically
+// KEYS[1] bucket key, ARGV: capacity, tokens per millisecond, now in milliseconds, tokens to take
+// Returns {allowed, tokens} with tokens as a string to keep the fraction.
+var tokenBucketScript = redis.NewScript(`
+local capacity = tonumber(ARGV[1])
+local rate = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local cost = tonumber(ARGV[4])
+
+local state = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(state[1])
+local ts = tonumber(state[2])
+if tokens == nil then
+	tokens = capacity
+	ts = now
+end
+
+if now > ts then
+	tokens = math.min(capacity, tokens + (now - ts) * rate)
+	ts = now
+end
+
+local allowed = 0
+if tokens >= cost then
+	tokens = tokens - cost
+	allowed = 1
+end
+
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", ts)
+redis.call("PEXPIRE", KEYS[1], math.ceil((capacity - tokens) / rate) + 1000)
+return {allowed, tostring(tokens)}
+`)
+
+// RedisStore keeps rate limit state in Redis, shared by all server instances
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a store on a Redis client, usually the one of the cache service
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Take takes tokens from the bucket with the given key, none if the bucket holds too few
+func (s *RedisStore) Take(ctx context.Context, key string, rule Rule, tokens int, now time.Time) (Result, error) {
+	values, err := tokenBucketScript.Run(ctx, s.client, []string{redisBucketPrefix + key},
+		rule.capacity(), rule.ratePerSecond()/1000, now.UnixMilli(), tokens).Slice()
+	if err != nil {
+		return Result{}, fmt.Errorf("rate limit script failed: %w", err)
+	}
+	if len(values) != 2 {
+		return Result{}, fmt.Errorf("unexpected rate limit script result %v", values)
+	}
+
+	allowed, _ := values[0].(int64)
+	tokenValue, _ := values[1].(string)
+	remaining, err := strconv.ParseFloat(tokenValue, 64)
+	if err != nil {
+		return Result{}, fmt.Errorf("unexpected rate limit token count %q", tokenValue)
+	}
+
+	return bucketResult(allowed == 1, remaining, tokens, rule), nil
+}
+
+// CountRequest counts a request for the overall request rate
+func (s *RedisStore) CountRequest(ctx context.Context, now time.Time) error {
+	key := redisLoadPrefix + strconv.FormatInt(now.Unix()/60, 10)
+
+	pipe := s.client.TxPipeline()
+	pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, 2*time.Minute)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// RequestsPerMinute returns the overall request rate over the last minute
+func (s *RedisStore) RequestsPerMinute(ctx context.Context, now time.Time) (float64, error) {
+	minute := now.Unix() / 60
+	values, err := s.client.MGet(ctx,
+		redisLoadPrefix+strconv.FormatInt(minute-1, 10),
+		redisLoadPrefix+strconv.FormatInt(minute, 10)).Result()
+	if err != nil {
+		return 0, err
+	}
+
+	counts := make([]int64, 2)
+	for i, value := range values {
+		if str, ok := value.(string); ok {
+			counts[i], _ = strconv.ParseInt(str, 10, 64)
+		}
+	}
+	return slidingWindowRate(counts[0], counts[1], now), nil
+}
+
+// NewStore creates a Redis store sharing the connection pool of the cache if Redis caching is
+// enabled, an in-memory store otherwise
+func NewStore(cacheService cache.CacheService) Store {
+	if redisService, ok := cacheService.(*cache.RedisService); ok && redisService.Client() != nil {
+		return NewRedisStore(redisService.Client())
+	}
+	return NewMemoryStore()
+}
@@ -1,16 +1,45 @@
 package repositories
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"portal64api/internal/database"
 	"portal64api/internal/models"
 	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
 
 	"gorm.io/gorm"
 )
 
+// organisationTypeClub is the organisationsart of chess clubs
+const organisationTypeClub = 20
+
+// addressSelectQuery selects officials with their person, organisation and function, filters are appended
+const addressSelectQuery = `
+	SELECT DISTINCT
+		a.id as address_id,
+		a.uuid as address_uuid,
+		COALESCE(p.name, '') as person_name,
+		COALESCE(p.vorname, '') as person_firstname,
+		COALESCE(o.name, '') as organisation_name,
+		COALESCE(o.kurzname, '') as organisation_shortname,
+		COALESCE(o.vkz, '') as organisation_vkz,
+		COALESCE(o.organisationsart, 0) as organisation_type,
+		o.verband as region,
+		COALESCE(fa.bezeichnung, f.funktionsalias, '') as function_name,
+		f.funktion as function_id,
+		a.organisation as organisation_id,
+		a.person as person_id
+	FROM adressen a
+	LEFT JOIN person p ON a.person = p.id AND a.istperson = 1
+	LEFT JOIN organisation o ON a.organisation = o.id
+	LEFT JOIN funktion f ON a.funktion = f.id
+	LEFT JOIN funktionsart fa ON f.funktion = fa.id
+	WHERE a.status = 1
+`
+
 // AddressRepository handles address-related database operations
 type AddressRepository struct {
 	mvdsb *gorm.DB
@@ -23,31 +52,18 @@ func NewAddressRepository(dbs *database.Databases) *AddressRepository {
 	}
 }
 
+// WithContext returns a copy of the repository running its queries with the context
+func (r *AddressRepository) WithContext(ctx context.Context) *AddressRepository {
+	if r == nil || r.mvdsb == nil {
+		return r
+	}
+	return &AddressRepository{mvdsb: r.mvdsb.WithContext(ctx)}
+}
+
 // GetRegionAddresses retrieves addresses for officials/functionaries in a specific region
 func (r *AddressRepository) GetRegionAddresses(region string, addressType string) ([]models.RegionAddressResponse, error) {
-	results := make([]models.RegionAddressResponse, 0)
-
 	// Build the query to get addresses for a specific region and type
-	query := `
-		SELECT DISTINCT
-			a.id as address_id,
-			a.uuid as address_uuid,
-			COALESCE(p.name, '') as person_name,
-			COALESCE(p.vorname, '') as person_firstname,
-			COALESCE(o.name, '') as organisation_name,
-			COALESCE(o.kurzname, '') as organisation_shortname,
-			o.verband as region,
-			COALESCE(fa.bezeichnung, f.funktionsalias, '') as function_name,
-			f.funktion as function_id,
-			a.organisation as organisation_id,
-			a.person as person_id
-		FROM adressen a
-		LEFT JOIN person p ON a.person = p.id AND a.istperson = 1
-		LEFT JOIN organisation o ON a.organisation = o.id
-		LEFT JOIN funktion f ON a.funktion = f.id
-		LEFT JOIN funktionsart fa ON f.funktion = fa.id
-		WHERE a.status = 1
-	`
+	query := addressSelectQuery
 
 	args := []interface{}{}
 
@@ -70,17 +86,54 @@ func (r *AddressRepository) GetRegionAddresses(region string, addressType string
 		case "kassenwart":
 			query += ` AND (fa.bezeichnung LIKE '%kasse%' OR fa.bezeichnung LIKE '%kassenwart%' OR f.funktionsalias LIKE '%kasse%')`
 		default:
-			query += ` AND (fa.bezeichnung LIKE ? OR f.funktionsalias LIKE ?)`
-			args = append(args, "%"+addressType+"%", "%"+addressType+"%")
+			query += ` AND (fa.bezeichnung LIKE ? ESCAPE '\\' OR f.funktionsalias LIKE ? ESCAPE '\\')`
+			pattern := "%" + utils.EscapeLike(addressType) + "%"
+			args = append(args, pattern, pattern)
 		}
 	}
 
 	query += ` ORDER BY o.name, fa.bezeichnung, f.funktionsalias, p.name`
 
+	return r.queryAddresses(query, args)
+}
+
+// SearchAddresses searches addresses of persons and organisations across all regions
+// Name and function are matched umlaut-insensitively, so "Mueller" finds "Müller" and vice versa.
+func (r *AddressRepository) SearchAddresses(name, function, region string, limit int) ([]models.RegionAddressResponse, error) {
+	query := addressSelectQuery
+
+	args := []interface{}{}
+
+	// Every word of the name has to match the person or the organisation
+	for _, word := range strings.Fields(name) {
+		query += ` AND ` + foldUmlautsSQL(`CONCAT_WS(' ', p.vorname, p.name, o.name, o.kurzname)`) + ` LIKE ? ESCAPE '\\'`
+		args = append(args, "%"+utils.EscapeLike(utils.FoldUmlauts(word))+"%")
+	}
+
+	if function != "" {
+		query += ` AND ` + foldUmlautsSQL(`COALESCE(fa.bezeichnung, f.funktionsalias, '')`) + ` LIKE ? ESCAPE '\\'`
+		args = append(args, "%"+utils.EscapeLike(utils.FoldUmlauts(function))+"%")
+	}
+
+	if region != "" {
+		query += ` AND o.verband = ?`
+		args = append(args, region)
+	}
+
+	query += ` ORDER BY o.verband, o.name, fa.bezeichnung, f.funktionsalias, p.name LIMIT ?`
+	args = append(args, limit)
+
+	return r.queryAddresses(query, args)
+}
+
+// queryAddresses runs an address query and attaches the contact details, keeping the row order
+func (r *AddressRepository) queryAddresses(query string, args []interface{}) ([]models.RegionAddressResponse, error) {
+	results := make([]models.RegionAddressResponse, 0)
+
 	// Execute the query
 	rows, err := r.mvdsb.Raw(query, args...).Rows()
 	if err != nil {
-		return nil, errors.NewInternalServerError("Failed to query region addresses: " + err.Error())
+		return nil, errors.NewDatabaseError(err, "Failed to query region addresses")
 	}
 	defer rows.Close()
 
@@ -96,6 +149,8 @@ func (r *AddressRepository) GetRegionAddresses(region string, addressType string
 			PersonFirstname       string  `json:"person_firstname"`
 			OrganisationName      string  `json:"organisation_name"`
 			OrganisationShortname string  `json:"organisation_shortname"`
+			OrganisationVKZ       string  `json:"organisation_vkz"`
+			OrganisationType      int     `json:"organisation_type"`
 			Region                *string `json:"region"`       // Made nullable to handle NULL values
 			FunctionName          string  `json:"function_name"`
 			FunctionID            *uint   `json:"function_id"`  // Made nullable to handle NULL values
@@ -110,13 +165,15 @@ func (r *AddressRepository) GetRegionAddresses(region string, addressType string
 			&result.PersonFirstname,
 			&result.OrganisationName,
 			&result.OrganisationShortname,
+			&result.OrganisationVKZ,
+			&result.OrganisationType,
 			&result.Region,
 			&result.FunctionName,
 			&result.FunctionID,
 			&result.OrganisationID,
 			&result.PersonID,
 		); err != nil {
-			return nil, errors.NewInternalServerError("Failed to scan address row: " + err.Error())
+			return nil, errors.NewDatabaseError(err, "Failed to scan address row")
 		}
 
 		// Create full name
@@ -159,6 +216,8 @@ func (r *AddressRepository) GetRegionAddresses(region string, addressType string
 			PersonFirstname:  result.PersonFirstname,
 			OrganisationName: result.OrganisationName,
 			OrganisationShortname: result.OrganisationShortname,
+			OrganisationVKZ:  result.OrganisationVKZ,
+			OrganisationLevel: organisationLevel(result.OrganisationID, result.OrganisationType),
 			Region:           region,
 			FunctionName:     result.FunctionName,
 			FunctionID:       functionID,
@@ -167,12 +226,14 @@ func (r *AddressRepository) GetRegionAddresses(region string, addressType string
 			ContactDetails:   []models.ContactDetail{},
 		}
 
+		if _, exists := addressMap[result.AddressID]; !exists {
+			addressIDs = append(addressIDs, result.AddressID)
+		}
 		addressMap[result.AddressID] = addressResp
-		addressIDs = append(addressIDs, result.AddressID)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, errors.NewInternalServerError("Error iterating over address rows: " + err.Error())
+		return nil, errors.NewDatabaseError(err, "Error iterating over address rows")
 	}
 
 	// If we have addresses, get their contact details
@@ -190,14 +251,47 @@ func (r *AddressRepository) GetRegionAddresses(region string, addressType string
 		}
 	}
 
-	// Convert map to slice
-	for _, addr := range addressMap {
-		results = append(results, *addr)
+	// Convert map to slice in query order
+	for _, addressID := range addressIDs {
+		results = append(results, *addressMap[addressID])
 	}
 
 	return results, nil
 }
 
+// GetPersonFunctions retrieves all functions a person holds in clubs and federations
+func (r *AddressRepository) GetPersonFunctions(personID uint) ([]models.RegionAddressResponse, error) {
+	query := addressSelectQuery + ` AND a.person = ? AND a.istperson = 1
+		ORDER BY o.organisationsart = ? DESC, o.vkz, fa.bezeichnung, f.funktionsalias`
+
+	return r.queryAddresses(query, []interface{}{personID, organisationTypeClub})
+}
+
+// GetPersonByUUID retrieves a person by UUID
+func (r *AddressRepository) GetPersonByUUID(uuid string) (*models.Person, error) {
+	var person models.Person
+	if err := r.mvdsb.Where("uuid = ?", uuid).First(&person).Error; err != nil {
+		return nil, err
+	}
+	return &person, nil
+}
+
+// organisationLevel classifies an organisation as club or federation (Verband, Bezirk, ...)
+func organisationLevel(organisationID *uint, organisationType int) string {
+	if organisationID == nil || *organisationID == 0 {
+		return ""
+	}
+	if organisationType == organisationTypeClub {
+		return "club"
+	}
+	return "federation"
+}
+
+// foldUmlautsSQL wraps a column expression to compare it like utils.FoldUmlauts
+func foldUmlautsSQL(expr string) string {
+	return `REPLACE(REPLACE(REPLACE(REPLACE(LOWER(` + expr + `), 'ä', 'ae'), 'ö', 'oe'), 'ü', 'ue'), 'ß', 'ss')`
+}
+
 // getContactDetailsForAddresses retrieves contact details for a list of address IDs
 func (r *AddressRepository) getContactDetailsForAddresses(addressIDs []uint) (map[uint][]models.ContactDetail, error) {
 	contactMap := make(map[uint][]models.ContactDetail)
@@ -232,7 +326,7 @@ func (r *AddressRepository) getContactDetailsForAddresses(addressIDs []uint) (ma
 
 	rows, err := r.mvdsb.Raw(query, args...).Rows()
 	if err != nil {
-		return nil, errors.NewInternalServerError("Failed to query contact details: " + err.Error())
+		return nil, errors.NewDatabaseError(err, "Failed to query contact details")
 	}
 	defer rows.Close()
 
@@ -250,13 +344,14 @@ func (r *AddressRepository) getContactDetailsForAddresses(addressIDs []uint) (ma
 			&result.ContactTypeName,
 			&result.ContactValue,
 		); err != nil {
-			return nil, errors.NewInternalServerError("Failed to scan contact detail row: " + err.Error())
+			return nil, errors.NewDatabaseError(err, "Failed to scan contact detail row")
 		}
 
 		// Create contact detail
 		detail := models.ContactDetail{
-			Type:  result.ContactTypeName,
-			Value: result.ContactValue,
+			Type:   result.ContactTypeName,
+			TypeID: result.ContactTypeID,
+			Value:  result.ContactValue,
 		}
 
 		// GDPR compliance: Filter out birth date information
@@ -269,7 +364,7 @@ func (r *AddressRepository) getContactDetailsForAddresses(addressIDs []uint) (ma
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, errors.NewInternalServerError("Error iterating over contact detail rows: " + err.Error())
+		return nil, errors.NewDatabaseError(err, "Error iterating over contact detail rows")
 	}
 
 	return contactMap, nil
@@ -294,14 +389,14 @@ func (r *AddressRepository) GetAvailableRegions() ([]models.RegionInfo, error) {
 
 	rows, err := r.mvdsb.Raw(query).Rows()
 	if err != nil {
-		return nil, errors.NewInternalServerError("Failed to query regions: " + err.Error())
+		return nil, errors.NewDatabaseError(err, "Failed to query regions")
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var region models.RegionInfo
 		if err := rows.Scan(&region.Code, &region.AddressCount); err != nil {
-			return nil, errors.NewInternalServerError("Failed to scan region row: " + err.Error())
+			return nil, errors.NewDatabaseError(err, "Failed to scan region row")
 		}
 
 		// Set region name based on code
@@ -310,7 +405,7 @@ func (r *AddressRepository) GetAvailableRegions() ([]models.RegionInfo, error) {
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, errors.NewInternalServerError("Error iterating over region rows: " + err.Error())
+		return nil, errors.NewDatabaseError(err, "Error iterating over region rows")
 	}
 
 	return regions, nil
@@ -348,21 +443,21 @@ func (r *AddressRepository) GetAddressTypes(region string) ([]models.AddressType
 
 	rows, err := r.mvdsb.Raw(query, args...).Rows()
 	if err != nil {
-		return nil, errors.NewInternalServerError("Failed to query address types: " + err.Error())
+		return nil, errors.NewDatabaseError(err, "Failed to query address types")
 	}
 	defer rows.Close()
 
 	for rows.Next() {
 		var addressType models.AddressTypeInfo
 		if err := rows.Scan(&addressType.ID, &addressType.Name, &addressType.Count); err != nil {
-			return nil, errors.NewInternalServerError("Failed to scan address type row: " + err.Error())
+			return nil, errors.NewDatabaseError(err, "Failed to scan address type row")
 		}
 
 		types = append(types, addressType)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, errors.NewInternalServerError("Error iterating over address type rows: " + err.Error())
+		return nil, errors.NewDatabaseError(err, "Error iterating over address type rows")
 	}
 
 	return types, nil
@@ -1,10 +1,13 @@
 package repositories
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 
 	"portal64api/internal/database"
 	"portal64api/internal/models"
+	"portal64api/pkg/utils"
 )
 
 // ClubRepository handles club data operations
@@ -17,6 +20,14 @@ func NewClubRepository(dbs *database.Databases) *ClubRepository {
 	return &ClubRepository{dbs: dbs}
 }
 
+// WithContext returns a copy of the repository running its queries with the context
+func (r *ClubRepository) WithContext(ctx context.Context) *ClubRepository {
+	if r == nil || r.dbs == nil {
+		return r
+	}
+	return &ClubRepository{dbs: r.dbs.WithContext(ctx)}
+}
+
 // GetClubByVKZ gets a club by its VKZ (Club ID)
 func (r *ClubRepository) GetClubByVKZ(vkz string) (*models.Organisation, error) {
 	var org models.Organisation
@@ -24,6 +35,17 @@ func (r *ClubRepository) GetClubByVKZ(vkz string) (*models.Organisation, error)
 	return &org, err
 }
 
+// GetClubsByVKZs gets several clubs by their VKZ in a single query
+// Clubs that do not exist are missing from the result.
+func (r *ClubRepository) GetClubsByVKZs(vkzs []string) ([]models.Organisation, error) {
+	clubs := make([]models.Organisation, 0, len(vkzs))
+	if len(vkzs) == 0 {
+		return clubs, nil
+	}
+	err := r.dbs.MVDSB.Where("vkz IN ? AND status = 0", vkzs).Order("id ASC").Find(&clubs).Error
+	return clubs, err
+}
+
 // SearchClubs searches for clubs by name or VKZ
 func (r *ClubRepository) SearchClubs(req models.SearchRequest) ([]models.Organisation, int64, error) {
 	clubs := make([]models.Organisation, 0)
@@ -33,8 +55,8 @@ func (r *ClubRepository) SearchClubs(req models.SearchRequest) ([]models.Organis
 
 	// Add search filter
 	if req.Query != "" {
-		searchPattern := "%" + req.Query + "%"
-		query = query.Where("name LIKE ? OR kurzname LIKE ? OR vkz LIKE ?", 
+		searchPattern := "%" + utils.EscapeLike(req.Query) + "%"
+		query = query.Where(`name LIKE ? ESCAPE '\\' OR kurzname LIKE ? ESCAPE '\\' OR vkz LIKE ? ESCAPE '\\'`,
 			searchPattern, searchPattern, searchPattern)
 	}
 
@@ -109,6 +131,73 @@ func (r *ClubRepository) GetClubAverageDWZ(organizationID uint) (float64, error)
 	return result.AvgDWZ, err
 }
 
+// GetClubMemberCounts gets the number of active members of several clubs, keyed by organisation ID
+func (r *ClubRepository) GetClubMemberCounts(organizationIDs []uint) (map[uint]int64, error) {
+	counts := make(map[uint]int64, len(organizationIDs))
+	if len(organizationIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		Organisation uint
+		Count        int64
+	}
+	err := r.dbs.MVDSB.Model(&models.Mitgliedschaft{}).
+		Select("organisation, COUNT(*) AS count").
+		Where("organisation IN ? AND bis IS NULL AND status = 0", organizationIDs).
+		Group("organisation").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.Organisation] = row.Count
+	}
+	return counts, nil
+}
+
+// GetClubAverageDWZs calculates the average DWZ of several clubs in a single query, keyed by organisation ID
+// Uses the window function of GetClubAverageDWZ partitioned by club as well.
+func (r *ClubRepository) GetClubAverageDWZs(organizationIDs []uint) (map[uint]float64, error) {
+	averages := make(map[uint]float64, len(organizationIDs))
+	if len(organizationIDs) == 0 {
+		return averages, nil
+	}
+
+	var rows []struct {
+		Organisation uint
+		AvgDWZ       float64
+	}
+	err := r.dbs.Portal64BDW.Raw(`
+		SELECT organisation, AVG(latest_dwz) as avg_dwz
+		FROM (
+			SELECT DISTINCT 
+				m.organisation,
+				e.idPerson,
+				FIRST_VALUE(e.dwzNew) OVER (
+					PARTITION BY m.organisation, e.idPerson 
+					ORDER BY e.id DESC
+				) as latest_dwz
+			FROM evaluation e
+			INNER JOIN mvdsb.mitgliedschaft m ON e.idPerson = m.person
+			WHERE m.organisation IN ? 
+				AND m.bis IS NULL 
+				AND m.status = 0
+				AND e.dwzNew > 0
+		) latest_evaluations
+		WHERE latest_dwz > 0
+		GROUP BY organisation
+	`, organizationIDs).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		averages[row.Organisation] = row.AvgDWZ
+	}
+	return averages, nil
+}
+
 // GetAllClubs gets all clubs for listing
 func (r *ClubRepository) GetAllClubs() ([]models.Organisation, error) {
 	clubs := make([]models.Organisation, 0)
@@ -117,6 +206,20 @@ func (r *ClubRepository) GetAllClubs() ([]models.Organisation, error) {
 	return clubs, err
 }
 
+// StreamAllClubs calls fn for each club of GetAllClubs in the same order, reading one row at a time
+func (r *ClubRepository) StreamAllClubs(fn func(models.Organisation) error) error {
+	query := r.dbs.MVDSB.Model(&models.Organisation{}).
+		Where("status = 0 AND organisationsart = 20 AND vkz != ''").
+		Order("name ASC")
+	return streamRows(query, func(rows *sql.Rows) error {
+		var club models.Organisation
+		if err := r.dbs.MVDSB.ScanRows(rows, &club); err != nil {
+			return err
+		}
+		return fn(club)
+	})
+}
+
 // GetClubContactInfo gets contact information for a club
 func (r *ClubRepository) GetClubContactInfo(organisationID uint) (map[string]string, error) {
 	contactInfo := make(map[string]string)
@@ -0,0 +1,169 @@
+package repositories
+
+import (
+	"context"
+	"database/sql"
+
+	"portal64api/internal/database"
+	"portal64api/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ExportRepository reads complete datasets for the bulk export
+// Large datasets are streamed row by row to a callback instead of being loaded into memory.
+type ExportRepository struct {
+	dbs *database.Databases
+}
+
+// NewExportRepository creates a new export repository
+func NewExportRepository(dbs *database.Databases) *ExportRepository {
+	return &ExportRepository{dbs: dbs}
+}
+
+// WithContext returns a copy of the repository running its queries with the context
+func (r *ExportRepository) WithContext(ctx context.Context) *ExportRepository {
+	if r == nil || r.dbs == nil {
+		return r
+	}
+	return &ExportRepository{dbs: r.dbs.WithContext(ctx)}
+}
+
+// CurrentRating is the DWZ of the latest evaluation of a person
+type CurrentRating struct {
+	IDPerson    uint `gorm:"column:idPerson"`
+	DWZNew      int  `gorm:"column:dwzNew"`
+	DWZNewIndex int  `gorm:"column:dwzNewIndex"`
+}
+
+// PersonMembership is a membership with the person holding it
+type PersonMembership struct {
+	models.Person
+	Organisation  uint `gorm:"column:organisation"`
+	Spielernummer uint `gorm:"column:spielernummer"`
+}
+
+// ExportMembershipRecord is a membership with joined club data and the UUID of the person
+type ExportMembershipRecord struct {
+	MembershipWithOrganisation
+	PersonUUID string `gorm:"column:person_uuid"`
+}
+
+// GetOrganisationsByID gets all organisations with a VKZ, keyed by ID
+func (r *ExportRepository) GetOrganisationsByID() (map[uint]models.Organisation, error) {
+	var orgs []models.Organisation
+	if err := r.dbs.MVDSB.Where("vkz != ''").Find(&orgs).Error; err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uint]models.Organisation, len(orgs))
+	for _, org := range orgs {
+		byID[org.ID] = org
+	}
+	return byID, nil
+}
+
+// GetCurrentRatings gets the DWZ of the latest evaluation of every person, keyed by person ID
+// Latest means highest evaluation ID, like in GetPlayerByID.
+func (r *ExportRepository) GetCurrentRatings() (map[uint]CurrentRating, error) {
+	ratings := make(map[uint]CurrentRating)
+	latest := r.dbs.Portal64BDW.Model(&models.Evaluation{}).Select("MAX(id)").Group("idPerson")
+	query := r.dbs.Portal64BDW.Model(&models.Evaluation{}).
+		Select("idPerson, dwzNew, dwzNewIndex").Where("id IN (?)", latest)
+
+	err := streamRows(query, func(rows *sql.Rows) error {
+		var rating CurrentRating
+		if err := r.dbs.Portal64BDW.ScanRows(rows, &rating); err != nil {
+			return err
+		}
+		ratings[rating.IDPerson] = rating
+		return nil
+	})
+	return ratings, err
+}
+
+// GetPersonUUIDs gets the UUIDs of all active persons, keyed by person ID
+func (r *ExportRepository) GetPersonUUIDs() (map[uint]string, error) {
+	type personUUID struct {
+		ID   uint   `gorm:"column:id"`
+		UUID string `gorm:"column:uuid"`
+	}
+
+	uuids := make(map[uint]string)
+	query := r.dbs.MVDSB.Model(&models.Person{}).Select("id, uuid").Where("status = 0")
+	err := streamRows(query, func(rows *sql.Rows) error {
+		var person personUUID
+		if err := r.dbs.MVDSB.ScanRows(rows, &person); err != nil {
+			return err
+		}
+		uuids[person.ID] = person.UUID
+		return nil
+	})
+	return uuids, err
+}
+
+// StreamCurrentMemberships calls fn for each current membership of an active person
+// PHP-style: include future-ending memberships. Ordered by club and membership number.
+func (r *ExportRepository) StreamCurrentMemberships(fn func(PersonMembership) error) error {
+	query := r.dbs.MVDSB.Table("mitgliedschaft m").
+		Select("p.*, m.organisation, m.spielernummer").
+		Joins("INNER JOIN person p ON p.id = m.person").
+		Where("p.status = 0 AND (m.bis IS NULL OR m.bis > CURDATE())").
+		Order("m.organisation ASC, m.spielernummer ASC")
+	return streamRows(query, func(rows *sql.Rows) error {
+		var membership PersonMembership
+		if err := r.dbs.MVDSB.ScanRows(rows, &membership); err != nil {
+			return err
+		}
+		return fn(membership)
+	})
+}
+
+// StreamMemberships calls fn for each membership of an active person in a club, current and past
+func (r *ExportRepository) StreamMemberships(fn func(ExportMembershipRecord) error) error {
+	query := r.dbs.MVDSB.Table("mitgliedschaft m").
+		Select("m.*, o.name AS club_name, o.vkz AS club_vkz, p.uuid AS person_uuid").
+		Joins("INNER JOIN organisation o ON m.organisation = o.id").
+		Joins("INNER JOIN person p ON p.id = m.person").
+		Where("p.status = 0 AND o.vkz != ''").
+		Order("m.person ASC, m.id ASC")
+	return streamRows(query, func(rows *sql.Rows) error {
+		var membership ExportMembershipRecord
+		if err := r.dbs.MVDSB.ScanRows(rows, &membership); err != nil {
+			return err
+		}
+		return fn(membership)
+	})
+}
+
+// StreamEvaluations calls fn for each evaluation of a computed tournament, like GetPlayerRatingHistory
+func (r *ExportRepository) StreamEvaluations(fn func(EvaluationWithTournament) error) error {
+	query := r.dbs.Portal64BDW.Table("evaluation e").
+		Select("e.*, tm.tname, tm.tcode, tm.finishedOn, tm.computedOn").
+		Joins("INNER JOIN tournamentmaster tm ON e.idMaster = tm.id").
+		Where("tm.computedOn IS NOT NULL").
+		Order("e.idPerson ASC, e.id ASC")
+	return streamRows(query, func(rows *sql.Rows) error {
+		var evaluation EvaluationWithTournament
+		if err := r.dbs.Portal64BDW.ScanRows(rows, &evaluation); err != nil {
+			return err
+		}
+		return fn(evaluation)
+	})
+}
+
+// streamRows runs a query and calls scan for each row, which reads it with ScanRows
+func streamRows(query *gorm.DB, scan func(rows *sql.Rows) error) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		if err := scan(rows); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
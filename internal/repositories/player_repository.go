@@ -1,7 +1,9 @@
 package repositories
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"portal64api/internal/database"
@@ -18,6 +20,14 @@ func NewPlayerRepository(dbs *database.Databases) *PlayerRepository {
 	return &PlayerRepository{dbs: dbs}
 }
 
+// WithContext returns a copy of the repository running its queries with the context
+func (r *PlayerRepository) WithContext(ctx context.Context) *PlayerRepository {
+	if r == nil || r.dbs == nil {
+		return r
+	}
+	return &PlayerRepository{dbs: r.dbs.WithContext(ctx)}
+}
+
 // GetPlayerByID gets a player by their ID (VKZ-Spielernummer format)
 func (r *PlayerRepository) GetPlayerByID(vkz string, spielernummer uint) (*models.Person, *models.Organisation, *models.Evaluation, error) {
 	// First, get the organization by VKZ
@@ -48,6 +58,227 @@ func (r *PlayerRepository) GetPlayerByID(vkz string, spielernummer uint) (*model
 	return &person, &org, &evaluation, nil
 }
 
+// PlayerKey identifies a player by the VKZ of the club and the membership number
+type PlayerKey struct {
+	VKZ           string
+	Spielernummer uint
+}
+
+// personBatchSize is the number of persons loaded per query by the batch lookups
+const personBatchSize = 1000
+
+// PlayerRecord holds the data of a player loaded by GetPlayersByIDs
+type PlayerRecord struct {
+	Person       models.Person
+	Organisation models.Organisation
+	Evaluation   *models.Evaluation // nil if the player has no DWZ evaluation
+}
+
+// GetPlayersByIDs gets several players with one query per table instead of one lookup per player
+// Players that do not exist are missing from the result. VKZs are compared case-insensitively like
+// the database does, the result is keyed by the requested keys.
+func (r *PlayerRepository) GetPlayersByIDs(keys []PlayerKey) (map[PlayerKey]PlayerRecord, error) {
+	if len(keys) == 0 {
+		return map[PlayerKey]PlayerRecord{}, nil
+	}
+
+	records, err := r.getPlayersByNormalizedKeys(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[PlayerKey]PlayerRecord, len(keys))
+	for _, key := range keys {
+		if record, found := records[key.normalized()]; found {
+			results[key] = record
+		}
+	}
+	return results, nil
+}
+
+// normalized returns the key with an upper case VKZ
+func (k PlayerKey) normalized() PlayerKey {
+	return PlayerKey{VKZ: strings.ToUpper(k.VKZ), Spielernummer: k.Spielernummer}
+}
+
+// getPlayersByNormalizedKeys loads the players of GetPlayersByIDs keyed by normalized keys
+func (r *PlayerRepository) getPlayersByNormalizedKeys(keys []PlayerKey) (map[PlayerKey]PlayerRecord, error) {
+	records := make(map[PlayerKey]PlayerRecord, len(keys))
+	wanted := make(map[PlayerKey]bool, len(keys))
+	vkzs := make([]string, 0, len(keys))
+	numbers := make([]uint, 0, len(keys))
+	for _, key := range keys {
+		wanted[key.normalized()] = true
+		vkzs = append(vkzs, key.VKZ)
+		numbers = append(numbers, key.Spielernummer)
+	}
+
+	orgsByID, orgIDs, err := r.getOrganisationsByVKZs(vkzs)
+	if err != nil {
+		return nil, err
+	}
+	if len(orgIDs) == 0 {
+		return records, nil
+	}
+
+	// Current memberships - PHP-style: include future-ending memberships
+	// The query matches every combination of club and number, only the requested ones are kept.
+	var memberships []models.Mitgliedschaft
+	err = r.dbs.MVDSB.Where("organisation IN ? AND spielernummer IN ? AND (bis IS NULL OR bis > CURDATE())", orgIDs, numbers).
+		Order("id ASC").Find(&memberships).Error
+	if err != nil {
+		return nil, err
+	}
+	personKeys := make(map[uint][]PlayerKey)
+	personIDs := make([]uint, 0, len(memberships))
+	found := make(map[PlayerKey]bool, len(memberships))
+	for _, membership := range memberships {
+		key := PlayerKey{VKZ: orgsByID[membership.Organisation].VKZ, Spielernummer: membership.Spielernummer}.normalized()
+		if !wanted[key] || found[key] {
+			continue
+		}
+		found[key] = true
+		if _, seen := personKeys[membership.Person]; !seen {
+			personIDs = append(personIDs, membership.Person)
+		}
+		personKeys[membership.Person] = append(personKeys[membership.Person], key)
+		records[key] = PlayerRecord{Organisation: orgsByID[membership.Organisation]}
+	}
+
+	if err := r.loadPersons(records, personKeys, personIDs); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// GetPlayersByClubs gets the current members of several clubs with one query per table
+// The result is keyed by the upper case VKZ of the club and the membership number. Like
+// GetPlayersByClub only active persons with a current membership are included, clubs that do
+// not exist have no players.
+func (r *PlayerRepository) GetPlayersByClubs(vkzs []string) (map[PlayerKey]PlayerRecord, error) {
+	records := make(map[PlayerKey]PlayerRecord)
+	if len(vkzs) == 0 {
+		return records, nil
+	}
+
+	orgsByID, orgIDs, err := r.getOrganisationsByVKZs(vkzs)
+	if err != nil {
+		return nil, err
+	}
+	if len(orgIDs) == 0 {
+		return records, nil
+	}
+
+	// Current memberships - PHP-style: include future-ending memberships
+	var memberships []models.Mitgliedschaft
+	err = r.dbs.MVDSB.Where("organisation IN ? AND (bis IS NULL OR bis > CURDATE())", orgIDs).
+		Order("id ASC").Find(&memberships).Error
+	if err != nil {
+		return nil, err
+	}
+	personKeys := make(map[uint][]PlayerKey)
+	personIDs := make([]uint, 0, len(memberships))
+	for _, membership := range memberships {
+		key := PlayerKey{VKZ: orgsByID[membership.Organisation].VKZ, Spielernummer: membership.Spielernummer}.normalized()
+		if _, found := records[key]; found {
+			continue
+		}
+		if _, seen := personKeys[membership.Person]; !seen {
+			personIDs = append(personIDs, membership.Person)
+		}
+		personKeys[membership.Person] = append(personKeys[membership.Person], key)
+		records[key] = PlayerRecord{Organisation: orgsByID[membership.Organisation]}
+	}
+
+	if err := r.loadPersons(records, personKeys, personIDs); err != nil {
+		return nil, err
+	}
+	for key, record := range records {
+		if record.Person.Status != 0 {
+			delete(records, key)
+		}
+	}
+	return records, nil
+}
+
+// getOrganisationsByVKZs loads the organisations of several VKZs keyed by ID
+// The first organisation of a VKZ wins like in GetPlayerByID, the IDs are returned in that order.
+func (r *PlayerRepository) getOrganisationsByVKZs(vkzs []string) (map[uint]models.Organisation, []uint, error) {
+	var orgs []models.Organisation
+	if err := r.dbs.MVDSB.Where("vkz IN ?", vkzs).Order("id ASC").Find(&orgs).Error; err != nil {
+		return nil, nil, err
+	}
+	orgsByID := make(map[uint]models.Organisation, len(orgs))
+	seenVKZ := make(map[string]bool, len(orgs))
+	orgIDs := make([]uint, 0, len(orgs))
+	for _, org := range orgs {
+		vkz := strings.ToUpper(org.VKZ)
+		if seenVKZ[vkz] {
+			continue
+		}
+		seenVKZ[vkz] = true
+		orgsByID[org.ID] = org
+		orgIDs = append(orgIDs, org.ID)
+	}
+	return orgsByID, orgIDs, nil
+}
+
+// loadPersons adds the persons and their latest DWZ evaluations to the records of their memberships
+func (r *PlayerRepository) loadPersons(records map[PlayerKey]PlayerRecord, personKeys map[uint][]PlayerKey, personIDs []uint) error {
+	if len(personIDs) == 0 {
+		return nil
+	}
+
+	// Persons are fetched in batches to stay below the MySQL placeholder limit for large rosters
+	var persons []models.Person
+	evaluationsByPerson := make(map[uint]models.Evaluation, len(personIDs))
+	for start := 0; start < len(personIDs); start += personBatchSize {
+		end := start + personBatchSize
+		if end > len(personIDs) {
+			end = len(personIDs)
+		}
+		batch := personIDs[start:end]
+
+		var batchPersons []models.Person
+		if err := r.dbs.MVDSB.Where("id IN ?", batch).Find(&batchPersons).Error; err != nil {
+			return err
+		}
+		persons = append(persons, batchPersons...)
+
+		// Latest DWZ evaluation per person from Portal64_BDW
+		var evaluations []models.Evaluation
+		latest := r.dbs.Portal64BDW.Model(&models.Evaluation{}).Select("MAX(id)").
+			Where("idPerson IN ?", batch).Group("idPerson")
+		if err := r.dbs.Portal64BDW.Where("id IN (?)", latest).Find(&evaluations).Error; err != nil {
+			return err
+		}
+		for _, evaluation := range evaluations {
+			evaluationsByPerson[evaluation.IDPerson] = evaluation
+		}
+	}
+
+	// Memberships without a person are dropped, GetPlayerByID fails for them as well
+	loaded := make(map[PlayerKey]bool, len(records))
+	for _, person := range persons {
+		for _, key := range personKeys[person.ID] {
+			record := records[key]
+			record.Person = person
+			if evaluation, ok := evaluationsByPerson[person.ID]; ok {
+				record.Evaluation = &evaluation
+			}
+			records[key] = record
+			loaded[key] = true
+		}
+	}
+	for key := range records {
+		if !loaded[key] {
+			delete(records, key)
+		}
+	}
+
+	return nil
+}
+
 // SearchPlayers searches for players by name
 func (r *PlayerRepository) SearchPlayers(req models.SearchRequest, showActive bool) ([]models.Person, int64, error) {
 	players := make([]models.Person, 0)
@@ -299,6 +530,30 @@ func (r *PlayerRepository) GetPlayerRatingHistory(personID uint) ([]EvaluationWi
 	return results, err
 }
 
+// GetPlayersRatingHistories gets the rating histories of several players in a single query
+// The histories are keyed by person ID and ordered like GetPlayerRatingHistory.
+func (r *PlayerRepository) GetPlayersRatingHistories(personIDs []uint) (map[uint][]EvaluationWithTournament, error) {
+	histories := make(map[uint][]EvaluationWithTournament, len(personIDs))
+	if len(personIDs) == 0 {
+		return histories, nil
+	}
+
+	var results []EvaluationWithTournament
+	err := r.dbs.Portal64BDW.Table("evaluation e").
+		Select("e.*, tm.tname, tm.tcode, tm.finishedOn, tm.computedOn").
+		Joins("INNER JOIN tournamentmaster tm ON e.idMaster = tm.id").
+		Where("e.idPerson IN ? AND tm.computedOn IS NOT NULL", personIDs).
+		Order("e.id DESC").Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		histories[result.IDPerson] = append(histories[result.IDPerson], result)
+	}
+	return histories, nil
+}
+
 // GetPlayerCurrentClub gets the current club for a player
 func (r *PlayerRepository) GetPlayerCurrentClub(personID uint) (*models.Organisation, error) {
 	// Get current club membership - PHP-style: include future-ending memberships
@@ -327,6 +582,48 @@ func (r *PlayerRepository) GetPlayerCurrentMembership(personID uint) (*models.Mi
 	return &membership, err
 }
 
+// MembershipWithOrganisation represents a club membership with joined club data
+type MembershipWithOrganisation struct {
+	models.Mitgliedschaft
+	ClubName string `gorm:"column:club_name"`
+	ClubVKZ  string `gorm:"column:club_vkz"`
+}
+
+// GetPlayerMemberships gets all club memberships of a player, current and past, newest first
+func (r *PlayerRepository) GetPlayerMemberships(personID uint) ([]MembershipWithOrganisation, error) {
+	var results []MembershipWithOrganisation
+	err := r.dbs.MVDSB.Table("mitgliedschaft m").
+		Select("m.*, o.name AS club_name, o.vkz AS club_vkz").
+		Joins("INNER JOIN organisation o ON m.organisation = o.id").
+		Where("m.person = ?", personID).
+		Order("IFNULL(m.bis, '9999-12-31') DESC, m.von DESC").Find(&results).Error
+	return results, err
+}
+
+// GetPlayersMemberships gets the memberships of several persons with one query, keyed by person ID
+// Like GetPlayerMemberships the memberships of each person are ordered newest first.
+func (r *PlayerRepository) GetPlayersMemberships(personIDs []uint) (map[uint][]MembershipWithOrganisation, error) {
+	memberships := make(map[uint][]MembershipWithOrganisation, len(personIDs))
+	if len(personIDs) == 0 {
+		return memberships, nil
+	}
+
+	var results []MembershipWithOrganisation
+	err := r.dbs.MVDSB.Table("mitgliedschaft m").
+		Select("m.*, o.name AS club_name, o.vkz AS club_vkz").
+		Joins("INNER JOIN organisation o ON m.organisation = o.id").
+		Where("m.person IN ?", personIDs).
+		Order("m.person, IFNULL(m.bis, '9999-12-31') DESC, m.von DESC").Find(&results).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range results {
+		memberships[result.Person] = append(memberships[result.Person], result)
+	}
+	return memberships, nil
+}
+
 // FormatPlayerID formats player ID in VKZ-XXX format
 func (r *PlayerRepository) FormatPlayerID(pkz, vkz string) string {
 	if pkz != "" {
@@ -1,12 +1,16 @@
 package repositories
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"portal64api/internal/database"
 	"portal64api/internal/models"
 	"portal64api/pkg/utils"
+
+	"gorm.io/gorm"
 )
 
 // TournamentRepository handles tournament data operations
@@ -19,6 +23,14 @@ func NewTournamentRepository(dbs *database.Databases) *TournamentRepository {
 	return &TournamentRepository{dbs: dbs}
 }
 
+// WithContext returns a copy of the repository running its queries with the context
+func (r *TournamentRepository) WithContext(ctx context.Context) *TournamentRepository {
+	if r == nil || r.dbs == nil {
+		return r
+	}
+	return &TournamentRepository{dbs: r.dbs.WithContext(ctx)}
+}
+
 // GetTournamentByCode gets a tournament by its code
 func (r *TournamentRepository) GetTournamentByCode(code string) (*models.Tournament, error) {
 	var tournament models.Tournament
@@ -26,8 +38,19 @@ func (r *TournamentRepository) GetTournamentByCode(code string) (*models.Tournam
 	return &tournament, err
 }
 
-// SearchTournaments searches for tournaments
-func (r *TournamentRepository) SearchTournaments(req models.SearchRequest) ([]models.Tournament, int64, error) {
+// GetTournamentsByCodes gets several tournaments by their code in a single query
+// Tournaments that do not exist are missing from the result.
+func (r *TournamentRepository) GetTournamentsByCodes(codes []string) ([]models.Tournament, error) {
+	tournaments := make([]models.Tournament, 0, len(codes))
+	if len(codes) == 0 {
+		return tournaments, nil
+	}
+	err := r.dbs.Portal64BDW.Where("tcode IN ?", codes).Order("id ASC").Find(&tournaments).Error
+	return tournaments, err
+}
+
+// SearchTournaments searches for tournaments with optional, combinable filters
+func (r *TournamentRepository) SearchTournaments(req models.SearchRequest, filter models.TournamentSearchFilter) ([]models.Tournament, int64, error) {
 	tournaments := make([]models.Tournament, 0)
 	var total int64
 
@@ -38,8 +61,8 @@ func (r *TournamentRepository) SearchTournaments(req models.SearchRequest) ([]mo
 
 	// Add search filter
 	if req.Query != "" {
-		searchPattern := "%" + req.Query + "%"
-		query = query.Where("tname LIKE ? OR tcode LIKE ?", searchPattern, searchPattern)
+		searchPattern := "%" + utils.EscapeLike(req.Query) + "%"
+		query = query.Where(`tname LIKE ? ESCAPE '\\' OR tcode LIKE ? ESCAPE '\\'`, searchPattern, searchPattern)
 	}
 
 	// Apply date filters if provided
@@ -47,6 +70,15 @@ func (r *TournamentRepository) SearchTournaments(req models.SearchRequest) ([]mo
 		query = query.Where("YEAR(finishedOn) = ?", req.FilterValue)
 	}
 
+	// Apply structured filters
+	query, ok, err := r.applyTournamentFilter(query, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !ok {
+		return tournaments, 0, nil
+	}
+
 	// Get total count
 	query.Count(&total)
 
@@ -61,13 +93,86 @@ func (r *TournamentRepository) SearchTournaments(req models.SearchRequest) ([]mo
 	}
 
 	// Apply pagination and execute
-	err := query.Order(orderBy).Limit(req.Limit).Offset(req.Offset).Find(&tournaments).Error
+	err = query.Order(orderBy).Limit(req.Limit).Offset(req.Offset).Find(&tournaments).Error
 	
 	return tournaments, total, err
 }
 
+// applyTournamentFilter adds the structured search filters to a tournament query
+// Returns false if the filter cannot match any tournament (e.g. unknown region)
+func (r *TournamentRepository) applyTournamentFilter(query *gorm.DB, filter models.TournamentSearchFilter) (*gorm.DB, bool, error) {
+	if filter.OrganisationID > 0 {
+		query = query.Where("idOrganisation = ?", filter.OrganisationID)
+	}
+	if filter.Region != "" {
+		// Organisations live in the MVDSB database, so resolve the VKZ prefix to IDs first
+		orgIDs, err := r.getOrganisationIDsByVKZPrefix(filter.Region)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(orgIDs) == 0 {
+			return query, false, nil
+		}
+		query = query.Where("idOrganisation IN ?", orgIDs)
+	}
+	if filter.TypeAcronym != "" {
+		// Compare the last part of the code exactly, the acronym may contain LIKE wildcards
+		query = query.Where("SUBSTRING_INDEX(tcode, '-', -1) = ?", filter.TypeAcronym)
+	}
+	if filter.Rounds > 0 {
+		query = query.Where("rounds = ?", filter.Rounds)
+	}
+	if filter.Computed != nil {
+		if *filter.Computed {
+			query = query.Where("computedOn IS NOT NULL")
+		} else {
+			query = query.Where("computedOn IS NULL")
+		}
+	}
+	if filter.AssessorID > 0 {
+		query = query.Where("assessor1 = ? OR assessor2 = ?", filter.AssessorID, filter.AssessorID)
+	}
+	if filter.FinishedFrom != nil {
+		query = query.Where("finishedOn >= ?", *filter.FinishedFrom)
+	}
+	if filter.FinishedTo != nil {
+		// Include the whole end day
+		query = query.Where("finishedOn < ?", filter.FinishedTo.AddDate(0, 0, 1))
+	}
+
+	return query, true, nil
+}
+
+// GetOrganisationNames gets the names of the given organisations keyed by ID
+func (r *TournamentRepository) GetOrganisationNames(orgIDs []uint) (map[uint]string, error) {
+	names := make(map[uint]string, len(orgIDs))
+	if len(orgIDs) == 0 {
+		return names, nil
+	}
+
+	var orgs []models.Organisation
+	err := r.dbs.MVDSB.Select("id, name").Where("id IN ?", orgIDs).Find(&orgs).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, org := range orgs {
+		names[org.ID] = org.Name
+	}
+	return names, nil
+}
+
+// getOrganisationIDsByVKZPrefix gets the IDs of all organisations whose VKZ starts with the given prefix
+func (r *TournamentRepository) getOrganisationIDsByVKZPrefix(prefix string) ([]uint, error) {
+	var ids []uint
+	err := r.dbs.MVDSB.Model(&models.Organisation{}).
+		Where(`vkz LIKE ? ESCAPE '\\'`, utils.EscapeLike(prefix)+"%").
+		Pluck("id", &ids).Error
+	return ids, err
+}
+
 // GetTournamentsByDateRange gets tournaments within a date range
-func (r *TournamentRepository) GetTournamentsByDateRange(startDate, endDate time.Time, req models.SearchRequest) ([]models.Tournament, int64, error) {
+func (r *TournamentRepository) GetTournamentsByDateRange(startDate, endDate time.Time, req models.SearchRequest, filter models.TournamentSearchFilter) ([]models.Tournament, int64, error) {
 	var tournaments []models.Tournament
 	var total int64
 
@@ -76,8 +181,16 @@ func (r *TournamentRepository) GetTournamentsByDateRange(startDate, endDate time
 
 	// Add search filter
 	if req.Query != "" {
-		searchPattern := "%" + req.Query + "%"
-		query = query.Where("tname LIKE ? OR tcode LIKE ?", searchPattern, searchPattern)
+		searchPattern := "%" + utils.EscapeLike(req.Query) + "%"
+		query = query.Where(`tname LIKE ? ESCAPE '\\' OR tcode LIKE ? ESCAPE '\\'`, searchPattern, searchPattern)
+	}
+
+	query, ok, err := r.applyTournamentFilter(query, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if !ok {
+		return tournaments, 0, nil
 	}
 
 	// Get total count
@@ -93,7 +206,7 @@ func (r *TournamentRepository) GetTournamentsByDateRange(startDate, endDate time
 		orderBy = fmt.Sprintf("%s %s", req.SortBy, direction)
 	}
 
-	err := query.Order(orderBy).Limit(req.Limit).Offset(req.Offset).Find(&tournaments).Error
+	err = query.Order(orderBy).Limit(req.Limit).Offset(req.Offset).Find(&tournaments).Error
 	
 	return tournaments, total, err
 }
@@ -107,12 +220,21 @@ func (r *TournamentRepository) GetTournamentResults(tournamentID uint) ([]models
 }
 
 // GetRecentTournaments gets recently finished tournaments
-func (r *TournamentRepository) GetRecentTournaments(days int, limit int) ([]models.Tournament, error) {
+func (r *TournamentRepository) GetRecentTournaments(days int, limit int, filter models.TournamentSearchFilter) ([]models.Tournament, error) {
 	var tournaments []models.Tournament
 	cutoff := time.Now().AddDate(0, 0, -days)
 	
-	err := r.dbs.Portal64BDW.Where("finishedOn >= ? AND tcode IS NOT NULL AND tcode != ''", cutoff).
-		Order("finishedOn DESC").Limit(limit).Find(&tournaments).Error
+	query := r.dbs.Portal64BDW.Where("finishedOn >= ? AND tcode IS NOT NULL AND tcode != ''", cutoff)
+
+	query, ok, err := r.applyTournamentFilter(query, filter)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return tournaments, nil
+	}
+
+	err = query.Order("finishedOn DESC").Limit(limit).Find(&tournaments).Error
 	
 	return tournaments, err
 }
@@ -125,6 +247,31 @@ func (r *TournamentRepository) GetParticipantCount(tournamentID uint) (int, erro
 	return int(count), err
 }
 
+// GetParticipantCounts gets the participant counts of several tournaments, keyed by tournament ID
+func (r *TournamentRepository) GetParticipantCounts(tournamentIDs []uint) (map[uint]int, error) {
+	counts := make(map[uint]int, len(tournamentIDs))
+	if len(tournamentIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		IDTournament uint `gorm:"column:idTournament"`
+		Count        int
+	}
+	err := r.dbs.Portal64BDW.Model(&models.Participant{}).
+		Select("idTournament, COUNT(*) AS count").
+		Where("idTournament IN ?", tournamentIDs).
+		Group("idTournament").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.IDTournament] = row.Count
+	}
+	return counts, nil
+}
+
 // GetEnhancedTournamentData gets comprehensive tournament data including participants, games, and evaluations
 func (r *TournamentRepository) GetEnhancedTournamentData(tournamentCode string) (*models.EnhancedTournamentResponse, error) {
 	// First get the basic tournament info
@@ -147,6 +294,16 @@ func (r *TournamentRepository) GetEnhancedTournamentData(tournamentCode string)
 		Note:             tournament.Note,
 	}
 
+	// Decode code fields; T-format codes carry no date and are left without code info
+	if codeInfo, err := utils.DecodeTournamentCode(tournament.TCode); err == nil {
+		response.CodeInfo = codeInfo
+	}
+
+	// League settings, only team competitions have them
+	if teamCompetition, err := r.getTeamCompetition(tournament.ID); err == nil {
+		response.TeamCompetition = teamCompetition
+	}
+
 	// Apply date normalization algorithm: if any date fields are null,
 	// use the latest available date to fill in null fields
 	r.normalizeTournamentDates(response)
@@ -173,7 +330,7 @@ func (r *TournamentRepository) GetEnhancedTournamentData(tournamentCode string)
 	}
 
 	// Get games and results
-	games, err := r.getGamesAndResults(tournament.ID)
+	games, err := r.GetTournamentRounds(tournament.ID)
 	if err == nil {
 		response.Games = games
 	}
@@ -189,6 +346,29 @@ func (r *TournamentRepository) GetEnhancedTournamentData(tournamentCode string)
 	return response, nil
 }
 
+// getTeamCompetition retrieves the league settings of a tournament, nil if it is no team competition
+func (r *TournamentRepository) getTeamCompetition(tournamentID uint) (*models.TeamCompetitionInfo, error) {
+	var turnier models.Turnier
+	err := r.dbs.Portal64BDW.Where("MID = ?", tournamentID).First(&turnier).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// A team of one player is an individual tournament with league settings
+	if turnier.AnzStammspieler < 2 {
+		return nil, nil
+	}
+	return &models.TeamCompetitionInfo{
+		Boards:      turnier.AnzStammspieler,
+		Substitutes: turnier.AnzErsatzspieler,
+		BoardSwap:   turnier.Brettertausch != 0,
+		Season:      turnier.Saison,
+	}, nil
+}
+
 // getOrganizationInfo retrieves organization details
 func (r *TournamentRepository) getOrganizationInfo(orgID uint) (*models.OrganizationInfo, error) {
 	var org models.Organisation
@@ -241,6 +421,7 @@ func (r *TournamentRepository) getAssessors(assessor1ID, assessor2ID uint) ([]mo
 }
 
 // getParticipantsWithDetails retrieves all participants with detailed information
+// Persons, memberships, clubs and historical ratings are loaded with one query each
 func (r *TournamentRepository) getParticipantsWithDetails(tournamentID uint, finishedOn *time.Time) ([]models.ParticipantInfo, error) {
 	// First get participants from portal64_bdw database
 	var participants []models.Participant
@@ -249,6 +430,37 @@ func (r *TournamentRepository) getParticipantsWithDetails(tournamentID uint, fin
 		return nil, err
 	}
 
+	personIDs := make([]uint, 0, len(participants))
+	membershipIDs := make([]uint, 0, len(participants))
+	for _, participant := range participants {
+		personIDs = append(personIDs, participant.IDPerson)
+		membershipIDs = append(membershipIDs, participant.IDMembership)
+	}
+
+	persons, err := r.getPersonsByIDs(personIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	memberships, err := r.getMembershipsByIDs(membershipIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	organisationIDs := make([]uint, 0, len(memberships))
+	for _, membership := range memberships {
+		organisationIDs = append(organisationIDs, membership.Organisation)
+	}
+	organisations, err := r.getOrganisationsByIDs(organisationIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	historicalRatings, err := r.getHistoricalRatings(personIDs, finishedOn)
+	if err != nil {
+		return nil, err
+	}
+
 	var participantInfos []models.ParticipantInfo
 	for _, participant := range participants {
 		participantInfo := models.ParticipantInfo{
@@ -266,11 +478,8 @@ func (r *TournamentRepository) getParticipantsWithDetails(tournamentID uint, fin
 			}
 		}
 
-		// Get person details from mvdsb database if person ID exists
 		if participant.IDPerson > 0 {
-			var person models.Person
-			err := r.dbs.MVDSB.Where("id = ?", participant.IDPerson).First(&person).Error
-			if err == nil {
+			if person, ok := persons[participant.IDPerson]; ok {
 				participantInfo.Name = person.Name
 				participantInfo.Firstname = person.Vorname
 				participantInfo.FullName = person.Name + ", " + person.Vorname
@@ -280,20 +489,14 @@ func (r *TournamentRepository) getParticipantsWithDetails(tournamentID uint, fin
 				participantInfo.FideID = person.IDFide
 			}
 
-			// Get club information if membership ID exists
-			if participant.IDMembership > 0 {
-				var membership models.Mitgliedschaft
-				err := r.dbs.MVDSB.Where("id = ?", participant.IDMembership).First(&membership).Error
-				if err == nil {
-					var organisation models.Organisation
-					err := r.dbs.MVDSB.Where("id = ?", membership.Organisation).First(&organisation).Error
-					if err == nil {
-						participantInfo.Club = &models.ClubInfo{
-							ID:               membership.ID,
-							Name:             organisation.Name,
-							VKZ:              organisation.VKZ,
-							MembershipNumber: int(membership.Spielberechtigung), // Using Spielberechtigung as membership number
-						}
+			// Get club information if membership exists
+			if membership, ok := memberships[participant.IDMembership]; ok {
+				if organisation, ok := organisations[membership.Organisation]; ok {
+					participantInfo.Club = &models.ClubInfo{
+						ID:               membership.ID,
+						Name:             organisation.Name,
+						VKZ:              organisation.VKZ,
+						MembershipNumber: int(membership.Spielberechtigung), // Using Spielberechtigung as membership number
 					}
 				}
 			}
@@ -302,8 +505,7 @@ func (r *TournamentRepository) getParticipantsWithDetails(tournamentID uint, fin
 			if participantInfo.Rating == nil {
 				participantInfo.Rating = &models.RatingInfo{}
 			}
-			historicalRating, err := r.getParticipantRating(participant.IDPerson, tournamentID, finishedOn)
-			if err == nil && historicalRating != nil {
+			if historicalRating, ok := historicalRatings[participant.IDPerson]; ok {
 				if participantInfo.Rating.DWZOld == nil {
 					participantInfo.Rating.DWZOld = historicalRating.DWZOld
 				}
@@ -319,129 +521,180 @@ func (r *TournamentRepository) getParticipantsWithDetails(tournamentID uint, fin
 	return participantInfos, nil
 }
 
-// getParticipantRating retrieves rating information for a participant
-func (r *TournamentRepository) getParticipantRating(personID uint, tournamentID uint, finishedOn *time.Time) (*models.RatingInfo, error) {
-	// This is a simplified version - the full logic from PHP is quite complex
-	// involving historical rating lookups and ELO ratings
-	
-	rating := &models.RatingInfo{}
-	
-	// Try to get the participant's use rating first
-	var participant models.Participant
-	err := r.dbs.Portal64BDW.Where("idTournament = ? AND idPerson = ?", tournamentID, personID).First(&participant).Error
-	if err == nil {
-		rating.UseRating = participant.UseRating
-		rating.UseRatingIndex = participant.UseRatingIndex
+// historicalRatingRow represents the last rating of a person before a tournament
+type historicalRatingRow struct {
+	IDPerson    uint `gorm:"column:id_person"`
+	DWZOld      *int `gorm:"column:dwz_old"`
+	DWZOldIndex *int `gorm:"column:dwz_old_index"`
+}
+
+// getHistoricalRatings retrieves the last computed rating before finishedOn for each person
+func (r *TournamentRepository) getHistoricalRatings(personIDs []uint, finishedOn *time.Time) (map[uint]*models.RatingInfo, error) {
+	ratings := make(map[uint]*models.RatingInfo)
+	personIDs = uniqueIDs(personIDs)
+	if finishedOn == nil || len(personIDs) == 0 {
+		return ratings, nil
 	}
 
-	// Get historical DWZ rating (simplified - the real logic is more complex)
-	if finishedOn != nil {
-		query := `
-			SELECT e.dwzNew as dwz_old, e.dwzNewIndex as dwz_old_index
+	// This is a simplified version - the full logic from PHP is quite complex
+	// involving historical rating lookups and ELO ratings.
+	// Only the newest evaluation per person is returned, long-standing players have thousands.
+	query := `
+		SELECT id_person, dwz_old, dwz_old_index
+		FROM (
+			SELECT e.idPerson as id_person, e.dwzNew as dwz_old, e.dwzNewIndex as dwz_old_index,
+				ROW_NUMBER() OVER (PARTITION BY e.idPerson ORDER BY tm.finishedOn DESC, tm.acron DESC) as rn
 			FROM evaluation e
 			INNER JOIN tournamentMaster tm ON e.idMaster = tm.id
-			WHERE e.idPerson = ? AND tm.finishedOn < ? AND tm.computedOn IS NOT NULL
-			ORDER BY tm.finishedOn DESC, tm.acron DESC
-			LIMIT 1
-		`
-		
-		type RatingRow struct {
-			DWZOld      *int `db:"dwz_old"`
-			DWZOldIndex *int `db:"dwz_old_index"`
-		}
-		
-		var ratingRow RatingRow
-		err := r.dbs.Portal64BDW.Raw(query, personID, finishedOn).Scan(&ratingRow).Error
-		if err == nil {
-			rating.DWZOld = ratingRow.DWZOld
-			rating.DWZOldIndex = ratingRow.DWZOldIndex
+			WHERE e.idPerson IN ? AND tm.finishedOn < ? AND tm.computedOn IS NOT NULL
+		) latest
+		WHERE rn = 1
+	`
+
+	var rows []historicalRatingRow
+	if err := r.dbs.Portal64BDW.Raw(query, personIDs, finishedOn).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		ratings[row.IDPerson] = &models.RatingInfo{
+			DWZOld:      row.DWZOld,
+			DWZOldIndex: row.DWZOldIndex,
 		}
 	}
 
-	return rating, nil
+	return ratings, nil
 }
 
-// getGamesAndResults retrieves all games and results for a tournament
-func (r *TournamentRepository) getGamesAndResults(tournamentID uint) ([]models.RoundInfo, error) {
+// GetTournamentRounds retrieves all rounds with games and results for a tournament
+// Appointments, games, results, participants, persons and result displays are loaded
+// with one query each and assembled in memory, independent of the tournament size
+func (r *TournamentRepository) GetTournamentRounds(tournamentID uint) ([]models.RoundInfo, error) {
 	// Get appointments (rounds)
 	var appointments []models.Appointment
 	err := r.dbs.Portal64BDW.Where("idTournament = ?", tournamentID).Order("round").Find(&appointments).Error
 	if err != nil {
 		return nil, err
 	}
+	if len(appointments) == 0 {
+		return nil, nil
+	}
 
-	var rounds []models.RoundInfo
+	appointmentIDs := make([]uint, 0, len(appointments))
 	for _, appointment := range appointments {
-		round := models.RoundInfo{
-			Round:       appointment.Round,
-			Appointment: appointment.Appointment,
-			Games:       []models.GameInfo{},
+		appointmentIDs = append(appointmentIDs, appointment.ID)
+	}
+
+	var games []models.Game
+	// Team matches number their boards from 1, the ID keeps the games of a board in entry order
+	err = r.dbs.Portal64BDW.Where("idAppointment IN ?", appointmentIDs).Order("board, id").Find(&games).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var results []models.Result
+	if len(games) > 0 {
+		gameIDs := make([]uint, 0, len(games))
+		for _, game := range games {
+			gameIDs = append(gameIDs, game.ID)
+		}
+		err = r.dbs.Portal64BDW.Where("idGame IN ?", gameIDs).Find(&results).Error
+		if err != nil {
+			return nil, err
 		}
+	}
 
-		// Get games for this round
-		var games []models.Game
-		err := r.dbs.Portal64BDW.Where("idAppointment = ?", appointment.ID).Order("board").Find(&games).Error
-		if err == nil {
-			for _, game := range games {
-				gameInfo := models.GameInfo{
-					ID:    game.ID,
-					Board: game.Board,
-				}
+	var participants []models.Participant
+	err = r.dbs.Portal64BDW.Select("idPerson, no").Where("idTournament = ?", tournamentID).Find(&participants).Error
+	if err != nil {
+		return nil, err
+	}
 
-				// Get results for this game
-				var results []models.Result
-				err := r.dbs.Portal64BDW.Where("idGame = ?", game.ID).Find(&results).Error
-				if err == nil {
-					// Process white and black results
-					for _, result := range results {
-						playerRef := models.PlayerRef{
-							ID: result.IDPerson,
-						}
-
-						// Get participant number and person details
-						var participant models.Participant
-						err := r.dbs.Portal64BDW.Where("idTournament = ? AND idPerson = ?", tournamentID, result.IDPerson).First(&participant).Error
-						if err == nil {
-							playerRef.No = participant.No
-						}
-
-						// Get person name from mvdsb database
-						if result.IDPerson > 0 {
-							var person models.Person
-							err := r.dbs.MVDSB.Where("id = ?", result.IDPerson).First(&person).Error
-							if err == nil {
-								playerRef.Name = person.Name + ", " + person.Vorname
-								playerRef.FullName = playerRef.Name
-							}
-						}
-
-						if result.Color == "W" {
-							gameInfo.White = playerRef
-							gameInfo.WhitePoints = result.Points
-						} else if result.Color == "B" {
-							gameInfo.Black = playerRef
-							gameInfo.BlackPoints = result.Points
-						}
-					}
+	personIDs := make([]uint, 0, len(results))
+	for _, result := range results {
+		personIDs = append(personIDs, result.IDPerson)
+	}
+	persons, err := r.getPersonsByIDs(personIDs)
+	if err != nil {
+		return nil, err
+	}
 
-					// Get result display information
-					if game.IDResultsDisplayRating > 0 {
-						var resultDisplay models.ResultsDisplay
-						err := r.dbs.Portal64BDW.Where("id = ?", game.IDResultsDisplayRating).First(&resultDisplay).Error
-						if err == nil {
-							gameInfo.Result = resultDisplay.Display
-						}
-					}
-				}
+	displayIDs := make([]uint, 0, len(games))
+	for _, game := range games {
+		displayIDs = append(displayIDs, game.IDResultsDisplayRating)
+	}
+	displays, err := r.getResultsDisplaysByIDs(displayIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	return assembleRounds(appointments, games, results, participants, persons, displays), nil
+}
+
+// assembleRounds builds the round structure from preloaded tournament data
+func assembleRounds(appointments []models.Appointment, games []models.Game, results []models.Result,
+	participants []models.Participant, persons map[uint]models.Person, displays map[uint]models.ResultsDisplay) []models.RoundInfo {
+
+	participantNumbers := make(map[uint]int, len(participants))
+	for _, participant := range participants {
+		participantNumbers[participant.IDPerson] = participant.No
+	}
+
+	resultsByGame := make(map[uint][]models.Result, len(games))
+	for _, result := range results {
+		resultsByGame[result.IDGame] = append(resultsByGame[result.IDGame], result)
+	}
+
+	// Games are ordered by board, so appending keeps the board order per round
+	gamesByAppointment := make(map[uint][]models.GameInfo, len(appointments))
+	for _, game := range games {
+		gameInfo := models.GameInfo{
+			ID:    game.ID,
+			Board: game.Board,
+		}
+
+		// Process white and black results
+		for _, result := range resultsByGame[game.ID] {
+			playerRef := models.PlayerRef{
+				ID: result.IDPerson,
+				No: participantNumbers[result.IDPerson],
+			}
+			if person, ok := persons[result.IDPerson]; ok {
+				playerRef.Name = person.Name + ", " + person.Vorname
+				playerRef.FullName = playerRef.Name
+			}
 
-				round.Games = append(round.Games, gameInfo)
+			if result.Color == "W" {
+				gameInfo.White = playerRef
+				gameInfo.WhitePoints = result.Points
+			} else if result.Color == "B" {
+				gameInfo.Black = playerRef
+				gameInfo.BlackPoints = result.Points
 			}
 		}
 
-		rounds = append(rounds, round)
+		// Get result display information
+		if display, ok := displays[game.IDResultsDisplayRating]; ok {
+			gameInfo.Result = display.Display
+		}
+
+		gamesByAppointment[game.IDAppointment] = append(gamesByAppointment[game.IDAppointment], gameInfo)
+	}
+
+	rounds := make([]models.RoundInfo, 0, len(appointments))
+	for _, appointment := range appointments {
+		roundGames := gamesByAppointment[appointment.ID]
+		if roundGames == nil {
+			roundGames = []models.GameInfo{}
+		}
+		rounds = append(rounds, models.RoundInfo{
+			Round:       appointment.Round,
+			Appointment: appointment.Appointment,
+			Games:       roundGames,
+		})
 	}
 
-	return rounds, nil
+	return rounds
 }
 
 // getEvaluations retrieves evaluation data for a computed tournament
@@ -452,6 +705,15 @@ func (r *TournamentRepository) getEvaluations(masterID uint) ([]models.Evaluatio
 		return nil, err
 	}
 
+	personIDs := make([]uint, 0, len(evaluations))
+	for _, eval := range evaluations {
+		personIDs = append(personIDs, eval.IDPerson)
+	}
+	persons, err := r.getPersonsByIDs(personIDs)
+	if err != nil {
+		return nil, err
+	}
+
 	var evaluationInfos []models.EvaluationInfo
 	for _, eval := range evaluations {
 		evaluationInfo := models.EvaluationInfo{
@@ -470,13 +732,8 @@ func (r *TournamentRepository) getEvaluations(masterID uint) ([]models.Evaluatio
 			DWZNewIndex:  eval.DWZNewIndex,
 		}
 
-		// Get player name from mvdsb database
-		if eval.IDPerson > 0 {
-			var person models.Person
-			err := r.dbs.MVDSB.Where("id = ?", eval.IDPerson).First(&person).Error
-			if err == nil {
-				evaluationInfo.PlayerName = person.Name + ", " + person.Vorname
-			}
+		if person, ok := persons[eval.IDPerson]; ok {
+			evaluationInfo.PlayerName = person.Name + ", " + person.Vorname
 		}
 
 		evaluationInfos = append(evaluationInfos, evaluationInfo)
@@ -485,6 +742,92 @@ func (r *TournamentRepository) getEvaluations(masterID uint) ([]models.Evaluatio
 	return evaluationInfos, nil
 }
 
+// getPersonsByIDs gets persons from the MVDSB database keyed by ID
+func (r *TournamentRepository) getPersonsByIDs(ids []uint) (map[uint]models.Person, error) {
+	persons := make(map[uint]models.Person)
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return persons, nil
+	}
+
+	var rows []models.Person
+	if err := r.dbs.MVDSB.Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, person := range rows {
+		persons[person.ID] = person
+	}
+	return persons, nil
+}
+
+// getMembershipsByIDs gets memberships from the MVDSB database keyed by ID
+func (r *TournamentRepository) getMembershipsByIDs(ids []uint) (map[uint]models.Mitgliedschaft, error) {
+	memberships := make(map[uint]models.Mitgliedschaft)
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return memberships, nil
+	}
+
+	var rows []models.Mitgliedschaft
+	if err := r.dbs.MVDSB.Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, membership := range rows {
+		memberships[membership.ID] = membership
+	}
+	return memberships, nil
+}
+
+// getOrganisationsByIDs gets organisations from the MVDSB database keyed by ID
+func (r *TournamentRepository) getOrganisationsByIDs(ids []uint) (map[uint]models.Organisation, error) {
+	organisations := make(map[uint]models.Organisation)
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return organisations, nil
+	}
+
+	var rows []models.Organisation
+	if err := r.dbs.MVDSB.Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, organisation := range rows {
+		organisations[organisation.ID] = organisation
+	}
+	return organisations, nil
+}
+
+// getResultsDisplaysByIDs gets result displays keyed by ID
+func (r *TournamentRepository) getResultsDisplaysByIDs(ids []uint) (map[uint]models.ResultsDisplay, error) {
+	displays := make(map[uint]models.ResultsDisplay)
+	ids = uniqueIDs(ids)
+	if len(ids) == 0 {
+		return displays, nil
+	}
+
+	var rows []models.ResultsDisplay
+	if err := r.dbs.Portal64BDW.Where("id IN ?", ids).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, display := range rows {
+		displays[display.ID] = display
+	}
+	return displays, nil
+}
+
+// uniqueIDs removes zero and duplicate IDs
+func uniqueIDs(ids []uint) []uint {
+	seen := make(map[uint]bool, len(ids))
+	unique := make([]uint, 0, len(ids))
+	for _, id := range ids {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return unique
+}
+
 // Helper methods
 func (r *TournamentRepository) getTournamentStatus(tournament *models.Tournament) string {
 	if tournament.FinishedOn != nil {
@@ -551,8 +894,12 @@ func (r *TournamentRepository) normalizeTournamentDates(response *models.Enhance
 		availableDates = append(availableDates, response.ComputedOn)
 	}
 	
-	// If no dates available, nothing to do
+	// If no dates available, fall back to the week encoded in the tournament code
 	if len(availableDates) == 0 {
+		if response.CodeInfo != nil && response.CodeInfo.ApproxStartDate != nil {
+			response.StartDate = response.CodeInfo.ApproxStartDate
+			response.EndDate = response.CodeInfo.ApproxStartDate
+		}
 		return
 	}
 	
@@ -2,19 +2,26 @@ package services
 
 import (
 	"context"
+	"strings"
 	"time"
 	
 	"portal64api/internal/cache"
 	"portal64api/internal/models"
 	"portal64api/internal/repositories"
+	"portal64api/internal/tracing"
 	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // AddressService handles address-related business logic
 type AddressService struct {
 	addressRepo  *repositories.AddressRepository
+	playerRepo   *repositories.PlayerRepository
 	cacheService cache.CacheService
 	keyGen       *cache.KeyGenerator
+	ctx          context.Context // Request context, set by WithContext
 }
 
 // NewAddressService creates a new address service
@@ -26,14 +33,51 @@ func NewAddressService(addressRepo *repositories.AddressRepository, cacheService
 	}
 }
 
+// WithContext returns a copy of the service handling a request with the given context
+// The context is detached from cancellation so background cache refreshes can complete.
+func (s *AddressService) WithContext(ctx context.Context) *AddressService {
+	ctx = context.WithoutCancel(ctx)
+	clone := *s
+	clone.ctx = ctx
+	if s.addressRepo != nil {
+		clone.addressRepo = s.addressRepo.WithContext(ctx)
+	}
+	if s.playerRepo != nil {
+		clone.playerRepo = s.playerRepo.WithContext(ctx)
+	}
+	return &clone
+}
+
+// startSpan starts a span for a service method and returns a copy of the service running in it
+func (s *AddressService) startSpan(method string) (*AddressService, trace.Span) {
+	ctx, span := tracing.Start(s.requestContext(), "AddressService."+method)
+	return s.WithContext(ctx), span
+}
+
+// requestContext returns the request context or a background context
+func (s *AddressService) requestContext() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
+// SetPlayerRepository sets the player repository used to resolve player IDs
+func (s *AddressService) SetPlayerRepository(playerRepo *repositories.PlayerRepository) {
+	s.playerRepo = playerRepo
+}
+
 // GetRegionAddresses retrieves addresses for officials/functionaries in a specific region
 func (s *AddressService) GetRegionAddresses(region string, addressType string) ([]models.RegionAddressResponse, error) {
+	s, span := s.startSpan("GetRegionAddresses")
+	defer span.End()
+
 	// Validate region parameter
 	if region == "" {
 		return nil, errors.NewBadRequestError("Region parameter is required")
 	}
 
-	ctx := context.Background()
+	ctx := s.requestContext()
 	cacheKey := s.keyGen.AddressRegionKey(region)
 	if addressType != "" {
 		cacheKey = s.keyGen.AddressTypesKey(region) // Use types key for filtered requests
@@ -59,9 +103,44 @@ func (s *AddressService) GetRegionAddresses(region string, addressType string) (
 	return addresses, nil
 }
 
+// SearchAddresses searches addresses across all regions by name and function
+func (s *AddressService) SearchAddresses(query, function, region string, limit int) ([]models.RegionAddressResponse, error) {
+	s, span := s.startSpan("SearchAddresses")
+	defer span.End()
+
+	query = strings.TrimSpace(query)
+	function = strings.TrimSpace(function)
+	if query == "" && function == "" {
+		return nil, errors.NewBadRequestError("Query parameter q or function is required")
+	}
+	if query != "" && len([]rune(query)) < 2 {
+		return nil, errors.NewBadRequestError("Query parameter q must have at least 2 characters")
+	}
+
+	ctx := s.requestContext()
+	cacheKey := s.keyGen.SearchKey("addresses", s.keyGen.GenerateAddressSearchHash(query, function, region, limit))
+
+	// Try cache first with background refresh
+	var cachedAddresses []models.RegionAddressResponse
+	err := s.cacheService.GetWithRefresh(ctx, cacheKey, &cachedAddresses,
+		func() (interface{}, error) {
+			return s.addressRepo.SearchAddresses(query, function, region, limit)
+		}, 1*time.Hour)
+
+	if err == nil {
+		return cachedAddresses, nil
+	}
+
+	// Cache miss or error - load directly from database
+	return s.addressRepo.SearchAddresses(query, function, region, limit)
+}
+
 // GetAvailableRegions retrieves all regions that have addresses
 func (s *AddressService) GetAvailableRegions() ([]models.RegionInfo, error) {
-	ctx := context.Background()
+	s, span := s.startSpan("GetAvailableRegions")
+	defer span.End()
+
+	ctx := s.requestContext()
 	cacheKey := s.keyGen.AddressRegionsKey()
 	
 	// Try cache first with background refresh
@@ -86,7 +165,10 @@ func (s *AddressService) GetAvailableRegions() ([]models.RegionInfo, error) {
 
 // GetAddressTypes retrieves available address types for a region
 func (s *AddressService) GetAddressTypes(region string) ([]models.AddressTypeInfo, error) {
-	ctx := context.Background()
+	s, span := s.startSpan("GetAddressTypes")
+	defer span.End()
+
+	ctx := s.requestContext()
 	cacheKey := s.keyGen.AddressTypesKey(region)
 	
 	// Try cache first with background refresh
@@ -108,3 +190,74 @@ func (s *AddressService) GetAddressTypes(region string) ([]models.AddressTypeInf
 
 	return types, nil
 }
+
+// GetPlayerFunctions retrieves all functions held by a player (VKZ-Spielernummer format)
+func (s *AddressService) GetPlayerFunctions(playerID string) (*models.PersonFunctionsResponse, error) {
+	s, span := s.startSpan("GetPlayerFunctions")
+	defer span.End()
+
+	vkz, spielernummer, err := utils.ParsePlayerID(playerID)
+	if err != nil {
+		return nil, errors.New(errors.CodeInvalidPlayerID, "Invalid player ID format")
+	}
+	if s.playerRepo == nil {
+		return nil, errors.New(errors.CodeServiceUnavailable, "Player repository not available")
+	}
+
+	return s.getPersonFunctions(s.keyGen.AddressPersonFunctionsKey(playerID), func() (*models.Person, error) {
+		person, _, _, err := s.playerRepo.GetPlayerByID(vkz, spielernummer)
+		return person, err
+	})
+}
+
+// GetPersonFunctionsByUUID retrieves all functions held by a person identified by UUID
+func (s *AddressService) GetPersonFunctionsByUUID(uuid string) (*models.PersonFunctionsResponse, error) {
+	s, span := s.startSpan("GetPersonFunctionsByUUID")
+	defer span.End()
+
+	if err := utils.ValidatePersonUUID(uuid); err != nil {
+		return nil, err
+	}
+	// UUIDs are case-insensitive, one cache entry per person
+	uuid = strings.ToLower(uuid)
+
+	return s.getPersonFunctions(s.keyGen.AddressPersonFunctionsKey(uuid), func() (*models.Person, error) {
+		return s.addressRepo.GetPersonByUUID(uuid)
+	})
+}
+
+// getPersonFunctions resolves a person and loads their functions, cached under the given key
+func (s *AddressService) getPersonFunctions(cacheKey string, findPerson func() (*models.Person, error)) (*models.PersonFunctionsResponse, error) {
+	load := func() (*models.PersonFunctionsResponse, error) {
+		person, err := findPerson()
+		if err != nil {
+			return nil, errors.FromLookup(err, errors.New(errors.CodePersonNotFound, "Person not found"))
+		}
+
+		functions, err := s.addressRepo.GetPersonFunctions(person.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.PersonFunctionsResponse{
+			UUID:      person.UUID,
+			Name:      person.Name,
+			Firstname: person.Vorname,
+			Functions: functions,
+		}, nil
+	}
+
+	// Try cache first with background refresh
+	var cachedFunctions models.PersonFunctionsResponse
+	err := s.cacheService.GetWithRefresh(s.requestContext(), cacheKey, &cachedFunctions,
+		func() (interface{}, error) {
+			return load()
+		}, 24*time.Hour) // Functions change with elections only
+
+	if err == nil {
+		return &cachedFunctions, nil
+	}
+
+	// Cache miss or error - load directly from database
+	return load()
+}
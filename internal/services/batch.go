@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"portal64api/internal/cache"
+	"portal64api/internal/models"
+	"portal64api/pkg/errors"
+)
+
+// uniqueIDs removes duplicate IDs of a batch lookup, keeping the order of the request
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// getCachedBatch looks up the cache entries of a batch lookup with a single MGet
+// keys maps the IDs to their cache keys. decode is called for each hit and reports whether the
+// entry could be used. The IDs that still have to be loaded are returned in the order of ids.
+// An unavailable cache is treated as a miss for every ID.
+func getCachedBatch(ctx context.Context, cacheService cache.CacheService, ids []string, keys map[string]string, decode func(id string, value interface{}) bool) []string {
+	cacheKeys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		cacheKeys = append(cacheKeys, keys[id])
+	}
+
+	cached, err := cacheService.MGet(ctx, cacheKeys)
+	if err != nil {
+		cached = nil
+	}
+
+	misses := make([]string, 0, len(ids))
+	for _, id := range ids {
+		value, hit := cached[keys[id]]
+		if !hit || !decode(id, value) {
+			misses = append(misses, id)
+		}
+	}
+	return misses
+}
+
+// decodeCached converts a value returned by MGet, which is decoded into generic JSON values,
+// into dest
+func decodeCached(value interface{}, dest interface{}) bool {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(data, dest) == nil
+}
+
+// newBatchError converts the error of a single ID into its batch result error
+func newBatchError(err error) *models.BatchError {
+	if apiErr, ok := err.(errors.APIError); ok {
+		return &models.BatchError{Code: apiErr.Code, ErrorCode: string(apiErr.ErrorCode), Message: apiErr.Message}
+	}
+	return &models.BatchError{Code: http.StatusInternalServerError, ErrorCode: string(errors.CodeInternal), Message: err.Error()}
+}
@@ -10,8 +10,11 @@ import (
 	"portal64api/internal/cache"
 	"portal64api/internal/models"
 	"portal64api/internal/repositories"
+	"portal64api/internal/tracing"
 	"portal64api/pkg/errors"
 	"portal64api/pkg/utils"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ClubService handles club business logic
@@ -20,6 +23,7 @@ type ClubService struct {
 	playerRepo   *repositories.PlayerRepository
 	cacheService cache.CacheService
 	keyGen       *cache.KeyGenerator
+	ctx          context.Context // Request context, set by WithContext
 }
 
 // NewClubService creates a new club service
@@ -32,6 +36,35 @@ func NewClubService(clubRepo *repositories.ClubRepository, cacheService cache.Ca
 	}
 }
 
+// WithContext returns a copy of the service handling a request with the given context
+// The context is detached from cancellation so background cache refreshes can complete.
+func (s *ClubService) WithContext(ctx context.Context) *ClubService {
+	ctx = context.WithoutCancel(ctx)
+	clone := *s
+	clone.ctx = ctx
+	if s.clubRepo != nil {
+		clone.clubRepo = s.clubRepo.WithContext(ctx)
+	}
+	if s.playerRepo != nil {
+		clone.playerRepo = s.playerRepo.WithContext(ctx)
+	}
+	return &clone
+}
+
+// startSpan starts a span for a service method and returns a copy of the service running in it
+func (s *ClubService) startSpan(method string) (*ClubService, trace.Span) {
+	ctx, span := tracing.Start(s.requestContext(), "ClubService."+method)
+	return s.WithContext(ctx), span
+}
+
+// requestContext returns the request context or a background context
+func (s *ClubService) requestContext() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
 // SetPlayerRepository sets the player repository for club-player operations
 func (s *ClubService) SetPlayerRepository(playerRepo *repositories.PlayerRepository) {
 	s.playerRepo = playerRepo
@@ -39,7 +72,10 @@ func (s *ClubService) SetPlayerRepository(playerRepo *repositories.PlayerReposit
 
 // GetClubByID gets a club by its VKZ (ID)
 func (s *ClubService) GetClubByID(clubID string) (*models.ClubResponse, error) {
-	ctx := context.Background()
+	s, span := s.startSpan("GetClubByID")
+	defer span.End()
+
+	ctx := s.requestContext()
 	cacheKey := s.keyGen.ClubKey(clubID)
 
 	// Try cache first with background refresh
@@ -61,7 +97,7 @@ func (s *ClubService) GetClubByID(clubID string) (*models.ClubResponse, error) {
 func (s *ClubService) loadClubFromDB(clubID string) (*models.ClubResponse, error) {
 	club, err := s.clubRepo.GetClubByVKZ(clubID)
 	if err != nil {
-		return nil, errors.NewNotFoundError("Club")
+		return nil, errors.FromLookup(err, errors.New(errors.CodeClubNotFound, "Club not found"))
 	}
 
 	// Get member count
@@ -70,7 +106,12 @@ func (s *ClubService) loadClubFromDB(clubID string) (*models.ClubResponse, error
 	// Get average DWZ
 	avgDWZ, _ := s.clubRepo.GetClubAverageDWZ(club.ID)
 
-	response := &models.ClubResponse{
+	return newClubResponse(club, memberCount, avgDWZ), nil
+}
+
+// newClubResponse converts the club data of the repository to response format
+func newClubResponse(club *models.Organisation, memberCount int64, avgDWZ float64) *models.ClubResponse {
+	return &models.ClubResponse{
 		ID:           club.VKZ,
 		Name:         club.Name,
 		ShortName:    club.Kurzname,
@@ -81,8 +122,91 @@ func (s *ClubService) loadClubFromDB(clubID string) (*models.ClubResponse, error
 		AverageDWZ:   avgDWZ,
 		Status:       getClubStatus(club.Status),
 	}
+}
 
-	return response, nil
+// GetClubsByIDs gets several clubs at once
+// Cached clubs are read with a single MGet, the others are loaded with set-based queries and
+// cached with MSet. There is one result per distinct ID in the order of the request, IDs that are
+// invalid or not found carry an error instead of data.
+func (s *ClubService) GetClubsByIDs(clubIDs []string) ([]models.ClubBatchResult, error) {
+	s, span := s.startSpan("GetClubsByIDs")
+	defer span.End()
+
+	ctx := s.requestContext()
+	ids := uniqueIDs(clubIDs)
+	failures := make(map[string]error)
+
+	valid := make([]string, 0, len(ids))
+	cacheKeys := make(map[string]string, len(ids))
+	for _, id := range ids {
+		if err := utils.ValidateClubID(id); err != nil {
+			failures[id] = err
+			continue
+		}
+		valid = append(valid, id)
+		cacheKeys[id] = s.keyGen.ClubKey(id)
+	}
+
+	clubs := make(map[string]*models.ClubResponse, len(valid))
+	misses := getCachedBatch(ctx, s.cacheService, valid, cacheKeys, func(id string, value interface{}) bool {
+		var club models.ClubResponse
+		if !decodeCached(value, &club) {
+			return false
+		}
+		clubs[id] = &club
+		return true
+	})
+
+	if len(misses) > 0 {
+		orgs, err := s.clubRepo.GetClubsByVKZs(misses)
+		if err != nil {
+			return nil, errors.NewDatabaseError(err, "Failed to get clubs")
+		}
+
+		// The first club wins like in GetClubByVKZ, VKZs are compared case-insensitively like the database does
+		orgsByVKZ := make(map[string]*models.Organisation, len(orgs))
+		orgIDs := make([]uint, 0, len(orgs))
+		for i := range orgs {
+			vkz := strings.ToUpper(orgs[i].VKZ)
+			if _, exists := orgsByVKZ[vkz]; !exists {
+				orgsByVKZ[vkz] = &orgs[i]
+				orgIDs = append(orgIDs, orgs[i].ID)
+			}
+		}
+
+		// Statistics are optional like in loadClubFromDB
+		memberCounts, err := s.clubRepo.GetClubMemberCounts(orgIDs)
+		if err != nil {
+			memberCounts = map[uint]int64{}
+		}
+		avgDWZs, err := s.clubRepo.GetClubAverageDWZs(orgIDs)
+		if err != nil {
+			avgDWZs = map[uint]float64{}
+		}
+
+		items := make(map[string]interface{}, len(misses))
+		for _, id := range misses {
+			org, found := orgsByVKZ[strings.ToUpper(id)]
+			if !found {
+				failures[id] = errors.New(errors.CodeClubNotFound, "Club not found")
+				continue
+			}
+			clubs[id] = newClubResponse(org, memberCounts[org.ID], avgDWZs[org.ID])
+			items[cacheKeys[id]] = clubs[id]
+		}
+		// Caching is best effort, the clubs are returned either way
+		_ = s.cacheService.MSet(ctx, items, 1*time.Hour)
+	}
+
+	results := make([]models.ClubBatchResult, 0, len(ids))
+	for _, id := range ids {
+		result := models.ClubBatchResult{ID: id, Data: clubs[id]}
+		if err, failed := failures[id]; failed {
+			result.Error = newBatchError(err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
 }
 
 // clubSearchResult wraps search results for caching
@@ -93,7 +217,10 @@ type clubSearchResult struct {
 
 // SearchClubs searches clubs by name or other criteria
 func (s *ClubService) SearchClubs(req models.SearchRequest) ([]models.ClubResponse, *models.Meta, error) {
-	ctx := context.Background()
+	s, span := s.startSpan("SearchClubs")
+	defer span.End()
+
+	ctx := s.requestContext()
 	searchHash := s.keyGen.GenerateSearchHash(req, false) // clubs don't have active flag
 	cacheKey := s.keyGen.SearchKey("club", searchHash)
 
@@ -120,7 +247,7 @@ func (s *ClubService) SearchClubs(req models.SearchRequest) ([]models.ClubRespon
 func (s *ClubService) executeClubSearch(req models.SearchRequest) (*clubSearchResult, error) {
 	clubs, total, err := s.clubRepo.SearchClubs(req)
 	if err != nil {
-		return nil, errors.NewInternalServerError("Failed to search clubs")
+		return nil, errors.NewDatabaseError(err, "Failed to search clubs")
 	}
 
 	responses := make([]models.ClubResponse, len(clubs))
@@ -159,7 +286,10 @@ func (s *ClubService) executeClubSearch(req models.SearchRequest) (*clubSearchRe
 
 // GetAllClubs gets all clubs
 func (s *ClubService) GetAllClubs() ([]models.ClubResponse, error) {
-	ctx := context.Background()
+	s, span := s.startSpan("GetAllClubs")
+	defer span.End()
+
+	ctx := s.requestContext()
 	cacheKey := s.keyGen.ClubListKey("all_clubs")
 
 	// Try cache first with background refresh
@@ -181,28 +311,54 @@ func (s *ClubService) GetAllClubs() ([]models.ClubResponse, error) {
 func (s *ClubService) loadAllClubsFromDB() ([]models.ClubResponse, error) {
 	clubs, err := s.clubRepo.GetAllClubs()
 	if err != nil {
-		return nil, errors.NewInternalServerError("Failed to get clubs")
+		return nil, errors.NewDatabaseError(err, "Failed to get clubs")
 	}
 
 	responses := make([]models.ClubResponse, len(clubs))
 	for i, club := range clubs {
-		responses[i] = models.ClubResponse{
-			ID:           club.VKZ,
-			Name:         club.Name,
-			ShortName:    club.Kurzname,
-			Region:       getRegionName(club.Verband),
-			District:     getDistrictName(club.Bezirk),
-			FoundingDate: club.Grundungsdatum,
-			Status:       getClubStatus(club.Status),
-		}
+		responses[i] = newClubListResponse(club)
 	}
 
 	return responses, nil
 }
 
+// StreamAllClubs calls fn for each club of GetAllClubs in the same order
+// The clubs are read row by row from the database and never held as a list, so the cache of
+// GetAllClubs is bypassed. Errors of fn are returned unchanged.
+func (s *ClubService) StreamAllClubs(fn func(models.ClubResponse) error) error {
+	s, span := s.startSpan("StreamAllClubs")
+	defer span.End()
+
+	var fnErr error
+	err := s.clubRepo.StreamAllClubs(func(club models.Organisation) error {
+		fnErr = fn(newClubListResponse(club))
+		return fnErr
+	})
+	if err != nil && err != fnErr {
+		return errors.NewDatabaseError(err, "Failed to get clubs")
+	}
+	return err
+}
+
+// newClubListResponse converts a club into the short form of club listings
+func newClubListResponse(club models.Organisation) models.ClubResponse {
+	return models.ClubResponse{
+		ID:           club.VKZ,
+		Name:         club.Name,
+		ShortName:    club.Kurzname,
+		Region:       getRegionName(club.Verband),
+		District:     getDistrictName(club.Bezirk),
+		FoundingDate: club.Grundungsdatum,
+		Status:       getClubStatus(club.Status),
+	}
+}
+
 // GetClubProfile gets comprehensive club profile with players and statistics
 func (s *ClubService) GetClubProfile(clubID string) (*models.ClubProfileResponse, error) {
-	ctx := context.Background()
+	s, span := s.startSpan("GetClubProfile")
+	defer span.End()
+
+	ctx := s.requestContext()
 	cacheKey := s.keyGen.ClubProfileKey(clubID)
 
 	// Try cache first with background refresh
@@ -0,0 +1,522 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"portal64api/internal/config"
+	"portal64api/internal/database"
+	"portal64api/internal/models"
+	"portal64api/internal/repositories"
+	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// exportManifestFile is the name of the manifest in a generation directory
+const exportManifestFile = "manifest.json"
+
+// exportGenerationLayout formats generation names, which sort chronologically
+const exportGenerationLayout = "20060102T150405Z"
+
+// exportGenerationPattern matches generation names, anything else is rejected as path
+var exportGenerationPattern = regexp.MustCompile(`^\d{8}T\d{6}Z$`)
+
+// exportFormats are the formats every dataset is written in
+var exportFormats = []string{models.ExportFormatNDJSON, models.ExportFormatCSV}
+
+// ExportService generates the bulk export and serves its files
+// Each generation is written to a temporary directory from one database snapshot and renamed to
+// its final name when complete, so readers only ever see complete generations.
+type ExportService struct {
+	config     *config.ExportConfig
+	dbs        *database.Databases
+	logger     *logrus.Logger
+	lastImport func() (time.Time, bool)
+	current    *models.ExportManifest
+	running    bool
+	mutex      sync.RWMutex
+	ctx        context.Context
+	cancel     context.CancelFunc
+}
+
+// NewExportService creates a new export service
+func NewExportService(config *config.ExportConfig, dbs *database.Databases, logger *logrus.Logger) *ExportService {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ExportService{
+		config: config,
+		dbs:    dbs,
+		logger: logger,
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// SetLastImportTime sets the source of the time of the last import, recorded in manifests
+func (s *ExportService) SetLastImportTime(lastImport func() (time.Time, bool)) {
+	s.lastImport = lastImport
+}
+
+// OnImportComplete implements ImportCompleteCallback interface
+func (s *ExportService) OnImportComplete() {
+	if _, err := s.Generate(s.ctx); err != nil {
+		s.logger.WithError(err).Error("Export after import failed")
+	}
+}
+
+// Start loads the newest generation and generates one in the background if there is none
+func (s *ExportService) Start() error {
+	if err := os.MkdirAll(s.config.Directory, 0755); err != nil {
+		return fmt.Errorf("failed to create export directory: %w", err)
+	}
+	s.removeIncomplete()
+
+	generations, err := s.generations()
+	if err != nil {
+		return err
+	}
+	for _, generation := range generations {
+		manifest, err := s.readManifest(generation)
+		if err != nil {
+			s.logger.WithError(err).Warnf("Skipping export generation %s", generation)
+			continue
+		}
+		s.mutex.Lock()
+		s.current = manifest
+		s.mutex.Unlock()
+		s.logger.Infof("Serving export generation %s", generation)
+		break
+	}
+
+	if s.Manifest() == nil && s.config.GenerateOnStartup {
+		go func() {
+			if _, err := s.Generate(s.ctx); err != nil {
+				s.logger.WithError(err).Error("Initial export failed")
+			}
+		}()
+	}
+	return nil
+}
+
+// Stop cancels a running generation
+func (s *ExportService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	return nil
+}
+
+// IsRunning reports whether a generation is running
+func (s *ExportService) IsRunning() bool {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.running
+}
+
+// Manifest returns the manifest of the newest generation, nil if there is none yet
+func (s *ExportService) Manifest() *models.ExportManifest {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.current
+}
+
+// GetManifest returns the manifest of a generation, the newest if generation is empty
+func (s *ExportService) GetManifest(generation string) (*models.ExportManifest, error) {
+	if generation == "" {
+		if manifest := s.Manifest(); manifest != nil {
+			return manifest, nil
+		}
+		if s.IsRunning() {
+			return nil, errors.New(errors.CodeExportNotReady, "The first export is being generated")
+		}
+		return nil, errors.New(errors.CodeExportNotReady, "No export has been generated yet")
+	}
+
+	if !exportGenerationPattern.MatchString(generation) {
+		return nil, errors.New(errors.CodeInvalidParameter, "Invalid generation, expected e.g. 20261018T031502Z")
+	}
+	manifest, err := s.readManifest(generation)
+	if err != nil {
+		return nil, errors.New(errors.CodeExportNotReady, fmt.Sprintf("Export generation %s is not available", generation))
+	}
+	return manifest, nil
+}
+
+// GetFile returns the path and description of a file of a generation, the newest if generation is empty
+func (s *ExportService) GetFile(generation, dataset, format string) (string, *models.ExportManifest, *models.ExportFile, error) {
+	manifest, err := s.GetManifest(generation)
+	if err != nil {
+		return "", nil, nil, err
+	}
+
+	for i := range manifest.Files {
+		file := &manifest.Files[i]
+		if file.Dataset == dataset && file.Format == format {
+			return filepath.Join(s.config.Directory, manifest.Generation, file.Name), manifest, file, nil
+		}
+	}
+	return "", nil, nil, errors.NewNotFoundError("Export dataset")
+}
+
+// StartGeneration generates a new export in the background
+func (s *ExportService) StartGeneration() error {
+	if err := s.begin(); err != nil {
+		return err
+	}
+	go func() {
+		if _, err := s.generate(s.ctx); err != nil {
+			s.logger.WithError(err).Error("Export failed")
+		}
+	}()
+	return nil
+}
+
+// Generate generates a new export and serves it once complete
+func (s *ExportService) Generate(ctx context.Context) (*models.ExportManifest, error) {
+	if err := s.begin(); err != nil {
+		return nil, err
+	}
+	return s.generate(ctx)
+}
+
+// begin marks a generation as running, only one runs at a time
+func (s *ExportService) begin() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.running {
+		return errors.New(errors.CodeExportInProgress, "An export is already being generated")
+	}
+	s.running = true
+	return nil
+}
+
+// generate writes a generation, begin must have been called
+func (s *ExportService) generate(ctx context.Context) (*models.ExportManifest, error) {
+	defer func() {
+		s.mutex.Lock()
+		s.running = false
+		s.mutex.Unlock()
+	}()
+
+	started := time.Now().UTC()
+	manifest := &models.ExportManifest{Generation: started.Format(exportGenerationLayout), GeneratedAt: started}
+	if s.lastImport != nil {
+		if importedAt, ok := s.lastImport(); ok {
+			manifest.ImportedAt = &importedAt
+		}
+	}
+	s.logger.Infof("Generating export %s", manifest.Generation)
+
+	dir := filepath.Join(s.config.Directory, manifest.Generation)
+	tempDir := filepath.Join(s.config.Directory, "."+manifest.Generation+".tmp")
+	if err := os.MkdirAll(tempDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	err := s.dbs.Snapshot(ctx, func(snapshot *database.Databases) error {
+		writer := &exportWriter{
+			dir:        tempDir,
+			generation: manifest.Generation,
+			exportRepo: repositories.NewExportRepository(snapshot),
+			clubRepo:   repositories.NewClubRepository(snapshot),
+		}
+		files, err := writer.writeAll()
+		manifest.Files = files
+		return err
+	})
+	if err == nil {
+		manifest.DurationMs = time.Since(started).Milliseconds()
+		err = writeExportManifest(filepath.Join(tempDir, exportManifestFile), manifest)
+	}
+	if err == nil {
+		err = os.Rename(tempDir, dir)
+	}
+	if err != nil {
+		os.RemoveAll(tempDir)
+		return nil, fmt.Errorf("failed to generate export %s: %w", manifest.Generation, err)
+	}
+
+	s.mutex.Lock()
+	s.current = manifest
+	s.mutex.Unlock()
+	s.logger.Infof("Export %s generated in %dms", manifest.Generation, manifest.DurationMs)
+
+	s.prune()
+	return manifest, nil
+}
+
+// generations returns the names of the complete generations on disk, newest first
+func (s *ExportService) generations() ([]string, error) {
+	entries, err := os.ReadDir(s.config.Directory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export directory: %w", err)
+	}
+
+	var generations []string
+	for _, entry := range entries {
+		if entry.IsDir() && exportGenerationPattern.MatchString(entry.Name()) {
+			generations = append(generations, entry.Name())
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(generations)))
+	return generations, nil
+}
+
+// prune removes the generations beyond the configured number to keep
+func (s *ExportService) prune() {
+	keep := s.config.Keep
+	if keep < 1 {
+		keep = 1
+	}
+
+	generations, err := s.generations()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to prune export generations")
+		return
+	}
+	for i := keep; i < len(generations); i++ {
+		if err := os.RemoveAll(filepath.Join(s.config.Directory, generations[i])); err != nil {
+			s.logger.WithError(err).Warnf("Failed to remove export generation %s", generations[i])
+		}
+	}
+}
+
+// removeIncomplete removes the temporary directories of interrupted generations
+func (s *ExportService) removeIncomplete() {
+	entries, err := os.ReadDir(s.config.Directory)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() && strings.HasPrefix(entry.Name(), ".") && strings.HasSuffix(entry.Name(), ".tmp") {
+			os.RemoveAll(filepath.Join(s.config.Directory, entry.Name()))
+		}
+	}
+}
+
+// readManifest reads the manifest of a generation
+func (s *ExportService) readManifest(generation string) (*models.ExportManifest, error) {
+	data, err := os.ReadFile(filepath.Join(s.config.Directory, generation, exportManifestFile))
+	if err != nil {
+		return nil, err
+	}
+	var manifest models.ExportManifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// writeExportManifest writes a manifest as indented JSON
+func writeExportManifest(path string, manifest *models.ExportManifest) error {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// exportWriter writes the datasets of a generation from a database snapshot
+type exportWriter struct {
+	dir        string
+	generation string
+	exportRepo *repositories.ExportRepository
+	clubRepo   *repositories.ClubRepository
+	orgs       map[uint]models.Organisation
+	uuids      map[uint]string
+}
+
+// writeAll writes all datasets and returns the descriptions of their files
+func (w *exportWriter) writeAll() ([]models.ExportFile, error) {
+	var err error
+	if w.orgs, err = w.exportRepo.GetOrganisationsByID(); err != nil {
+		return nil, fmt.Errorf("failed to get organisations: %w", err)
+	}
+	if w.uuids, err = w.exportRepo.GetPersonUUIDs(); err != nil {
+		return nil, fmt.Errorf("failed to get persons: %w", err)
+	}
+
+	datasets := []struct {
+		name   string
+		sample interface{}
+		write  func(dataset *exportDataset) error
+	}{
+		{models.ExportDatasetClubs, models.ClubResponse{}, w.writeClubs},
+		{models.ExportDatasetPlayers, models.ExportPlayer{}, w.writePlayers},
+		{models.ExportDatasetMemberships, models.ExportMembership{}, w.writeMemberships},
+		{models.ExportDatasetEvaluations, models.ExportEvaluation{}, w.writeEvaluations},
+	}
+
+	var files []models.ExportFile
+	for _, d := range datasets {
+		dataset, err := w.createDataset(d.name, d.sample)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create %s export: %w", d.name, err)
+		}
+		if err := d.write(dataset); err != nil {
+			dataset.abort()
+			return nil, fmt.Errorf("failed to export %s: %w", d.name, err)
+		}
+		written, err := dataset.close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to complete %s export: %w", d.name, err)
+		}
+		files = append(files, written...)
+	}
+	return files, nil
+}
+
+// writeClubs writes the active clubs with member count and average DWZ
+func (w *exportWriter) writeClubs(dataset *exportDataset) error {
+	clubs, err := w.clubRepo.GetAllClubs()
+	if err != nil {
+		return err
+	}
+
+	orgIDs := make([]uint, len(clubs))
+	for i, club := range clubs {
+		orgIDs[i] = club.ID
+	}
+	memberCounts, err := w.clubRepo.GetClubMemberCounts(orgIDs)
+	if err != nil {
+		return err
+	}
+	avgDWZs, err := w.clubRepo.GetClubAverageDWZs(orgIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range clubs {
+		club := &clubs[i]
+		if err := dataset.write(newClubResponse(club, memberCounts[club.ID], avgDWZs[club.ID])); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writePlayers writes a row per current membership of an active person with the current DWZ
+func (w *exportWriter) writePlayers(dataset *exportDataset) error {
+	ratings, err := w.exportRepo.GetCurrentRatings()
+	if err != nil {
+		return err
+	}
+
+	return w.exportRepo.StreamCurrentMemberships(func(membership repositories.PersonMembership) error {
+		org, ok := w.orgs[membership.Organisation]
+		if !ok {
+			return nil // Memberships of organisations without VKZ have no player ID
+		}
+
+		var evaluation *models.Evaluation
+		if rating, ok := ratings[membership.Person.ID]; ok {
+			evaluation = &models.Evaluation{DWZNew: rating.DWZNew, DWZNewIndex: rating.DWZNewIndex}
+		}
+		playerID := utils.GeneratePlayerID(org.VKZ, membership.Spielernummer)
+		return dataset.write(models.ExportPlayer{
+			PlayerResponse: *newPlayerResponse(playerID, &membership.Person, &org, evaluation),
+			PersonUUID:     membership.Person.UUID,
+		})
+	})
+}
+
+// writeMemberships writes the current and past memberships of active persons
+func (w *exportWriter) writeMemberships(dataset *exportDataset) error {
+	today := time.Now().Truncate(24 * time.Hour)
+	return w.exportRepo.StreamMemberships(func(membership repositories.ExportMembershipRecord) error {
+		return dataset.write(models.ExportMembership{
+			PersonUUID:         membership.PersonUUID,
+			MembershipResponse: newMembershipResponse(membership.MembershipWithOrganisation, today),
+		})
+	})
+}
+
+// writeEvaluations writes the evaluations of active persons like their rating histories
+func (w *exportWriter) writeEvaluations(dataset *exportDataset) error {
+	return w.exportRepo.StreamEvaluations(func(evaluation repositories.EvaluationWithTournament) error {
+		uuid, ok := w.uuids[evaluation.IDPerson]
+		if !ok {
+			return nil // Inactive or deleted person
+		}
+		entry, ok := newRatingHistoryEntry(evaluation)
+		if !ok {
+			return nil
+		}
+		return dataset.write(models.ExportEvaluation{PersonUUID: uuid, RatingHistoryResponse: entry})
+	})
+}
+
+// createDataset creates the files of a dataset in all export formats
+func (w *exportWriter) createDataset(name string, sample interface{}) (*exportDataset, error) {
+	dataset := &exportDataset{name: name, generation: w.generation}
+	for _, format := range exportFormats {
+		fileName := name + "." + format + ".gz"
+		file, err := utils.CreateTableFile(filepath.Join(w.dir, fileName), utils.Format(format), sample)
+		if err != nil {
+			dataset.abort()
+			return nil, err
+		}
+		dataset.formats = append(dataset.formats, format)
+		dataset.names = append(dataset.names, fileName)
+		dataset.files = append(dataset.files, file)
+	}
+	return dataset, nil
+}
+
+// exportDataset writes each row of a dataset to the files of all formats
+type exportDataset struct {
+	name       string
+	generation string
+	formats    []string
+	names      []string
+	files      []*utils.TableFile
+}
+
+func (d *exportDataset) write(row interface{}) error {
+	for _, file := range d.files {
+		if err := file.Write(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// close completes the files and returns their descriptions
+func (d *exportDataset) close() ([]models.ExportFile, error) {
+	files := make([]models.ExportFile, 0, len(d.files))
+	for i, file := range d.files {
+		info, err := file.Close()
+		if err != nil {
+			for _, remaining := range d.files[i+1:] {
+				remaining.Abort()
+			}
+			return nil, err
+		}
+		files = append(files, models.ExportFile{
+			Dataset:       d.name,
+			Format:        d.formats[i],
+			Name:          d.names[i],
+			URL:           fmt.Sprintf("/api/v1/export/%s?format=%s&generation=%s", d.name, d.formats[i], d.generation),
+			Rows:          info.Rows,
+			Size:          info.Size,
+			SHA256:        info.SHA256,
+			ContentSHA256: info.ContentSHA256,
+		})
+	}
+	return files, nil
+}
+
+func (d *exportDataset) abort() {
+	for _, file := range d.files {
+		file.Abort()
+	}
+}
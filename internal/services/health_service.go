@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"portal64api/internal/cache"
+	"portal64api/internal/database"
+	"portal64api/internal/models"
+	"portal64api/internal/version"
+)
+
+// healthCheckTimeout bounds each component check so a hanging dependency cannot block the probe
+const healthCheckTimeout = 2 * time.Second
+
+// Component names reported by the readiness check
+const (
+	ComponentMVDSB        = "mvdsb"
+	ComponentPortal64BDW  = "portal64_bdw"
+	ComponentCache        = "cache"
+	ComponentImport       = "import"
+	ComponentKaderPlanung = "kader_planung"
+)
+
+// HealthService checks the dependencies of the service for liveness and readiness probes
+type HealthService struct {
+	dbs                 *database.Databases
+	cacheService        cache.CacheService
+	importService       *ImportService
+	kaderPlanungService *KaderPlanungService
+	started             time.Time
+}
+
+// NewHealthService creates a new health service
+// Import and Kader-Planung services may be nil if they are disabled.
+func NewHealthService(dbs *database.Databases, cacheService cache.CacheService, importService *ImportService, kaderPlanungService *KaderPlanungService) *HealthService {
+	return &HealthService{
+		dbs:                 dbs,
+		cacheService:        cacheService,
+		importService:       importService,
+		kaderPlanungService: kaderPlanungService,
+		started:             time.Now(),
+	}
+}
+
+// componentCheck checks a single component
+// A nil result error means up; errComponentDisabled reports a component that is switched off.
+type componentCheck struct {
+	name     string
+	critical bool
+	check    func(ctx context.Context) error
+}
+
+var errComponentDisabled = errors.New("disabled")
+
+// Live reports that the process is running, without checking dependencies
+func (s *HealthService) Live() models.LivenessResponse {
+	return models.LivenessResponse{
+		Status:        models.HealthStatusHealthy,
+		Build:         BuildInfo(),
+		UptimeSeconds: int64(time.Since(s.started).Seconds()),
+	}
+}
+
+// Ready checks all components concurrently
+// The service is ready unless a critical component is down: a database is unreachable or an
+// import is replacing the databases. Cache and Kader-Planung problems only degrade the service.
+func (s *HealthService) Ready(ctx context.Context) models.ReadinessResponse {
+	checks := s.checks()
+	results := make([]models.ComponentHealth, len(checks))
+
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func(i int, check componentCheck) {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	response := models.ReadinessResponse{
+		Status:     models.HealthStatusHealthy,
+		Ready:      true,
+		Build:      BuildInfo(),
+		Components: make(map[string]models.ComponentHealth, len(checks)),
+	}
+	for i, check := range checks {
+		result := results[i]
+		response.Components[check.name] = result
+		if result.Status != models.ComponentStatusDown {
+			continue
+		}
+		if result.Critical {
+			response.Status = models.HealthStatusUnhealthy
+			response.Ready = false
+		} else if response.Status == models.HealthStatusHealthy {
+			response.Status = models.HealthStatusDegraded
+		}
+	}
+	return response
+}
+
+// checks returns the checks of all components
+func (s *HealthService) checks() []componentCheck {
+	return []componentCheck{
+		{name: ComponentMVDSB, critical: true, check: func(ctx context.Context) error {
+			return s.dbs.Ping(ctx, database.MVDSBName)
+		}},
+		{name: ComponentPortal64BDW, critical: true, check: func(ctx context.Context) error {
+			return s.dbs.Ping(ctx, database.Portal64BDWName)
+		}},
+		{name: ComponentImport, critical: true, check: func(ctx context.Context) error {
+			if s.importService == nil {
+				return errComponentDisabled
+			}
+			if s.importService.IsReplacingDatabases() {
+				return errors.New("import is replacing the databases")
+			}
+			return nil
+		}},
+		{name: ComponentCache, check: func(ctx context.Context) error {
+			if s.cacheService == nil {
+				return errComponentDisabled
+			}
+			err := s.cacheService.Ping(ctx)
+			if errors.Is(err, cache.ErrCacheNotEnabled) {
+				return errComponentDisabled
+			}
+			return err
+		}},
+		{name: ComponentKaderPlanung, check: func(ctx context.Context) error {
+			if s.kaderPlanungService == nil {
+				return errComponentDisabled
+			}
+			return s.kaderPlanungService.CheckBinary()
+		}},
+	}
+}
+
+// runCheck runs a component check with a timeout and measures its latency
+func runCheck(ctx context.Context, check componentCheck) models.ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.check(ctx)
+	result := models.ComponentHealth{
+		Status:    models.ComponentStatusUp,
+		Critical:  check.critical,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	switch {
+	case errors.Is(err, errComponentDisabled):
+		result.Status = models.ComponentStatusDisabled
+	case err != nil:
+		result.Status = models.ComponentStatusDown
+		result.Message = err.Error()
+	}
+	return result
+}
+
+// BuildInfo returns the version information set at build time
+func BuildInfo() models.BuildInfo {
+	return models.BuildInfo{
+		Version:   version.Version,
+		Commit:    version.Commit,
+		BuildTime: version.BuildTime,
+	}
+}
@@ -2,12 +2,14 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
 	"portal64api/internal/cache"
 	"portal64api/internal/config"
+	"portal64api/internal/events"
 	"portal64api/internal/importers"
 	"portal64api/internal/models"
 	"sync"
@@ -21,6 +23,16 @@ type ImportCompleteCallback interface {
 	OnImportComplete()
 }
 
+// ImportFailedCallback is implemented by completion callbacks that are also notified of failed imports
+type ImportFailedCallback interface {
+	OnImportFailed(err error)
+}
+
+// LoadMonitor reports the current API request rate, used to delay imports under heavy load
+type LoadMonitor interface {
+	RequestsPerMinute(ctx context.Context) (float64, error)
+}
+
 // ImportService handles scheduled and manual database imports
 type ImportService struct {
 	config       *config.ImportConfig
@@ -46,6 +58,13 @@ type ImportService struct {
 
 	// Callbacks
 	onCompleteCallbacks []ImportCompleteCallback
+
+	// Load monitoring
+	loadMonitor LoadMonitor
+
+	// Last successful import, loaded from the import metadata on first use
+	lastImportTime   time.Time
+	lastImportLoaded bool
 }
 
 // NewImportService creates a new import service instance
@@ -155,6 +174,49 @@ func (is *ImportService) GetStatus() *models.ImportStatus {
 	return is.statusTracker.GetStatus()
 }
 
+// SubscribeEvents subscribes to import status changes and log entries, the returned function unsubscribes
+func (is *ImportService) SubscribeEvents() (<-chan events.Event, func()) {
+	return is.statusTracker.Subscribe()
+}
+
+// GetMetrics returns the import outcomes and step durations for monitoring
+func (is *ImportService) GetMetrics() models.ImportMetrics {
+	return is.statusTracker.GetMetrics()
+}
+
+// IsReplacingDatabases reports whether an import is currently dropping and restoring the databases
+// Queries fail or return incomplete data during this step.
+func (is *ImportService) IsReplacingDatabases() bool {
+	status := is.statusTracker.GetStatus()
+	return status.Status == models.StatusRunning && status.CurrentStep == models.StepDatabaseImport
+}
+
+// GetLastImportTime returns the time of the last successful import
+// The second result is false if no successful import has been recorded.
+func (is *ImportService) GetLastImportTime() (time.Time, bool) {
+	is.mutex.RLock()
+	lastImport, loaded := is.lastImportTime, is.lastImportLoaded
+	is.mutex.RUnlock()
+
+	if !loaded {
+		lastImport = time.Time{}
+		record, err := is.freshnessChecker.GetLastImportInfo()
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			// The metadata may be readable again with the next request
+			return lastImport, false
+		}
+		if err == nil && record.Success {
+			lastImport = record.Timestamp
+		}
+
+		is.mutex.Lock()
+		is.lastImportTime, is.lastImportLoaded = lastImport, true
+		is.mutex.Unlock()
+	}
+
+	return lastImport, !lastImport.IsZero()
+}
+
 // GetLogs returns recent import log entries
 func (is *ImportService) GetLogs(limit int) []models.ImportLogEntry {
 	if limit <= 0 {
@@ -213,6 +275,7 @@ func (is *ImportService) executeImport() error {
 	// Execute import phases
 	if err := is.executeImportPhases(); err != nil {
 		is.statusTracker.MarkFailed(err, is.statusTracker.GetCurrentStep())
+		is.notifyFailureCallbacks(err)
 		return err
 	}
 
@@ -443,6 +506,11 @@ func (is *ImportService) executeCleanupPhase(downloadedFiles []models.FileMetada
 		// Don't fail the entire import for metadata issues
 	}
 
+	// Reload the last import time for conditional requests
+	is.mutex.Lock()
+	is.lastImportLoaded = false
+	is.mutex.Unlock()
+
 	// Cleanup temporary files if configured
 	if is.config.Storage.CleanupOnSuccess {
 		tempDir := is.config.Storage.TempDir
@@ -460,16 +528,7 @@ func (is *ImportService) executeCleanupPhase(downloadedFiles []models.FileMetada
 
 // checkLoadAndDelay checks API load and delays if necessary
 func (is *ImportService) checkLoadAndDelay() error {
-	// For now, implement a simple check
-	// This could be enhanced to check actual API metrics
-
 	for attempt := 0; attempt < is.config.LoadCheck.MaxDelays; attempt++ {
-		// Simple load check - in production this could check:
-		// - Active database connections
-		// - CPU usage
-		// - Memory usage
-		// - Request rate
-
 		if !is.isAPIUnderHeavyLoad() {
 			return nil // Proceed with import
 		}
@@ -488,11 +547,33 @@ func (is *ImportService) checkLoadAndDelay() error {
 	return fmt.Errorf("max delays exceeded, API still under heavy load")
 }
 
-// isAPIUnderHeavyLoad checks if the API is under heavy load
+// isAPIUnderHeavyLoad checks if the API request rate exceeds the load threshold (requests per minute)
+// Without a load monitor, or if the rate cannot be determined, the API is not considered under load.
 func (is *ImportService) isAPIUnderHeavyLoad() bool {
-	// Placeholder implementation
-	// In production, this could check actual metrics
-	return false
+	is.mutex.RLock()
+	monitor := is.loadMonitor
+	is.mutex.RUnlock()
+
+	if monitor == nil {
+		return false
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	rate, err := monitor.RequestsPerMinute(ctx)
+	if err != nil {
+		is.logger.Printf("Failed to determine API load, proceeding with import: %v", err)
+		return false
+	}
+	return rate > float64(is.config.LoadCheck.LoadThreshold)
+}
+
+// SetLoadMonitor sets the source of the API request rate for the load check
+func (is *ImportService) SetLoadMonitor(monitor LoadMonitor) {
+	is.mutex.Lock()
+	defer is.mutex.Unlock()
+	is.loadMonitor = monitor
 }
 
 // AddCompletionCallback adds a callback to be called when import completes successfully
@@ -521,3 +602,26 @@ func (is *ImportService) notifyCompletionCallbacks() {
 		}(callback)
 	}
 }
+
+// notifyFailureCallbacks calls the registered callbacks that are notified of failed imports
+func (is *ImportService) notifyFailureCallbacks(importErr error) {
+	is.mutex.RLock()
+	callbacks := make([]ImportCompleteCallback, len(is.onCompleteCallbacks))
+	copy(callbacks, is.onCompleteCallbacks)
+	is.mutex.RUnlock()
+
+	for _, callback := range callbacks {
+		failureCallback, ok := callback.(ImportFailedCallback)
+		if !ok {
+			continue
+		}
+		go func(cb ImportFailedCallback) {
+			defer func() {
+				if r := recover(); r != nil {
+					is.logger.Printf("Import failure callback panicked: %v", r)
+				}
+			}()
+			cb.OnImportFailed(importErr)
+		}(failureCallback)
+	}
+}
@@ -1,6 +1,9 @@
 package services
 
-import "portal64api/internal/models"
+import (
+	"portal64api/internal/events"
+	"portal64api/internal/models"
+)
 
 // ImportServiceInterface defines the contract for import services
 type ImportServiceInterface interface {
@@ -9,5 +12,6 @@ type ImportServiceInterface interface {
 	TriggerManualImport() error
 	GetStatus() *models.ImportStatus
 	GetLogs(limit int) []models.ImportLogEntry
+	SubscribeEvents() (<-chan events.Event, func())
 	TestConnection() error
 }
@@ -1,18 +1,25 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"portal64api/internal/config"
+	"portal64api/internal/events"
+	"portal64api/internal/tracing"
+
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ExecutionStatus represents the current execution status
@@ -24,16 +31,58 @@ type ExecutionStatus struct {
 	LastError       string    `json:"last_error"`
 	OutputFile      string    `json:"output_file"`
 	OutputFiles     []string  `json:"output_files"`
+	Job             string    `json:"job,omitempty"` // Job type of the current or last run
+	Progress        int       `json:"progress"`      // Progress of the current or last run in percent
+}
+
+// Analysis job types, used to report finished runs
+const (
+	JobKaderPlanung        = "kader_planung"
+	JobStatisticalAnalysis = "statistical_analysis"
+	JobHybridAnalysis      = "hybrid_analysis"
+)
+
+// AnalysisJobStats counts the finished runs of an analysis job type
+type AnalysisJobStats struct {
+	Succeeded    int64
+	Failed       int64
+	LastDuration time.Duration
+	LastSuccess  time.Time
+}
+
+// AnalysisRun describes a finished analysis run
+type AnalysisRun struct {
+	Job        string    `json:"job" example:"kader_planung"`
+	Success    bool      `json:"success"`
+	Error      string    `json:"error,omitempty"`
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	DurationMs int64     `json:"duration_ms"`
+}
+
+// AnalysisLogEntry is a line of output of an analysis run
+type AnalysisLogEntry struct {
+	Timestamp time.Time `json:"timestamp"`
+	Job       string    `json:"job" example:"kader_planung"`
+	Message   string    `json:"message"`
+}
+
+// AnalysisCompleteCallback defines the interface for analysis completion callbacks
+type AnalysisCompleteCallback interface {
+	OnAnalysisComplete(run AnalysisRun)
 }
 
 // KaderPlanungService manages the kader-planung functionality
 type KaderPlanungService struct {
-	config *config.KaderPlanungConfig
-	logger *logrus.Logger
-	status ExecutionStatus
-	mutex  sync.RWMutex
-	cancel context.CancelFunc
-	ctx    context.Context
+	config    *config.KaderPlanungConfig
+	logger    *logrus.Logger
+	status    ExecutionStatus
+	jobs      map[string]*AnalysisJobStats
+	callbacks []AnalysisCompleteCallback
+	events    *events.Broker
+	mutex     sync.RWMutex
+	cancel    context.CancelFunc
+	ctx       context.Context
 }
 
 // NewKaderPlanungService creates a new kader-planung service
@@ -52,6 +101,8 @@ func NewKaderPlanungService(config *config.KaderPlanungConfig, logger *logrus.Lo
 			OutputFile:    "",
 			OutputFiles:   []string{},
 		},
+		jobs:   make(map[string]*AnalysisJobStats),
+		events: events.NewBroker(events.DefaultBuffer),
 		cancel: cancel,
 		ctx:    ctx,
 	}
@@ -92,6 +143,99 @@ func (s *KaderPlanungService) GetStatus() ExecutionStatus {
 	return s.status
 }
 
+// SubscribeEvents subscribes to analysis progress, the returned function unsubscribes
+// Status events carry an ExecutionStatus, log events an AnalysisLogEntry and completed events an AnalysisRun.
+func (s *KaderPlanungService) SubscribeEvents() (<-chan events.Event, func()) {
+	return s.events.Subscribe()
+}
+
+// publishStatusUnsafe publishes a copy of the current status, the caller holds the lock
+func (s *KaderPlanungService) publishStatusUnsafe() {
+	status := s.status
+	status.OutputFiles = append([]string(nil), s.status.OutputFiles...)
+	s.events.Publish(events.TypeStatus, status)
+}
+
+// CheckBinary verifies that the kader-planung binary exists and is executable
+func (s *KaderPlanungService) CheckBinary() error {
+	path, err := exec.LookPath(s.config.BinaryPath)
+	if err != nil {
+		return fmt.Errorf("kader-planung binary not available: %w", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("kader-planung binary not available: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("kader-planung binary %s is a directory", path)
+	}
+	return nil
+}
+
+// GetJobStats returns the finished runs per analysis job type
+func (s *KaderPlanungService) GetJobStats() map[string]AnalysisJobStats {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	result := make(map[string]AnalysisJobStats, len(s.jobs))
+	for job, stats := range s.jobs {
+		result[job] = *stats
+	}
+	return result
+}
+
+// AddCompletionCallback adds a callback to be called when an analysis run finishes
+func (s *KaderPlanungService) AddCompletionCallback(callback AnalysisCompleteCallback) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.callbacks = append(s.callbacks, callback)
+}
+
+// recordJobUnsafe counts a finished run started at status.StartTime, the caller holds the lock
+// Completion callbacks are called in the background.
+func (s *KaderPlanungService) recordJobUnsafe(job string, err error) {
+	if s.jobs == nil {
+		s.jobs = make(map[string]*AnalysisJobStats)
+	}
+	stats, ok := s.jobs[job]
+	if !ok {
+		stats = &AnalysisJobStats{}
+		s.jobs[job] = stats
+	}
+
+	now := time.Now()
+	stats.LastDuration = now.Sub(s.status.StartTime)
+	if err != nil {
+		stats.Failed++
+	} else {
+		stats.Succeeded++
+		stats.LastSuccess = now
+		s.status.Progress = 100
+	}
+
+	run := AnalysisRun{
+		Job:        job,
+		Success:    err == nil,
+		StartedAt:  s.status.StartTime,
+		FinishedAt: now,
+		DurationMs: stats.LastDuration.Milliseconds(),
+	}
+	if err != nil {
+		run.Error = err.Error()
+	}
+	s.events.Publish(events.TypeCompleted, run)
+	for _, callback := range s.callbacks {
+		go func(cb AnalysisCompleteCallback) {
+			defer func() {
+				if r := recover(); r != nil {
+					s.logger.Errorf("Analysis completion callback panicked: %v", r)
+				}
+			}()
+			cb.OnAnalysisComplete(run)
+		}(callback)
+	}
+}
+
 // ExecuteManually executes kader-planung manually
 func (s *KaderPlanungService) ExecuteManually(params map[string]interface{}) error {
 	s.mutex.Lock()
@@ -100,8 +244,11 @@ func (s *KaderPlanungService) ExecuteManually(params map[string]interface{}) err
 		return fmt.Errorf("kader-planung execution already running")
 	}
 	s.status.Running = true
+	s.status.Job = JobKaderPlanung
+	s.status.Progress = 0
 	s.status.StartTime = time.Now()
 	s.status.LastError = ""
+	s.publishStatusUnsafe()
 	s.mutex.Unlock()
 
 	s.logger.Info("Manual kader-planung execution requested")
@@ -115,6 +262,7 @@ func (s *KaderPlanungService) ExecuteManually(params map[string]interface{}) err
 
 		s.status.Running = false
 		s.status.LastExecution = time.Now()
+		s.recordJobUnsafe(JobKaderPlanung, err)
 
 		if err != nil {
 			s.logger.Errorf("Kader-planung execution failed: %v", err)
@@ -130,6 +278,7 @@ func (s *KaderPlanungService) ExecuteManually(params map[string]interface{}) err
 				s.status.OutputFile = outputFiles[0]
 			}
 		}
+		s.publishStatusUnsafe()
 	}()
 
 	return nil
@@ -193,7 +342,10 @@ func (s *KaderPlanungService) ExecuteStatisticalAnalysis(params map[string]inter
 	
 	s.logger.Info("Starting statistical analysis")
 	s.status.Running = true
+	s.status.Job = JobStatisticalAnalysis
+	s.status.Progress = 0
 	s.status.StartTime = time.Now()
+	s.publishStatusUnsafe()
 	
 	// TODO: Implement actual statistical analysis execution
 	// For now, just simulate execution
@@ -204,6 +356,8 @@ func (s *KaderPlanungService) ExecuteStatisticalAnalysis(params map[string]inter
 		s.status.Running = false
 		s.status.LastExecution = time.Now()
 		s.status.LastSuccess = time.Now()
+		s.recordJobUnsafe(JobStatisticalAnalysis, nil)
+		s.publishStatusUnsafe()
 		s.logger.Info("Statistical analysis completed")
 	}()
 	
@@ -221,7 +375,10 @@ func (s *KaderPlanungService) ExecuteHybridAnalysis(params map[string]interface{
 	
 	s.logger.Info("Starting hybrid analysis")
 	s.status.Running = true
+	s.status.Job = JobHybridAnalysis
+	s.status.Progress = 0
 	s.status.StartTime = time.Now()
+	s.publishStatusUnsafe()
 	
 	// TODO: Implement actual hybrid analysis execution
 	// For now, just simulate execution
@@ -232,6 +389,8 @@ func (s *KaderPlanungService) ExecuteHybridAnalysis(params map[string]interface{
 		s.status.Running = false
 		s.status.LastExecution = time.Now()
 		s.status.LastSuccess = time.Now()
+		s.recordJobUnsafe(JobHybridAnalysis, nil)
+		s.publishStatusUnsafe()
 		s.logger.Info("Hybrid analysis completed")
 	}()
 	
@@ -282,25 +441,97 @@ func (s *KaderPlanungService) executeKaderPlanung(params map[string]interface{})
 
 	s.logger.Infof("Executing kader-planung: %s %v", s.config.BinaryPath, args)
 
+	// The run is a trace of its own, the API requests made by kader-planung join it
+	ctx, span := tracing.Start(s.ctx, "KaderPlanung.run", trace.WithNewRoot(),
+		trace.WithAttributes(attribute.StringSlice("process.command_args", args)))
+	defer span.End()
+
 	// Create command with context for cancellation
-	cmd := exec.CommandContext(s.ctx, s.config.BinaryPath, args...)
+	cmd := exec.CommandContext(ctx, s.config.BinaryPath, args...)
+	if traceParent := tracing.TraceParent(ctx); traceParent != "" {
+		cmd.Env = append(os.Environ(), "TRACEPARENT="+traceParent)
+	}
 
 	// Set working directory
 	if s.config.OutputDir != "" {
 		cmd.Dir = filepath.Dir(s.config.OutputDir)
 	}
 
-	// Capture output
-	output, err := cmd.CombinedOutput()
+	// Capture output, publishing it line by line while the run is in progress
+	output := &outputWriter{onLine: func(line string) { s.handleOutputLine(JobKaderPlanung, line) }}
+	cmd.Stdout = output
+	cmd.Stderr = output
+	err := cmd.Run()
+	output.Flush()
 	if err != nil {
-		return fmt.Errorf("kader-planung execution failed: exit status %v, output: %s", err, string(output))
+		tracing.RecordError(span, err)
+		return fmt.Errorf("kader-planung execution failed: exit status %v, output: %s", err, output.String())
 	}
 
-	s.logger.Infof("Kader-planung output: %s", string(output))
+	s.logger.Infof("Kader-planung output: %s", output.String())
 
 	return nil
 }
 
+// progressPattern matches the progress bar of kader-planung, e.g. "Processing clubs  45% |####   | (45/100)"
+var progressPattern = regexp.MustCompile(`(\d{1,3})%\s*\|`)
+
+// handleOutputLine publishes a line of output of a run, progress bar updates as status changes
+func (s *KaderPlanungService) handleOutputLine(job, line string) {
+	if match := progressPattern.FindStringSubmatch(line); match != nil {
+		progress, err := strconv.Atoi(match[1])
+		if err != nil || progress > 100 {
+			return
+		}
+
+		s.mutex.Lock()
+		defer s.mutex.Unlock()
+		if s.status.Running && s.status.Job == job && progress != s.status.Progress {
+			s.status.Progress = progress
+			s.publishStatusUnsafe()
+		}
+		return
+	}
+
+	s.events.Publish(events.TypeLog, AnalysisLogEntry{Timestamp: time.Now(), Job: job, Message: line})
+}
+
+// outputWriter collects the output of a run and passes it on line by line
+// Progress bars redraw their line with carriage returns, so '\r' ends a line as well.
+// The same writer is used for stdout and stderr; exec.Cmd then writes from a single goroutine.
+type outputWriter struct {
+	output bytes.Buffer
+	line   []byte
+	onLine func(line string)
+}
+
+// Write collects the output and passes on complete lines
+func (w *outputWriter) Write(p []byte) (int, error) {
+	w.output.Write(p)
+	for _, b := range p {
+		if b == '\n' || b == '\r' {
+			w.Flush()
+			continue
+		}
+		w.line = append(w.line, b)
+	}
+	return len(p), nil
+}
+
+// Flush passes on the pending line, if any
+func (w *outputWriter) Flush() {
+	line := strings.TrimSpace(string(w.line))
+	w.line = w.line[:0]
+	if line != "" {
+		w.onLine(line)
+	}
+}
+
+// String returns the collected output
+func (w *outputWriter) String() string {
+	return w.output.String()
+}
+
 // buildCommandArgs builds command line arguments from parameters
 func (s *KaderPlanungService) buildCommandArgs(params map[string]interface{}) []string {
 	args := []string{}
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"portal64api/internal/config"
+	"portal64api/internal/events"
 	"testing"
 	"time"
 
@@ -295,3 +296,35 @@ func TestKaderPlanungService_No_Stub_Methods(t *testing.T) {
 		assert.NotNil(t, files, "Should return initialized slice, not nil")
 	})
 }
+
+func TestKaderPlanungService_OutputEvents(t *testing.T) {
+	service := NewKaderPlanungService(&config.KaderPlanungConfig{}, logrus.New())
+	service.status.Running = true
+	service.status.Job = JobKaderPlanung
+
+	ch, unsubscribe := service.SubscribeEvents()
+	defer unsubscribe()
+
+	output := &outputWriter{onLine: func(line string) { service.handleOutputLine(JobKaderPlanung, line) }}
+	// The progress bar redraws its line, log lines end with newlines
+	_, err := output.Write([]byte("Fetching clubs\nProcessing clubs  45% |####    | (45/100)\rProcessing clubs  45% |####    | (45/100)"))
+	require.NoError(t, err)
+	output.Flush()
+
+	event := <-ch
+	assert.Equal(t, events.TypeLog, event.Type)
+	assert.Equal(t, "Fetching clubs", event.Data.(AnalysisLogEntry).Message)
+
+	// Unchanged progress is published once
+	event = <-ch
+	assert.Equal(t, events.TypeStatus, event.Type)
+	assert.Equal(t, 45, event.Data.(ExecutionStatus).Progress)
+	select {
+	case event = <-ch:
+		t.Fatalf("unexpected event %+v", event)
+	default:
+	}
+
+	assert.Contains(t, output.String(), "Fetching clubs\n")
+	assert.Equal(t, 45, service.GetStatus().Progress)
+}
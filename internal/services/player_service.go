@@ -3,13 +3,19 @@ package services
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"portal64api/internal/cache"
 	"portal64api/internal/interfaces"
 	"portal64api/internal/models"
+	"portal64api/internal/repositories"
+	"portal64api/internal/tracing"
 	"portal64api/pkg/errors"
 	"portal64api/pkg/utils"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // searchResult holds cached search results
@@ -31,6 +37,7 @@ type PlayerService struct {
 	tournamentRepo interfaces.TournamentRepositoryInterface
 	cacheService   cache.CacheService
 	keyGen         *cache.KeyGenerator
+	ctx            context.Context // Request context, set by WithContext
 }
 
 // NewPlayerService creates a new player service
@@ -44,9 +51,45 @@ func NewPlayerService(playerRepo interfaces.PlayerRepositoryInterface, clubRepo
 	}
 }
 
+// WithContext returns a copy of the service handling a request with the given context
+// Repository queries and log entries of the copy carry the request ID. The context is detached
+// from cancellation so background cache refreshes started by the request can complete.
+func (s *PlayerService) WithContext(ctx context.Context) *PlayerService {
+	ctx = context.WithoutCancel(ctx)
+	clone := *s
+	clone.ctx = ctx
+	if s.playerRepo != nil {
+		clone.playerRepo = s.playerRepo.WithContext(ctx)
+	}
+	if s.clubRepo != nil {
+		clone.clubRepo = s.clubRepo.WithContext(ctx)
+	}
+	if s.tournamentRepo != nil {
+		clone.tournamentRepo = s.tournamentRepo.WithContext(ctx)
+	}
+	return &clone
+}
+
+// startSpan starts a span for a service method and returns a copy of the service running in it
+func (s *PlayerService) startSpan(method string) (*PlayerService, trace.Span) {
+	ctx, span := tracing.Start(s.requestContext(), "PlayerService."+method)
+	return s.WithContext(ctx), span
+}
+
+// requestContext returns the request context or a background context
+func (s *PlayerService) requestContext() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
 // GetPlayerByID gets a player by their ID
 func (s *PlayerService) GetPlayerByID(playerID string) (*models.PlayerResponse, error) {
-	ctx := context.Background()
+	s, span := s.startSpan("GetPlayerByID")
+	defer span.End()
+
+	ctx := s.requestContext()
 	cacheKey := s.keyGen.PlayerKey(playerID)
 
 	// Try cache first with background refresh
@@ -69,16 +112,20 @@ func (s *PlayerService) loadPlayerFromDB(playerID string) (*models.PlayerRespons
 	// Parse player ID
 	vkz, spielernummer, err := utils.ParsePlayerID(playerID)
 	if err != nil {
-		return nil, errors.NewBadRequestError("Invalid player ID format")
+		return nil, errors.New(errors.CodeInvalidPlayerID, "Invalid player ID format")
 	}
 
 	// Get player data
 	person, org, evaluation, err := s.playerRepo.GetPlayerByID(vkz, spielernummer)
 	if err != nil {
-		return nil, errors.NewNotFoundError("Player")
+		return nil, errors.FromLookup(err, errors.New(errors.CodePlayerNotFound, "Player not found"))
 	}
 
-	// Convert to response format
+	return newPlayerResponse(playerID, person, org, evaluation), nil
+}
+
+// newPlayerResponse converts the player data of the repository to response format
+func newPlayerResponse(playerID string, person *models.Person, org *models.Organisation, evaluation *models.Evaluation) *models.PlayerResponse {
 	response := &models.PlayerResponse{
 		ID:        playerID,
 		PKZ:       person.PKZ,                                     // NEW: Add PKZ from Person table
@@ -103,12 +150,104 @@ func (s *PlayerService) loadPlayerFromDB(playerID string) (*models.PlayerRespons
 		response.DWZIndex = evaluation.DWZNewIndex
 	}
 
-	return response, nil
+	return response
+}
+
+// GetPlayersByIDs gets several players at once
+// Cached players are read with a single MGet, the others are loaded with set-based queries and
+// cached with MSet. There is one result per distinct ID in the order of the request, IDs that are
+// invalid or not found carry an error instead of data.
+func (s *PlayerService) GetPlayersByIDs(playerIDs []string) ([]models.PlayerBatchResult, error) {
+	s, span := s.startSpan("GetPlayersByIDs")
+	defer span.End()
+
+	ctx := s.requestContext()
+	ids := uniqueIDs(playerIDs)
+	failures := make(map[string]error)
+	valid, playerKeys := parseBatchPlayerIDs(ids, failures)
+
+	cacheKeys := make(map[string]string, len(valid))
+	for _, id := range valid {
+		cacheKeys[id] = s.keyGen.PlayerKey(id)
+	}
+
+	players := make(map[string]*models.PlayerResponse, len(valid))
+	misses := getCachedBatch(ctx, s.cacheService, valid, cacheKeys, func(id string, value interface{}) bool {
+		var player models.PlayerResponse
+		if !decodeCached(value, &player) {
+			return false
+		}
+		players[id] = &player
+		return true
+	})
+
+	if len(misses) > 0 {
+		records, err := s.playerRepo.GetPlayersByIDs(batchPlayerKeys(misses, playerKeys))
+		if err != nil {
+			return nil, errors.NewDatabaseError(err, "Failed to get players")
+		}
+
+		items := make(map[string]interface{}, len(misses))
+		for _, id := range misses {
+			record, found := records[playerKeys[id]]
+			if !found {
+				failures[id] = errors.New(errors.CodePlayerNotFound, "Player not found")
+				continue
+			}
+			players[id] = newPlayerResponse(id, &record.Person, &record.Organisation, record.Evaluation)
+			items[cacheKeys[id]] = players[id]
+		}
+		// Caching is best effort, the players are returned either way
+		_ = s.cacheService.MSet(ctx, items, 1*time.Hour)
+	}
+
+	results := make([]models.PlayerBatchResult, 0, len(ids))
+	for _, id := range ids {
+		result := models.PlayerBatchResult{ID: id, Data: players[id]}
+		if err, failed := failures[id]; failed {
+			result.Error = newBatchError(err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// parseBatchPlayerIDs returns the valid player IDs of a batch lookup with their repository keys
+// Invalid IDs are added to failures.
+func parseBatchPlayerIDs(ids []string, failures map[string]error) ([]string, map[string]repositories.PlayerKey) {
+	valid := make([]string, 0, len(ids))
+	keys := make(map[string]repositories.PlayerKey, len(ids))
+	for _, id := range ids {
+		if err := utils.ValidatePlayerID(id); err != nil {
+			failures[id] = err
+			continue
+		}
+		vkz, spielernummer, err := utils.ParsePlayerID(id)
+		if err != nil {
+			failures[id] = errors.New(errors.CodeInvalidPlayerID, "Invalid player ID format")
+			continue
+		}
+		valid = append(valid, id)
+		keys[id] = repositories.PlayerKey{VKZ: vkz, Spielernummer: spielernummer}
+	}
+	return valid, keys
+}
+
+// batchPlayerKeys returns the repository keys of the given player IDs
+func batchPlayerKeys(ids []string, keys map[string]repositories.PlayerKey) []repositories.PlayerKey {
+	playerKeys := make([]repositories.PlayerKey, 0, len(ids))
+	for _, id := range ids {
+		playerKeys = append(playerKeys, keys[id])
+	}
+	return playerKeys
 }
 
 // SearchPlayers searches players by name
 func (s *PlayerService) SearchPlayers(req models.SearchRequest, showActive bool) ([]models.PlayerResponse, *models.Meta, error) {
-	ctx := context.Background()
+	s, span := s.startSpan("SearchPlayers")
+	defer span.End()
+
+	ctx := s.requestContext()
 
 	// Generate cache key for this search
 	searchHash := s.keyGen.GenerateSearchHash(req, showActive)
@@ -139,7 +278,7 @@ func (s *PlayerService) SearchPlayers(req models.SearchRequest, showActive bool)
 func (s *PlayerService) executePlayerSearch(req models.SearchRequest, showActive bool) (interface{}, error) {
 	players, _, err := s.playerRepo.SearchPlayers(req, showActive)
 	if err != nil {
-		return nil, errors.NewInternalServerError("Failed to search players")
+		return nil, errors.NewDatabaseError(err, "Failed to search players")
 	}
 
 	// Convert to response format, but only include players with valid club memberships when showActive is true
@@ -204,7 +343,10 @@ func (s *PlayerService) executePlayerSearch(req models.SearchRequest, showActive
 
 // GetPlayersByClub gets all players in a specific club
 func (s *PlayerService) GetPlayersByClub(clubID string, req models.SearchRequest, showActive bool) ([]models.PlayerResponse, *models.Meta, error) {
-	ctx := context.Background()
+	s, span := s.startSpan("GetPlayersByClub")
+	defer span.End()
+
+	ctx := s.requestContext()
 
 	// Generate cache key for club players (include sort order and showActive flag)
 	sortKey := fmt.Sprintf("%s:%s:%t", req.SortBy, req.SortOrder, showActive)
@@ -235,13 +377,13 @@ func (s *PlayerService) GetPlayersByClub(clubID string, req models.SearchRequest
 func (s *PlayerService) executeClubPlayersSearch(clubID string, req models.SearchRequest, showActive bool) (interface{}, error) {
 	players, _, err := s.playerRepo.GetPlayersByClub(clubID, req, showActive)
 	if err != nil {
-		return nil, errors.NewNotFoundError("Club or players")
+		return nil, errors.FromLookup(err, errors.New(errors.CodeClubNotFound, "Club not found"))
 	}
 
 	// Get club info
 	club, err := s.clubRepo.GetClubByVKZ(clubID)
 	if err != nil {
-		return nil, errors.NewNotFoundError("Club")
+		return nil, errors.FromLookup(err, errors.New(errors.CodeClubNotFound, "Club not found"))
 	}
 
 	// Convert to response format, but only include players with valid memberships when showActive is true
@@ -303,9 +445,97 @@ func (s *PlayerService) executeClubPlayersSearch(clubID string, req models.Searc
 	}, nil
 }
 
+// GetClubsPlayers gets the players of several clubs at once, highest DWZ first
+// Works like GetPlayersByIDs: cached rosters are read with MGet, the others are loaded with
+// set-based queries and cached with MSet. A roster holds the active players with a current
+// membership like GetPlayersByClub, clubs that do not exist have an empty roster.
+func (s *PlayerService) GetClubsPlayers(clubIDs []string) ([]models.ClubPlayersBatchResult, error) {
+	s, span := s.startSpan("GetClubsPlayers")
+	defer span.End()
+
+	ctx := s.requestContext()
+	ids := uniqueIDs(clubIDs)
+	failures := make(map[string]error)
+
+	valid := make([]string, 0, len(ids))
+	cacheKeys := make(map[string]string, len(ids))
+	for _, id := range ids {
+		if err := utils.ValidateClubID(id); err != nil {
+			failures[id] = err
+			continue
+		}
+		valid = append(valid, id)
+		cacheKeys[id] = s.keyGen.ClubPlayersKey(id, "roster")
+	}
+
+	rosters := make(map[string][]models.PlayerResponse, len(valid))
+	misses := getCachedBatch(ctx, s.cacheService, valid, cacheKeys, func(id string, value interface{}) bool {
+		var roster []models.PlayerResponse
+		if !decodeCached(value, &roster) || roster == nil {
+			return false
+		}
+		rosters[id] = roster
+		return true
+	})
+
+	if len(misses) > 0 {
+		records, err := s.playerRepo.GetPlayersByClubs(misses)
+		if err != nil {
+			return nil, errors.NewDatabaseError(err, "Failed to get club players")
+		}
+
+		// Records are keyed by upper case VKZ, the database compares VKZs case-insensitively
+		byClub := make(map[string][]models.PlayerResponse, len(misses))
+		for key, record := range records {
+			playerID := utils.GeneratePlayerID(record.Organisation.VKZ, key.Spielernummer)
+			byClub[key.VKZ] = append(byClub[key.VKZ], *newPlayerResponse(playerID, &record.Person, &record.Organisation, record.Evaluation))
+		}
+
+		items := make(map[string]interface{}, len(misses))
+		for _, id := range misses {
+			roster := byClub[strings.ToUpper(id)]
+			if roster == nil {
+				roster = []models.PlayerResponse{}
+			}
+			sortByDWZ(roster)
+			rosters[id] = roster
+			items[cacheKeys[id]] = roster
+		}
+		// Caching is best effort, the rosters are returned either way
+		_ = s.cacheService.MSet(ctx, items, 30*time.Minute)
+	}
+
+	results := make([]models.ClubPlayersBatchResult, 0, len(ids))
+	for _, id := range ids {
+		result := models.ClubPlayersBatchResult{ID: id, Data: rosters[id]}
+		if err, failed := failures[id]; failed {
+			result.Error = newBatchError(err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// sortByDWZ orders players by DWZ, highest first, and by name and ID for equal ratings
+func sortByDWZ(players []models.PlayerResponse) {
+	sort.Slice(players, func(i, j int) bool {
+		a, b := players[i], players[j]
+		if a.CurrentDWZ != b.CurrentDWZ {
+			return a.CurrentDWZ > b.CurrentDWZ
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.ID < b.ID
+	})
+}
+
 // GetPlayerRatingHistory gets rating history for a player
 func (s *PlayerService) GetPlayerRatingHistory(playerID string) ([]models.RatingHistoryResponse, error) {
-	ctx := context.Background()
+	s, span := s.startSpan("GetPlayerRatingHistory")
+	defer span.End()
+
+	ctx := s.requestContext()
 	cacheKey := s.keyGen.PlayerRatingHistoryKey(playerID)
 
 	// Try cache first with background refresh
@@ -329,59 +559,274 @@ func (s *PlayerService) loadPlayerRatingHistoryFromDB(playerID string) ([]models
 	// Parse player ID to get VKZ and spielernummer
 	vkz, spielernummer, err := utils.ParsePlayerID(playerID)
 	if err != nil {
-		return nil, errors.NewBadRequestError("Invalid player ID format")
+		return nil, errors.New(errors.CodeInvalidPlayerID, "Invalid player ID format")
 	}
 
 	// Get player data to find the actual person ID
 	person, _, _, err := s.playerRepo.GetPlayerByID(vkz, spielernummer)
 	if err != nil {
-		return nil, errors.NewNotFoundError("Player")
+		return nil, errors.FromLookup(err, errors.New(errors.CodePlayerNotFound, "Player not found"))
 	}
 
 	// Get rating history with tournament details in single optimized query
 	results, err := s.playerRepo.GetPlayerRatingHistory(person.ID)
 	if err != nil {
-		return nil, errors.NewInternalServerError("Failed to get rating history")
+		return nil, errors.NewDatabaseError(err, "Failed to get rating history")
 	}
 
+	return newRatingHistory(results), nil
+}
+
+// newRatingHistory converts the evaluations of a player to response format
+func newRatingHistory(results []repositories.EvaluationWithTournament) []models.RatingHistoryResponse {
 	// Convert results to response format - no more N+1 queries needed!
 	validEvaluations := []models.RatingHistoryResponse{}
 	for _, result := range results {
-		// Tournament code and name are already available from the JOIN
-		if result.TournamentCode == "" {
-			continue // Skip evaluations without valid tournament codes
+		if validEvaluation, ok := newRatingHistoryEntry(result); ok {
+			validEvaluations = append(validEvaluations, validEvaluation)
+		}
+	}
+
+	return validEvaluations
+}
+
+// newRatingHistoryEntry converts an evaluation to response format
+// Evaluations without a valid tournament code are skipped (ok is false).
+func newRatingHistoryEntry(result repositories.EvaluationWithTournament) (models.RatingHistoryResponse, bool) {
+	// Tournament code and name are already available from the JOIN
+	if result.TournamentCode == "" {
+		return models.RatingHistoryResponse{}, false // Skip evaluations without valid tournament codes
+	}
+
+	// Select tournament date: prefer finishedOn over computedOn as requested
+	var tournamentDate *time.Time
+	if result.TournamentFinishedOn != nil {
+		tournamentDate = result.TournamentFinishedOn
+	} else if result.TournamentComputedOn != nil {
+		tournamentDate = result.TournamentComputedOn
+	} else if codeInfo, err := utils.DecodeTournamentCode(result.TournamentCode); err == nil {
+		tournamentDate = codeInfo.ApproxStartDate
+	}
+
+	return models.RatingHistoryResponse{
+		ID:             result.ID,
+		TournamentID:   result.TournamentCode, // From JOIN - no separate query needed
+		TournamentName: result.TournamentName, // From JOIN - new field for demo
+		TournamentDate: tournamentDate,        // From JOIN - new field for kader-planung
+		ECoefficient:   result.ECoefficient,
+		We:             result.We,
+		Achievement:    result.Achievement,
+		Level:          result.Level,
+		Games:          result.Games,
+		UnratedGames:   result.UnratedGames,
+		Points:         result.Points,
+		DWZOld:         result.DWZOld,
+		DWZOldIndex:    result.DWZOldIndex,
+		DWZNew:         result.DWZNew,
+		DWZNewIndex:    result.DWZNewIndex,
+	}, true
+}
+
+// GetPlayersRatingHistories gets the rating histories of several players at once
+// Works like GetPlayersByIDs: cached histories are read with MGet, the others are loaded with
+// set-based queries and cached with MSet.
+func (s *PlayerService) GetPlayersRatingHistories(playerIDs []string) ([]models.RatingHistoryBatchResult, error) {
+	s, span := s.startSpan("GetPlayersRatingHistories")
+	defer span.End()
+
+	ctx := s.requestContext()
+	ids := uniqueIDs(playerIDs)
+	failures := make(map[string]error)
+	valid, playerKeys := parseBatchPlayerIDs(ids, failures)
+
+	cacheKeys := make(map[string]string, len(valid))
+	for _, id := range valid {
+		cacheKeys[id] = s.keyGen.PlayerRatingHistoryKey(id)
+	}
+
+	histories := make(map[string][]models.RatingHistoryResponse, len(valid))
+	misses := getCachedBatch(ctx, s.cacheService, valid, cacheKeys, func(id string, value interface{}) bool {
+		var history []models.RatingHistoryResponse
+		if !decodeCached(value, &history) || history == nil {
+			return false
+		}
+		histories[id] = history
+		return true
+	})
+
+	if len(misses) > 0 {
+		// The person IDs are needed for the evaluations
+		records, err := s.playerRepo.GetPlayersByIDs(batchPlayerKeys(misses, playerKeys))
+		if err != nil {
+			return nil, errors.NewDatabaseError(err, "Failed to get rating history")
+		}
+		personIDs := make([]uint, 0, len(records))
+		for _, record := range records {
+			personIDs = append(personIDs, record.Person.ID)
 		}
 
-		// Select tournament date: prefer finishedOn over computedOn as requested
-		var tournamentDate *time.Time
-		if result.TournamentFinishedOn != nil {
-			tournamentDate = result.TournamentFinishedOn
-		} else if result.TournamentComputedOn != nil {
-			tournamentDate = result.TournamentComputedOn
+		results, err := s.playerRepo.GetPlayersRatingHistories(personIDs)
+		if err != nil {
+			return nil, errors.NewDatabaseError(err, "Failed to get rating history")
 		}
 
-		validEvaluation := models.RatingHistoryResponse{
-			ID:             result.ID,
-			TournamentID:   result.TournamentCode, // From JOIN - no separate query needed
-			TournamentName: result.TournamentName, // From JOIN - new field for demo
-			TournamentDate: tournamentDate,        // From JOIN - new field for kader-planung
-			ECoefficient:   result.ECoefficient,
-			We:             result.We,
-			Achievement:    result.Achievement,
-			Level:          result.Level,
-			Games:          result.Games,
-			UnratedGames:   result.UnratedGames,
-			Points:         result.Points,
-			DWZOld:         result.DWZOld,
-			DWZOldIndex:    result.DWZOldIndex,
-			DWZNew:         result.DWZNew,
-			DWZNewIndex:    result.DWZNewIndex,
+		items := make(map[string]interface{}, len(misses))
+		for _, id := range misses {
+			record, found := records[playerKeys[id]]
+			if !found {
+				failures[id] = errors.New(errors.CodePlayerNotFound, "Player not found")
+				continue
+			}
+			histories[id] = newRatingHistory(results[record.Person.ID])
+			items[cacheKeys[id]] = histories[id]
 		}
+		// Caching is best effort, the histories are returned either way
+		_ = s.cacheService.MSet(ctx, items, 7*24*time.Hour)
+	}
 
-		validEvaluations = append(validEvaluations, validEvaluation)
+	batchResults := make([]models.RatingHistoryBatchResult, 0, len(ids))
+	for _, id := range ids {
+		result := models.RatingHistoryBatchResult{ID: id, Data: histories[id]}
+		if err, failed := failures[id]; failed {
+			result.Error = newBatchError(err)
+		}
+		batchResults = append(batchResults, result)
+	}
+	return batchResults, nil
+}
+
+// GetPlayerMemberships gets the current and past club memberships of a player
+func (s *PlayerService) GetPlayerMemberships(playerID string) ([]models.MembershipResponse, error) {
+	s, span := s.startSpan("GetPlayerMemberships")
+	defer span.End()
+
+	ctx := s.requestContext()
+	cacheKey := s.keyGen.PlayerMembershipsKey(playerID)
+
+	// Try cache first with background refresh
+	var cachedMemberships []models.MembershipResponse
+	err := s.cacheService.GetWithRefresh(ctx, cacheKey, &cachedMemberships,
+		func() (interface{}, error) {
+			return s.loadPlayerMembershipsFromDB(playerID)
+		}, 24*time.Hour) // Memberships only change with an import
+
+	if err == nil {
+		return cachedMemberships, nil
+	}
+
+	// Cache miss or error - load directly from database
+	return s.loadPlayerMembershipsFromDB(playerID)
+}
+
+// GetPlayersMemberships gets the memberships of several players at once
+// Works like GetPlayersRatingHistories: cached memberships are read with MGet, the others are
+// loaded with set-based queries and cached with MSet.
+func (s *PlayerService) GetPlayersMemberships(playerIDs []string) ([]models.MembershipsBatchResult, error) {
+	s, span := s.startSpan("GetPlayersMemberships")
+	defer span.End()
+
+	ctx := s.requestContext()
+	ids := uniqueIDs(playerIDs)
+	failures := make(map[string]error)
+	valid, playerKeys := parseBatchPlayerIDs(ids, failures)
+
+	cacheKeys := make(map[string]string, len(valid))
+	for _, id := range valid {
+		cacheKeys[id] = s.keyGen.PlayerMembershipsKey(id)
 	}
 
-	return validEvaluations, nil
+	memberships := make(map[string][]models.MembershipResponse, len(valid))
+	misses := getCachedBatch(ctx, s.cacheService, valid, cacheKeys, func(id string, value interface{}) bool {
+		var cached []models.MembershipResponse
+		if !decodeCached(value, &cached) || cached == nil {
+			return false
+		}
+		memberships[id] = cached
+		return true
+	})
+
+	if len(misses) > 0 {
+		records, err := s.playerRepo.GetPlayersByIDs(batchPlayerKeys(misses, playerKeys))
+		if err != nil {
+			return nil, errors.NewDatabaseError(err, "Failed to get memberships")
+		}
+		personIDs := make([]uint, 0, len(records))
+		for _, record := range records {
+			personIDs = append(personIDs, record.Person.ID)
+		}
+
+		results, err := s.playerRepo.GetPlayersMemberships(personIDs)
+		if err != nil {
+			return nil, errors.NewDatabaseError(err, "Failed to get memberships")
+		}
+
+		today := time.Now().Truncate(24 * time.Hour)
+		items := make(map[string]interface{}, len(misses))
+		for _, id := range misses {
+			record, found := records[playerKeys[id]]
+			if !found {
+				failures[id] = errors.New(errors.CodePlayerNotFound, "Player not found")
+				continue
+			}
+			personMemberships := make([]models.MembershipResponse, 0, len(results[record.Person.ID]))
+			for _, result := range results[record.Person.ID] {
+				personMemberships = append(personMemberships, newMembershipResponse(result, today))
+			}
+			memberships[id] = personMemberships
+			items[cacheKeys[id]] = personMemberships
+		}
+		// Caching is best effort, the memberships are returned either way
+		_ = s.cacheService.MSet(ctx, items, 24*time.Hour)
+	}
+
+	batchResults := make([]models.MembershipsBatchResult, 0, len(ids))
+	for _, id := range ids {
+		result := models.MembershipsBatchResult{ID: id, Data: memberships[id]}
+		if err, failed := failures[id]; failed {
+			result.Error = newBatchError(err)
+		}
+		batchResults = append(batchResults, result)
+	}
+	return batchResults, nil
+}
+
+// loadPlayerMembershipsFromDB loads the memberships of a player from database (used by cache refresh)
+func (s *PlayerService) loadPlayerMembershipsFromDB(playerID string) ([]models.MembershipResponse, error) {
+	vkz, spielernummer, err := utils.ParsePlayerID(playerID)
+	if err != nil {
+		return nil, errors.New(errors.CodeInvalidPlayerID, "Invalid player ID format")
+	}
+
+	person, _, _, err := s.playerRepo.GetPlayerByID(vkz, spielernummer)
+	if err != nil {
+		return nil, errors.FromLookup(err, errors.New(errors.CodePlayerNotFound, "Player not found"))
+	}
+
+	results, err := s.playerRepo.GetPlayerMemberships(person.ID)
+	if err != nil {
+		return nil, errors.NewDatabaseError(err, "Failed to get memberships")
+	}
+
+	today := time.Now().Truncate(24 * time.Hour)
+	memberships := make([]models.MembershipResponse, 0, len(results))
+	for _, result := range results {
+		memberships = append(memberships, newMembershipResponse(result, today))
+	}
+
+	return memberships, nil
+}
+
+// newMembershipResponse converts a membership to response format, today decides whether it is current
+func newMembershipResponse(result repositories.MembershipWithOrganisation, today time.Time) models.MembershipResponse {
+	return models.MembershipResponse{
+		PlayerID: utils.GeneratePlayerID(result.ClubVKZ, result.Spielernummer),
+		ClubID:   result.ClubVKZ,
+		Club:     result.ClubName,
+		From:     result.Von,
+		Until:    result.Bis,
+		Status:   getPlayerStatus(result.Status),
+		Current:  result.Bis == nil || !result.Bis.Before(today),
+	}
 }
 
 // Helper methods
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"math"
+	"sort"
+
+	"portal64api/internal/models"
+)
+
+const (
+	// maxPerformanceDifference caps the rating difference for 0% and 100% scores
+	maxPerformanceDifference = 800
+	// topRatingChanges is the number of biggest gainers and losers reported
+	topRatingChanges = 5
+)
+
+// playerStats accumulates game statistics of a participant
+type playerStats struct {
+	games           int
+	points          float64
+	ratedGames      int
+	ratedPoints     float64
+	opponentRatings int
+	expected        float64
+}
+
+// CalculateTournamentPerformance computes performance ratings and rating changes
+// from the games and evaluations of an already loaded tournament
+func CalculateTournamentPerformance(tournament *models.EnhancedTournamentResponse) *models.TournamentPerformance {
+	ratings := preTournamentRatings(tournament)
+
+	evaluations := make(map[uint]models.EvaluationInfo, len(tournament.Evaluations))
+	for _, eval := range tournament.Evaluations {
+		evaluations[eval.PersonID] = eval
+	}
+
+	stats := make(map[uint]*playerStats, len(tournament.Participants))
+	statsFor := func(personID uint) *playerStats {
+		if stats[personID] == nil {
+			stats[personID] = &playerStats{}
+		}
+		return stats[personID]
+	}
+
+	for _, round := range tournament.Games {
+		for _, game := range round.Games {
+			// Skip byes and games without result
+			if game.White.ID == 0 || game.Black.ID == 0 {
+				continue
+			}
+			if game.Result == "" && game.WhitePoints == 0 && game.BlackPoints == 0 {
+				continue
+			}
+			addGame(statsFor(game.White.ID), game.WhitePoints, ratings, game.White.ID, game.Black.ID)
+			addGame(statsFor(game.Black.ID), game.BlackPoints, ratings, game.Black.ID, game.White.ID)
+		}
+	}
+
+	performance := &models.TournamentPerformance{
+		Players: make([]models.PlayerPerformance, 0, len(tournament.Participants)),
+	}
+
+	ratingSum, ratedPlayers := 0, 0
+	for _, participant := range tournament.Participants {
+		player := models.PlayerPerformance{
+			PersonID: participant.PersonID,
+			No:       participant.No,
+			Name:     participant.FullName,
+		}
+
+		if rating, ok := ratings[participant.PersonID]; ok {
+			player.Rating = intPtr(rating)
+			ratingSum += rating
+			ratedPlayers++
+		}
+
+		if s, ok := stats[participant.PersonID]; ok {
+			player.Games = s.games
+			player.Points = s.points
+			player.RatedGames = s.ratedGames
+			player.RatedPoints = s.ratedPoints
+			if s.ratedGames > 0 {
+				average := int(math.Round(float64(s.opponentRatings) / float64(s.ratedGames)))
+				player.AverageOpponentRating = intPtr(average)
+				player.Performance = intPtr(performanceRating(average, s.ratedPoints, s.ratedGames))
+				if player.Rating != nil {
+					expected := math.Round(s.expected*100) / 100
+					player.ExpectedScore = &expected
+				}
+			}
+		}
+
+		if eval, ok := evaluations[participant.PersonID]; ok && eval.DWZNew > 0 {
+			player.DWZNew = intPtr(eval.DWZNew)
+			if eval.DWZOld > 0 {
+				player.DWZOld = intPtr(eval.DWZOld)
+				player.DWZDelta = intPtr(eval.DWZNew - eval.DWZOld)
+			}
+		}
+
+		performance.Players = append(performance.Players, player)
+	}
+
+	if ratedPlayers > 0 {
+		performance.RatingAverage = int(math.Round(float64(ratingSum) / float64(ratedPlayers)))
+		performance.Category = tournamentCategory(performance.RatingAverage)
+	}
+
+	performance.TopGainers, performance.TopLosers = biggestRatingChanges(performance.Players)
+
+	return performance
+}
+
+// preTournamentRatings collects the rating of each participant before the tournament
+// Evaluated DWZ takes precedence over the historical DWZ and the rating used by the organiser
+func preTournamentRatings(tournament *models.EnhancedTournamentResponse) map[uint]int {
+	ratings := make(map[uint]int, len(tournament.Participants))
+
+	for _, participant := range tournament.Participants {
+		if participant.PersonID == 0 || participant.Rating == nil {
+			continue
+		}
+		if participant.Rating.DWZOld != nil && *participant.Rating.DWZOld > 0 {
+			ratings[participant.PersonID] = *participant.Rating.DWZOld
+		} else if participant.Rating.UseRating != nil && *participant.Rating.UseRating > 0 {
+			ratings[participant.PersonID] = *participant.Rating.UseRating
+		}
+	}
+
+	for _, eval := range tournament.Evaluations {
+		if eval.PersonID > 0 && eval.DWZOld > 0 {
+			ratings[eval.PersonID] = eval.DWZOld
+		}
+	}
+
+	return ratings
+}
+
+// addGame adds a single game result to the statistics of a player
+func addGame(s *playerStats, points float64, ratings map[uint]int, playerID, opponentID uint) {
+	s.games++
+	s.points += points
+
+	opponentRating, ok := ratings[opponentID]
+	if !ok {
+		return
+	}
+	s.ratedGames++
+	s.ratedPoints += points
+	s.opponentRatings += opponentRating
+
+	if rating, ok := ratings[playerID]; ok {
+		s.expected += expectedScore(rating, opponentRating)
+	}
+}
+
+// expectedScore returns the expected score against an opponent (logistic rating formula)
+func expectedScore(rating, opponentRating int) float64 {
+	return 1 / (1 + math.Pow(10, float64(opponentRating-rating)/400))
+}
+
+// performanceRating returns the tournament performance rating (TPR)
+// TPR is the average opponent rating plus the rating difference matching the score,
+// capped at +/-800 for perfect and zero scores
+func performanceRating(averageOpponentRating int, points float64, games int) int {
+	score := points / float64(games)
+
+	var difference float64
+	switch {
+	case score >= 1:
+		difference = maxPerformanceDifference
+	case score <= 0:
+		difference = -maxPerformanceDifference
+	default:
+		difference = 400 * math.Log10(score/(1-score))
+		difference = math.Max(-maxPerformanceDifference, math.Min(maxPerformanceDifference, difference))
+	}
+
+	return int(math.Round(float64(averageOpponentRating) + difference))
+}
+
+// tournamentCategory returns the FIDE category for a rating average
+// Category 1 starts at 2251 with steps of 25 rating points
+func tournamentCategory(ratingAverage int) int {
+	if ratingAverage < 2251 {
+		return 0
+	}
+	return (ratingAverage-2251)/25 + 1
+}
+
+// biggestRatingChanges returns the players with the largest DWZ gains and losses
+func biggestRatingChanges(players []models.PlayerPerformance) ([]models.PlayerPerformance, []models.PlayerPerformance) {
+	gainers := make([]models.PlayerPerformance, 0)
+	losers := make([]models.PlayerPerformance, 0)
+
+	for _, player := range players {
+		if player.DWZDelta == nil {
+			continue
+		}
+		if *player.DWZDelta > 0 {
+			gainers = append(gainers, player)
+		} else if *player.DWZDelta < 0 {
+			losers = append(losers, player)
+		}
+	}
+
+	sort.SliceStable(gainers, func(i, j int) bool { return *gainers[i].DWZDelta > *gainers[j].DWZDelta })
+	sort.SliceStable(losers, func(i, j int) bool { return *losers[i].DWZDelta < *losers[j].DWZDelta })
+
+	if len(gainers) > topRatingChanges {
+		gainers = gainers[:topRatingChanges]
+	}
+	if len(losers) > topRatingChanges {
+		losers = losers[:topRatingChanges]
+	}
+
+	return gainers, losers
+}
+
+func intPtr(value int) *int {
+	return &value
+}
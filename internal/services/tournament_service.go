@@ -3,12 +3,17 @@ package services
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"portal64api/internal/cache"
 	"portal64api/internal/models"
 	"portal64api/internal/repositories"
+	"portal64api/internal/tracing"
 	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 // TournamentService handles tournament business logic
@@ -16,6 +21,7 @@ type TournamentService struct {
 	tournamentRepo *repositories.TournamentRepository
 	cacheService   cache.CacheService
 	keyGen         *cache.KeyGenerator
+	ctx            context.Context // Request context, set by WithContext
 }
 
 // NewTournamentService creates a new tournament service
@@ -27,9 +33,38 @@ func NewTournamentService(tournamentRepo *repositories.TournamentRepository, cac
 	}
 }
 
+// WithContext returns a copy of the service handling a request with the given context
+// The context is detached from cancellation so background cache refreshes can complete.
+func (s *TournamentService) WithContext(ctx context.Context) *TournamentService {
+	ctx = context.WithoutCancel(ctx)
+	clone := *s
+	clone.ctx = ctx
+	if s.tournamentRepo != nil {
+		clone.tournamentRepo = s.tournamentRepo.WithContext(ctx)
+	}
+	return &clone
+}
+
+// startSpan starts a span for a service method and returns a copy of the service running in it
+func (s *TournamentService) startSpan(method string) (*TournamentService, trace.Span) {
+	ctx, span := tracing.Start(s.requestContext(), "TournamentService."+method)
+	return s.WithContext(ctx), span
+}
+
+// requestContext returns the request context or a background context
+func (s *TournamentService) requestContext() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
 // GetTournamentByID gets a tournament by its code/ID
 func (s *TournamentService) GetTournamentByID(tournamentID string) (*models.EnhancedTournamentResponse, error) {
-	ctx := context.Background()
+	s, span := s.startSpan("GetTournamentByID")
+	defer span.End()
+
+	ctx := s.requestContext()
 	cacheKey := s.keyGen.TournamentKey(tournamentID)
 
 	// Try cache first with background refresh
@@ -47,12 +82,26 @@ func (s *TournamentService) GetTournamentByID(tournamentID string) (*models.Enha
 	return s.loadTournamentFromDB(tournamentID)
 }
 
+// GetTournamentWithPerformance gets a tournament including its performance section
+func (s *TournamentService) GetTournamentWithPerformance(tournamentID string) (*models.EnhancedTournamentResponse, error) {
+	s, span := s.startSpan("GetTournamentWithPerformance")
+	defer span.End()
+
+	tournament, err := s.GetTournamentByID(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	tournament.Performance = CalculateTournamentPerformance(tournament)
+	return tournament, nil
+}
+
 // loadTournamentFromDB loads tournament data from database
 func (s *TournamentService) loadTournamentFromDB(tournamentID string) (*models.EnhancedTournamentResponse, error) {
 	// Get comprehensive tournament data
 	tournament, err := s.tournamentRepo.GetEnhancedTournamentData(tournamentID)
 	if err != nil {
-		return nil, errors.NewNotFoundError("Tournament")
+		return nil, errors.FromLookup(err, errors.New(errors.CodeTournamentNotFound, "Tournament not found"))
 	}
 
 	return tournament, nil
@@ -60,7 +109,10 @@ func (s *TournamentService) loadTournamentFromDB(tournamentID string) (*models.E
 
 // GetBasicTournamentByID gets basic tournament info (for backward compatibility)
 func (s *TournamentService) GetBasicTournamentByID(tournamentID string) (*models.TournamentResponse, error) {
-	ctx := context.Background()
+	s, span := s.startSpan("GetBasicTournamentByID")
+	defer span.End()
+
+	ctx := s.requestContext()
 	cacheKey := s.keyGen.TournamentKey(fmt.Sprintf("basic_%s", tournamentID))
 
 	// Try cache first with background refresh
@@ -82,7 +134,7 @@ func (s *TournamentService) GetBasicTournamentByID(tournamentID string) (*models
 func (s *TournamentService) loadBasicTournamentFromDB(tournamentID string) (*models.TournamentResponse, error) {
 	tournament, err := s.tournamentRepo.GetTournamentByCode(tournamentID)
 	if err != nil {
-		return nil, errors.NewNotFoundError("Tournament")
+		return nil, errors.FromLookup(err, errors.New(errors.CodeTournamentNotFound, "Tournament not found"))
 	}
 
 	// Get participant count
@@ -92,19 +144,91 @@ func (s *TournamentService) loadBasicTournamentFromDB(tournamentID string) (*mod
 		participantCount = 0
 	}
 
-	response := &models.TournamentResponse{
-		ID:               tournament.TCode,
-		Name:             tournament.TName,
-		Code:             tournament.TCode,
-		Type:             tournament.Type,
-		Rounds:           tournament.Rounds,
-		StartDate:        tournament.FinishedOn, // Adjust based on actual schema
-		EndDate:          tournament.FinishedOn,
-		Status:           getTournamentStatus(tournament),
-		ParticipantCount: participantCount,
+	response := newTournamentResponse(tournament, participantCount)
+
+	return &response, nil
+}
+
+// GetTournamentsByIDs gets the basic info of several tournaments at once
+// Works like GetPlayersByIDs of the player service: cached tournaments are read with MGet, the
+// others are loaded with set-based queries and cached with MSet. There is one result per distinct
+// ID in the order of the request.
+func (s *TournamentService) GetTournamentsByIDs(tournamentIDs []string) ([]models.TournamentBatchResult, error) {
+	s, span := s.startSpan("GetTournamentsByIDs")
+	defer span.End()
+
+	ctx := s.requestContext()
+	ids := uniqueIDs(tournamentIDs)
+	failures := make(map[string]error)
+
+	valid := make([]string, 0, len(ids))
+	cacheKeys := make(map[string]string, len(ids))
+	for _, id := range ids {
+		if err := utils.ValidateTournamentID(id); err != nil {
+			failures[id] = err
+			continue
+		}
+		valid = append(valid, id)
+		cacheKeys[id] = s.keyGen.TournamentKey(fmt.Sprintf("basic_%s", id))
+	}
+
+	tournaments := make(map[string]*models.TournamentResponse, len(valid))
+	misses := getCachedBatch(ctx, s.cacheService, valid, cacheKeys, func(id string, value interface{}) bool {
+		var tournament models.TournamentResponse
+		if !decodeCached(value, &tournament) {
+			return false
+		}
+		tournaments[id] = &tournament
+		return true
+	})
+
+	if len(misses) > 0 {
+		rows, err := s.tournamentRepo.GetTournamentsByCodes(misses)
+		if err != nil {
+			return nil, errors.NewDatabaseError(err, "Failed to get tournaments")
+		}
+
+		// The first tournament wins like in GetTournamentByCode
+		byCode := make(map[string]*models.Tournament, len(rows))
+		tournamentIDs := make([]uint, 0, len(rows))
+		for i := range rows {
+			code := strings.ToUpper(rows[i].TCode)
+			if _, exists := byCode[code]; !exists {
+				byCode[code] = &rows[i]
+				tournamentIDs = append(tournamentIDs, rows[i].ID)
+			}
+		}
+
+		// Participant counts are optional like in loadBasicTournamentFromDB
+		participantCounts, err := s.tournamentRepo.GetParticipantCounts(tournamentIDs)
+		if err != nil {
+			participantCounts = map[uint]int{}
+		}
+
+		items := make(map[string]interface{}, len(misses))
+		for _, id := range misses {
+			tournament, found := byCode[strings.ToUpper(id)]
+			if !found {
+				failures[id] = errors.New(errors.CodeTournamentNotFound, "Tournament not found")
+				continue
+			}
+			response := newTournamentResponse(tournament, participantCounts[tournament.ID])
+			tournaments[id] = &response
+			items[cacheKeys[id]] = tournaments[id]
+		}
+		// Caching is best effort, the tournaments are returned either way
+		_ = s.cacheService.MSet(ctx, items, 1*time.Hour)
 	}
 
-	return response, nil
+	results := make([]models.TournamentBatchResult, 0, len(ids))
+	for _, id := range ids {
+		result := models.TournamentBatchResult{ID: id, Data: tournaments[id]}
+		if err, failed := failures[id]; failed {
+			result.Error = newBatchError(err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
 }
 
 // tournamentSearchResult wraps search results for caching
@@ -113,17 +237,20 @@ type tournamentSearchResult struct {
 	Meta      *models.Meta
 }
 
-// SearchTournaments searches tournaments
-func (s *TournamentService) SearchTournaments(req models.SearchRequest) ([]models.TournamentResponse, *models.Meta, error) {
-	ctx := context.Background()
-	searchHash := s.keyGen.GenerateSearchHash(req, false) // tournaments don't have active flag
+// SearchTournaments searches tournaments with optional filters
+func (s *TournamentService) SearchTournaments(req models.SearchRequest, filter models.TournamentSearchFilter) ([]models.TournamentResponse, *models.Meta, error) {
+	s, span := s.startSpan("SearchTournaments")
+	defer span.End()
+
+	ctx := s.requestContext()
+	searchHash := s.keyGen.GenerateTournamentSearchHash(req, filter)
 	cacheKey := s.keyGen.SearchKey("tournament", searchHash)
 
 	// Try cache first with background refresh
 	var cachedResult tournamentSearchResult
 	err := s.cacheService.GetWithRefresh(ctx, cacheKey, &cachedResult,
 		func() (interface{}, error) {
-			return s.executeTournamentSearch(req)
+			return s.executeTournamentSearch(req, filter)
 		}, 15*time.Minute) // Cache search results for 15 minutes
 
 	if err == nil {
@@ -131,7 +258,7 @@ func (s *TournamentService) SearchTournaments(req models.SearchRequest) ([]model
 	}
 
 	// Fallback to direct execution if cache fails
-	result, err := s.executeTournamentSearch(req)
+	result, err := s.executeTournamentSearch(req, filter)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -139,10 +266,10 @@ func (s *TournamentService) SearchTournaments(req models.SearchRequest) ([]model
 }
 
 // executeTournamentSearch performs the actual tournament search
-func (s *TournamentService) executeTournamentSearch(req models.SearchRequest) (*tournamentSearchResult, error) {
-	tournaments, total, err := s.tournamentRepo.SearchTournaments(req)
+func (s *TournamentService) executeTournamentSearch(req models.SearchRequest, filter models.TournamentSearchFilter) (*tournamentSearchResult, error) {
+	tournaments, total, err := s.tournamentRepo.SearchTournaments(req, filter)
 	if err != nil {
-		return nil, errors.NewInternalServerError("Failed to search tournaments")
+		return nil, errors.NewDatabaseError(err, "Failed to search tournaments")
 	}
 
 	responses := make([]models.TournamentResponse, len(tournaments))
@@ -154,18 +281,9 @@ func (s *TournamentService) executeTournamentSearch(req models.SearchRequest) (*
 			participantCount = 0
 		}
 
-		responses[i] = models.TournamentResponse{
-			ID:               tournament.TCode,
-			Name:             tournament.TName,
-			Code:             tournament.TCode,
-			Type:             tournament.Type,
-			Rounds:           tournament.Rounds,
-			StartDate:        tournament.FinishedOn,
-			EndDate:          tournament.FinishedOn,
-			Status:           getTournamentStatus(&tournament),
-			ParticipantCount: participantCount,
-		}
+		responses[i] = newTournamentResponse(&tournament, participantCount)
 	}
+	s.setOrganizationNames(tournaments, responses)
 
 	meta := &models.Meta{
 		Total:  int(total),
@@ -181,10 +299,13 @@ func (s *TournamentService) executeTournamentSearch(req models.SearchRequest) (*
 }
 
 // GetTournamentsByDateRange gets tournaments within a date range
-func (s *TournamentService) GetTournamentsByDateRange(startDate, endDate time.Time, req models.SearchRequest) ([]models.TournamentResponse, *models.Meta, error) {
-	tournaments, total, err := s.tournamentRepo.GetTournamentsByDateRange(startDate, endDate, req)
+func (s *TournamentService) GetTournamentsByDateRange(startDate, endDate time.Time, req models.SearchRequest, filter models.TournamentSearchFilter) ([]models.TournamentResponse, *models.Meta, error) {
+	s, span := s.startSpan("GetTournamentsByDateRange")
+	defer span.End()
+
+	tournaments, total, err := s.tournamentRepo.GetTournamentsByDateRange(startDate, endDate, req, filter)
 	if err != nil {
-		return nil, nil, errors.NewInternalServerError("Failed to get tournaments by date range")
+		return nil, nil, errors.NewDatabaseError(err, "Failed to get tournaments by date range")
 	}
 
 	responses := make([]models.TournamentResponse, len(tournaments))
@@ -196,18 +317,9 @@ func (s *TournamentService) GetTournamentsByDateRange(startDate, endDate time.Ti
 			participantCount = 0
 		}
 
-		responses[i] = models.TournamentResponse{
-			ID:               tournament.TCode,
-			Name:             tournament.TName,
-			Code:             tournament.TCode,
-			Type:             tournament.Type,
-			Rounds:           tournament.Rounds,
-			StartDate:        tournament.FinishedOn,
-			EndDate:          tournament.FinishedOn,
-			Status:           getTournamentStatus(&tournament),
-			ParticipantCount: participantCount,
-		}
+		responses[i] = newTournamentResponse(&tournament, participantCount)
 	}
+	s.setOrganizationNames(tournaments, responses)
 
 	meta := &models.Meta{
 		Total:  int(total),
@@ -220,7 +332,10 @@ func (s *TournamentService) GetTournamentsByDateRange(startDate, endDate time.Ti
 }
 
 // GetRecentTournaments gets recently finished tournaments
-func (s *TournamentService) GetRecentTournaments(days, limit int) ([]models.TournamentResponse, error) {
+func (s *TournamentService) GetRecentTournaments(days, limit int, filter models.TournamentSearchFilter) ([]models.TournamentResponse, error) {
+	s, span := s.startSpan("GetRecentTournaments")
+	defer span.End()
+
 	if days == 0 {
 		days = 30 // Default to last 30 days
 	}
@@ -228,9 +343,9 @@ func (s *TournamentService) GetRecentTournaments(days, limit int) ([]models.Tour
 		limit = 500
 	}
 
-	tournaments, err := s.tournamentRepo.GetRecentTournaments(days, limit)
+	tournaments, err := s.tournamentRepo.GetRecentTournaments(days, limit, filter)
 	if err != nil {
-		return nil, errors.NewInternalServerError("Failed to get recent tournaments")
+		return nil, errors.NewDatabaseError(err, "Failed to get recent tournaments")
 	}
 
 	responses := make([]models.TournamentResponse, len(tournaments))
@@ -242,24 +357,65 @@ func (s *TournamentService) GetRecentTournaments(days, limit int) ([]models.Tour
 			participantCount = 0
 		}
 
-		responses[i] = models.TournamentResponse{
-			ID:               tournament.TCode,
-			Name:             tournament.TName,
-			Code:             tournament.TCode,
-			Type:             tournament.Type,
-			Rounds:           tournament.Rounds,
-			StartDate:        tournament.FinishedOn,
-			EndDate:          tournament.FinishedOn,
-			Status:           getTournamentStatus(&tournament),
-			ParticipantCount: participantCount,
-		}
+		responses[i] = newTournamentResponse(&tournament, participantCount)
 	}
+	s.setOrganizationNames(tournaments, responses)
 
 	return responses, nil
 }
 
 // Helper methods
 
+// setOrganizationNames fills the organiser name of list responses with a single lookup
+func (s *TournamentService) setOrganizationNames(tournaments []models.Tournament, responses []models.TournamentResponse) {
+	orgIDs := make([]uint, 0, len(tournaments))
+	for _, tournament := range tournaments {
+		if tournament.IDOrganisation > 0 {
+			orgIDs = append(orgIDs, tournament.IDOrganisation)
+		}
+	}
+
+	names, err := s.tournamentRepo.GetOrganisationNames(orgIDs)
+	if err != nil {
+		// Organiser names are informational, don't fail the request
+		return
+	}
+
+	for i, tournament := range tournaments {
+		responses[i].Organization = names[tournament.IDOrganisation]
+	}
+}
+
+// newTournamentResponse builds the list/basic response for a tournament
+// Dates fall back from FinishedOn to ComputedOn to the week encoded in the tournament code
+func newTournamentResponse(tournament *models.Tournament, participantCount int) models.TournamentResponse {
+	response := models.TournamentResponse{
+		ID:               tournament.TCode,
+		Name:             tournament.TName,
+		Code:             tournament.TCode,
+		Type:             tournament.Type,
+		Rounds:           tournament.Rounds,
+		Status:           getTournamentStatus(tournament),
+		ParticipantCount: participantCount,
+	}
+
+	if codeInfo, err := utils.DecodeTournamentCode(tournament.TCode); err == nil {
+		response.CodeInfo = codeInfo
+	}
+
+	date := tournament.FinishedOn
+	if date == nil {
+		date = tournament.ComputedOn
+	}
+	if date == nil && response.CodeInfo != nil {
+		date = response.CodeInfo.ApproxStartDate
+	}
+	response.StartDate = date
+	response.EndDate = date
+
+	return response
+}
+
 // getTournamentStatus determines tournament status
 func getTournamentStatus(tournament *models.Tournament) string {
 	if tournament.FinishedOn != nil {
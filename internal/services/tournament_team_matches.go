@@ -0,0 +1,386 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"portal64api/internal/models"
+	"portal64api/pkg/errors"
+)
+
+// Match points awarded per team match
+const (
+	matchPointsWin  = 2
+	matchPointsDraw = 1
+)
+
+// teamMatch collects the boards of one team match while grouping a round
+// The lineups are the players of both sides, oddWhite being the side with white on the odd boards.
+// Positions are the board numbers within the match.
+type teamMatch struct {
+	info           models.TeamMatchInfo
+	positions      []int
+	oddWhiteClub   string
+	oddBlackClub   string
+	oddWhiteSide   []uint
+	oddBlackSide   []uint
+	oddWhitePoints float64
+	oddBlackPoints float64
+}
+
+// teamRound holds the matches of one round until the teams are known
+type teamRound struct {
+	round   models.RoundInfo
+	matches []*teamMatch
+}
+
+// GetTournamentTeamMatches gets the team match view of a league tournament
+func (s *TournamentService) GetTournamentTeamMatches(tournamentID string) (*models.TeamMatchesResponse, error) {
+	s, span := s.startSpan("GetTournamentTeamMatches")
+	defer span.End()
+
+	tournament, err := s.GetTournamentByID(tournamentID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := BuildTeamMatches(tournament)
+	if response == nil {
+		return nil, errors.New(errors.CodeNotTeamCompetition, "Tournament is not a team competition")
+	}
+	return response, nil
+}
+
+// BuildTeamMatches groups the board pairings of a team competition into team matches
+// Team competitions are recognised by their league settings, not by their pairings. The boards
+// of a round are grouped into matches first: boards numbered through the round form blocks of
+// the number of boards per team, boards numbered from 1 in every match are assigned to the
+// matches between the same clubs in entry order. Teams are the lineups of these matches:
+// players who played on the same side of a match belong to the same team, which keeps several
+// teams of one club apart. Colours alternate by board within a match and following the
+// Turnierordnung the home team has white on the even boards. Boards without opponent
+// (forfeits) belong to the match of the team that was present. Returns nil if the tournament
+// is no team competition.
+func BuildTeamMatches(tournament *models.EnhancedTournamentResponse) *models.TeamMatchesResponse {
+	if tournament.TeamCompetition == nil {
+		return nil
+	}
+
+	participants := make(map[uint]models.ParticipantInfo, len(tournament.Participants))
+	for _, participant := range tournament.Participants {
+		if participant.Club != nil && participant.Club.VKZ != "" {
+			participants[participant.PersonID] = participant
+		}
+	}
+
+	rounds := make([]teamRound, 0, len(tournament.Games))
+	for _, round := range tournament.Games {
+		matches := groupTeamMatches(round.Games, tournament.TeamCompetition.Boards, participants)
+		rounds = append(rounds, teamRound{round: round, matches: matches})
+	}
+
+	teams := identifyTeams(rounds, participants)
+
+	response := &models.TeamMatchesResponse{
+		TournamentID: tournament.ID,
+		Name:         tournament.Name,
+		Rounds:       make([]models.TeamRoundInfo, 0, len(rounds)),
+	}
+	for _, round := range rounds {
+		teamRoundInfo := models.TeamRoundInfo{
+			Round:       round.round.Round,
+			Appointment: round.round.Appointment,
+			Matches:     make([]models.TeamMatchInfo, 0, len(round.matches)),
+		}
+		for _, match := range round.matches {
+			teamRoundInfo.Matches = append(teamRoundInfo.Matches, match.resolve(teams))
+		}
+		response.Rounds = append(response.Rounds, teamRoundInfo)
+	}
+
+	response.Table = buildLeagueTable(response.Rounds)
+	return response
+}
+
+// groupTeamMatches groups the games of a round into the matches of boardsPerTeam boards
+func groupTeamMatches(games []models.GameInfo, boardsPerTeam int, participants map[uint]models.ParticipantInfo) []*teamMatch {
+	sortedGames := make([]models.GameInfo, len(games))
+	copy(sortedGames, games)
+	sort.SliceStable(sortedGames, func(i, j int) bool { return sortedGames[i].Board < sortedGames[j].Board })
+
+	// Boards beyond the size of a team are numbered through the round
+	numberedThrough := boardsPerTeam > 0 && len(sortedGames) > 0 && sortedGames[len(sortedGames)-1].Board > boardsPerTeam
+
+	var matches []*teamMatch
+	blocks := make(map[int]*teamMatch)
+	for _, game := range sortedGames {
+		position := game.Board
+		if numberedThrough {
+			position = (game.Board-1)%boardsPerTeam + 1
+		}
+
+		oddWhite, oddBlack := game.White.ID, game.Black.ID
+		if position%2 == 0 {
+			oddWhite, oddBlack = oddBlack, oddWhite
+		}
+		var oddWhiteClub, oddBlackClub string
+		if participant, ok := participants[oddWhite]; ok {
+			oddWhiteClub = participant.Club.VKZ
+		} else {
+			oddWhite = 0
+		}
+		if participant, ok := participants[oddBlack]; ok {
+			oddBlackClub = participant.Club.VKZ
+		} else {
+			oddBlack = 0
+		}
+		if oddWhite == 0 && oddBlack == 0 {
+			continue
+		}
+
+		var match *teamMatch
+		if numberedThrough {
+			block := (game.Board - 1) / boardsPerTeam
+			if match = blocks[block]; match == nil {
+				match = &teamMatch{}
+				blocks[block] = match
+				matches = append(matches, match)
+			}
+		} else if match = findTeamMatch(matches, position, oddWhiteClub, oddBlackClub); match == nil {
+			match = &teamMatch{}
+			matches = append(matches, match)
+		}
+		match.addBoard(game, position, oddWhite, oddBlack, oddWhiteClub, oddBlackClub)
+	}
+
+	return matches
+}
+
+// addBoard adds a board to the match, oddWhite and oddBlack are the players of both sides (0 if absent)
+func (m *teamMatch) addBoard(game models.GameInfo, position int, oddWhite, oddBlack uint, oddWhiteClub, oddBlackClub string) {
+	m.info.Boards = append(m.info.Boards, game)
+	m.positions = append(m.positions, position)
+	if oddWhite != 0 {
+		m.oddWhiteSide = append(m.oddWhiteSide, oddWhite)
+		if m.oddWhiteClub == "" {
+			m.oddWhiteClub = oddWhiteClub
+		}
+	}
+	if oddBlack != 0 {
+		m.oddBlackSide = append(m.oddBlackSide, oddBlack)
+		if m.oddBlackClub == "" {
+			m.oddBlackClub = oddBlackClub
+		}
+	}
+	if position%2 == 0 {
+		m.oddWhitePoints += game.BlackPoints
+		m.oddBlackPoints += game.WhitePoints
+	} else {
+		m.oddWhitePoints += game.WhitePoints
+		m.oddBlackPoints += game.BlackPoints
+	}
+}
+
+// hasBoard reports whether the match already has a game on the board
+func (m *teamMatch) hasBoard(position int) bool {
+	for _, p := range m.positions {
+		if p == position {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve fills in the teams and scores the match, the home team has white on the even boards
+func (m *teamMatch) resolve(teams map[uint]models.TeamInfo) models.TeamMatchInfo {
+	info := m.info
+	info.Home = sideTeam(m.oddBlackSide, teams)
+	info.Away = sideTeam(m.oddWhiteSide, teams)
+	info.HomeBoardPoints = m.oddBlackPoints
+	info.AwayBoardPoints = m.oddWhitePoints
+
+	switch {
+	case info.HomeBoardPoints > info.AwayBoardPoints:
+		info.HomeMatchPoints = matchPointsWin
+	case info.HomeBoardPoints < info.AwayBoardPoints:
+		info.AwayMatchPoints = matchPointsWin
+	default:
+		info.HomeMatchPoints = matchPointsDraw
+		info.AwayMatchPoints = matchPointsDraw
+	}
+	return info
+}
+
+// sideTeam returns the team of the players of one side of a match
+func sideTeam(side []uint, teams map[uint]models.TeamInfo) models.TeamInfo {
+	if len(side) == 0 {
+		return models.TeamInfo{}
+	}
+	return teams[side[0]]
+}
+
+// findTeamMatch finds the first match between the given clubs that has no game on the board yet
+// A side without players yet (boards without opponent) matches any club.
+func findTeamMatch(matches []*teamMatch, position int, oddWhiteClub, oddBlackClub string) *teamMatch {
+	sameClub := func(a, b string) bool { return a == "" || b == "" || a == b }
+	for _, match := range matches {
+		if !match.hasBoard(position) && sameClub(match.oddWhiteClub, oddWhiteClub) && sameClub(match.oddBlackClub, oddBlackClub) {
+			return match
+		}
+	}
+	return nil
+}
+
+// identifyTeams assigns every player who played in a team match to a team
+// Players on the same side of a match are teammates across all rounds. Teams of a club are
+// numbered by their best starting number, the first team keeps the plain club name.
+func identifyTeams(rounds []teamRound, participants map[uint]models.ParticipantInfo) map[uint]models.TeamInfo {
+	lineups := newLineupSet()
+	for _, round := range rounds {
+		for _, match := range round.matches {
+			lineups.union(match.oddWhiteSide)
+			lineups.union(match.oddBlackSide)
+		}
+	}
+
+	// Group the lineups by club, keyed by the root player of each lineup
+	type lineup struct {
+		root    uint
+		firstNo int
+	}
+	clubLineups := make(map[string][]*lineup)
+	byRoot := make(map[uint]*lineup)
+	for player := range lineups.parent {
+		root := lineups.find(player)
+		participant := participants[player]
+		entry, exists := byRoot[root]
+		if !exists {
+			entry = &lineup{root: root, firstNo: participant.No}
+			byRoot[root] = entry
+			clubLineups[participant.Club.VKZ] = append(clubLineups[participant.Club.VKZ], entry)
+		}
+		if participant.No < entry.firstNo {
+			entry.firstNo = participant.No
+		}
+	}
+
+	rootTeams := make(map[uint]models.TeamInfo, len(byRoot))
+	for clubID, clubTeams := range clubLineups {
+		sort.Slice(clubTeams, func(i, j int) bool {
+			if clubTeams[i].firstNo != clubTeams[j].firstNo {
+				return clubTeams[i].firstNo < clubTeams[j].firstNo
+			}
+			return clubTeams[i].root < clubTeams[j].root
+		})
+		for i, entry := range clubTeams {
+			team := models.TeamInfo{
+				ID:     fmt.Sprintf("%s-%d", clubID, i+1),
+				ClubID: clubID,
+				Name:   participants[entry.root].Club.Name,
+			}
+			if i > 0 {
+				team.Name = fmt.Sprintf("%s %d", team.Name, i+1)
+			}
+			rootTeams[entry.root] = team
+		}
+	}
+
+	teams := make(map[uint]models.TeamInfo, len(lineups.parent))
+	for player := range lineups.parent {
+		teams[player] = rootTeams[lineups.find(player)]
+	}
+	return teams
+}
+
+// lineupSet is a union-find over player IDs
+type lineupSet struct {
+	parent map[uint]uint
+}
+
+func newLineupSet() *lineupSet {
+	return &lineupSet{parent: make(map[uint]uint)}
+}
+
+// find returns the root player of the lineup the player belongs to
+func (l *lineupSet) find(player uint) uint {
+	if _, exists := l.parent[player]; !exists {
+		l.parent[player] = player
+	}
+	for l.parent[player] != player {
+		l.parent[player] = l.parent[l.parent[player]]
+		player = l.parent[player]
+	}
+	return player
+}
+
+// union puts all given players into one lineup
+func (l *lineupSet) union(players []uint) {
+	if len(players) == 0 {
+		return
+	}
+	root := l.find(players[0])
+	for _, player := range players[1:] {
+		if other := l.find(player); other != root {
+			l.parent[other] = root
+		}
+	}
+}
+
+// buildLeagueTable ranks the teams by match points, then board points
+func buildLeagueTable(rounds []models.TeamRoundInfo) []models.LeagueTableEntry {
+	entries := make(map[string]*models.LeagueTableEntry)
+	entryFor := func(team models.TeamInfo) *models.LeagueTableEntry {
+		if entries[team.ID] == nil {
+			entries[team.ID] = &models.LeagueTableEntry{Team: team}
+		}
+		return entries[team.ID]
+	}
+
+	for _, round := range rounds {
+		for _, match := range round.Matches {
+			addMatchResult(entryFor(match.Home), match.HomeMatchPoints, match.AwayMatchPoints, match.HomeBoardPoints)
+			addMatchResult(entryFor(match.Away), match.AwayMatchPoints, match.HomeMatchPoints, match.AwayBoardPoints)
+		}
+	}
+
+	table := make([]models.LeagueTableEntry, 0, len(entries))
+	for _, entry := range entries {
+		table = append(table, *entry)
+	}
+
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].MatchPoints != table[j].MatchPoints {
+			return table[i].MatchPoints > table[j].MatchPoints
+		}
+		if table[i].BoardPoints != table[j].BoardPoints {
+			return table[i].BoardPoints > table[j].BoardPoints
+		}
+		return table[i].Team.Name < table[j].Team.Name
+	})
+
+	// Teams with equal match and board points share a rank
+	for i := range table {
+		table[i].Rank = i + 1
+		if i > 0 && table[i].MatchPoints == table[i-1].MatchPoints && table[i].BoardPoints == table[i-1].BoardPoints {
+			table[i].Rank = table[i-1].Rank
+		}
+	}
+
+	return table
+}
+
+// addMatchResult adds a single match to a league table entry
+func addMatchResult(entry *models.LeagueTableEntry, matchPoints, opponentMatchPoints int, boardPoints float64) {
+	entry.Matches++
+	entry.MatchPoints += matchPoints
+	entry.BoardPoints += boardPoints
+
+	switch {
+	case matchPoints > opponentMatchPoints:
+		entry.Wins++
+	case matchPoints < opponentMatchPoints:
+		entry.Losses++
+	default:
+		entry.Draws++
+	}
+}
@@ -0,0 +1,373 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"portal64api/internal/config"
+	"portal64api/internal/database"
+	"portal64api/internal/models"
+	"portal64api/internal/repositories"
+	"portal64api/internal/webhooks"
+	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Files in the webhook directory
+const (
+	webhookSubscriptionsFile = "subscriptions.json"
+	webhookRatingsFile       = "ratings.json" // DWZ of every person after the last import
+)
+
+// webhookChangesPerEvent limits the rating changes of a player.rating_changed event
+const webhookChangesPerEvent = 1000
+
+// ratingBaseline is the on-disk format of the DWZ after the last import
+type ratingBaseline struct {
+	TakenAt time.Time       `json:"taken_at"`
+	Ratings map[uint][2]int `json:"ratings"` // Person ID: DWZ and DWZ index
+}
+
+// WebhookService manages webhook subscriptions and publishes events to them
+// Import and analysis events are published by the completion callbacks. Rating changes are found
+// by comparing the DWZ of every person after an import with the baseline stored after the previous one.
+type WebhookService struct {
+	config       *config.WebhooksConfig
+	dbs          *database.Databases
+	store        *webhooks.Store
+	dispatcher   *webhooks.Dispatcher
+	logger       *logrus.Logger
+	importStatus func() *models.ImportStatus
+	ratingsMutex sync.Mutex // Serialises rating comparisons
+}
+
+// NewWebhookService creates a new webhook service, loading the stored subscriptions
+func NewWebhookService(config *config.WebhooksConfig, dbs *database.Databases, logger *logrus.Logger) (*WebhookService, error) {
+	store, err := webhooks.NewStore(filepath.Join(config.Directory, webhookSubscriptionsFile))
+	if err != nil {
+		return nil, err
+	}
+
+	dispatcher := webhooks.NewDispatcher(webhooks.Options{
+		MaxAttempts: config.MaxAttempts,
+		Backoff:     config.RetryBackoff,
+		Timeout:     config.Timeout,
+		Workers:     config.Workers,
+		LogSize:     config.DeliveryLogSize,
+
+		AllowPrivateNetworks: config.AllowPrivateNetworks,
+	}, logger)
+
+	return &WebhookService{
+		config:     config,
+		dbs:        dbs,
+		store:      store,
+		dispatcher: dispatcher,
+		logger:     logger,
+	}, nil
+}
+
+// SetImportStatus sets the source of the import status, sent with import events
+func (s *WebhookService) SetImportStatus(importStatus func() *models.ImportStatus) {
+	s.importStatus = importStatus
+}
+
+// Start starts delivering and takes the DWZ baseline in the background if there is none yet
+func (s *WebhookService) Start() error {
+	s.dispatcher.Start()
+
+	if _, err := os.Stat(s.ratingsPath()); os.IsNotExist(err) {
+		go s.publishRatingChanges()
+	}
+	return nil
+}
+
+// Stop stops delivering, pending retries are dropped
+func (s *WebhookService) Stop() error {
+	s.dispatcher.Stop()
+	return nil
+}
+
+// CreateSubscription creates a subscription, the result contains the secret
+func (s *WebhookService) CreateSubscription(subscription webhooks.Subscription) (*webhooks.Subscription, error) {
+	created, err := s.store.Create(subscription)
+	if err != nil {
+		return nil, webhookError(err)
+	}
+	s.logger.Infof("Webhook subscription %s created for %s", created.ID, created.URL)
+	return created, nil
+}
+
+// ListSubscriptions returns all subscriptions without their secrets
+func (s *WebhookService) ListSubscriptions() []webhooks.Subscription {
+	subscriptions := s.store.List()
+	for i := range subscriptions {
+		subscriptions[i].Secret = ""
+	}
+	return subscriptions
+}
+
+// GetSubscription returns a subscription without its secret
+func (s *WebhookService) GetSubscription(id string) (*webhooks.Subscription, error) {
+	subscription, err := s.store.Get(id)
+	if err != nil {
+		return nil, webhookError(err)
+	}
+	subscription.Secret = ""
+	return subscription, nil
+}
+
+// DeleteSubscription deletes a subscription
+func (s *WebhookService) DeleteSubscription(id string) error {
+	if err := s.store.Delete(id); err != nil {
+		return webhookError(err)
+	}
+	s.logger.Infof("Webhook subscription %s deleted", id)
+	return nil
+}
+
+// GetDeliveries returns the logged deliveries of a subscription, newest first
+func (s *WebhookService) GetDeliveries(id string, limit int) ([]webhooks.Delivery, error) {
+	if _, err := s.store.Get(id); err != nil {
+		return nil, webhookError(err)
+	}
+	deliveries := s.dispatcher.Deliveries(id, limit)
+	if deliveries == nil {
+		deliveries = []webhooks.Delivery{}
+	}
+	return deliveries, nil
+}
+
+// Ping sends a ping event to a subscription
+func (s *WebhookService) Ping(id string) (*webhooks.Delivery, error) {
+	subscription, err := s.store.Get(id)
+	if err != nil {
+		return nil, webhookError(err)
+	}
+	delivery, err := s.dispatcher.Dispatch(*subscription, webhooks.NewEvent(webhooks.EventPing, map[string]string{"subscription_id": id}))
+	if err != nil {
+		return nil, errors.NewInternalServerError(err.Error())
+	}
+	return &delivery, nil
+}
+
+// Publish sends an event to every subscription of its type
+func (s *WebhookService) Publish(eventType string, data interface{}) {
+	subscribers := s.store.Subscribers(eventType)
+	if len(subscribers) == 0 {
+		return
+	}
+
+	event := webhooks.NewEvent(eventType, data)
+	for _, subscription := range subscribers {
+		if _, err := s.dispatcher.Dispatch(subscription, event); err != nil {
+			s.logger.WithError(err).Errorf("Failed to dispatch %s event", eventType)
+		}
+	}
+}
+
+// OnImportComplete implements ImportCompleteCallback interface
+func (s *WebhookService) OnImportComplete() {
+	s.Publish(webhooks.EventImportCompleted, s.importData())
+	s.publishRatingChanges()
+}
+
+// OnImportFailed implements ImportFailedCallback interface
+func (s *WebhookService) OnImportFailed(err error) {
+	data := s.importData()
+	data.Error = err.Error()
+	s.Publish(webhooks.EventImportFailed, data)
+}
+
+// OnAnalysisComplete implements AnalysisCompleteCallback interface
+func (s *WebhookService) OnAnalysisComplete(run AnalysisRun) {
+	s.Publish(webhooks.EventAnalysisCompleted, run)
+}
+
+// importData returns the event data of the current import status
+func (s *WebhookService) importData() models.WebhookImportData {
+	var data models.WebhookImportData
+	if s.importStatus == nil {
+		return data
+	}
+
+	status := s.importStatus()
+	data.Status = status.Status
+	data.StartedAt = status.StartedAt
+	data.CompletedAt = status.CompletedAt
+	if status.Status == models.StatusFailed {
+		data.Step = status.CurrentStep
+	}
+	if status.FilesInfo != nil {
+		data.Files = status.FilesInfo.Imported
+	}
+	return data
+}
+
+// publishRatingChanges compares the DWZ of every person with the baseline, publishes the changes
+// and stores the DWZ as new baseline. Without a baseline only the baseline is stored.
+func (s *WebhookService) publishRatingChanges() {
+	s.ratingsMutex.Lock()
+	defer s.ratingsMutex.Unlock()
+
+	previous, err := s.loadRatingBaseline()
+	if err != nil {
+		s.logger.WithError(err).Warn("Failed to read DWZ baseline, taking a new one")
+	}
+	subscribers := s.store.Subscribers(webhooks.EventPlayerRatingChanged)
+
+	current := make(map[uint][2]int)
+	var changes []models.WebhookRatingChange
+	err = s.dbs.Snapshot(context.Background(), func(snapshot *database.Databases) error {
+		exportRepo := repositories.NewExportRepository(snapshot)
+		ratings, err := exportRepo.GetCurrentRatings()
+		if err != nil {
+			return err
+		}
+		for personID, rating := range ratings {
+			current[personID] = [2]int{rating.DWZNew, rating.DWZNewIndex}
+		}
+		if previous == nil || len(subscribers) == 0 {
+			return nil
+		}
+
+		changed := make(map[uint]bool)
+		for personID, rating := range current {
+			if old, ok := previous.Ratings[personID]; !ok || old != rating {
+				changed[personID] = true
+			}
+		}
+		if len(changed) == 0 {
+			return nil
+		}
+
+		orgs, err := exportRepo.GetOrganisationsByID()
+		if err != nil {
+			return err
+		}
+		return exportRepo.StreamCurrentMemberships(func(membership repositories.PersonMembership) error {
+			org, ok := orgs[membership.Organisation]
+			if !ok || !changed[membership.Person.ID] {
+				return nil
+			}
+			change := models.WebhookRatingChange{
+				PlayerID:    utils.GeneratePlayerID(org.VKZ, membership.Spielernummer),
+				PersonUUID:  membership.Person.UUID,
+				ClubID:      org.VKZ,
+				Name:        membership.Person.Name,
+				Firstname:   membership.Person.Vorname,
+				DWZNew:      current[membership.Person.ID][0],
+				DWZNewIndex: current[membership.Person.ID][1],
+			}
+			if old, ok := previous.Ratings[membership.Person.ID]; ok {
+				change.DWZOld, change.DWZOldIndex = &old[0], &old[1]
+			}
+			changes = append(changes, change)
+			return nil
+		})
+	})
+	if err != nil {
+		s.logger.WithError(err).Error("Failed to determine DWZ changes")
+		return
+	}
+
+	if err := s.saveRatingBaseline(&ratingBaseline{TakenAt: time.Now().UTC(), Ratings: current}); err != nil {
+		s.logger.WithError(err).Error("Failed to store DWZ baseline")
+	}
+	if previous == nil {
+		s.logger.Infof("DWZ baseline of %d persons taken for rating change events", len(current))
+		return
+	}
+
+	var importedAt *time.Time
+	if s.importStatus != nil {
+		importedAt = s.importStatus().CompletedAt
+	}
+	for _, subscription := range subscribers {
+		s.publishRatingChangesTo(subscription, changes, importedAt)
+	}
+}
+
+// publishRatingChangesTo sends the changes passing the filters of a subscription, split into parts
+func (s *WebhookService) publishRatingChangesTo(subscription webhooks.Subscription, changes []models.WebhookRatingChange, importedAt *time.Time) {
+	var matching []models.WebhookRatingChange
+	for _, change := range changes {
+		if subscription.Filters.Matches(change.ClubID, change.PlayerID) {
+			matching = append(matching, change)
+		}
+	}
+
+	parts := (len(matching) + webhookChangesPerEvent - 1) / webhookChangesPerEvent
+	for part := 0; part < parts; part++ {
+		end := (part + 1) * webhookChangesPerEvent
+		if end > len(matching) {
+			end = len(matching)
+		}
+		event := webhooks.NewEvent(webhooks.EventPlayerRatingChanged, models.WebhookRatingChangesData{
+			ImportedAt: importedAt,
+			Part:       part + 1,
+			Parts:      parts,
+			Changes:    matching[part*webhookChangesPerEvent : end],
+		})
+		if _, err := s.dispatcher.Dispatch(subscription, event); err != nil {
+			s.logger.WithError(err).Errorf("Failed to dispatch %s event", event.Type)
+		}
+	}
+}
+
+func (s *WebhookService) ratingsPath() string {
+	return filepath.Join(s.config.Directory, webhookRatingsFile)
+}
+
+// loadRatingBaseline reads the DWZ baseline, nil if there is none
+func (s *WebhookService) loadRatingBaseline() (*ratingBaseline, error) {
+	data, err := os.ReadFile(s.ratingsPath())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var baseline ratingBaseline
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return nil, fmt.Errorf("invalid DWZ baseline: %w", err)
+	}
+	return &baseline, nil
+}
+
+// saveRatingBaseline writes the DWZ baseline atomically
+func (s *WebhookService) saveRatingBaseline(baseline *ratingBaseline) error {
+	data, err := json.Marshal(baseline)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.config.Directory, 0o700); err != nil {
+		return err
+	}
+
+	tmp := s.ratingsPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.ratingsPath())
+}
+
+// webhookError converts an error of the subscription store
+func webhookError(err error) error {
+	switch {
+	case stderrors.Is(err, webhooks.ErrSubscriptionNotFound):
+		return errors.New(errors.CodeWebhookNotFound, "Webhook not found")
+	case stderrors.Is(err, webhooks.ErrInvalidSubscription):
+		return errors.New(errors.CodeInvalidRequestBody, err.Error())
+	}
+	return errors.NewInternalServerError(err.Error())
+}
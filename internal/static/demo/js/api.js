@@ -10,12 +10,36 @@ class Portal64API {
         };
     }
 
+    // API key for administrative endpoints, kept in the browser's local storage
+    getAPIKey() {
+        return window.localStorage ? localStorage.getItem('portal64ApiKey') || '' : '';
+    }
+
+    setAPIKey(key) {
+        if (!window.localStorage) return;
+        if (key) {
+            localStorage.setItem('portal64ApiKey', key);
+        } else {
+            localStorage.removeItem('portal64ApiKey');
+        }
+    }
+
+    // Request headers including the API key if one is set
+    headers() {
+        const headers = { ...this.defaultHeaders };
+        const key = this.getAPIKey();
+        if (key) {
+            headers['X-API-Key'] = key;
+        }
+        return headers;
+    }
+
     // Generic API request method with timeout handling
-    async request(endpoint, options = {}) {
+    async request(endpoint, options = {}, retried = false) {
         const url = `${this.baseURL}${endpoint}`;
         const config = {
             method: 'GET',
-            headers: this.defaultHeaders,
+            headers: this.headers(),
             ...options
         };
 
@@ -31,6 +55,15 @@ class Portal64API {
                 timeoutPromise
             ]);
 
+            // Ask for an API key once if an administrative endpoint requires one
+            if ((response.status === 401 || response.status === 403) && !retried) {
+                const key = window.prompt('Dieser Bereich erfordert einen API-Schlüssel:', this.getAPIKey());
+                if (key) {
+                    this.setAPIKey(key.trim());
+                    return this.request(endpoint, options, true);
+                }
+            }
+
             const data = await response.json();
             
             if (!response.ok) {
@@ -44,6 +77,66 @@ class Portal64API {
         }
     }
 
+    // Download a file from an authenticated endpoint
+    async download(endpoint, filename) {
+        const response = await fetch(`${this.baseURL}${endpoint}`, { headers: this.headers() });
+        if (!response.ok) {
+            throw new Error(`HTTP error! status: ${response.status}`);
+        }
+
+        const blob = await response.blob();
+        const link = document.createElement('a');
+        link.href = URL.createObjectURL(blob);
+        link.download = filename;
+        link.style.display = 'none';
+
+        document.body.appendChild(link);
+        link.click();
+        document.body.removeChild(link);
+        URL.revokeObjectURL(link.href);
+    }
+
+    // Read a Server-Sent Events stream until it ends, EventSource cannot send the API key header
+    async stream(endpoint, onEvent, signal) {
+        const headers = { ...this.headers(), 'Accept': 'text/event-stream' };
+        delete headers['Content-Type'];
+
+        const response = await fetch(`${this.baseURL}${endpoint}`, { headers, signal });
+        if (!response.ok) {
+            throw new Error(`HTTP error! status: ${response.status}`);
+        }
+
+        const reader = response.body.pipeThrough(new TextDecoderStream()).getReader();
+        let buffer = '';
+        for (;;) {
+            const { value, done } = await reader.read();
+            if (done) {
+                return;
+            }
+
+            // Events are separated by blank lines, comments (heartbeats) have no data
+            buffer += value;
+            let end;
+            while ((end = buffer.indexOf('\n\n')) >= 0) {
+                const block = buffer.slice(0, end);
+                buffer = buffer.slice(end + 2);
+
+                let event = 'message';
+                let data = '';
+                for (const line of block.split('\n')) {
+                    if (line.startsWith('event: ')) {
+                        event = line.slice(7);
+                    } else if (line.startsWith('data: ')) {
+                        data += line.slice(6);
+                    }
+                }
+                if (data) {
+                    onEvent(event, JSON.parse(data));
+                }
+            }
+        }
+    }
+
     // Health check
     async healthCheck() {
         return this.request('/health');
@@ -1,8 +1,12 @@
 // Kader-Planung JavaScript functionality
 
-// Global status refresh interval (every 30 seconds when running)
+// Global status refresh interval (every 30 seconds when running and the event stream is not available)
 let statusRefreshInterval = null;
 
+// Event stream with live progress while an analysis is running
+let statusStream = null;
+let lastAvailableFiles = [];
+
 // API calls for Kader-Planung
 async function getKaderPlanungStatus() {
     return api.request('/api/v1/kader-planung/status');
@@ -61,6 +65,7 @@ async function refreshStatus() {
             status: response.data.status,
             available_files: response.data.available_files || []
         };
+        lastAvailableFiles = statusResponse.available_files;
         
         displayStatus(statusResponse);
         
@@ -146,11 +151,44 @@ function setupStatusAutoRefresh(status) {
         statusRefreshInterval = null;
     }
     
-    // Setup auto-refresh if running - handle both possible field names
-    if (status.running || status.Running) {
+    // Follow the progress if running - handle both possible field names
+    if (!(status.running || status.Running) || statusStream) {
+        return;
+    }
+
+    const controller = new AbortController();
+    statusStream = controller;
+    api.stream('/api/v1/kader-planung/events', (event, data) => {
+        if (event === 'status') {
+            displayStatus({ status: data, available_files: lastAvailableFiles });
+        } else if (event === 'completed') {
+            stopStatusStream();
+            refreshStatus();
+            refreshFiles();
+        }
+    }, controller.signal).then(() => {
+        // The server ended the stream, reconnect if still running
+        if (statusStream === controller) {
+            statusStream = null;
+            refreshStatus();
+        }
+    }).catch(error => {
+        if (statusStream !== controller) {
+            return;
+        }
+        console.error('Status stream failed, polling instead:', error);
+        statusStream = null;
         statusRefreshInterval = setInterval(() => {
             refreshStatus();
         }, 30000); // Refresh every 30 seconds
+    });
+}
+
+function stopStatusStream() {
+    if (statusStream) {
+        const controller = statusStream;
+        statusStream = null;
+        controller.abort();
     }
 }
 
@@ -257,20 +295,13 @@ function displayFiles(files) {
 }
 
 // File download function
-function downloadFile(filename) {
-    const downloadUrl = `${api.baseURL}/api/v1/kader-planung/download/${encodeURIComponent(filename)}`;
-    
-    // Create temporary link element for download
-    const link = document.createElement('a');
-    link.href = downloadUrl;
-    link.download = filename;
-    link.style.display = 'none';
-    
-    document.body.appendChild(link);
-    link.click();
-    document.body.removeChild(link);
-    
-    Utils.showSuccess('files-result', `Download für "${filename}" wurde gestartet.`, 3000);
+async function downloadFile(filename) {
+    try {
+        await api.download(`/api/v1/kader-planung/download/${encodeURIComponent(filename)}`, filename);
+        Utils.showSuccess('files-result', `Download für "${filename}" wurde gestartet.`, 3000);
+    } catch (error) {
+        Utils.showError('files-result', `Download fehlgeschlagen: ${error.message}`);
+    }
 }
 
 // Utility functions
@@ -481,6 +512,13 @@ function displayStatusEnhanced(response) {
                 <span class="status-indicator">${statusIndicator}</span>
                 <strong>Status:</strong> ${statusText}
             </div>
+            ${(status.running || status.Running) ? `
+                <div class="status-row">
+                    <span class="status-indicator">📈</span>
+                    <strong>Fortschritt:</strong>
+                    <progress value="${status.progress || 0}" max="100"></progress> ${status.progress || 0}%
+                </div>
+            ` : ''}
             <div class="status-row">
                 <span class="status-indicator">⚙️</span>
                 <strong>Modus:</strong> ${modeText}
@@ -535,4 +573,5 @@ window.addEventListener('beforeunload', function() {
     if (statusRefreshInterval) {
         clearInterval(statusRefreshInterval);
     }
+    stopStatusStream();
 });
\ No newline at end of file
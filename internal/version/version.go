@@ -0,0 +1,12 @@
+// Package version holds the build information of the service
+// The values are set at build time, e.g.
+//
+//	go build -ldflags "-X portal64api/internal/version.Version=1.2.0 -X portal64api/internal/version.Commit=$(git rev-parse --short HEAD)"
+package version
+
+// Build information, overridden with -ldflags -X
+var (
+	Version   = "dev"
+	Commit    = ""
+	BuildTime = ""
+)
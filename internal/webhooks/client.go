@@ -0,0 +1,66 @@
+package webhooks
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedAddress is returned for deliveries to addresses that are not public
+// Subscriptions could otherwise make the server post to internal services or cloud metadata
+// endpoints such as 169.254.169.254. Blocked deliveries are not retried.
+var ErrBlockedAddress = errors.New("webhook receiver address is not public")
+
+// newClient returns the HTTP client deliveries are sent with
+// The dialer checks the resolved address of every connection, so host names resolving to
+// private addresses are rejected as well. Redirects are not followed, the redirect answer is
+// the result of the attempt. Proxies from the environment are not used, they would be dialed
+// instead of the receiver.
+func newClient(timeout time.Duration, allowPrivateNetworks bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivateNetworks {
+		dialer.Control = rejectNonPublicAddress
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// rejectNonPublicAddress is the dialer control rejecting connections to addresses that are not public
+func rejectNonPublicAddress(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIP(ip) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	return nil
+}
+
+// isPublicIP reports whether an address is a public unicast address
+// Loopback, private (RFC 1918, RFC 4193), link-local including 169.254.169.254, shared
+// (RFC 6598), unspecified and multicast addresses are not public.
+func isPublicIP(ip net.IP) bool {
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return false
+	}
+	return !sharedAddressSpace.Contains(ip)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range of RFC 6598
+var sharedAddressSpace = &net.IPNet{IP: net.IPv4(100, 64, 0, 0).To4(), Mask: net.CIDRMask(10, 32)}
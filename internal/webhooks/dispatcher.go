@@ -0,0 +1,304 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Headers of a delivery request
+const (
+	HeaderEvent     = "X-Portal64-Event"
+	HeaderDelivery  = "X-Portal64-Delivery"
+	HeaderTimestamp = "X-Portal64-Timestamp"
+	HeaderSignature = "X-Portal64-Signature"
+)
+
+// Statuses of a delivery
+const (
+	DeliveryPending   = "pending"   // Queued for its first attempt
+	DeliveryRetrying  = "retrying"  // An attempt failed, the next one is scheduled
+	DeliverySucceeded = "succeeded" // The receiver answered with 2xx
+	DeliveryFailed    = "failed"    // All attempts failed, or the receiver rejected the event
+)
+
+// userAgent identifies delivery requests
+const userAgent = "Portal64-Webhooks/1.0"
+
+// Sign returns the signature of a delivery: the hex encoded HMAC-SHA256 of "<timestamp>.<body>"
+// keyed with the secret of the subscription, prefixed with "sha256=".
+// Receivers recompute it from the X-Portal64-Timestamp header and the raw body.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Delivery is an entry of the delivery log
+type Delivery struct {
+	ID             string     `json:"id" example:"dlv_5a4b3c2d1e0f9a8b"`
+	SubscriptionID string     `json:"subscription_id"`
+	EventID        string     `json:"event_id"`
+	EventType      string     `json:"event_type"`
+	URL            string     `json:"url"`
+	Status         string     `json:"status" example:"succeeded"`
+	Attempts       int        `json:"attempts"`
+	StatusCode     int        `json:"status_code,omitempty"` // Response status of the last attempt
+	Error          string     `json:"error,omitempty"`       // Error of the last attempt
+	DurationMs     int64      `json:"duration_ms"`           // Duration of the last attempt
+	CreatedAt      time.Time  `json:"created_at"`
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"`
+}
+
+// Options configure deliveries
+type Options struct {
+	MaxAttempts int           // Attempts per delivery including the first
+	Backoff     time.Duration // Delay before the first retry, doubled for each further retry
+	Timeout     time.Duration // Timeout of a delivery request
+	Workers     int           // Concurrent delivery requests
+	LogSize     int           // Deliveries kept in the delivery log
+
+	AllowPrivateNetworks bool // Deliver to loopback, private and link-local addresses, for tests and internal receivers
+}
+
+// job is a queued delivery attempt
+type job struct {
+	delivery *Delivery
+	secret   string
+	body     []byte
+}
+
+// Dispatcher delivers events to subscriptions
+// Failed attempts are retried with exponential backoff if the receiver could not be reached, timed out
+// or answered 408, 429 or 5xx. Pending retries do not survive a restart.
+type Dispatcher struct {
+	options Options
+	client  *http.Client
+	logger  *logrus.Logger
+	queue   chan job
+
+	mu         sync.RWMutex
+	deliveries []*Delivery // Delivery log, oldest first
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewDispatcher creates a dispatcher, Start starts delivering
+func NewDispatcher(options Options, logger *logrus.Logger) *Dispatcher {
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 1
+	}
+	if options.Workers < 1 {
+		options.Workers = 1
+	}
+	if options.LogSize < 1 {
+		options.LogSize = 1
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Dispatcher{
+		options: options,
+		client:  newClient(options.Timeout, options.AllowPrivateNetworks),
+		logger:  logger,
+		queue:   make(chan job, 100),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start starts the delivery workers
+func (d *Dispatcher) Start() {
+	for i := 0; i < d.options.Workers; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			for {
+				select {
+				case j := <-d.queue:
+					d.attempt(j)
+				case <-d.ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+}
+
+// Stop stops the workers, queued and scheduled attempts are dropped
+func (d *Dispatcher) Stop() {
+	d.cancel()
+	d.wg.Wait()
+}
+
+// Dispatch queues the delivery of an event to a subscription and returns its log entry
+func (d *Dispatcher) Dispatch(subscription Subscription, event Event) (Delivery, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return Delivery{}, fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	delivery := &Delivery{
+		ID:             newID("dlv"),
+		SubscriptionID: subscription.ID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		URL:            subscription.URL,
+		Status:         DeliveryPending,
+		CreatedAt:      time.Now().UTC(),
+	}
+	d.record(delivery)
+	result := *delivery
+
+	d.enqueue(job{delivery: delivery, secret: subscription.Secret, body: body})
+	return result, nil
+}
+
+// Deliveries returns the logged deliveries of a subscription, all if subscriptionID is empty, newest first
+func (d *Dispatcher) Deliveries(subscriptionID string, limit int) []Delivery {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var deliveries []Delivery
+	for i := len(d.deliveries) - 1; i >= 0; i-- {
+		if limit > 0 && len(deliveries) >= limit {
+			break
+		}
+		if subscriptionID == "" || d.deliveries[i].SubscriptionID == subscriptionID {
+			deliveries = append(deliveries, *d.deliveries[i])
+		}
+	}
+	return deliveries
+}
+
+// record adds a delivery to the log, dropping the oldest entries beyond the log size
+func (d *Dispatcher) record(delivery *Delivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.deliveries = append(d.deliveries, delivery)
+	if excess := len(d.deliveries) - d.options.LogSize; excess > 0 {
+		d.deliveries = append([]*Delivery(nil), d.deliveries[excess:]...)
+	}
+}
+
+// enqueue queues an attempt, unless the dispatcher is stopped
+func (d *Dispatcher) enqueue(j job) {
+	select {
+	case d.queue <- j:
+	case <-d.ctx.Done():
+	}
+}
+
+// attempt sends a delivery request and schedules a retry if it failed
+func (d *Dispatcher) attempt(j job) {
+	started := time.Now()
+	statusCode, err := d.send(j)
+	finished := time.Now().UTC()
+
+	d.mu.Lock()
+	delivery := j.delivery
+	delivery.Attempts++
+	delivery.StatusCode = statusCode
+	delivery.DurationMs = time.Since(started).Milliseconds()
+	delivery.LastAttemptAt = &finished
+	delivery.NextAttemptAt = nil
+	delivery.Error = ""
+	if err != nil {
+		delivery.Error = err.Error()
+	}
+
+	var retryIn time.Duration
+	switch {
+	case err == nil && statusCode >= 200 && statusCode < 300:
+		delivery.Status = DeliverySucceeded
+	case isRetryable(statusCode, err) && delivery.Attempts < d.options.MaxAttempts:
+		retryIn = d.options.Backoff << (delivery.Attempts - 1)
+		next := finished.Add(retryIn)
+		delivery.Status = DeliveryRetrying
+		delivery.NextAttemptAt = &next
+	default:
+		delivery.Status = DeliveryFailed
+	}
+	entry := *delivery
+	d.mu.Unlock()
+
+	fields := logrus.Fields{
+		"delivery_id":     entry.ID,
+		"subscription_id": entry.SubscriptionID,
+		"event_type":      entry.EventType,
+		"attempt":         entry.Attempts,
+		"status_code":     entry.StatusCode,
+	}
+	switch entry.Status {
+	case DeliverySucceeded:
+		d.logger.WithFields(fields).Debug("Webhook delivered")
+	case DeliveryRetrying:
+		d.logger.WithFields(fields).Warnf("Webhook delivery failed, retrying in %s: %s", retryIn, entry.Error)
+		time.AfterFunc(retryIn, func() { d.enqueue(j) })
+	default:
+		d.logger.WithFields(fields).Errorf("Webhook delivery failed: %s", entry.Error)
+	}
+}
+
+// send posts the signed event and returns the response status
+func (d *Dispatcher) send(j job) (int, error) {
+	ctx := d.ctx
+	if d.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, d.options.Timeout)
+		defer cancel()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, j.delivery.URL, bytes.NewReader(j.body))
+	if err != nil {
+		return 0, err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+	req.Header.Set(HeaderEvent, j.delivery.EventType)
+	req.Header.Set(HeaderDelivery, j.delivery.ID)
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(j.secret, timestamp, j.body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	// Drain a bounded part of the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("receiver answered %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// isRetryable reports whether a failed attempt may succeed later
+func isRetryable(statusCode int, err error) bool {
+	if errors.Is(err, ErrBlockedAddress) {
+		return false
+	}
+	if statusCode == 0 {
+		return err != nil // Not reached or timed out
+	}
+	return statusCode == http.StatusRequestTimeout || statusCode == http.StatusTooManyRequests || statusCode >= 500
+}
@@ -0,0 +1,238 @@
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Errors returned by the subscription store
+var (
+	ErrInvalidSubscription  = errors.New("invalid webhook subscription")
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+)
+
+// subscriptionFile is the on-disk format of the subscription store
+type subscriptionFile struct {
+	Subscriptions []*Subscription `json:"subscriptions"`
+}
+
+// Store keeps webhook subscriptions in a JSON file
+// The file holds the signing secrets, so it is only readable by the owner.
+type Store struct {
+	path          string
+	mu            sync.RWMutex
+	subscriptions map[string]*Subscription
+}
+
+// NewStore opens the subscription store at path, a missing file is an empty store
+func NewStore(path string) (*Store, error) {
+	store := &Store{
+		path:          path,
+		subscriptions: make(map[string]*Subscription),
+	}
+	if err := store.load(); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// Create validates and persists a new subscription
+// A secret is generated if none is given. The returned copy includes the secret.
+func (s *Store) Create(subscription Subscription) (*Subscription, error) {
+	if err := validate(&subscription); err != nil {
+		return nil, err
+	}
+	if subscription.Secret == "" {
+		secret, err := generateSecret()
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate secret: %w", err)
+		}
+		subscription.Secret = secret
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscription.ID = newID("wh")
+	for s.subscriptions[subscription.ID] != nil {
+		subscription.ID = newID("wh")
+	}
+	subscription.CreatedAt = time.Now().UTC()
+	s.subscriptions[subscription.ID] = &subscription
+
+	if err := s.saveLocked(); err != nil {
+		delete(s.subscriptions, subscription.ID)
+		return nil, err
+	}
+
+	result := subscription
+	return &result, nil
+}
+
+// Delete removes a subscription
+func (s *Store) Delete(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	subscription, exists := s.subscriptions[id]
+	if !exists {
+		return ErrSubscriptionNotFound
+	}
+	delete(s.subscriptions, id)
+
+	if err := s.saveLocked(); err != nil {
+		s.subscriptions[id] = subscription
+		return err
+	}
+	return nil
+}
+
+// Get returns a subscription including its secret
+func (s *Store) Get(id string) (*Subscription, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subscription, exists := s.subscriptions[id]
+	if !exists {
+		return nil, ErrSubscriptionNotFound
+	}
+	result := *subscription
+	return &result, nil
+}
+
+// List returns all subscriptions including their secrets, ordered by creation time
+func (s *Store) List() []Subscription {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	subscriptions := make([]Subscription, 0, len(s.subscriptions))
+	for _, subscription := range s.subscriptions {
+		subscriptions = append(subscriptions, *subscription)
+	}
+	sort.Slice(subscriptions, func(i, j int) bool {
+		return subscriptions[i].CreatedAt.Before(subscriptions[j].CreatedAt)
+	})
+	return subscriptions
+}
+
+// Subscribers returns the subscriptions receiving events of a type
+func (s *Store) Subscribers(eventType string) []Subscription {
+	var subscribers []Subscription
+	for _, subscription := range s.List() {
+		if subscription.Wants(eventType) {
+			subscribers = append(subscribers, subscription)
+		}
+	}
+	return subscribers
+}
+
+// validate checks and normalises a subscription
+func validate(subscription *Subscription) error {
+	target, err := url.Parse(subscription.URL)
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") || target.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http or https URL", ErrInvalidSubscription)
+	}
+
+	if len(subscription.Events) == 0 {
+		return fmt.Errorf("%w: at least one event type is required (%s)", ErrInvalidSubscription, strings.Join(EventTypes, ", "))
+	}
+	events := make([]string, 0, len(subscription.Events))
+	for _, eventType := range subscription.Events {
+		if !IsValidEventType(eventType) {
+			return fmt.Errorf("%w: unknown event type %q (supported: %s)", ErrInvalidSubscription, eventType, strings.Join(EventTypes, ", "))
+		}
+		if !containsFold(events, eventType) {
+			events = append(events, eventType)
+		}
+	}
+	subscription.Events = events
+
+	subscription.Filters.ClubIDs = normaliseIDs(subscription.Filters.ClubIDs)
+	subscription.Filters.PlayerIDs = normaliseIDs(subscription.Filters.PlayerIDs)
+	return nil
+}
+
+// normaliseIDs trims and upper-cases IDs, dropping empty ones
+func normaliseIDs(ids []string) []string {
+	var result []string
+	for _, id := range ids {
+		if id = strings.ToUpper(strings.TrimSpace(id)); id != "" {
+			result = append(result, id)
+		}
+	}
+	return result
+}
+
+// generateSecret creates a random signing secret
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// load reads the subscription file
+func (s *Store) load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read webhook file: %w", err)
+	}
+
+	var file subscriptionFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return fmt.Errorf("failed to parse webhook file %s: %w", s.path, err)
+	}
+
+	subscriptions := make(map[string]*Subscription, len(file.Subscriptions))
+	for _, subscription := range file.Subscriptions {
+		subscriptions[subscription.ID] = subscription
+	}
+	s.subscriptions = subscriptions
+	return nil
+}
+
+// saveLocked writes the subscription file atomically, the caller holds the write lock
+func (s *Store) saveLocked() error {
+	file := subscriptionFile{Subscriptions: make([]*Subscription, 0, len(s.subscriptions))}
+	for _, subscription := range s.subscriptions {
+		file.Subscriptions = append(file.Subscriptions, subscription)
+	}
+	sort.Slice(file.Subscriptions, func(i, j int) bool {
+		return file.Subscriptions[i].CreatedAt.Before(file.Subscriptions[j].CreatedAt)
+	})
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode webhooks: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create webhook directory: %w", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write webhook file: %w", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		return fmt.Errorf("failed to write webhook file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// Event types that can be subscribed to
+const (
+	EventImportCompleted     = "import.completed"      // A database import completed successfully
+	EventImportFailed        = "import.failed"         // A database import failed
+	EventAnalysisCompleted   = "analysis.completed"    // A Kader-Planung or statistical analysis run finished
+	EventPlayerRatingChanged = "player.rating_changed" // The DWZ of players changed with an import
+)
+
+// EventPing is sent on request to test a subscription, it cannot be subscribed to
+const EventPing = "ping"
+
+// EventTypes lists every event type that can be subscribed to
+var EventTypes = []string{EventImportCompleted, EventImportFailed, EventAnalysisCompleted, EventPlayerRatingChanged}
+
+// IsValidEventType reports whether an event type can be subscribed to
+func IsValidEventType(eventType string) bool {
+	for _, t := range EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Filters restrict the player events of a subscription to some clubs or players
+// Events that do not concern players are always delivered.
+type Filters struct {
+	ClubIDs   []string `json:"club_ids,omitempty" example:"C0101"`
+	PlayerIDs []string `json:"player_ids,omitempty" example:"C0101-1014"`
+}
+
+// IsEmpty reports whether the filters let all players pass
+func (f Filters) IsEmpty() bool {
+	return len(f.ClubIDs) == 0 && len(f.PlayerIDs) == 0
+}
+
+// Matches reports whether an event about a player of a club passes the filters
+// A player passes if the club or the player ID is listed.
+func (f Filters) Matches(clubID, playerID string) bool {
+	if f.IsEmpty() {
+		return true
+	}
+	return containsFold(f.ClubIDs, clubID) || containsFold(f.PlayerIDs, playerID)
+}
+
+// Subscription is a webhook subscription. The secret signs the deliveries.
+type Subscription struct {
+	ID          string    `json:"id" example:"wh_1a2b3c4d5e6f"`
+	URL         string    `json:"url" example:"https://example.org/portal64/webhook"`
+	Secret      string    `json:"secret,omitempty"` // Only returned on creation
+	Description string    `json:"description,omitempty"`
+	Events      []string  `json:"events" example:"import.completed,player.rating_changed"`
+	Filters     Filters   `json:"filters"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Wants reports whether the subscription receives events of a type
+// Ping events are sent to every subscription.
+func (s *Subscription) Wants(eventType string) bool {
+	if eventType == EventPing {
+		return true
+	}
+	for _, t := range s.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is the payload of a delivery
+type Event struct {
+	ID        string      `json:"id" example:"evt_9f8e7d6c5b4a3f2e"`
+	Type      string      `json:"type" example:"import.completed"`
+	CreatedAt time.Time   `json:"created_at"`
+	Data      interface{} `json:"data"`
+}
+
+// NewEvent creates an event with a new ID
+func NewEvent(eventType string, data interface{}) Event {
+	return Event{ID: newID("evt"), Type: eventType, CreatedAt: time.Now().UTC(), Data: data}
+}
+
+// newID returns a random identifier with a prefix, e.g. wh_1a2b3c4d5e6f7a8b
+func newID(prefix string) string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		// crypto/rand does not fail on supported platforms
+		panic(err)
+	}
+	return prefix + "_" + hex.EncodeToString(b)
+}
+
+func containsFold(values []string, value string) bool {
+	if value == "" {
+		return false
+	}
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
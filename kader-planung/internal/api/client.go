@@ -7,6 +7,7 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"strconv"
 	"strings"
 	"sync"
@@ -16,12 +17,20 @@ import (
 	"github.com/sirupsen/logrus"
 )
 
+// Retries of requests denied by the rate limit of the API
+const (
+	maxRateLimitRetries = 5
+	maxRetryAfter       = time.Minute
+)
+
 // Client represents the Portal64 API client
 type Client struct {
 	baseURL     string
 	httpClient  *http.Client
 	logger      *logrus.Logger
 	concurrency int // Number of concurrent workers for efficient bulk operations
+	traceParent string // W3C trace context passed by the Portal64 API when it started this process
+	traceState  string
 }
 
 // NewClient creates a new API client
@@ -48,6 +57,8 @@ func NewClient(baseURL string, timeout time.Duration) *Client {
 		},
 		logger:      logrus.StandardLogger(),
 		concurrency: 8, // Default concurrency level
+		traceParent: os.Getenv("TRACEPARENT"),
+		traceState:  os.Getenv("TRACESTATE"),
 	}
 }
 
@@ -112,13 +123,13 @@ func (c *Client) GetClubPlayers(clubID string, active bool, limit, offset int) (
 func (c *Client) GetTournamentDetails(tournamentID string) (*models.Tournament, error) {
 	endpoint := fmt.Sprintf("/api/v1/tournaments/%s", tournamentID)
 	
-	var tournament models.Tournament
-	if err := c.makeRequest("GET", endpoint, nil, &tournament); err != nil {
+	var response models.TournamentAPIResponse
+	if err := c.makeRequest("GET", endpoint, nil, &response); err != nil {
 		c.logger.Debugf("Failed to get tournament details for %s: %v", tournamentID, err)
 		return nil, fmt.Errorf("failed to get tournament details for %s: %w", tournamentID, err)
 	}
 
-	return &tournament, nil
+	return &response.Data, nil
 }
 
 // GetPlayerProfile retrieves detailed information about a specific player
@@ -163,9 +174,9 @@ func (c *Client) GetPlayerRatingHistory(playerID string) (*models.RatingHistory,
 		if result.TournamentDate != nil {
 			tournamentDate = *result.TournamentDate
 		} else {
-			// Fallback to estimation only if date not available from API
-			c.logger.Debugf("Tournament %s has no pre-computed date, using estimation fallback", result.TournamentID)
-			tournamentDate = c.estimateTournamentDate(result.TournamentID)
+			// Fall back to the tournament details only if the date is not available
+			c.logger.Debugf("Tournament %s has no pre-computed date, fetching tournament details", result.TournamentID)
+			tournamentDate = c.getTournamentDate(result.TournamentID)
 		}
 
 		point := models.RatingPoint{
@@ -267,27 +278,49 @@ func (c *Client) CheckHealth() error {
 }
 
 // makeRequest performs an HTTP request and handles the response
+// Requests without body that are denied by the rate limit of the API (429) are retried after
+// the time given by Retry-After, at most maxRateLimitRetries times.
 func (c *Client) makeRequest(method, endpoint string, body io.Reader, result interface{}) error {
 	url := c.baseURL + endpoint
 	
 	c.logger.Debugf("Making %s request to %s", method, url)
-	
-	req, err := http.NewRequest(method, url, body)
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
-	}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
+	var (
+		resp         *http.Response
+		responseBody []byte
+	)
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(method, url, body)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
 
-	resp, err := c.httpClient.Do(req)
-	if err != nil {
-		return fmt.Errorf("request failed: %w", err)
-	}
-	defer resp.Body.Close()
-	responseBody, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("failed to read response body: %w", err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		if c.traceParent != "" {
+			// Join the trace of the run so the API requests show up under it
+			req.Header.Set("traceparent", c.traceParent)
+			if c.traceState != "" {
+				req.Header.Set("tracestate", c.traceState)
+			}
+		}
+
+		resp, err = c.httpClient.Do(req)
+		if err != nil {
+			return fmt.Errorf("request failed: %w", err)
+		}
+		responseBody, err = io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return fmt.Errorf("failed to read response body: %w", err)
+		}
+
+		if resp.StatusCode != http.StatusTooManyRequests || body != nil || attempt >= maxRateLimitRetries {
+			break
+		}
+		wait := retryAfter(resp.Header.Get("Retry-After"))
+		c.logger.Debugf("Rate limited on %s, retrying in %v", url, wait)
+		time.Sleep(wait)
 	}
 
 	c.logger.Debugf("Response status: %d, body length: %d", resp.StatusCode, len(responseBody))
@@ -309,6 +342,18 @@ func (c *Client) makeRequest(method, endpoint string, body io.Reader, result int
 	return nil
 }
 
+// retryAfter returns the wait time of a Retry-After header in seconds, one second if it is missing
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return time.Second
+	}
+	if wait := time.Duration(seconds) * time.Second; wait < maxRetryAfter {
+		return wait
+	}
+	return maxRetryAfter
+}
+
 // SetTimeout updates the client timeout
 func (c *Client) SetTimeout(timeout time.Duration) {
 	c.httpClient.Timeout = timeout
@@ -321,19 +366,18 @@ func (c *Client) SetLogger(logger *logrus.Logger) {
 
 // getTournamentDate fetches tournament details and returns the latest available date
 // from start_date, end_date, finished_on, computed_on fields
-// 
-// DEPRECATED: This method is no longer used due to API optimization.
-// The Portal64 API now returns pre-computed tournament dates in rating history responses,
-// eliminating the need for additional API calls. This method is kept for reference only.
+//
+// Only used for rating history entries without pre-computed tournament date. Tournaments without
+// any date use the week the API decodes from the tournament code (code_info), tournaments
+// without encoded date (e.g. T117893) fall back to one year ago.
 func (c *Client) getTournamentDate(tournamentID string) time.Time {
+	fallbackDate := time.Now().AddDate(-1, 0, 0) // 1 year ago
+
 	// Try to fetch tournament details
 	tournament, err := c.GetTournamentDetails(tournamentID)
 	if err != nil {
-		c.logger.Debugf("Could not fetch tournament details for %s, trying to estimate date from ID: %v", tournamentID, err)
-		// Use estimation as fallback instead of generic date
-		estimatedDate := c.estimateTournamentDate(tournamentID)
-		c.logger.Debugf("Tournament %s: estimated date %v from tournament ID", tournamentID, estimatedDate)
-		return estimatedDate
+		c.logger.Debugf("Could not fetch tournament details for %s, using fallback: %v", tournamentID, err)
+		return fallbackDate
 	}
 
 	// Collect all available dates
@@ -359,12 +403,14 @@ func (c *Client) getTournamentDate(tournamentID string) time.Time {
 		c.logger.Debugf("Tournament %s has computed_on: %v", tournamentID, *tournament.ComputedOn)
 	}
 
-	// If no dates are available, use estimation as fallback
+	// If no dates are available, use the week encoded in the tournament code
 	if len(availableDates) == 0 {
-		c.logger.Debugf("Tournament %s has no available dates, estimating date from ID", tournamentID)
-		estimatedDate := c.estimateTournamentDate(tournamentID)
-		c.logger.Debugf("Tournament %s: estimated date %v from tournament ID", tournamentID, estimatedDate)
-		return estimatedDate
+		if tournament.CodeInfo != nil && tournament.CodeInfo.ApproxStartDate != nil {
+			c.logger.Debugf("Tournament %s has no dates, using the week of its code: %v", tournamentID, *tournament.CodeInfo.ApproxStartDate)
+			return *tournament.CodeInfo.ApproxStartDate
+		}
+		c.logger.Debugf("Tournament %s has no available dates, using fallback", tournamentID)
+		return fallbackDate
 	}
 
 	// Find the latest date
@@ -381,59 +427,6 @@ func (c *Client) getTournamentDate(tournamentID string) time.Time {
 	return latestDate
 }
 
-// estimateTournamentDate attempts to estimate tournament date from tournament ID
-// Tournament IDs follow patterns like: B914-550-P4P, C413-612-DSV, T117893
-// Format appears to be: [LETTER][YY][WW]-[OTHER]-[SUFFIX]
-// Where YY = year (14 = 2014, 13 = 2013, etc.) and WW = week of year
-// 
-// DEPRECATED: This function is kept for fallback purposes only.
-// Use getTournamentDate() instead which fetches real tournament data.
-func (c *Client) estimateTournamentDate(tournamentID string) time.Time {
-	// Default fallback date if parsing fails
-	fallbackDate := time.Now().AddDate(-1, 0, 0) // 1 year ago
-	
-	if len(tournamentID) < 4 {
-		return fallbackDate
-	}
-
-	// Try to parse tournament ID patterns
-	if len(tournamentID) >= 4 && (tournamentID[0] == 'B' || tournamentID[0] == 'C') {
-		// Pattern: B914-550-P4P or C413-612-DSV
-		yearStr := tournamentID[1:3]
-		if year, err := strconv.Atoi(yearStr); err == nil {
-			// Convert 2-digit year to 4-digit year
-			// Assume years 00-30 are 2000-2030, years 31-99 are 1931-1999
-			fullYear := 2000 + year
-			if year >= 31 {
-				fullYear = 1900 + year
-			}
-			
-			// Try to extract week number
-			if len(tournamentID) >= 6 {
-				weekStr := tournamentID[3:5]
-				if week, err := strconv.Atoi(weekStr); err == nil && week >= 1 && week <= 53 {
-					// Calculate date from year and week
-					jan1 := time.Date(fullYear, 1, 1, 0, 0, 0, 0, time.UTC)
-					_, week1 := jan1.ISOWeek()
-					weekDiff := week - week1
-					tournamentDate := jan1.AddDate(0, 0, weekDiff*7)
-					return tournamentDate
-				}
-			}
-			
-			// If we can't parse week, just use mid-year
-			return time.Date(fullYear, 6, 15, 0, 0, 0, 0, time.UTC)
-		}
-	} else if strings.HasPrefix(tournamentID, "T") {
-		// Pattern: T117893 - might be some other encoding
-		// For now, just use fallback
-		return fallbackDate
-	}
-
-	c.logger.Debugf("Could not parse tournament date from ID: %s, using fallback", tournamentID)
-	return fallbackDate
-}
-
 // ========================================
 // ENHANCED EFFICIENT BULK OPERATIONS
 // Inspired by Somatogramm's concurrent approach
@@ -0,0 +1,87 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// TestMakeRequest_RetriesRateLimitedRequests tests that requests denied with 429 are retried
+func TestMakeRequest_RetriesRateLimitedRequests(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&requests, 1) <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"status":"healthy"}`))
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, 5*time.Second)
+	if err := client.CheckHealth(); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Errorf("Expected 3 requests, got %d", got)
+	}
+}
+
+// TestMakeRequest_GivesUpAfterMaxRetries tests that a request stays rate limited after the retries
+func TestMakeRequest_GivesUpAfterMaxRetries(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, 5*time.Second)
+	if err := client.CheckHealth(); err == nil {
+		t.Error("Expected an error for a request that stays rate limited")
+	}
+	if got := atomic.LoadInt32(&requests); got != maxRateLimitRetries+1 {
+		t.Errorf("Expected %d requests, got %d", maxRateLimitRetries+1, got)
+	}
+}
+
+// TestRetryAfter tests parsing of the Retry-After header
+func TestRetryAfter(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":     time.Second,
+		"soon": time.Second,
+		"0":    0,
+		"3":    3 * time.Second,
+		"3600": maxRetryAfter,
+	}
+	for header, want := range tests {
+		if got := retryAfter(header); got != want {
+			t.Errorf("retryAfter(%q) = %v, want %v", header, got, want)
+		}
+	}
+}
+
+// TestGetTournamentDate_UsesCodeInfo tests that tournaments without dates use the week decoded by the API
+func TestGetTournamentDate_UsesCodeInfo(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/tournaments/C529-K00-HT1":
+			w.Write([]byte(`{"success":true,"data":{"id":"C529-K00-HT1","code_info":{"year":2025,"week":29,"approx_start_date":"2025-07-14T00:00:00Z"}}}`))
+		default:
+			w.Write([]byte(`{"success":true,"data":{"id":"T117893"}}`))
+		}
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL, 5*time.Second)
+	if got, want := client.getTournamentDate("C529-K00-HT1"), time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Errorf("Expected the week of the code %v, got %v", want, got)
+	}
+	if got := client.getTournamentDate("T117893"); time.Since(got) < 364*24*time.Hour {
+		t.Errorf("Expected the fallback of one year ago, got %v", got)
+	}
+}
@@ -169,15 +169,31 @@ func (e APIError) Error() string {
 
 // Tournament represents a tournament with date information
 type Tournament struct {
-	ID           string     `json:"id"`
-	Name         string     `json:"name"`
-	Code         string     `json:"code"`
-	Type         string     `json:"type"`
-	StartDate    *time.Time `json:"start_date"`
-	EndDate      *time.Time `json:"end_date"`
-	FinishedOn   *time.Time `json:"finished_on"`
-	ComputedOn   *time.Time `json:"computed_on"`
-	Status       string     `json:"status"`
+	ID         string              `json:"id"`
+	Name       string              `json:"name"`
+	Code       string              `json:"code"`
+	Type       string              `json:"type"`
+	StartDate  *time.Time          `json:"start_date"`
+	EndDate    *time.Time          `json:"end_date"`
+	FinishedOn *time.Time          `json:"finished_on"`
+	ComputedOn *time.Time          `json:"computed_on"`
+	Status     string              `json:"status"`
+	CodeInfo   *TournamentCodeInfo `json:"code_info,omitempty"`
+}
+
+// TournamentAPIResponse represents the API response of a tournament
+type TournamentAPIResponse struct {
+	Success bool       `json:"success"`
+	Data    Tournament `json:"data"`
+}
+
+// TournamentCodeInfo represents the fields the API decodes from a tournament code
+type TournamentCodeInfo struct {
+	Year            int        `json:"year"`
+	Week            int        `json:"week"`
+	ApproxStartDate *time.Time `json:"approx_start_date"`
+	SubCode         string     `json:"sub_code"`
+	TypeAcronym     string     `json:"type_acronym"`
 }
 
 // Constants for data availability
@@ -0,0 +1,135 @@
+package errors
+
+import "net/http"
+
+// ErrorCode is a stable, machine-readable identifier of an error
+// Codes are part of the API contract: they are never renamed or reused, messages may change.
+type ErrorCode string
+
+// Generic errors
+const (
+	CodeBadRequest         ErrorCode = "BAD_REQUEST"
+	CodeInvalidParameter   ErrorCode = "INVALID_PARAMETER"
+	CodeInvalidRequestBody ErrorCode = "INVALID_REQUEST_BODY"
+	CodeAPIKeyRequired     ErrorCode = "API_KEY_REQUIRED"
+	CodeInvalidAPIKey      ErrorCode = "INVALID_API_KEY"
+	CodeInsufficientScope  ErrorCode = "INSUFFICIENT_SCOPE"
+	CodeNotFound           ErrorCode = "NOT_FOUND"
+	CodeRouteNotFound      ErrorCode = "ROUTE_NOT_FOUND"
+	CodeNotAcceptable      ErrorCode = "NOT_ACCEPTABLE"
+	CodeConflict           ErrorCode = "CONFLICT"
+	CodeRateLimited        ErrorCode = "RATE_LIMITED"
+	CodeInternal           ErrorCode = "INTERNAL_ERROR"
+	CodeServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE"
+)
+
+// Errors of identifiers and resources
+const (
+	CodeInvalidPlayerID     ErrorCode = "INVALID_PLAYER_ID"
+	CodeInvalidClubID       ErrorCode = "INVALID_CLUB_ID"
+	CodeInvalidTournamentID ErrorCode = "INVALID_TOURNAMENT_ID"
+	CodeInvalidPersonUUID   ErrorCode = "INVALID_PERSON_UUID"
+	CodePlayerNotFound      ErrorCode = "PLAYER_NOT_FOUND"
+	CodeClubNotFound        ErrorCode = "CLUB_NOT_FOUND"
+	CodeTournamentNotFound  ErrorCode = "TOURNAMENT_NOT_FOUND"
+	CodePersonNotFound      ErrorCode = "PERSON_NOT_FOUND"
+	CodeAddressNotFound     ErrorCode = "ADDRESS_NOT_FOUND"
+	CodeFileNotFound        ErrorCode = "FILE_NOT_FOUND"
+	CodeWebhookNotFound     ErrorCode = "WEBHOOK_NOT_FOUND"
+	CodeNotTeamCompetition  ErrorCode = "NOT_A_TEAM_COMPETITION"
+)
+
+// Errors of the databases and background operations
+const (
+	CodeDatabaseUnavailable     ErrorCode = "DATABASE_UNAVAILABLE"
+	CodeDatabaseError           ErrorCode = "DATABASE_ERROR"
+	CodeImportInProgress        ErrorCode = "IMPORT_IN_PROGRESS"
+	CodeImportDisabled          ErrorCode = "IMPORT_DISABLED"
+	CodeExecutionAlreadyRunning ErrorCode = "EXECUTION_ALREADY_RUNNING"
+	CodeExportInProgress        ErrorCode = "EXPORT_IN_PROGRESS"
+	CodeExportNotReady          ErrorCode = "EXPORT_NOT_READY"
+)
+
+// CatalogueEntry describes an error code
+type CatalogueEntry struct {
+	Code        ErrorCode `json:"code" example:"PLAYER_NOT_FOUND"`
+	Status      int       `json:"status" example:"404"`
+	Title       string    `json:"title" example:"Player not found"`
+	Description string    `json:"description"`
+}
+
+// catalogue lists all error codes the API returns, in documentation order
+var catalogue = []CatalogueEntry{
+	{CodeBadRequest, http.StatusBadRequest, "Bad request", "The request is invalid."},
+	{CodeInvalidParameter, http.StatusBadRequest, "Invalid parameter", "A query or path parameter is missing or invalid; the detail names it."},
+	{CodeInvalidRequestBody, http.StatusBadRequest, "Invalid request body", "The request body is no valid JSON or misses required fields."},
+	{CodeInvalidPlayerID, http.StatusBadRequest, "Invalid player ID", "Player IDs have the format CLUBID-PERSONID, e.g. C0101-1014."},
+	{CodeInvalidClubID, http.StatusBadRequest, "Invalid club ID", "Club IDs (VKZ) have 3 to 10 letters and digits, e.g. C0101."},
+	{CodeInvalidTournamentID, http.StatusBadRequest, "Invalid tournament ID", "Tournament IDs have the format B718-A08-BEL, C529-K00-HT1 or T117893."},
+	{CodeInvalidPersonUUID, http.StatusBadRequest, "Invalid person UUID", "Person UUIDs have the format 0b9f7c1e-5a1d-4c7e-9a55-3f1f2c6d8e01."},
+	{CodeImportDisabled, http.StatusBadRequest, "Import disabled", "The import service is disabled in the configuration."},
+	{CodeAPIKeyRequired, http.StatusUnauthorized, "API key required", "The endpoint requires an API key in the X-API-Key or Authorization header."},
+	{CodeInvalidAPIKey, http.StatusUnauthorized, "Invalid API key", "The API key is unknown, revoked or expired."},
+	{CodeInsufficientScope, http.StatusForbidden, "Insufficient scope", "The API key lacks the scope the endpoint requires."},
+	{CodeNotFound, http.StatusNotFound, "Not found", "The requested resource does not exist."},
+	{CodeRouteNotFound, http.StatusNotFound, "Route not found", "No endpoint exists for the requested path."},
+	{CodePlayerNotFound, http.StatusNotFound, "Player not found", "No active membership exists for the player ID."},
+	{CodeClubNotFound, http.StatusNotFound, "Club not found", "No active club exists for the club ID."},
+	{CodeTournamentNotFound, http.StatusNotFound, "Tournament not found", "No tournament exists for the tournament ID."},
+	{CodeNotTeamCompetition, http.StatusNotFound, "Not a team competition", "Team matches are only available for team competitions."},
+	{CodePersonNotFound, http.StatusNotFound, "Person not found", "No person exists for the UUID or player ID."},
+	{CodeAddressNotFound, http.StatusNotFound, "Address not found", "No address exists for the ID in the region."},
+	{CodeFileNotFound, http.StatusNotFound, "File not found", "The requested result file does not exist."},
+	{CodeWebhookNotFound, http.StatusNotFound, "Webhook not found", "No webhook subscription exists for the ID."},
+	{CodeNotAcceptable, http.StatusNotAcceptable, "Not acceptable", "The requested response format is not supported; the detail lists the supported formats."},
+	{CodeConflict, http.StatusConflict, "Conflict", "The request conflicts with the current state of the server."},
+	{CodeImportInProgress, http.StatusConflict, "Import in progress", "An import is running; retry after it has completed."},
+	{CodeExecutionAlreadyRunning, http.StatusConflict, "Execution already running", "An analysis of the same kind is running; retry after it has completed."},
+	{CodeExportInProgress, http.StatusConflict, "Export in progress", "An export is being generated; the current export stays available meanwhile."},
+	{CodeRateLimited, http.StatusTooManyRequests, "Rate limit exceeded", "Too many requests; retry after the number of seconds in the Retry-After header."},
+	{CodeInternal, http.StatusInternalServerError, "Internal server error", "An unexpected error occurred; quote the request ID when reporting it."},
+	{CodeDatabaseError, http.StatusInternalServerError, "Database error", "A database query failed; quote the request ID when reporting it."},
+	{CodeServiceUnavailable, http.StatusServiceUnavailable, "Service unavailable", "A service the endpoint depends on is not available."},
+	{CodeDatabaseUnavailable, http.StatusServiceUnavailable, "Database unavailable", "A database cannot be reached, e.g. while an import replaces it; retry later."},
+	{CodeExportNotReady, http.StatusServiceUnavailable, "Export not ready", "No export has been generated yet, or the requested generation was removed; retry later."},
+}
+
+// Catalogue returns all error codes the API returns
+func Catalogue() []CatalogueEntry {
+	entries := make([]CatalogueEntry, len(catalogue))
+	copy(entries, catalogue)
+	return entries
+}
+
+// Lookup returns the catalogue entry of an error code
+func Lookup(code ErrorCode) (CatalogueEntry, bool) {
+	for _, entry := range catalogue {
+		if entry.Code == code {
+			return entry, true
+		}
+	}
+	return CatalogueEntry{}, false
+}
+
+// codeForStatus returns the generic error code of an HTTP status
+func codeForStatus(status int) ErrorCode {
+	switch status {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeAPIKeyRequired
+	case http.StatusForbidden:
+		return CodeInsufficientScope
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusNotAcceptable:
+		return CodeNotAcceptable
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	case http.StatusServiceUnavailable:
+		return CodeServiceUnavailable
+	}
+	return CodeInternal
+}
@@ -0,0 +1,58 @@
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	stderrors "errors"
+	"net"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// MySQL errors of a server that does not accept queries
+const (
+	mysqlTooManyConnections = 1040
+	mysqlServerShutdown     = 1053
+)
+
+// FromLookup converts the error of a repository lookup of a single record
+// Missing records become notFound; other errors are database errors, so clients can tell
+// missing data from outages.
+func FromLookup(err error, notFound APIError) APIError {
+	if stderrors.Is(err, gorm.ErrRecordNotFound) {
+		return notFound
+	}
+	return NewDatabaseError(err, "Database query failed")
+}
+
+// NewDatabaseError converts a repository error; message describes the failed operation
+// Errors of databases that cannot be reached are DATABASE_UNAVAILABLE (503, retrying may help),
+// all others DATABASE_ERROR (500). API errors are returned unchanged.
+func NewDatabaseError(err error, message string) APIError {
+	var apiErr APIError
+	if stderrors.As(err, &apiErr) {
+		return apiErr
+	}
+	if isUnavailable(err) {
+		return New(CodeDatabaseUnavailable, "Database unavailable", err.Error())
+	}
+	return New(CodeDatabaseError, message, err.Error())
+}
+
+// isUnavailable reports whether an error is caused by a database that cannot be reached
+func isUnavailable(err error) bool {
+	var netErr net.Error
+	var mysqlErr *mysql.MySQLError
+	switch {
+	case stderrors.Is(err, driver.ErrBadConn), stderrors.Is(err, sql.ErrConnDone),
+		stderrors.Is(err, mysql.ErrInvalidConn), stderrors.Is(err, context.DeadlineExceeded):
+		return true
+	case stderrors.As(err, &netErr):
+		return true
+	case stderrors.As(err, &mysqlErr):
+		return mysqlErr.Number == mysqlTooManyConnections || mysqlErr.Number == mysqlServerShutdown
+	}
+	return false
+}
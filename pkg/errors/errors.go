@@ -6,10 +6,12 @@ import (
 )
 
 // APIError represents an API error with status code and message
+// ErrorCode identifies the error for clients, Message and Details are meant for humans.
 type APIError struct {
-	Code    int    `json:"code"`
-	Message string `json:"message"`
-	Details string `json:"details,omitempty"`
+	Code      int       `json:"code"`
+	ErrorCode ErrorCode `json:"error_code"`
+	Message   string    `json:"message"`
+	Details   string    `json:"details,omitempty"`
 }
 
 // Error implements the error interface
@@ -20,36 +22,59 @@ func (e APIError) Error() string {
 // Predefined errors
 var (
 	ErrNotFound = APIError{
-		Code:    http.StatusNotFound,
-		Message: "Resource not found",
+		Code:      http.StatusNotFound,
+		ErrorCode: CodeNotFound,
+		Message:   "Resource not found",
 	}
 	
 	ErrBadRequest = APIError{
-		Code:    http.StatusBadRequest,
-		Message: "Bad request",
+		Code:      http.StatusBadRequest,
+		ErrorCode: CodeBadRequest,
+		Message:   "Bad request",
 	}
 	
 	ErrInternalServer = APIError{
-		Code:    http.StatusInternalServerError,
-		Message: "Internal server error",
+		Code:      http.StatusInternalServerError,
+		ErrorCode: CodeInternal,
+		Message:   "Internal server error",
 	}
 	
 	ErrUnauthorized = APIError{
-		Code:    http.StatusUnauthorized,
-		Message: "Unauthorized",
+		Code:      http.StatusUnauthorized,
+		ErrorCode: CodeAPIKeyRequired,
+		Message:   "Unauthorized",
 	}
 	
 	ErrForbidden = APIError{
-		Code:    http.StatusForbidden,
-		Message: "Forbidden",
+		Code:      http.StatusForbidden,
+		ErrorCode: CodeInsufficientScope,
+		Message:   "Forbidden",
 	}
 )
 
-// NewAPIError creates a new API error
+// New creates an error with a code of the catalogue
+func New(code ErrorCode, message string, details ...string) APIError {
+	status := http.StatusInternalServerError
+	if entry, ok := Lookup(code); ok {
+		status = entry.Status
+	}
+	err := APIError{
+		Code:      status,
+		ErrorCode: code,
+		Message:   message,
+	}
+	if len(details) > 0 {
+		err.Details = details[0]
+	}
+	return err
+}
+
+// NewAPIError creates a new API error with the generic error code of the status
 func NewAPIError(code int, message string, details ...string) APIError {
 	err := APIError{
-		Code:    code,
-		Message: message,
+		Code:      code,
+		ErrorCode: codeForStatus(code),
+		Message:   message,
 	}
 	if len(details) > 0 {
 		err.Details = details[0]
@@ -60,24 +85,27 @@ func NewAPIError(code int, message string, details ...string) APIError {
 // NewNotFoundError creates a not found error
 func NewNotFoundError(resource string) APIError {
 	return APIError{
-		Code:    http.StatusNotFound,
-		Message: fmt.Sprintf("%s not found", resource),
+		Code:      http.StatusNotFound,
+		ErrorCode: CodeNotFound,
+		Message:   fmt.Sprintf("%s not found", resource),
 	}
 }
 
-// NewBadRequestError creates a bad request error
+// NewBadRequestError creates an error of an invalid parameter
 func NewBadRequestError(message string) APIError {
 	return APIError{
-		Code:    http.StatusBadRequest,
-		Message: message,
+		Code:      http.StatusBadRequest,
+		ErrorCode: CodeInvalidParameter,
+		Message:   message,
 	}
 }
 
 // NewInternalServerError creates an internal server error
 func NewInternalServerError(details string) APIError {
 	return APIError{
-		Code:    http.StatusInternalServerError,
-		Message: "Internal server error",
-		Details: details,
+		Code:      http.StatusInternalServerError,
+		ErrorCode: CodeInternal,
+		Message:   "Internal server error",
+		Details:   details,
 	}
 }
@@ -0,0 +1,51 @@
+package errors
+
+import "net/http"
+
+// ProblemContentType is the media type of problem details (RFC 7807)
+const ProblemContentType = "application/problem+json"
+
+// TypeURIPrefix is the prefix of problem type URIs; the catalogue entry of a code is served there
+const TypeURIPrefix = "/api/v1/errors/"
+
+// Problem is an error response as problem details (RFC 7807)
+// success and error repeat the response envelope for clients written against it.
+type Problem struct {
+	Type      string    `json:"type" example:"/api/v1/errors/PLAYER_NOT_FOUND"`
+	Title     string    `json:"title" example:"Player not found"`
+	Status    int       `json:"status" example:"404"`
+	Detail    string    `json:"detail,omitempty" example:"Player not found"`
+	Details   string    `json:"details,omitempty"`
+	Instance  string    `json:"instance,omitempty" example:"/api/v1/players/C0101-9999"`
+	ErrorCode ErrorCode `json:"error_code" example:"PLAYER_NOT_FOUND"`
+	RequestID string    `json:"request_id,omitempty"`
+	Success   bool      `json:"success" example:"false"`
+	Error     string    `json:"error" example:"Player not found"`
+}
+
+// NewProblem creates the problem details of an error answered with the given status
+// Details of server errors may contain internals such as SQL errors and are left out.
+func NewProblem(err APIError, status int, instance string) Problem {
+	code := err.ErrorCode
+	if code == "" {
+		code = codeForStatus(status)
+	}
+	title := http.StatusText(status)
+	if entry, ok := Lookup(code); ok {
+		title = entry.Title
+	}
+
+	problem := Problem{
+		Type:      TypeURIPrefix + string(code),
+		Title:     title,
+		Status:    status,
+		Detail:    err.Message,
+		Instance:  instance,
+		ErrorCode: code,
+		Error:     err.Message,
+	}
+	if status < http.StatusInternalServerError {
+		problem.Details = err.Details
+	}
+	return problem
+}
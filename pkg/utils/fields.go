@@ -0,0 +1,271 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"portal64api/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// includedField is the field holding related resources embedded with ?include=
+// It is kept when the fields of a resource are selected.
+const includedField = "included"
+
+// Record is a resource reduced to selected fields
+// It is encoded as a JSON object and CSV row with the fields in the requested order.
+type Record struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+// Keys returns the field names of the record in order
+func (r Record) Keys() []string {
+	return r.keys
+}
+
+// Get returns the value of a field
+func (r Record) Get(key string) (interface{}, bool) {
+	value, ok := r.values[key]
+	return value, ok
+}
+
+func (r *Record) set(key string, value interface{}) {
+	if r.values == nil {
+		r.values = make(map[string]interface{})
+	}
+	if _, exists := r.values[key]; !exists {
+		r.keys = append(r.keys, key)
+	}
+	r.values[key] = value
+}
+
+// MarshalJSON encodes the record as an object with the fields in order
+func (r Record) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range r.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(r.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// ParseFields returns the fields requested via ?fields=a,b, or nil for all fields
+func ParseFields(c *gin.Context) []string {
+	return splitList(c.Query("fields"))
+}
+
+// ParseIncludes returns the related resources requested via ?include=a,b
+// Names not in supported are rejected, so typos do not silently return less data.
+func ParseIncludes(c *gin.Context, supported ...string) ([]string, error) {
+	includes := splitList(strings.ToLower(c.Query("include")))
+	for _, include := range includes {
+		if !containsString(supported, include) {
+			return nil, errors.NewBadRequestError(fmt.Sprintf("Unknown include '%s' (supported: %s)",
+				include, strings.Join(supported, ", ")))
+		}
+	}
+	return includes, nil
+}
+
+// SelectFields reduces the resources in data to the given fields, in the given order
+// Lists and paginated responses (structs with a Data slice) are reduced element by element,
+// other fields of the response such as meta are kept. Related resources embedded with
+// ?include= are kept as well. Unknown field names are rejected with a bad request error.
+func SelectFields(data interface{}, fields []string) (interface{}, error) {
+	if len(fields) == 0 || data == nil {
+		return data, nil
+	}
+
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return data, nil
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		return selectListFields(v, fields)
+	case reflect.Struct:
+		dataField, hasData := v.Type().FieldByName("Data")
+		if !hasData || v.FieldByIndex(dataField.Index).Kind() != reflect.Slice {
+			return selectResourceFields(v, fields)
+		}
+
+		// Paginated response: reduce the elements, keep the envelope
+		var envelope Record
+		for _, column := range structColumns(v) {
+			if column.goName != dataField.Name {
+				envelope.set(column.name, column.value.Interface())
+				continue
+			}
+			records, err := selectListFields(column.value, fields)
+			if err != nil {
+				return nil, err
+			}
+			envelope.set(column.name, records)
+		}
+		return envelope, nil
+	}
+
+	// Not a resource, e.g. a map or a scalar
+	return data, nil
+}
+
+// selectListFields reduces each struct element of a list to the given fields
+func selectListFields(list reflect.Value, fields []string) (interface{}, error) {
+	elemType := list.Type().Elem()
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() == reflect.Struct {
+		// Validate against the element type, so empty lists reject unknown fields as well
+		if err := validateFields(structColumns(reflect.New(elemType).Elem()), fields); err != nil {
+			return nil, err
+		}
+	}
+
+	records := make([]interface{}, 0, list.Len())
+	for i := 0; i < list.Len(); i++ {
+		element := list.Index(i)
+		for element.Kind() == reflect.Ptr || element.Kind() == reflect.Interface {
+			if element.IsNil() {
+				break
+			}
+			element = element.Elem()
+		}
+		if element.Kind() != reflect.Struct {
+			records = append(records, list.Index(i).Interface())
+			continue
+		}
+
+		record, err := selectResourceFields(element, fields)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+// selectResourceFields reduces a struct to the given fields
+func selectResourceFields(v reflect.Value, fields []string) (Record, error) {
+	columns := structColumns(v)
+	if err := validateFields(columns, fields); err != nil {
+		return Record{}, err
+	}
+
+	byName := make(map[string]reflect.Value, len(columns))
+	for _, column := range columns {
+		byName[column.name] = column.value
+	}
+
+	var record Record
+	for _, field := range fields {
+		record.set(field, byName[field].Interface())
+	}
+	if included, ok := byName[includedField]; ok && !included.IsZero() {
+		record.set(includedField, included.Interface())
+	}
+	return record, nil
+}
+
+// validateFields rejects field names the resource does not have
+func validateFields(columns []structColumn, fields []string) error {
+	names := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column.name != includedField {
+			names = append(names, column.name)
+		}
+	}
+	for _, field := range fields {
+		if !containsString(names, field) {
+			return errors.NewBadRequestError(fmt.Sprintf("Unknown field '%s' (available: %s)",
+				field, strings.Join(names, ", ")))
+		}
+	}
+	return nil
+}
+
+// structColumn is a struct field named by its json tag
+type structColumn struct {
+	name   string
+	goName string
+	value  reflect.Value
+}
+
+// structColumns returns the json tagged fields of a struct in order
+// Fields of embedded structs are promoted like encoding/json does.
+func structColumns(v reflect.Value) []structColumn {
+	t := v.Type()
+	columns := make([]structColumn, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		jsonTag := field.Tag.Get("json")
+
+		if field.Anonymous && jsonTag == "" {
+			embedded := v.Field(i)
+			if embedded.Kind() == reflect.Ptr {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				columns = append(columns, structColumns(embedded)...)
+			}
+			continue
+		}
+
+		if jsonTag == "" || jsonTag == "-" || !field.IsExported() {
+			continue
+		}
+
+		// Remove options like omitempty
+		name, _, _ := strings.Cut(jsonTag, ",")
+		columns = append(columns, structColumn{name: name, goName: field.Name, value: v.Field(i)})
+	}
+
+	return columns
+}
+
+// splitList splits a comma separated query parameter, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
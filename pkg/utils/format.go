@@ -0,0 +1,184 @@
+package utils
+
+import (
+	"fmt"
+	"mime"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"portal64api/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Format is a representation of a response, negotiated via ?format= or the Accept header
+type Format string
+
+// Formats of data responses
+const (
+	FormatJSON      Format = "json"
+	FormatCSV       Format = "csv"
+	FormatExcelCSV  Format = "csv-excel" // CSV with UTF-8 BOM, CRLF and decimal commas for German Excel
+	FormatNDJSON    Format = "ndjson"
+	FormatXLSX      Format = "xlsx"
+	FormatXML       Format = "xml"
+	FormatICalendar Format = "ics" // Tournament calendars only
+	FormatVCard     Format = "vcf" // Addresses only
+)
+
+// FormatKey is the gin context key of the format negotiated by the ResponseFormat middleware
+const FormatKey = "response_format"
+
+// formats lists the supported formats with their content types, in documentation order
+var formats = []struct {
+	format      Format
+	contentType string
+	mediaTypes  []string // Media types of the Accept header selecting the format
+}{
+	// Browsers prefer text/html and would otherwise get XML, which they rank above */*
+	{FormatJSON, "application/json; charset=utf-8", []string{"application/json", "text/html", "application/*", "*/*"}},
+	{FormatCSV, "text/csv", []string{"text/csv"}},
+	{FormatExcelCSV, "text/csv; charset=utf-8", nil},
+	{FormatNDJSON, "application/x-ndjson", []string{"application/x-ndjson", "application/ndjson", "application/jsonl"}},
+	{FormatXLSX, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+		[]string{"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"}},
+	{FormatXML, "application/xml; charset=utf-8", []string{"application/xml", "text/xml"}},
+	{FormatICalendar, "text/calendar; charset=utf-8", []string{"text/calendar"}},
+	{FormatVCard, "text/vcard; charset=utf-8", []string{"text/vcard"}},
+}
+
+// ContentType returns the Content-Type header of the format
+func (f Format) ContentType() string {
+	for _, entry := range formats {
+		if entry.format == f {
+			return entry.contentType
+		}
+	}
+	return FormatJSON.ContentType()
+}
+
+// IsCSV reports whether the format is one of the CSV dialects
+func (f Format) IsCSV() bool {
+	return f == FormatCSV || f == FormatExcelCSV
+}
+
+// DataFormats are the formats HandleResponse sends, supported by most data routes
+var DataFormats = []Format{FormatJSON, FormatCSV, FormatExcelCSV, FormatNDJSON, FormatXLSX, FormatXML}
+
+// NegotiateFormat returns the format requested via ?format=, or else the preferred format of the
+// Accept header, among the formats the route supports. Other ?format= values, known or not, are
+// rejected with a not acceptable error; an Accept header without supported media types falls back
+// to JSON, like a missing one. Without supported formats all formats are accepted.
+func NegotiateFormat(c *gin.Context, supported ...Format) (Format, error) {
+	if len(supported) == 0 {
+		supported = allFormats()
+	}
+
+	if value := strings.ToLower(strings.TrimSpace(c.Query("format"))); value != "" {
+		names := make([]string, 0, len(supported))
+		for _, format := range supported {
+			if string(format) == value {
+				return format, nil
+			}
+			names = append(names, string(format))
+		}
+		return FormatJSON, errors.New(errors.CodeNotAcceptable,
+			fmt.Sprintf("Unsupported format '%s' (supported: %s)", value, strings.Join(names, ", ")))
+	}
+
+	return acceptedFormat(c.GetHeader("Accept"), supported), nil
+}
+
+// RequestedFormat returns the format negotiated by the ResponseFormat middleware
+// Without the middleware the format is negotiated among all formats, unknown ones fall back to JSON.
+func RequestedFormat(c *gin.Context) Format {
+	if value, ok := c.Get(FormatKey); ok {
+		if format, ok := value.(Format); ok {
+			return format
+		}
+	}
+	format, _ := NegotiateFormat(c)
+	return format
+}
+
+// allFormats returns every format, in documentation order
+func allFormats() []Format {
+	all := make([]Format, len(formats))
+	for i, entry := range formats {
+		all[i] = entry.format
+	}
+	return all
+}
+
+// acceptedFormat returns the supported format the Accept header prefers
+// Media types are ranked by their q parameter; equally ranked ones keep the order of the header.
+func acceptedFormat(accept string, supported []Format) Format {
+	type acceptedType struct {
+		mediaType string
+		quality   float64
+	}
+
+	var accepted []acceptedType
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			accepted = append(accepted, acceptedType{mediaType, quality})
+		}
+	}
+	sort.SliceStable(accepted, func(i, j int) bool {
+		return accepted[i].quality > accepted[j].quality
+	})
+
+	for _, candidate := range accepted {
+		for _, entry := range formats {
+			if containsFormat(supported, entry.format) && containsString(entry.mediaTypes, candidate.mediaType) {
+				return entry.format
+			}
+		}
+	}
+	return FormatJSON
+}
+
+// containsFormat reports whether a format is in the list
+func containsFormat(list []Format, format Format) bool {
+	for _, entry := range list {
+		if entry == format {
+			return true
+		}
+	}
+	return false
+}
+
+// filenameWithExtension replaces the extension of a download filename, e.g. players.csv -> players.xlsx
+func filenameWithExtension(filename, extension string) string {
+	return strings.TrimSuffix(filename, path.Ext(filename)) + extension
+}
+
+// AcceptsGzip reports whether an Accept-Encoding header allows gzip
+func AcceptsGzip(acceptEncoding string) bool {
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if name, value, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(name) == "q" {
+			if q, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil && q == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
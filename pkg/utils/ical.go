@@ -0,0 +1,98 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ICalEvent represents a single all-day VEVENT of an iCalendar feed
+type ICalEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	URL         string
+	Start       time.Time
+	End         time.Time // Inclusive last day of the event
+}
+
+// WantsICalendar reports whether the client requested iCalendar output
+func WantsICalendar(c *gin.Context) bool {
+	return RequestedFormat(c) == FormatICalendar
+}
+
+// SendICalendarResponse sends events as an RFC 5545 iCalendar feed
+func SendICalendarResponse(c *gin.Context, filename, calendarName string, events []ICalEvent) {
+	c.Header("Content-Type", "text/calendar; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%s", filename))
+	c.String(http.StatusOK, BuildICalendar(calendarName, events, time.Now()))
+}
+
+// BuildICalendar renders events as an RFC 5545 iCalendar document
+func BuildICalendar(calendarName string, events []ICalEvent, stamp time.Time) string {
+	var b strings.Builder
+	dtStamp := stamp.UTC().Format("20060102T150405Z")
+
+	writeContentLine(&b, "BEGIN:VCALENDAR")
+	writeContentLine(&b, "VERSION:2.0")
+	writeContentLine(&b, "PRODID:-//Portal64//Portal64 API//DE")
+	writeContentLine(&b, "CALSCALE:GREGORIAN")
+	writeContentLine(&b, "METHOD:PUBLISH")
+	writeContentLine(&b, "X-WR-CALNAME:"+escapeContentText(calendarName))
+
+	for _, event := range events {
+		writeContentLine(&b, "BEGIN:VEVENT")
+		writeContentLine(&b, "UID:"+escapeContentText(event.UID))
+		writeContentLine(&b, "DTSTAMP:"+dtStamp)
+		writeContentLine(&b, "DTSTART;VALUE=DATE:"+event.Start.Format("20060102"))
+		// DTEND of all-day events is exclusive
+		writeContentLine(&b, "DTEND;VALUE=DATE:"+event.End.AddDate(0, 0, 1).Format("20060102"))
+		writeContentLine(&b, "SUMMARY:"+escapeContentText(event.Summary))
+		if event.Description != "" {
+			writeContentLine(&b, "DESCRIPTION:"+escapeContentText(event.Description))
+		}
+		if event.Location != "" {
+			writeContentLine(&b, "LOCATION:"+escapeContentText(event.Location))
+		}
+		if event.URL != "" {
+			writeContentLine(&b, "URL:"+event.URL)
+		}
+		writeContentLine(&b, "END:VEVENT")
+	}
+
+	writeContentLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// escapeContentText escapes TEXT property values (RFC 5545 section 3.3.11, RFC 6350 section 3.4)
+func escapeContentText(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, ";", "\\;")
+	value = strings.ReplaceAll(value, ",", "\\,")
+	value = strings.ReplaceAll(value, "\r\n", "\\n")
+	value = strings.ReplaceAll(value, "\n", "\\n")
+	return value
+}
+
+// writeContentLine writes an iCalendar or vCard content line folded at 75 octets and terminated by CRLF
+func writeContentLine(b *strings.Builder, line string) {
+	maxOctets := 75
+	for len(line) > maxOctets {
+		// Don't split multi-byte UTF-8 sequences
+		cut := maxOctets
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space which counts towards the limit
+		maxOctets = 74
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
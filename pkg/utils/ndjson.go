@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"portal64api/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SendNDJSONResponse sends the rows of data as newline delimited JSON, one object per line
+// The rows are those of the CSV response; the envelope of paginated responses (meta) is dropped.
+// Lines are streamed, the client receives data every StreamFlushItems rows.
+func SendNDJSONResponse(c *gin.Context, filename string, data interface{}) {
+	rows, err := tableRows(data)
+	if err != nil {
+		SendJSONResponse(c, http.StatusInternalServerError,
+			errors.NewInternalServerError("Failed to generate NDJSON: "+err.Error()))
+		return
+	}
+
+	c.Header("Content-Type", FormatNDJSON.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("inline; filename=%s", filename))
+	c.Status(http.StatusOK)
+
+	encoder := json.NewEncoder(c.Writer)
+	written := 0
+	err = eachTableRow(rows, func(i int, element reflect.Value) error {
+		if err := encoder.Encode(element.Interface()); err != nil {
+			return fmt.Errorf("failed to write row %d: %w", i, err)
+		}
+		if written++; written%StreamFlushItems == 0 {
+			c.Writer.Flush()
+		}
+		return nil
+	})
+	if err != nil {
+		// Headers are sent, the client sees a truncated stream
+		c.Error(err)
+	}
+	c.Writer.Flush()
+}
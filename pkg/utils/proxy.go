@@ -0,0 +1,11 @@
+package utils
+
+import "github.com/gin-gonic/gin"
+
+// FromTrustedProxy reports whether the request was forwarded by one of the trusted proxies
+// gin only takes the client IP from X-Forwarded-For for proxies set with SetTrustedProxies, so a
+// client IP other than the remote address means a trusted proxy forwarded the request. The
+// X-Forwarded-* headers of other requests are set by the client and must not be trusted.
+func FromTrustedProxy(c *gin.Context) bool {
+	return c.ClientIP() != c.RemoteIP()
+}
@@ -0,0 +1,153 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+
+	"portal64api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Responses whose data is an array of at least StreamMinItems elements are streamed element by
+// element instead of being encoded in memory; the client receives data every StreamFlushItems
+// elements.
+const (
+	StreamMinItems   = 500
+	StreamFlushItems = 200
+)
+
+// ShouldStreamJSON reports whether data is an array large enough to be streamed
+func ShouldStreamJSON(data interface{}) bool {
+	v, ok := streamableArray(data)
+	return ok && v.Len() >= StreamMinItems
+}
+
+// RowStream produces the elements of a streamed array, calling emit once per element
+// Streams fed by row-wise database reads never hold the whole array in memory.
+type RowStream func(emit func(element interface{}) error) error
+
+// StreamJSONResponse sends a successful response whose data is an array, encoding the elements
+// one at a time with encoding/json and flushing periodically
+// The output is identical to c.JSON with models.Response{Success: true, Data: data}.
+func StreamJSONResponse(c *gin.Context, statusCode int, data interface{}) {
+	v, ok := streamableArray(data)
+	if !ok {
+		c.JSON(statusCode, models.Response{Success: true, Data: data})
+		return
+	}
+
+	err := StreamJSONRows(c, statusCode, func(emit func(element interface{}) error) error {
+		for i := 0; i < v.Len(); i++ {
+			if err := emit(v.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.Error(err)
+	}
+}
+
+// StreamJSONRows sends a successful response whose data is the array produced by stream
+// Elements are encoded and written as they are emitted, the client receives data every
+// StreamFlushItems elements. Nothing is written before the first element, so an error the
+// stream returns before it is returned to the caller, who can still send an error response.
+// Later errors truncate the document and are only recorded with c.Error.
+func StreamJSONRows(c *gin.Context, statusCode int, stream RowStream) error {
+	// The envelope is encoded around a placeholder, so it follows models.Response exactly
+	envelope, err := json.Marshal(models.Response{Success: true, Data: json.RawMessage("[]")})
+	if err != nil {
+		return err
+	}
+	split := bytes.LastIndex(envelope, []byte("[]"))
+
+	writer := &jsonArrayWriter{c: c, statusCode: statusCode, prefix: envelope[:split]}
+	if err := stream(writer.writeElement); err != nil {
+		if !writer.started {
+			return err
+		}
+		// Headers are sent, the client sees a truncated document
+		c.Error(err)
+		c.Writer.Flush()
+		return nil
+	}
+
+	if err := writer.start(); err != nil {
+		c.Error(err)
+		return nil
+	}
+	if _, err := c.Writer.Write(append([]byte{']'}, envelope[split+2:]...)); err != nil {
+		c.Error(err)
+	}
+	c.Writer.Flush()
+	return nil
+}
+
+// jsonArrayWriter writes the elements of a streamed array, the response starts with the first one
+type jsonArrayWriter struct {
+	c          *gin.Context
+	statusCode int
+	prefix     []byte
+	started    bool
+	count      int
+}
+
+// start writes the headers and the envelope up to the array, once
+func (w *jsonArrayWriter) start() error {
+	if w.started {
+		return nil
+	}
+	w.started = true
+	w.c.Header("Content-Type", "application/json; charset=utf-8")
+	w.c.Status(w.statusCode)
+	_, err := w.c.Writer.Write(append(w.prefix, '['))
+	return err
+}
+
+// writeElement encodes and writes one element, flushing every StreamFlushItems elements
+func (w *jsonArrayWriter) writeElement(element interface{}) error {
+	encoded, err := json.Marshal(element)
+	if err != nil {
+		return err
+	}
+
+	if !w.started {
+		if err := w.start(); err != nil {
+			return err
+		}
+	} else if _, err := w.c.Writer.Write([]byte{','}); err != nil {
+		return err
+	}
+	if _, err := w.c.Writer.Write(encoded); err != nil {
+		return err
+	}
+
+	w.count++
+	if w.count%StreamFlushItems == 0 {
+		w.c.Writer.Flush()
+	}
+	return nil
+}
+
+// streamableArray returns the array or slice held by data, nil slices and []byte excluded
+// as encoding/json writes them as null and base64
+func streamableArray(data interface{}) (reflect.Value, bool) {
+	v := reflect.ValueOf(data)
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+
+	switch v.Kind() {
+	case reflect.Slice:
+		return v, !v.IsNil() && v.Type().Elem().Kind() != reflect.Uint8
+	case reflect.Array:
+		return v, v.Type().Elem().Kind() != reflect.Uint8
+	}
+	return v, false
+}
@@ -0,0 +1,136 @@
+package utils
+
+import (
+	"bufio"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"reflect"
+)
+
+// TableFileInfo describes a written table file
+type TableFileInfo struct {
+	Rows          int64
+	Size          int64  // Bytes of the gzip file
+	SHA256        string // Checksum of the gzip file
+	ContentSHA256 string // Checksum of the uncompressed content
+}
+
+// TableFile writes rows to a gzip compressed NDJSON or CSV file
+// Rows have the columns of the CSV response; CSV files use a semicolon separator and start with
+// a header row. Both the file and its uncompressed content are checksummed while writing.
+type TableFile struct {
+	path    string
+	file    *os.File
+	counter *countingWriter
+	fileSum hash.Hash
+	gz      *gzip.Writer
+	content hash.Hash
+	buf     *bufio.Writer
+	json    *json.Encoder
+	csv     *csv.Writer
+	rows    int64
+}
+
+// CreateTableFile creates a table file in the given format
+// sample is a row whose columns become the CSV header, e.g. the zero value of the row type.
+func CreateTableFile(path string, format Format, sample interface{}) (*TableFile, error) {
+	if format != FormatNDJSON && format != FormatCSV {
+		return nil, fmt.Errorf("unsupported table file format %s", format)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f := &TableFile{path: path, file: file, fileSum: sha256.New(), content: sha256.New()}
+	f.counter = &countingWriter{w: io.MultiWriter(file, f.fileSum)}
+	f.gz = gzip.NewWriter(f.counter)
+	f.buf = bufio.NewWriter(io.MultiWriter(f.gz, f.content))
+
+	if format == FormatNDJSON {
+		f.json = json.NewEncoder(f.buf)
+		return f, nil
+	}
+
+	f.csv = csv.NewWriter(f.buf)
+	f.csv.Comma = ';' // Same separator as CSV responses
+	if err := f.csv.Write(getCSVHeaders(tableRowValue(sample))); err != nil {
+		f.Abort()
+		return nil, fmt.Errorf("failed to write headers: %w", err)
+	}
+	return f, nil
+}
+
+// Write appends a row
+func (f *TableFile) Write(row interface{}) error {
+	f.rows++
+	if f.json != nil {
+		return f.json.Encode(row)
+	}
+	return f.csv.Write(getCSVRow(tableRowValue(row), formatCSVCell))
+}
+
+// Close completes the file and returns its description
+func (f *TableFile) Close() (TableFileInfo, error) {
+	if f.csv != nil {
+		f.csv.Flush()
+		if err := f.csv.Error(); err != nil {
+			f.Abort()
+			return TableFileInfo{}, err
+		}
+	}
+	if err := f.buf.Flush(); err != nil {
+		f.Abort()
+		return TableFileInfo{}, err
+	}
+	if err := f.gz.Close(); err != nil {
+		f.Abort()
+		return TableFileInfo{}, err
+	}
+	if err := f.file.Close(); err != nil {
+		os.Remove(f.path)
+		return TableFileInfo{}, err
+	}
+
+	return TableFileInfo{
+		Rows:          f.rows,
+		Size:          f.counter.n,
+		SHA256:        hex.EncodeToString(f.fileSum.Sum(nil)),
+		ContentSHA256: hex.EncodeToString(f.content.Sum(nil)),
+	}, nil
+}
+
+// Abort closes and removes an incomplete file
+func (f *TableFile) Abort() {
+	f.file.Close()
+	os.Remove(f.path)
+}
+
+// tableRowValue returns the struct of a row
+func tableRowValue(row interface{}) reflect.Value {
+	v := reflect.ValueOf(row)
+	for v.Kind() == reflect.Ptr && !v.IsNil() {
+		v = v.Elem()
+	}
+	return v
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"portal64api/internal/logging"
 	"portal64api/internal/models"
 	"portal64api/pkg/errors"
 
@@ -66,22 +67,102 @@ func ParseSearchParamsWithDefaults(c *gin.Context, defaultSortBy, defaultSortOrd
 	}, nil
 }
 
+// HasInclude reports whether an optional section was requested via ?include=a,b
+func HasInclude(c *gin.Context, section string) bool {
+	for _, include := range strings.Split(c.Query("include"), ",") {
+		if strings.EqualFold(strings.TrimSpace(include), section) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTournamentSearchFilter parses the optional tournament search filters from gin context
+func ParseTournamentSearchFilter(c *gin.Context) (models.TournamentSearchFilter, error) {
+	var filter models.TournamentSearchFilter
+
+	if orgStr := c.Query("organisation_id"); orgStr != "" {
+		orgID, err := strconv.ParseUint(orgStr, 10, 32)
+		if err != nil {
+			return filter, errors.NewBadRequestError("Invalid organisation_id parameter")
+		}
+		filter.OrganisationID = uint(orgID)
+	}
+
+	if region := strings.ToUpper(c.Query("region")); region != "" {
+		for _, ch := range region {
+			if (ch < 'A' || ch > 'Z') && (ch < '0' || ch > '9') {
+				return filter, errors.NewBadRequestError("Invalid region parameter (expected VKZ prefix like C03)")
+			}
+		}
+		filter.Region = region
+	}
+
+	filter.TypeAcronym = strings.ToUpper(c.Query("type"))
+
+	if roundsStr := c.Query("rounds"); roundsStr != "" {
+		rounds, err := strconv.Atoi(roundsStr)
+		if err != nil || rounds < 1 {
+			return filter, errors.NewBadRequestError("Invalid rounds parameter")
+		}
+		filter.Rounds = rounds
+	}
+
+	if computedStr := c.Query("computed"); computedStr != "" {
+		computed, err := strconv.ParseBool(computedStr)
+		if err != nil {
+			return filter, errors.NewBadRequestError("Invalid computed parameter (expected true or false)")
+		}
+		filter.Computed = &computed
+	}
+
+	if assessorStr := c.Query("assessor_id"); assessorStr != "" {
+		assessorID, err := strconv.ParseUint(assessorStr, 10, 32)
+		if err != nil {
+			return filter, errors.NewBadRequestError("Invalid assessor_id parameter")
+		}
+		filter.AssessorID = uint(assessorID)
+	}
+
+	if fromStr := c.Query("finished_from"); fromStr != "" {
+		from, err := time.Parse("2006-01-02", fromStr)
+		if err != nil {
+			return filter, errors.NewBadRequestError("Invalid finished_from format (expected YYYY-MM-DD)")
+		}
+		filter.FinishedFrom = &from
+	}
+
+	if toStr := c.Query("finished_to"); toStr != "" {
+		to, err := time.Parse("2006-01-02", toStr)
+		if err != nil {
+			return filter, errors.NewBadRequestError("Invalid finished_to format (expected YYYY-MM-DD)")
+		}
+		filter.FinishedTo = &to
+	}
+
+	if filter.FinishedFrom != nil && filter.FinishedTo != nil && filter.FinishedFrom.After(*filter.FinishedTo) {
+		return filter, errors.NewBadRequestError("finished_from must not be after finished_to")
+	}
+
+	return filter, nil
+}
+
 // ValidateClubID validates a club ID format (e.g., D300H, A080T, C0101, UNKNOWN)
 func ValidateClubID(clubID string) error {
 	if clubID == "" {
-		return errors.NewBadRequestError("Club ID cannot be empty")
+		return errors.New(errors.CodeInvalidClubID, "Club ID cannot be empty")
 	}
 
 	// Club ID should be alphanumeric and between 3-10 characters
 	if len(clubID) < 3 || len(clubID) > 10 {
-		return errors.NewBadRequestError("Invalid club ID format")
+		return errors.New(errors.CodeInvalidClubID, "Invalid club ID format")
 	}
 
 	// Check if all characters are alphanumeric
 	for i := 0; i < len(clubID); i++ {
 		c := clubID[i]
 		if !((c >= '0' && c <= '9') || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z')) {
-			return errors.NewBadRequestError("Invalid club ID format")
+			return errors.New(errors.CodeInvalidClubID, "Invalid club ID format")
 		}
 	}
 
@@ -91,22 +172,48 @@ func ValidateClubID(clubID string) error {
 // ValidatePlayerID validates a player ID format (e.g., D300H-1014, UNKNOWN-10849749)
 func ValidatePlayerID(playerID string) error {
 	if playerID == "" {
-		return errors.NewBadRequestError("Player ID cannot be empty")
+		return errors.New(errors.CodeInvalidPlayerID, "Player ID cannot be empty")
 	}
 
 	parts := strings.Split(playerID, "-")
 	if len(parts) != 2 {
-		return errors.NewBadRequestError("Invalid player ID format (expected: CLUBID-PERSONID)")
+		return errors.New(errors.CodeInvalidPlayerID, "Invalid player ID format (expected: CLUBID-PERSONID)")
 	}
 
 	// Validate club part
 	if err := ValidateClubID(parts[0]); err != nil {
-		return errors.NewBadRequestError("Invalid player ID format (expected: CLUBID-PERSONID)")
+		return errors.New(errors.CodeInvalidPlayerID, "Invalid player ID format (expected: CLUBID-PERSONID)")
 	}
 
 	// Validate person ID part
 	if _, err := strconv.ParseUint(parts[1], 10, 32); err != nil {
-		return errors.NewBadRequestError("Invalid player ID format (expected: CLUBID-PERSONID)")
+		return errors.New(errors.CodeInvalidPlayerID, "Invalid player ID format (expected: CLUBID-PERSONID)")
+	}
+
+	return nil
+}
+
+// ValidatePersonUUID validates a person UUID (e.g., 0b9f7c1e-5a1d-4c7e-9a55-3f1f2c6d8e01)
+func ValidatePersonUUID(uuid string) error {
+	if uuid == "" {
+		return errors.New(errors.CodeInvalidPersonUUID, "Person UUID cannot be empty")
+	}
+
+	if len(uuid) != 36 {
+		return errors.New(errors.CodeInvalidPersonUUID, "Invalid person UUID format")
+	}
+
+	for i := 0; i < len(uuid); i++ {
+		c := uuid[i]
+		if i == 8 || i == 13 || i == 18 || i == 23 {
+			if c != '-' {
+				return errors.New(errors.CodeInvalidPersonUUID, "Invalid person UUID format")
+			}
+			continue
+		}
+		if !((c >= '0' && c <= '9') || (c >= 'a' && c <= 'f') || (c >= 'A' && c <= 'F')) {
+			return errors.New(errors.CodeInvalidPersonUUID, "Invalid person UUID format")
+		}
 	}
 
 	return nil
@@ -116,25 +223,25 @@ func ValidatePlayerID(playerID string) error {
 // Supports multiple formats: B718-A08-BEL, C529-K00-HT1, T117893
 func ValidateTournamentID(tournamentID string) error {
 	if tournamentID == "" {
-		return errors.NewBadRequestError("Tournament ID cannot be empty")
+		return errors.New(errors.CodeInvalidTournamentID, "Tournament ID cannot be empty")
 	}
 
 	// First character must be a letter A-Z
 	if len(tournamentID) < 1 || tournamentID[0] < 'A' || tournamentID[0] > 'Z' {
-		return errors.NewBadRequestError("Invalid tournament ID format (expected: B718-A08-BEL, C529-K00-HT1, or T117893)")
+		return errors.New(errors.CodeInvalidTournamentID, "Invalid tournament ID format (expected: B718-A08-BEL, C529-K00-HT1, or T117893)")
 	}
 
 	// Handle "T" format tournaments (e.g., T117893)
 	if tournamentID[0] == 'T' {
 		// T format: T followed by digits
 		if len(tournamentID) < 2 {
-			return errors.NewBadRequestError("Invalid tournament ID format (expected: B718-A08-BEL, C529-K00-HT1, or T117893)")
+			return errors.New(errors.CodeInvalidTournamentID, "Invalid tournament ID format (expected: B718-A08-BEL, C529-K00-HT1, or T117893)")
 		}
 
 		// Rest should be digits
 		for j := 1; j < len(tournamentID); j++ {
 			if tournamentID[j] < '0' || tournamentID[j] > '9' {
-				return errors.NewBadRequestError("Invalid tournament ID format (expected: B718-A08-BEL, C529-K00-HT1, or T117893)")
+				return errors.New(errors.CodeInvalidTournamentID, "Invalid tournament ID format (expected: B718-A08-BEL, C529-K00-HT1, or T117893)")
 			}
 		}
 
@@ -144,31 +251,80 @@ func ValidateTournamentID(tournamentID string) error {
 	// Handle traditional format tournaments (e.g., B718-A08-BEL, C529-K00-HT1)
 	parts := strings.Split(tournamentID, "-")
 	if len(parts) != 3 {
-		return errors.NewBadRequestError("Invalid tournament ID format (expected: B718-A08-BEL, C529-K00-HT1, or T117893)")
+		return errors.New(errors.CodeInvalidTournamentID, "Invalid tournament ID format (expected: B718-A08-BEL, C529-K00-HT1, or T117893)")
 	}
 
 	// First part: should start with a letter (A-Z) followed by digits
 	// Letter represents decade: A=2000-2009, B=2010-2019, C=2020-2029, etc.
 	if len(parts[0]) < 2 {
-		return errors.NewBadRequestError("Invalid tournament ID format (expected: B718-A08-BEL, C529-K00-HT1, or T117893)")
+		return errors.New(errors.CodeInvalidTournamentID, "Invalid tournament ID format (expected: B718-A08-BEL, C529-K00-HT1, or T117893)")
 	}
 
 	// Rest of first part should be digits (year digit + week number)
 	for j := 1; j < len(parts[0]); j++ {
 		if parts[0][j] < '0' || parts[0][j] > '9' {
-			return errors.NewBadRequestError("Invalid tournament ID format (expected: B718-A08-BEL, C529-K00-HT1, or T117893)")
+			return errors.New(errors.CodeInvalidTournamentID, "Invalid tournament ID format (expected: B718-A08-BEL, C529-K00-HT1, or T117893)")
 		}
 	}
 
 	// Validate other parts have some basic structure
 	for i := 1; i < len(parts); i++ {
 		if len(parts[i]) < 1 {
-			return errors.NewBadRequestError("Invalid tournament ID format (expected: B718-A08-BEL, C529-K00-HT1, or T117893)")
+			return errors.New(errors.CodeInvalidTournamentID, "Invalid tournament ID format (expected: B718-A08-BEL, C529-K00-HT1, or T117893)")
 		}
 	}
 
 	return nil
 }
+
+// DecodeTournamentCode decodes the date and classification fields of a tournament code
+// Codes like C529-K00-HT1 encode decade letter (A=2000-2009, B=2010-2019, C=2020-2029),
+// year digit and week number in the first part, followed by sub-code and type acronym.
+// "T" codes (e.g. T117893) are sequential numbers without encoded date and return an error.
+func DecodeTournamentCode(code string) (*models.TournamentCodeInfo, error) {
+	if err := ValidateTournamentID(code); err != nil {
+		return nil, err
+	}
+	if code[0] == 'T' {
+		return nil, fmt.Errorf("tournament code %s does not encode a date", code)
+	}
+
+	parts := strings.Split(code, "-")
+	datePart := parts[0]
+	if len(datePart) != 4 {
+		return nil, fmt.Errorf("tournament code %s has no year/week part", code)
+	}
+
+	year := 2000 + int(datePart[0]-'A')*10 + int(datePart[1]-'0')
+	week, _ := strconv.Atoi(datePart[2:4])
+	if week < 1 || week > 53 {
+		return nil, fmt.Errorf("tournament code %s has invalid week %d", code, week)
+	}
+
+	startDate := isoWeekStart(year, week)
+
+	return &models.TournamentCodeInfo{
+		Year:            year,
+		Week:            week,
+		ApproxStartDate: &startDate,
+		SubCode:         parts[1],
+		TypeAcronym:     parts[2],
+	}, nil
+}
+
+// isoWeekStart returns the Monday of the given ISO week
+func isoWeekStart(year, week int) time.Time {
+	// January 4th is always in ISO week 1
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, time.UTC)
+	weekday := int(jan4.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	week1Monday := jan4.AddDate(0, 0, 1-weekday)
+	return week1Monday.AddDate(0, 0, (week-1)*7)
+}
+
+// GeneratePlayerID generates a player ID from VKZ and membership number
 func GeneratePlayerID(vkz string, spielernummer uint) string {
 	return fmt.Sprintf("%s-%03d", vkz, spielernummer)
 }
@@ -189,22 +345,50 @@ func ParsePlayerID(playerID string) (string, uint, error) {
 }
 
 // SendJSONResponse sends a JSON response
+// Errors (status 400 and above) are sent as problem details, see SendProblemResponse.
 func SendJSONResponse(c *gin.Context, statusCode int, data interface{}) {
+	if statusCode >= 400 {
+		SendProblemResponse(c, statusCode, data)
+		return
+	}
+
+	if ShouldStreamJSON(data) {
+		StreamJSONResponse(c, statusCode, data)
+		return
+	}
+
 	response := models.Response{
-		Success: statusCode < 400,
+		Success: true,
 		Data:    data,
 	}
+	c.JSON(statusCode, response)
+}
 
-	if statusCode >= 400 {
-		if err, ok := data.(errors.APIError); ok {
-			response.Error = err.Message
-		} else {
-			response.Error = fmt.Sprintf("%v", data)
+// SendProblemResponse sends an error as application/problem+json (RFC 7807)
+// Errors other than errors.APIError get the generic error code of the status.
+func SendProblemResponse(c *gin.Context, statusCode int, data interface{}) {
+	apiErr, ok := data.(errors.APIError)
+	if !ok {
+		apiErr = errors.NewAPIError(statusCode, fmt.Sprintf("%v", data))
+	}
+
+	var instance string
+	if c.Request != nil {
+		instance = c.Request.URL.Path
+	}
+	problem := errors.NewProblem(apiErr, statusCode, instance)
+	if c.Request != nil {
+		problem.RequestID = logging.RequestID(c.Request.Context())
+		if statusCode >= http.StatusInternalServerError && apiErr.Details != "" {
+			logging.FromContext(c.Request.Context()).
+				WithField("error_code", string(problem.ErrorCode)).
+				WithField("details", apiErr.Details).
+				Error(apiErr.Message)
 		}
-		response.Data = nil
 	}
 
-	c.JSON(statusCode, response)
+	c.Header("Content-Type", errors.ProblemContentType)
+	c.JSON(statusCode, problem)
 }
 
 // validateCSVData validates data before setting CSV headers
@@ -221,6 +405,8 @@ func validateCSVData(data interface{}) error {
 		v = v.Elem()
 	}
 
+	v = unwrapRecordData(v)
+
 	// Handle wrapped response structures that contain Data field
 	if v.Kind() == reflect.Struct {
 		// Check if it has a Data field (for paginated responses)
@@ -243,7 +429,24 @@ func validateCSVData(data interface{}) error {
 	return fmt.Errorf("data type %v is not suitable for CSV generation", v.Kind())
 }
 
+// unwrapRecordData returns the data list of a paginated response reduced to selected fields
+func unwrapRecordData(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Struct || !v.CanInterface() {
+		return v
+	}
+	if record, ok := v.Interface().(Record); ok {
+		if data, ok := record.Get("data"); ok {
+			if list := reflect.ValueOf(data); list.Kind() == reflect.Slice {
+				return list
+			}
+		}
+	}
+	return v
+}
+
 // SendCSVResponse sends a CSV response
+// The Excel dialect (?format=csv-excel) starts with a UTF-8 byte order mark, ends lines with CRLF
+// and writes decimal commas, so German Excel opens the file without the import wizard.
 func SendCSVResponse(c *gin.Context, filename string, data interface{}) {
 	// Validate data first before setting headers
 	if err := validateCSVData(data); err != nil {
@@ -252,15 +455,27 @@ func SendCSVResponse(c *gin.Context, filename string, data interface{}) {
 		return
 	}
 
-	c.Header("Content-Type", "text/csv")
+	format := FormatCSV
+	formatCell := formatCSVCell
+	if RequestedFormat(c) == FormatExcelCSV {
+		format = FormatExcelCSV
+		formatCell = formatExcelCSVCell
+	}
+
+	c.Header("Content-Type", format.ContentType())
 	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
 
+	if format == FormatExcelCSV {
+		c.Writer.WriteString("\uFEFF")
+	}
+
 	writer := csv.NewWriter(c.Writer)
 	writer.Comma = ';' // Use semicolon separator for German Excel compatibility
+	writer.UseCRLF = format == FormatExcelCSV
 	defer writer.Flush()
 
 	// Convert data to CSV
-	if err := writeCSV(writer, data); err != nil {
+	if err := writeCSV(writer, data, formatCell); err != nil {
 		// Can't change headers now, just log the error
 		c.Writer.WriteString("Error generating CSV: " + err.Error())
 		return
@@ -268,19 +483,48 @@ func SendCSVResponse(c *gin.Context, filename string, data interface{}) {
 }
 
 // writeCSV writes data to CSV writer using reflection
-func writeCSV(writer *csv.Writer, data interface{}) error {
+func writeCSV(writer *csv.Writer, data interface{}, formatCell func(reflect.Value) string) error {
+	rows, err := tableRows(data)
+	if err != nil {
+		return err
+	}
+
+	headers, err := tableHeaders(rows)
+	if err != nil || headers == nil {
+		return err
+	}
+
+	if err := writer.Write(headers); err != nil {
+		return fmt.Errorf("failed to write headers: %w", err)
+	}
+
+	// Write data rows
+	return eachTableRow(rows, func(i int, element reflect.Value) error {
+		if err := writer.Write(getCSVRow(element, formatCell)); err != nil {
+			return fmt.Errorf("failed to write row %d: %w", i, err)
+		}
+		return nil
+	})
+}
+
+// tableRows returns the rows of data written as CSV, NDJSON or spreadsheet
+// These are the elements of a list or of the data list of a paginated response; a single
+// resource is one row.
+func tableRows(data interface{}) (reflect.Value, error) {
 	if data == nil {
-		return fmt.Errorf("data cannot be nil")
+		return reflect.Value{}, fmt.Errorf("data cannot be nil")
 	}
 
 	v := reflect.ValueOf(data)
 	if v.Kind() == reflect.Ptr {
 		if v.IsNil() {
-			return fmt.Errorf("data pointer is nil")
+			return reflect.Value{}, fmt.Errorf("data pointer is nil")
 		}
 		v = v.Elem()
 	}
 
+	v = unwrapRecordData(v)
+
 	// Handle wrapped response structures that contain Data field
 	if v.Kind() == reflect.Struct {
 		// Check if it has a Data field (for paginated responses)
@@ -297,30 +541,29 @@ func writeCSV(writer *csv.Writer, data interface{}) error {
 	}
 
 	if v.Kind() != reflect.Slice {
-		return fmt.Errorf("data must be a slice or struct with Data slice field")
-	}
-
-	if v.Len() == 0 {
-		// For empty data, write a header-only CSV if we can determine the structure
-		// Try to create a sample struct to get headers
-		sliceType := v.Type()
-		if sliceType.Kind() == reflect.Slice {
-			elemType := sliceType.Elem()
-			if elemType.Kind() == reflect.Struct {
-				// Create a zero value to get headers
-				zeroValue := reflect.Zero(elemType)
-				headers := getCSVHeaders(zeroValue)
-				if len(headers) > 0 {
-					return writer.Write(headers)
-				}
+		return reflect.Value{}, fmt.Errorf("data must be a slice or struct with Data slice field")
+	}
+	return v, nil
+}
+
+// tableHeaders returns the column names of the rows, nil if they cannot be determined
+// All formats use these columns in this order: json names in struct order, or the selected fields.
+func tableHeaders(rows reflect.Value) ([]string, error) {
+	if rows.Len() == 0 {
+		// For empty data, write a header-only table if we can determine the structure
+		elemType := rows.Type().Elem()
+		if elemType.Kind() == reflect.Struct {
+			// Create a zero value to get headers
+			if headers := getCSVHeaders(reflect.Zero(elemType)); len(headers) > 0 {
+				return headers, nil
 			}
 		}
 		// If we can't determine headers, just return with no content
-		return nil
+		return nil, nil
 	}
 
 	// Get headers from first element
-	firstElement := v.Index(0)
+	firstElement := rows.Index(0)
 
 	// Handle interface{} elements - extract underlying value
 	if firstElement.Kind() == reflect.Interface && !firstElement.IsNil() {
@@ -329,27 +572,26 @@ func writeCSV(writer *csv.Writer, data interface{}) error {
 
 	if firstElement.Kind() == reflect.Ptr {
 		if firstElement.IsNil() {
-			return fmt.Errorf("first element is nil")
+			return nil, fmt.Errorf("first element is nil")
 		}
 		firstElement = firstElement.Elem()
 	}
 
 	if firstElement.Kind() != reflect.Struct {
-		return fmt.Errorf("slice elements must be structs, got %v", firstElement.Kind())
+		return nil, fmt.Errorf("slice elements must be structs, got %v", firstElement.Kind())
 	}
 
 	headers := getCSVHeaders(firstElement)
 	if len(headers) == 0 {
-		return fmt.Errorf("no valid fields found for CSV headers")
-	}
-
-	if err := writer.Write(headers); err != nil {
-		return fmt.Errorf("failed to write headers: %w", err)
+		return nil, fmt.Errorf("no valid fields found for CSV headers")
 	}
+	return headers, nil
+}
 
-	// Write data rows
-	for i := 0; i < v.Len(); i++ {
-		element := v.Index(i)
+// eachTableRow calls fn with the struct of each row, skipping nil and non-struct elements
+func eachTableRow(rows reflect.Value, fn func(i int, element reflect.Value) error) error {
+	for i := 0; i < rows.Len(); i++ {
+		element := rows.Index(i)
 
 		// Handle interface{} elements - extract underlying value
 		if element.Kind() == reflect.Interface && !element.IsNil() {
@@ -367,9 +609,8 @@ func writeCSV(writer *csv.Writer, data interface{}) error {
 			continue // Skip non-struct elements
 		}
 
-		row := getCSVRow(element)
-		if err := writer.Write(row); err != nil {
-			return fmt.Errorf("failed to write row %d: %w", i, err)
+		if err := fn(i, element); err != nil {
+			return err
 		}
 	}
 
@@ -377,128 +618,168 @@ func writeCSV(writer *csv.Writer, data interface{}) error {
 }
 
 // getCSVHeaders extracts headers from struct using json tags
+// Records of selected fields use their field names.
 func getCSVHeaders(v reflect.Value) []string {
-	t := v.Type()
-	headers := make([]string, 0, t.NumField())
-
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == "" || jsonTag == "-" {
-			continue
-		}
-
-		// Remove options like omitempty
-		if idx := strings.Index(jsonTag, ","); idx != -1 {
-			jsonTag = jsonTag[:idx]
-		}
+	if record, ok := v.Interface().(Record); ok {
+		return record.Keys()
+	}
 
-		headers = append(headers, jsonTag)
+	columns := structColumns(v)
+	headers := make([]string, 0, len(columns))
+	for _, column := range columns {
+		headers = append(headers, column.name)
 	}
 
 	return headers
 }
 
 // getCSVRow extracts row data from struct
-func getCSVRow(v reflect.Value) []string {
-	t := v.Type()
-	row := make([]string, 0, t.NumField())
-
-	for i := 0; i < t.NumField(); i++ {
-		field := t.Field(i)
-		jsonTag := field.Tag.Get("json")
-		if jsonTag == "" || jsonTag == "-" {
-			continue
+func getCSVRow(v reflect.Value, formatCell func(reflect.Value) string) []string {
+	values := getRowValues(v)
+	row := make([]string, 0, len(values))
+	for _, value := range values {
+		row = append(row, formatCell(value))
+	}
+
+	return row
+}
+
+// getRowValues returns the values of the columns of a row, see getCSVHeaders
+func getRowValues(v reflect.Value) []reflect.Value {
+	if record, ok := v.Interface().(Record); ok {
+		values := make([]reflect.Value, 0, len(record.Keys()))
+		for _, key := range record.Keys() {
+			value, _ := record.Get(key)
+			values = append(values, reflect.ValueOf(value))
 		}
+		return values
+	}
 
-		// Remove options like omitempty
-		if idx := strings.Index(jsonTag, ","); idx != -1 {
-			jsonTag = jsonTag[:idx]
-		}
-
-		fieldValue := v.Field(i)
-		var cellValue string
-
-		// Handle different field types more robustly
-		switch fieldValue.Kind() {
-		case reflect.String:
-			cellValue = fieldValue.String()
-		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-			cellValue = strconv.FormatInt(fieldValue.Int(), 10)
-		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-			cellValue = strconv.FormatUint(fieldValue.Uint(), 10)
-		case reflect.Float32, reflect.Float64:
-			cellValue = strconv.FormatFloat(fieldValue.Float(), 'f', -1, 64)
-		case reflect.Bool:
-			cellValue = strconv.FormatBool(fieldValue.Bool())
-		case reflect.Ptr:
-			if fieldValue.IsNil() {
-				cellValue = ""
-			} else {
-				// Handle pointer types
-				elem := fieldValue.Elem()
-				switch elem.Kind() {
-				case reflect.Struct:
-					// Special handling for time.Time
-					if elem.Type().String() == "time.Time" {
-						if t, ok := fieldValue.Interface().(*time.Time); ok {
-							cellValue = t.Format("2006-01-02 15:04:05")
-						} else {
-							cellValue = ""
-						}
+	columns := structColumns(v)
+	values := make([]reflect.Value, 0, len(columns))
+	for _, column := range columns {
+		values = append(values, column.value)
+	}
+
+	return values
+}
+
+// formatCSVCell formats a field value as CSV cell
+func formatCSVCell(fieldValue reflect.Value) string {
+	if !fieldValue.IsValid() {
+		return ""
+	}
+
+	var cellValue string
+
+	// Handle different field types more robustly
+	switch fieldValue.Kind() {
+	case reflect.String:
+		cellValue = fieldValue.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		cellValue = strconv.FormatInt(fieldValue.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		cellValue = strconv.FormatUint(fieldValue.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		cellValue = strconv.FormatFloat(fieldValue.Float(), 'f', -1, 64)
+	case reflect.Bool:
+		cellValue = strconv.FormatBool(fieldValue.Bool())
+	case reflect.Ptr:
+		if fieldValue.IsNil() {
+			cellValue = ""
+		} else {
+			// Handle pointer types
+			elem := fieldValue.Elem()
+			switch elem.Kind() {
+			case reflect.Struct:
+				// Special handling for time.Time
+				if elem.Type().String() == "time.Time" {
+					if t, ok := fieldValue.Interface().(*time.Time); ok {
+						cellValue = t.Format("2006-01-02 15:04:05")
 					} else {
-						// For other structs, try to marshal to JSON
-						if jsonBytes, err := json.Marshal(fieldValue.Interface()); err == nil {
-							cellValue = string(jsonBytes)
-						} else {
-							cellValue = fmt.Sprintf("%v", fieldValue.Interface())
-						}
+						cellValue = ""
 					}
-				default:
-					cellValue = fmt.Sprintf("%v", elem.Interface())
-				}
-			}
-		case reflect.Struct:
-			// Special handling for time.Time
-			if fieldValue.Type().String() == "time.Time" {
-				if t, ok := fieldValue.Interface().(time.Time); ok {
-					cellValue = t.Format("2006-01-02 15:04:05")
 				} else {
-					cellValue = ""
+					// For other structs, try to marshal to JSON
+					if jsonBytes, err := json.Marshal(fieldValue.Interface()); err == nil {
+						cellValue = string(jsonBytes)
+					} else {
+						cellValue = fmt.Sprintf("%v", fieldValue.Interface())
+					}
 				}
+			default:
+				cellValue = fmt.Sprintf("%v", elem.Interface())
+			}
+		}
+	case reflect.Struct:
+		// Special handling for time.Time
+		if fieldValue.Type().String() == "time.Time" {
+			if t, ok := fieldValue.Interface().(time.Time); ok {
+				cellValue = t.Format("2006-01-02 15:04:05")
 			} else {
-				// For other structs, try to marshal to JSON
-				if jsonBytes, err := json.Marshal(fieldValue.Interface()); err == nil {
-					cellValue = string(jsonBytes)
-				} else {
-					cellValue = fmt.Sprintf("%v", fieldValue.Interface())
-				}
+				cellValue = ""
 			}
-		case reflect.Slice, reflect.Array:
-			// Handle slices/arrays by marshaling to JSON
+		} else {
+			// For other structs, try to marshal to JSON
 			if jsonBytes, err := json.Marshal(fieldValue.Interface()); err == nil {
 				cellValue = string(jsonBytes)
 			} else {
 				cellValue = fmt.Sprintf("%v", fieldValue.Interface())
 			}
-		default:
+		}
+	case reflect.Slice, reflect.Array:
+		// Handle slices/arrays by marshaling to JSON
+		if jsonBytes, err := json.Marshal(fieldValue.Interface()); err == nil {
+			cellValue = string(jsonBytes)
+		} else {
 			cellValue = fmt.Sprintf("%v", fieldValue.Interface())
 		}
-
-		row = append(row, cellValue)
+	default:
+		cellValue = fmt.Sprintf("%v", fieldValue.Interface())
 	}
 
-	return row
+	return cellValue
+}
+
+// formatExcelCSVCell formats a field value as cell of the Excel CSV dialect, with decimal commas
+func formatExcelCSVCell(fieldValue reflect.Value) string {
+	value := fieldValue
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return ""
+		}
+		value = value.Elem()
+	}
+	if value.Kind() == reflect.Float32 || value.Kind() == reflect.Float64 {
+		return strings.Replace(strconv.FormatFloat(value.Float(), 'f', -1, 64), ".", ",", 1)
+	}
+	return formatCSVCell(fieldValue)
 }
 
-// HandleResponse handles both JSON and CSV responses based on Accept header
+// HandleResponse sends data in the format negotiated via ?format= or the Accept header
+// JSON, CSV, NDJSON, XLSX and XML are supported, see NegotiateFormat. Resources are reduced to
+// the fields requested via ?fields=a,b in all formats.
 func HandleResponse(c *gin.Context, data interface{}, filename string) {
-	accept := c.GetHeader("Accept")
-	format := c.Query("format")
+	data, err := SelectFields(data, ParseFields(c))
+	if err != nil {
+		if apiErr, ok := err.(errors.APIError); ok {
+			SendJSONResponse(c, apiErr.Code, apiErr)
+			return
+		}
+		SendJSONResponse(c, http.StatusBadRequest, errors.NewBadRequestError(err.Error()))
+		return
+	}
 
-	if format == "csv" || strings.Contains(accept, "text/csv") {
+	switch format := RequestedFormat(c); {
+	case format.IsCSV():
 		SendCSVResponse(c, filename, data)
-	} else {
+	case format == FormatNDJSON:
+		SendNDJSONResponse(c, filenameWithExtension(filename, ".ndjson"), data)
+	case format == FormatXLSX:
+		SendXLSXResponse(c, filenameWithExtension(filename, ".xlsx"), data)
+	case format == FormatXML:
+		SendXMLResponse(c, http.StatusOK, data)
+	default:
 		SendJSONResponse(c, http.StatusOK, data)
 	}
 }
@@ -533,3 +814,19 @@ func MapGeschlechtToGender(geschlecht int) string {
 		return "m" // default to man as most chess players are men
 	}
 }
+
+// umlautFolder maps German umlauts and sharp s to their two-letter transcription
+var umlautFolder = strings.NewReplacer("ä", "ae", "ö", "oe", "ü", "ue", "ß", "ss")
+
+// FoldUmlauts lowercases a string and transcribes umlauts, so "Müller" and "Mueller" compare equal
+func FoldUmlauts(value string) string {
+	return umlautFolder.Replace(strings.ToLower(strings.TrimSpace(value)))
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// EscapeLike escapes the LIKE wildcards in user input, so "%" and "_" match literally
+// Patterns built from it have to be compared with LIKE ? ESCAPE '\\'.
+func EscapeLike(value string) string {
+	return likeEscaper.Replace(value)
+}
@@ -0,0 +1,147 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// VCard represents a single vCard 4.0 contact
+type VCard struct {
+	UID          string
+	Kind         string // "individual" or "org"
+	FullName     string
+	FamilyName   string
+	GivenName    string
+	Organization string
+	Title        string // Function held by the official
+	Street       string
+	Extended     string // Address addition, e.g. "c/o"
+	PostalCode   string
+	Locality     string
+	Country      string
+	Phones       []string
+	Faxes        []string
+	Emails       []string
+	URLs         []string
+	Latitude     string
+	Longitude    string
+	Note         string
+	Categories   []string
+}
+
+// WantsVCard reports whether the client requested vCard output
+func WantsVCard(c *gin.Context) bool {
+	return RequestedFormat(c) == FormatVCard
+}
+
+// SendVCardResponse sends contacts as an RFC 6350 vCard file
+func SendVCardResponse(c *gin.Context, filename string, cards []VCard) {
+	c.Header("Content-Type", "text/vcard; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.String(http.StatusOK, BuildVCards(cards))
+}
+
+// BuildVCards renders contacts as consecutive vCard 4.0 objects
+func BuildVCards(cards []VCard) string {
+	var b strings.Builder
+	for _, card := range cards {
+		writeVCard(&b, card)
+	}
+	return b.String()
+}
+
+// writeVCard writes a single BEGIN:VCARD ... END:VCARD block
+func writeVCard(b *strings.Builder, card VCard) {
+	writeContentLine(b, "BEGIN:VCARD")
+	writeContentLine(b, "VERSION:4.0")
+	if card.Kind != "" {
+		writeContentLine(b, "KIND:"+card.Kind)
+	}
+	// FN is the only mandatory property
+	writeContentLine(b, "FN:"+escapeContentText(card.FullName))
+	if card.FamilyName != "" || card.GivenName != "" {
+		writeContentLine(b, "N:"+joinComponents(card.FamilyName, card.GivenName, "", "", ""))
+	}
+	if card.Organization != "" {
+		writeContentLine(b, "ORG:"+escapeContentText(card.Organization))
+	}
+	if card.Title != "" {
+		writeContentLine(b, "TITLE:"+escapeContentText(card.Title))
+	}
+	if card.Street != "" || card.Extended != "" || card.PostalCode != "" || card.Locality != "" || card.Country != "" {
+		writeContentLine(b, "ADR;TYPE=work:"+joinComponents("", card.Extended, card.Street, card.Locality, "", card.PostalCode, card.Country))
+	}
+	for _, phone := range card.Phones {
+		writeContentLine(b, "TEL;TYPE=work,voice;VALUE=uri:tel:"+telURI(phone))
+	}
+	for _, fax := range card.Faxes {
+		writeContentLine(b, "TEL;TYPE=work,fax;VALUE=uri:tel:"+telURI(fax))
+	}
+	for i, email := range card.Emails {
+		if i == 0 {
+			writeContentLine(b, "EMAIL;TYPE=work;PREF=1:"+escapeContentText(email))
+		} else {
+			writeContentLine(b, "EMAIL;TYPE=work:"+escapeContentText(email))
+		}
+	}
+	for _, url := range card.URLs {
+		writeContentLine(b, "URL:"+url)
+	}
+	if card.Latitude != "" && card.Longitude != "" {
+		writeContentLine(b, fmt.Sprintf("GEO:geo:%s,%s", card.Latitude, card.Longitude))
+	}
+	if len(card.Categories) > 0 {
+		categories := make([]string, len(card.Categories))
+		for i, category := range card.Categories {
+			categories[i] = escapeContentText(category)
+		}
+		writeContentLine(b, "CATEGORIES:"+strings.Join(categories, ","))
+	}
+	if card.Note != "" {
+		writeContentLine(b, "NOTE:"+escapeContentText(card.Note))
+	}
+	if card.UID != "" {
+		writeContentLine(b, "UID:"+card.UID)
+	}
+	writeContentLine(b, "END:VCARD")
+}
+
+// joinComponents joins the escaped components of a structured property (N, ADR)
+func joinComponents(components ...string) string {
+	escaped := make([]string, len(components))
+	for i, component := range components {
+		escaped[i] = escapeContentText(component)
+	}
+	return strings.Join(escaped, ";")
+}
+
+// telURI converts a free-form phone number to a tel URI (RFC 3966) value
+// Keeps digits and a leading plus, visual separators become hyphens
+func telURI(phone string) string {
+	phone = strings.TrimSpace(phone)
+	if strings.HasPrefix(phone, "+") {
+		// Drop the national trunk prefix of international numbers, e.g. "+49 (0)731"
+		phone = strings.Replace(phone, "(0)", "", 1)
+	}
+
+	var b strings.Builder
+	digits, separator := false, false
+	for i, r := range phone {
+		switch {
+		case r >= '0' && r <= '9':
+			if separator && digits {
+				b.WriteByte('-')
+			}
+			digits, separator = true, false
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		default:
+			separator = true
+		}
+	}
+	return b.String()
+}
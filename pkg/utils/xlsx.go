@@ -0,0 +1,210 @@
+package utils
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"portal64api/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"github.com/xuri/excelize/v2"
+)
+
+// Built-in Excel number formats of date cells
+const (
+	xlsxDateFormat     = 14 // Localized short date, e.g. 20.07.2025 in German Excel
+	xlsxDateTimeFormat = 22 // Localized short date and time
+)
+
+// maxSheetNameLength is the longest sheet name Excel accepts
+const maxSheetNameLength = 31
+
+// SendXLSXResponse sends the rows of data as Excel workbook with one sheet
+// The sheet has the columns of the CSV response below a bold, frozen header row. Numbers and
+// dates are written as typed cells, so they can be summed and sorted without conversion.
+func SendXLSXResponse(c *gin.Context, filename string, data interface{}) {
+	rows, err := tableRows(data)
+	var file *excelize.File
+	if err == nil {
+		file, err = buildXLSX(sheetName(filename), rows)
+	}
+	if err != nil {
+		SendJSONResponse(c, http.StatusInternalServerError,
+			errors.NewInternalServerError("Failed to generate XLSX: "+err.Error()))
+		return
+	}
+	defer file.Close()
+
+	c.Header("Content-Type", FormatXLSX.ContentType())
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%s", filename))
+	c.Status(http.StatusOK)
+	if err := file.Write(c.Writer); err != nil {
+		// Headers are sent, the client receives a broken workbook
+		c.Error(err)
+	}
+}
+
+// buildXLSX writes the rows to a workbook with a single sheet
+func buildXLSX(sheet string, rows reflect.Value) (*excelize.File, error) {
+	headers, err := tableHeaders(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	file := excelize.NewFile()
+	if err := file.SetSheetName(file.GetSheetName(0), sheet); err != nil {
+		file.Close()
+		return nil, err
+	}
+	writer, err := file.NewStreamWriter(sheet)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	styles, err := newXLSXStyles(file)
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	err = writeXLSXSheet(writer, headers, rows, styles)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// xlsxStyles are the cell styles of a workbook
+type xlsxStyles struct {
+	header   int
+	date     int
+	dateTime int
+}
+
+func newXLSXStyles(file *excelize.File) (xlsxStyles, error) {
+	var styles xlsxStyles
+	var err error
+	if styles.header, err = file.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}}); err != nil {
+		return styles, err
+	}
+	if styles.date, err = file.NewStyle(&excelize.Style{NumFmt: xlsxDateFormat}); err != nil {
+		return styles, err
+	}
+	styles.dateTime, err = file.NewStyle(&excelize.Style{NumFmt: xlsxDateTimeFormat})
+	return styles, err
+}
+
+// writeXLSXSheet writes the header row and the data rows
+func writeXLSXSheet(writer *excelize.StreamWriter, headers []string, rows reflect.Value, styles xlsxStyles) error {
+	if headers == nil {
+		return nil
+	}
+
+	// Widths and panes must be set before the first row
+	for i, header := range headers {
+		width := float64(len(header) + 4)
+		if width < 12 {
+			width = 12
+		}
+		if err := writer.SetColWidth(i+1, i+1, width); err != nil {
+			return err
+		}
+	}
+	if err := writer.SetPanes(&excelize.Panes{Freeze: true, YSplit: 1, TopLeftCell: "A2", ActivePane: "bottomLeft"}); err != nil {
+		return err
+	}
+
+	headerRow := make([]interface{}, len(headers))
+	for i, header := range headers {
+		headerRow[i] = header
+	}
+	if err := writer.SetRow("A1", headerRow, excelize.RowOpts{StyleID: styles.header}); err != nil {
+		return err
+	}
+
+	rowNumber := 1
+	return eachTableRow(rows, func(i int, element reflect.Value) error {
+		values := getRowValues(element)
+		row := make([]interface{}, len(values))
+		for j, value := range values {
+			row[j] = xlsxCellValue(value, styles)
+		}
+
+		rowNumber++
+		cell, err := excelize.CoordinatesToCellName(1, rowNumber)
+		if err != nil {
+			return err
+		}
+		if err := writer.SetRow(cell, row); err != nil {
+			return fmt.Errorf("failed to write row %d: %w", i, err)
+		}
+		return nil
+	})
+}
+
+// xlsxCellValue returns the typed value of a cell
+// Nested values are written as JSON like in CSV.
+func xlsxCellValue(fieldValue reflect.Value, styles xlsxStyles) interface{} {
+	value := fieldValue
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return nil
+		}
+		value = value.Elem()
+	}
+
+	switch value.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.String:
+		return value.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return value.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return value.Uint()
+	case reflect.Float32, reflect.Float64:
+		return value.Float()
+	case reflect.Bool:
+		return value.Bool()
+	}
+
+	if t, ok := value.Interface().(time.Time); ok {
+		if t.IsZero() {
+			return nil
+		}
+		style := styles.dateTime
+		if t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0 {
+			style = styles.date
+		}
+		return excelize.Cell{StyleID: style, Value: t}
+	}
+
+	return formatCSVCell(fieldValue)
+}
+
+// sheetName returns the sheet name of a download, e.g. club_players for club_players.xlsx
+// Characters Excel does not allow in sheet names are replaced.
+func sheetName(filename string) string {
+	name := strings.TrimSuffix(filename, ".xlsx")
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return '_'
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > maxSheetNameLength {
+		name = string(runes[:maxSheetNameLength])
+	}
+	if name == "" {
+		return "Sheet1"
+	}
+	return name
+}
@@ -0,0 +1,128 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"portal64api/internal/models"
+	"portal64api/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Names of the document element and of the elements of array values
+const (
+	xmlRootElement  = "response"
+	xmlItemElement  = "item"
+	xmlEntryElement = "entry" // Keys that are no XML names, e.g. years, are written as <entry key="2024">
+)
+
+// SendXMLResponse sends a response as XML
+// The document mirrors the JSON response: object keys become elements in the same order,
+// array elements become <item> elements and null values empty elements.
+func SendXMLResponse(c *gin.Context, statusCode int, data interface{}) {
+	body, err := json.Marshal(models.Response{Success: true, Data: data})
+	if err == nil {
+		body, err = JSONToXML(body)
+	}
+	if err != nil {
+		SendJSONResponse(c, http.StatusInternalServerError,
+			errors.NewInternalServerError("Failed to generate XML: "+err.Error()))
+		return
+	}
+
+	c.Data(statusCode, FormatXML.ContentType(), body)
+}
+
+// JSONToXML converts a JSON document to XML, see SendXMLResponse
+func JSONToXML(document []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(document))
+	decoder.UseNumber()
+
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	encoder := xml.NewEncoder(&buf)
+	if err := writeXMLValue(encoder, decoder, xml.StartElement{Name: xml.Name{Local: xmlRootElement}}); err != nil {
+		return nil, err
+	}
+	if err := encoder.Flush(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeXMLValue writes the next JSON value of the decoder as element
+func writeXMLValue(encoder *xml.Encoder, decoder *json.Decoder, element xml.StartElement) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if err := encoder.EncodeToken(element); err != nil {
+		return err
+	}
+
+	switch value := token.(type) {
+	case json.Delim:
+		for decoder.More() {
+			child := xml.StartElement{Name: xml.Name{Local: xmlItemElement}}
+			if value == '{' {
+				key, err := decoder.Token()
+				if err != nil {
+					return err
+				}
+				child = xmlElement(fmt.Sprint(key))
+			}
+			if err := writeXMLValue(encoder, decoder, child); err != nil {
+				return err
+			}
+		}
+		// Closing delimiter
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+	case string:
+		err = encoder.EncodeToken(xml.CharData(value))
+	case json.Number:
+		err = encoder.EncodeToken(xml.CharData(value.String()))
+	case bool:
+		err = encoder.EncodeToken(xml.CharData(strconv.FormatBool(value)))
+	}
+	if err != nil {
+		return err
+	}
+
+	return encoder.EncodeToken(element.End())
+}
+
+// xmlElement returns the element of an object key
+func xmlElement(key string) xml.StartElement {
+	if isXMLName(key) {
+		return xml.StartElement{Name: xml.Name{Local: key}}
+	}
+	return xml.StartElement{
+		Name: xml.Name{Local: xmlEntryElement},
+		Attr: []xml.Attr{{Name: xml.Name{Local: "key"}, Value: key}},
+	}
+}
+
+// isXMLName reports whether a key can be used as element name as is
+func isXMLName(key string) bool {
+	if key == "" || strings.HasPrefix(strings.ToLower(key), "xml") {
+		return false
+	}
+	for i, r := range key {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i > 0 && (unicode.IsDigit(r) || r == '-' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,176 @@
+package benchmarks
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// fakeTable holds the rows of an in-memory table
+type fakeTable struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+// fakeDB is a minimal read-only SQL backend for benchmarking query patterns
+// It understands "col = ?" and "col IN (?,...)" conditions combined with AND,
+// ignores ordering and limits, and sleeps for a fixed latency on every query
+// to emulate the network round trip to MySQL.
+type fakeDB struct {
+	tables  map[string]*fakeTable
+	latency time.Duration
+	queries int64
+}
+
+var (
+	fakeTablePattern     = regexp.MustCompile("(?i)\\bFROM\\s+`?(\\w+)`?")
+	fakeConditionPattern = regexp.MustCompile("(?i)`?(\\w+)`?\\s*(=\\s*\\?|IN\\s*\\(([?,\\s]*)\\))")
+)
+
+// newFakeGormDB opens a gorm connection backed by the fake database
+func newFakeGormDB(fake *fakeDB) (*gorm.DB, error) {
+	sqlDB := sql.OpenDB(fake)
+	return gorm.Open(mysql.New(mysql.Config{
+		Conn:                      sqlDB,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{
+		Logger:                 logger.Default.LogMode(logger.Silent),
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+}
+
+// QueryCount returns the number of queries executed so far
+func (f *fakeDB) QueryCount() int64 {
+	return atomic.LoadInt64(&f.queries)
+}
+
+// Connect implements driver.Connector
+func (f *fakeDB) Connect(ctx context.Context) (driver.Conn, error) {
+	return &fakeConn{db: f}, nil
+}
+
+// Driver implements driver.Connector
+func (f *fakeDB) Driver() driver.Driver {
+	return fakeDriver{db: f}
+}
+
+type fakeDriver struct {
+	db *fakeDB
+}
+
+func (d fakeDriver) Open(name string) (driver.Conn, error) {
+	return &fakeConn{db: d.db}, nil
+}
+
+type fakeConn struct {
+	db *fakeDB
+}
+
+func (c *fakeConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("fake database does not support prepared statements")
+}
+
+func (c *fakeConn) Close() error {
+	return nil
+}
+
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return nil, fmt.Errorf("fake database is read-only")
+}
+
+// QueryContext implements driver.QueryerContext
+func (c *fakeConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	atomic.AddInt64(&c.db.queries, 1)
+	time.Sleep(c.db.latency)
+
+	match := fakeTablePattern.FindStringSubmatch(query)
+	if match == nil {
+		return nil, fmt.Errorf("fake database cannot parse query: %s", query)
+	}
+	table, ok := c.db.tables[match[1]]
+	if !ok {
+		return nil, fmt.Errorf("fake database has no table %s", match[1])
+	}
+
+	// Collect conditions in placeholder order
+	type condition struct {
+		column int
+		values map[string]bool
+	}
+	var conditions []condition
+	argIndex := 0
+	where := ""
+	if idx := strings.Index(strings.ToUpper(query), " WHERE "); idx >= 0 {
+		where = query[idx:]
+	}
+	for _, m := range fakeConditionPattern.FindAllStringSubmatch(where, -1) {
+		placeholders := 1
+		if strings.HasPrefix(strings.ToUpper(m[2]), "IN") {
+			placeholders = strings.Count(m[3], "?")
+		}
+		values := make(map[string]bool, placeholders)
+		for i := 0; i < placeholders && argIndex < len(args); i++ {
+			values[fmt.Sprint(args[argIndex].Value)] = true
+			argIndex++
+		}
+		column := -1
+		for i, name := range table.columns {
+			if strings.EqualFold(name, m[1]) {
+				column = i
+			}
+		}
+		if column < 0 {
+			return nil, fmt.Errorf("fake database table %s has no column %s", match[1], m[1])
+		}
+		conditions = append(conditions, condition{column: column, values: values})
+	}
+
+	rows := &fakeRows{columns: table.columns}
+	for _, row := range table.rows {
+		matches := true
+		for _, cond := range conditions {
+			if !cond.values[fmt.Sprint(row[cond.column])] {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			rows.rows = append(rows.rows, row)
+		}
+	}
+	return rows, nil
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+	pos     int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.rows) {
+		return io.EOF
+	}
+	copy(dest, r.rows[r.pos])
+	r.pos++
+	return nil
+}
@@ -0,0 +1,208 @@
+package benchmarks
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"testing"
+	"time"
+
+	"portal64api/internal/database"
+	"portal64api/internal/models"
+	"portal64api/internal/repositories"
+
+	"gorm.io/gorm"
+)
+
+// Simulated MySQL round trip per query
+const benchmarkQueryLatency = 100 * time.Microsecond
+
+// newTournamentFixture builds a Swiss tournament with the given rounds and players
+// Every player plays every round, so each round has players/2 games.
+func newTournamentFixture(tournamentID uint, rounds, players int) *fakeDB {
+	appointments := &fakeTable{columns: []string{"id", "idTournament", "round", "appointment"}}
+	games := &fakeTable{columns: []string{"id", "idTournament", "idAppointment", "idResultsDisplayRating", "board"}}
+	results := &fakeTable{columns: []string{"id", "idPerson", "idTournament", "idGame", "color", "points", "rating"}}
+	participants := &fakeTable{columns: []string{"id", "idTournament", "idPerson", "no", "idMembership", "useRating", "useRatingIndex"}}
+	persons := &fakeTable{columns: []string{"id", "name", "vorname"}}
+	displays := &fakeTable{
+		columns: []string{"id", "display", "pointsWhite", "pointsBlack", "ratingWhite", "ratingBlack"},
+		rows: [][]driver.Value{
+			{int64(1), "1-0", 1.0, 0.0, int64(1), int64(1)},
+			{int64(2), "0-1", 0.0, 1.0, int64(1), int64(1)},
+			{int64(3), "½-½", 0.5, 0.5, int64(1), int64(1)},
+		},
+	}
+
+	for p := 1; p <= players; p++ {
+		personID := int64(1000 + p)
+		participants.rows = append(participants.rows, []driver.Value{
+			int64(p), int64(tournamentID), personID, int64(p), int64(0), nil, nil,
+		})
+		persons.rows = append(persons.rows, []driver.Value{
+			personID, fmt.Sprintf("Spieler%d", p), "Max",
+		})
+	}
+
+	gameID, resultID := int64(0), int64(0)
+	for r := 1; r <= rounds; r++ {
+		appointmentID := int64(r)
+		appointments.rows = append(appointments.rows, []driver.Value{
+			appointmentID, int64(tournamentID), int64(r), fmt.Sprintf("2025-07-%02d", r),
+		})
+
+		for board := 1; board <= players/2; board++ {
+			gameID++
+			displayID := int64(board%3 + 1)
+			games.rows = append(games.rows, []driver.Value{
+				gameID, int64(tournamentID), appointmentID, displayID, int64(board),
+			})
+
+			white := int64(1000 + (board*2-1+r)%players + 1)
+			black := int64(1000 + (board*2+r)%players + 1)
+			resultID++
+			results.rows = append(results.rows, []driver.Value{
+				resultID, white, int64(tournamentID), gameID, "W", 1.0, int64(1),
+			})
+			resultID++
+			results.rows = append(results.rows, []driver.Value{
+				resultID, black, int64(tournamentID), gameID, "B", 0.0, int64(1),
+			})
+		}
+	}
+
+	return &fakeDB{
+		latency: benchmarkQueryLatency,
+		tables: map[string]*fakeTable{
+			"appointment":    appointments,
+			"game":           games,
+			"results":        results,
+			"participant":    participants,
+			"person":         persons,
+			"resultsDisplay": displays,
+		},
+	}
+}
+
+// loadRoundsPerGame reproduces the former access pattern of the tournament detail page:
+// one query per appointment, per game, per result, plus participant and person lookups per result
+func loadRoundsPerGame(db *gorm.DB, tournamentID uint) ([]models.RoundInfo, error) {
+	var appointments []models.Appointment
+	if err := db.Where("idTournament = ?", tournamentID).Order("round").Find(&appointments).Error; err != nil {
+		return nil, err
+	}
+
+	var rounds []models.RoundInfo
+	for _, appointment := range appointments {
+		round := models.RoundInfo{Round: appointment.Round, Appointment: appointment.Appointment}
+
+		var games []models.Game
+		db.Where("idAppointment = ?", appointment.ID).Order("board").Find(&games)
+		for _, game := range games {
+			gameInfo := models.GameInfo{ID: game.ID, Board: game.Board}
+
+			var results []models.Result
+			db.Where("idGame = ?", game.ID).Find(&results)
+			for _, result := range results {
+				playerRef := models.PlayerRef{ID: result.IDPerson}
+
+				var participant models.Participant
+				if db.Where("idTournament = ? AND idPerson = ?", tournamentID, result.IDPerson).First(&participant).Error == nil {
+					playerRef.No = participant.No
+				}
+				var person models.Person
+				if db.Where("id = ?", result.IDPerson).First(&person).Error == nil {
+					playerRef.Name = person.Name + ", " + person.Vorname
+				}
+
+				if result.Color == "W" {
+					gameInfo.White = playerRef
+				} else {
+					gameInfo.Black = playerRef
+				}
+			}
+
+			var display models.ResultsDisplay
+			if db.Where("id = ?", game.IDResultsDisplayRating).First(&display).Error == nil {
+				gameInfo.Result = display.Display
+			}
+			round.Games = append(round.Games, gameInfo)
+		}
+		rounds = append(rounds, round)
+	}
+	return rounds, nil
+}
+
+// BenchmarkTournamentRounds_PerGameQueries measures the former per-game loading of a 9-round open
+func BenchmarkTournamentRounds_PerGameQueries(b *testing.B) {
+	fake := newTournamentFixture(1, 9, 100)
+	db, err := newFakeGormDB(fake)
+	if err != nil {
+		b.Fatalf("failed to open fake database: %v", err)
+	}
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := loadRoundsPerGame(db, 1); err != nil {
+			b.Fatalf("failed to load rounds: %v", err)
+		}
+	}
+
+	b.ReportMetric(float64(fake.QueryCount())/float64(b.N), "queries/op")
+}
+
+// BenchmarkTournamentRounds_BatchQueries measures the set-based loading of a 9-round open
+func BenchmarkTournamentRounds_BatchQueries(b *testing.B) {
+	fake := newTournamentFixture(1, 9, 100)
+	db, err := newFakeGormDB(fake)
+	if err != nil {
+		b.Fatalf("failed to open fake database: %v", err)
+	}
+	repo := repositories.NewTournamentRepository(&database.Databases{MVDSB: db, Portal64BDW: db})
+
+	// Both loaders must produce the same rounds
+	expected, err := loadRoundsPerGame(db, 1)
+	if err != nil {
+		b.Fatalf("failed to load rounds: %v", err)
+	}
+	actual, err := repo.GetTournamentRounds(1)
+	if err != nil {
+		b.Fatalf("failed to load rounds: %v", err)
+	}
+	if err := compareRounds(expected, actual); err != nil {
+		b.Fatalf("batch loader differs from per-game loader: %v", err)
+	}
+
+	fake.queries = 0
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := repo.GetTournamentRounds(1); err != nil {
+			b.Fatalf("failed to load rounds: %v", err)
+		}
+	}
+
+	b.ReportMetric(float64(fake.QueryCount())/float64(b.N), "queries/op")
+}
+
+// compareRounds checks that two round lists contain the same pairings and results
+func compareRounds(expected, actual []models.RoundInfo) error {
+	if len(expected) != len(actual) {
+		return fmt.Errorf("expected %d rounds, got %d", len(expected), len(actual))
+	}
+	for i := range expected {
+		if len(expected[i].Games) != len(actual[i].Games) {
+			return fmt.Errorf("round %d: expected %d games, got %d", expected[i].Round, len(expected[i].Games), len(actual[i].Games))
+		}
+		for j, game := range expected[i].Games {
+			other := actual[i].Games[j]
+			if game.Board != other.Board || game.White.Name != other.White.Name || game.White.No != other.White.No ||
+				game.Black.Name != other.Black.Name || game.Black.No != other.Black.No || game.Result != other.Result {
+				return fmt.Errorf("round %d board %d differs", expected[i].Round, game.Board)
+			}
+		}
+	}
+	return nil
+}
@@ -71,7 +71,9 @@ func (suite *IntegrationTestSuite) SetupSuite() {
 	// Create nil import service for integration tests (not needed for basic API tests)
 	var importService *services.ImportService = nil
 	
-	suite.router = api.SetupRoutes(dbs, mockCacheService, importService)
+	router, err := api.SetupRoutes(api.RouterOptions{Databases: dbs, Cache: mockCacheService, ImportService: importService})
+	suite.Require().NoError(err)
+	suite.router = router
 }
 
 // TearDownSuite runs once after all tests in the suite
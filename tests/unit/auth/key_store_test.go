@@ -0,0 +1,96 @@
+package auth
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"portal64api/internal/auth"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyStoreCreateAndAuthenticate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	store, err := auth.NewKeyStore(path)
+	require.NoError(t, err)
+
+	key, apiKey, err := store.Create("Nightly import", []string{auth.ScopeAdminImport}, nil)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(key, "p64_"+apiKey.ID+"_"))
+
+	// Only the hash is persisted
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), key)
+	assert.Contains(t, string(data), auth.HashKey(key))
+
+	authenticated, err := store.Authenticate(key)
+	require.NoError(t, err)
+	assert.Equal(t, "Nightly import", authenticated.Name)
+	assert.True(t, authenticated.HasScope(auth.ScopeAdminImport))
+	assert.False(t, authenticated.HasScope(auth.ScopeAdminCache))
+
+	_, err = store.Authenticate(key + "x")
+	assert.ErrorIs(t, err, auth.ErrInvalidKey)
+	_, err = store.Authenticate("not-a-key")
+	assert.ErrorIs(t, err, auth.ErrInvalidKey)
+}
+
+func TestKeyStoreRejectsUnknownScope(t *testing.T) {
+	store, err := auth.NewKeyStore(filepath.Join(t.TempDir(), "keys.json"))
+	require.NoError(t, err)
+
+	_, _, err = store.Create("test", []string{"admin:everything"}, nil)
+	assert.Error(t, err)
+	_, _, err = store.Create("test", nil, nil)
+	assert.Error(t, err)
+}
+
+func TestKeyStoreRevokeAndExpiry(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	store, err := auth.NewKeyStore(path)
+	require.NoError(t, err)
+
+	key, apiKey, err := store.Create("revoked", []string{auth.ScopeRead}, nil)
+	require.NoError(t, err)
+	require.NoError(t, store.Revoke(apiKey.ID))
+	_, err = store.Authenticate(key)
+	assert.ErrorIs(t, err, auth.ErrKeyRevoked)
+	assert.ErrorIs(t, store.Revoke("unknown"), auth.ErrKeyNotFound)
+
+	past := time.Now().Add(-time.Hour)
+	expiredKey, _, err := store.Create("expired", []string{auth.ScopeRead}, &past)
+	require.NoError(t, err)
+	_, err = store.Authenticate(expiredKey)
+	assert.ErrorIs(t, err, auth.ErrKeyExpired)
+
+	// Revoked keys remain listed
+	assert.Len(t, store.List(), 2)
+}
+
+func TestKeyStorePicksUpExternalChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.json")
+	server, err := auth.NewKeyStore(path)
+	require.NoError(t, err)
+
+	// The CLI works on its own store instance
+	cli, err := auth.NewKeyStore(path)
+	require.NoError(t, err)
+	key, apiKey, err := cli.Create("created by CLI", []string{auth.ScopeAdminCache}, nil)
+	require.NoError(t, err)
+
+	_, err = server.Authenticate(key)
+	require.NoError(t, err)
+
+	require.NoError(t, cli.Revoke(apiKey.ID))
+	// Ensure a distinct modification time on filesystems with coarse timestamps
+	future := time.Now().Add(2 * time.Second)
+	require.NoError(t, os.Chtimes(path, future, future))
+
+	_, err = server.Authenticate(key)
+	assert.ErrorIs(t, err, auth.ErrKeyRevoked)
+}
@@ -0,0 +1,115 @@
+package errors
+
+import (
+	"database/sql/driver"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"portal64api/pkg/errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestCatalogue(t *testing.T) {
+	seen := map[errors.ErrorCode]bool{}
+	for _, entry := range errors.Catalogue() {
+		assert.False(t, seen[entry.Code], "duplicate code %s", entry.Code)
+		seen[entry.Code] = true
+		assert.GreaterOrEqual(t, entry.Status, 400, entry.Code)
+		assert.NotEmpty(t, entry.Title, entry.Code)
+		assert.NotEmpty(t, entry.Description, entry.Code)
+	}
+
+	// The codes the request mentions are part of the catalogue
+	for _, code := range []errors.ErrorCode{errors.CodePlayerNotFound, errors.CodeInvalidPlayerID,
+		errors.CodeDatabaseUnavailable, errors.CodeImportInProgress} {
+		assert.True(t, seen[code], code)
+	}
+
+	// Callers cannot change the catalogue
+	errors.Catalogue()[0].Title = "changed"
+	assert.NotEqual(t, "changed", errors.Catalogue()[0].Title)
+}
+
+func TestNew(t *testing.T) {
+	err := errors.New(errors.CodePlayerNotFound, "Player not found")
+	assert.Equal(t, http.StatusNotFound, err.Code)
+	assert.Equal(t, errors.CodePlayerNotFound, err.ErrorCode)
+
+	// Generic constructors use generic codes
+	assert.Equal(t, errors.CodeInvalidParameter, errors.NewBadRequestError("Invalid limit parameter").ErrorCode)
+	assert.Equal(t, errors.CodeNotFound, errors.NewNotFoundError("Region").ErrorCode)
+	assert.Equal(t, errors.CodeInternal, errors.NewInternalServerError("Failed to get all clubs").ErrorCode)
+	assert.Equal(t, errors.CodeRateLimited, errors.NewAPIError(http.StatusTooManyRequests, "Slow down").ErrorCode)
+	assert.Equal(t, errors.CodeInternal, errors.NewAPIError(http.StatusTeapot, "Teapot").ErrorCode)
+}
+
+func TestFromLookup(t *testing.T) {
+	notFound := errors.New(errors.CodeClubNotFound, "Club not found")
+
+	assert.Equal(t, notFound, errors.FromLookup(gorm.ErrRecordNotFound, notFound))
+	assert.Equal(t, notFound, errors.FromLookup(fmt.Errorf("club: %w", gorm.ErrRecordNotFound), notFound))
+
+	unavailable := []error{
+		driver.ErrBadConn,
+		mysql.ErrInvalidConn,
+		fmt.Errorf("query: %w", &mysql.MySQLError{Number: 1040, Message: "Too many connections"}),
+		fmt.Errorf("dial: %w", &timeoutError{}),
+	}
+	for _, cause := range unavailable {
+		err := errors.FromLookup(cause, notFound)
+		assert.Equal(t, errors.CodeDatabaseUnavailable, err.ErrorCode, cause.Error())
+		assert.Equal(t, http.StatusServiceUnavailable, err.Code, cause.Error())
+	}
+
+	err := errors.FromLookup(&mysql.MySQLError{Number: 1054, Message: "Unknown column"}, notFound)
+	assert.Equal(t, errors.CodeDatabaseError, err.ErrorCode)
+	assert.Equal(t, http.StatusInternalServerError, err.Code)
+	assert.Contains(t, err.Details, "Unknown column")
+}
+
+func TestNewDatabaseErrorKeepsAPIErrors(t *testing.T) {
+	apiErr := errors.NewBadRequestError("Region parameter is required")
+	assert.Equal(t, apiErr, errors.NewDatabaseError(apiErr, "Failed to query regions"))
+
+	err := errors.NewDatabaseError(stderrors.New("syntax error"), "Failed to query regions")
+	assert.Equal(t, "Failed to query regions", err.Message)
+}
+
+func TestNewProblem(t *testing.T) {
+	problem := errors.NewProblem(errors.New(errors.CodeRateLimited, "Rate limit exceeded", "Retry after 3 seconds"),
+		http.StatusTooManyRequests, "/api/v1/players")
+	assert.Equal(t, errors.Problem{
+		Type:      "/api/v1/errors/RATE_LIMITED",
+		Title:     "Rate limit exceeded",
+		Status:    http.StatusTooManyRequests,
+		Detail:    "Rate limit exceeded",
+		Details:   "Retry after 3 seconds",
+		Instance:  "/api/v1/players",
+		ErrorCode: errors.CodeRateLimited,
+		Error:     "Rate limit exceeded",
+	}, problem)
+
+	// Details of server errors are not exposed
+	problem = errors.NewProblem(errors.NewDatabaseError(stderrors.New("Table 'person' is marked as crashed"), "Failed to search players"),
+		http.StatusInternalServerError, "/api/v1/players")
+	require.Equal(t, errors.CodeDatabaseError, problem.ErrorCode)
+	assert.Equal(t, "Database error", problem.Title)
+	assert.Empty(t, problem.Details)
+
+	// Errors without code get the generic code of the status
+	problem = errors.NewProblem(errors.APIError{Message: "gone"}, http.StatusNotFound, "")
+	assert.Equal(t, errors.CodeNotFound, problem.ErrorCode)
+}
+
+// timeoutError is a network error as returned when a database cannot be reached
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
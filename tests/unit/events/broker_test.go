@@ -0,0 +1,67 @@
+package events
+
+import (
+	"testing"
+
+	"portal64api/internal/events"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrokerPublishesToSubscribers(t *testing.T) {
+	broker := events.NewBroker(4)
+
+	first, unsubscribeFirst := broker.Subscribe()
+	defer unsubscribeFirst()
+	second, unsubscribeSecond := broker.Subscribe()
+	defer unsubscribeSecond()
+	assert.Equal(t, 2, broker.Subscribers())
+
+	broker.Publish(events.TypeStatus, "running")
+	broker.Publish(events.TypeLog, "downloading")
+
+	for _, ch := range []<-chan events.Event{first, second} {
+		event := <-ch
+		assert.Equal(t, uint64(1), event.ID)
+		assert.Equal(t, events.TypeStatus, event.Type)
+		assert.Equal(t, "running", event.Data)
+		assert.False(t, event.Time.IsZero())
+
+		event = <-ch
+		assert.Equal(t, uint64(2), event.ID)
+		assert.Equal(t, events.TypeLog, event.Type)
+	}
+}
+
+func TestBrokerDropsSlowSubscribers(t *testing.T) {
+	broker := events.NewBroker(2)
+
+	slow, unsubscribe := broker.Subscribe()
+	defer unsubscribe()
+
+	for i := 0; i < 3; i++ {
+		broker.Publish(events.TypeLog, i)
+	}
+	assert.Equal(t, 0, broker.Subscribers(), "the third event does not fit")
+
+	// The buffered events are still delivered, then the channel is closed
+	received := 0
+	for range slow {
+		received++
+	}
+	assert.Equal(t, 2, received)
+}
+
+func TestBrokerUnsubscribe(t *testing.T) {
+	broker := events.NewBroker(1)
+
+	ch, unsubscribe := broker.Subscribe()
+	unsubscribe()
+	unsubscribe()
+	assert.Equal(t, 0, broker.Subscribers())
+
+	_, open := <-ch
+	require.False(t, open)
+	broker.Publish(events.TypeStatus, nil)
+}
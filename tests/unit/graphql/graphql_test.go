@@ -0,0 +1,219 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"portal64api/internal/cache"
+	"portal64api/internal/graphql"
+	"portal64api/internal/models"
+	"portal64api/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingCache counts the MGet calls of the batched lookups
+type countingCache struct {
+	cache.CacheService
+	mgets int
+}
+
+func (c *countingCache) MGet(ctx context.Context, keys []string) (map[string]interface{}, error) {
+	c.mgets++
+	return c.CacheService.MGet(ctx, keys)
+}
+
+// newTestServer creates a server whose services answer from a cache filled with two players
+// of the same club, their rating histories, a tournament and the rosters of two clubs
+func newTestServer(t *testing.T, limits graphql.Limits) (*graphql.Server, *countingCache) {
+	t.Helper()
+	ctx := context.Background()
+	keys := cache.NewKeyGenerator()
+	store := cache.NewMockCacheService(true)
+
+	finished := time.Date(2025, 7, 20, 0, 0, 0, 0, time.UTC)
+	entries := map[string]interface{}{
+		keys.PlayerKey("C0101-1014"): models.PlayerResponse{ID: "C0101-1014", Name: "Müller", ClubID: "C0101", CurrentDWZ: 1850},
+		keys.PlayerKey("C0101-1015"): models.PlayerResponse{ID: "C0101-1015", Name: "Schmidt", ClubID: "C0101", CurrentDWZ: 1620},
+		keys.ClubKey("C0101"):        models.ClubResponse{ID: "C0101", Name: "Post-SV Ulm", MemberCount: 42},
+		keys.ClubKey("C0102"):        models.ClubResponse{ID: "C0102", Name: "SF Ulm"},
+		keys.ClubPlayersKey("C0101", "roster"): []models.PlayerResponse{
+			{ID: "C0101-1014", Name: "Müller", ClubID: "C0101", CurrentDWZ: 1850},
+			{ID: "C0101-1015", Name: "Schmidt", ClubID: "C0101", CurrentDWZ: 1620},
+		},
+		keys.ClubPlayersKey("C0102", "roster"): []models.PlayerResponse{},
+		keys.PlayerRatingHistoryKey("C0101-1014"): []models.RatingHistoryResponse{
+			{ID: 2, TournamentID: "C529-K00-HT1", DWZNew: 1850, TournamentDate: &finished},
+			{ID: 1, TournamentID: "C418-K00-HT1", DWZNew: 1830},
+		},
+		keys.PlayerRatingHistoryKey("C0101-1015"): []models.RatingHistoryResponse{},
+		keys.TournamentKey("basic_C529-K00-HT1"):  models.TournamentResponse{ID: "C529-K00-HT1", Name: "Ulmer Sommer-Open"},
+	}
+	require.NoError(t, store.MSet(ctx, entries, time.Hour))
+
+	counting := &countingCache{CacheService: store}
+	schema, err := graphql.NewSchema()
+	require.NoError(t, err)
+	server := graphql.NewServer(schema, graphql.Services{
+		Players:     services.NewPlayerService(nil, nil, nil, counting),
+		Clubs:       services.NewClubService(nil, counting),
+		Tournaments: services.NewTournamentService(nil, counting),
+		Addresses:   services.NewAddressService(nil, counting),
+	}, limits)
+	return server, counting
+}
+
+func execute(t *testing.T, server *graphql.Server, query string, variables map[string]interface{}) (map[string]interface{}, []map[string]interface{}, bool) {
+	t.Helper()
+	result, executed := server.Execute(context.Background(), graphql.Request{Query: query, Variables: variables})
+
+	encoded, err := json.Marshal(result)
+	require.NoError(t, err)
+	var decoded struct {
+		Data   map[string]interface{}   `json:"data"`
+		Errors []map[string]interface{} `json:"errors"`
+	}
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	return decoded.Data, decoded.Errors, executed
+}
+
+func TestQueryBatchesNestedLookups(t *testing.T) {
+	server, counting := newTestServer(t, graphql.Limits{MaxDepth: 8, MaxComplexity: 5000})
+
+	data, errs, executed := execute(t, server, `query($ids: [String!]!) {
+		players(ids: $ids) {
+			name
+			current_dwz
+			current_club { name member_count }
+			rating_history(limit: 1) { dwz_new tournament { name } }
+		}
+	}`, map[string]interface{}{"ids": []interface{}{"C0101-1014", "C0101-1015", "C0101-1014"}})
+	require.True(t, executed)
+	require.Empty(t, errs)
+
+	players := data["players"].([]interface{})
+	require.Len(t, players, 3)
+	assert.Equal(t, players[0], players[2])
+	first := players[0].(map[string]interface{})
+	assert.Equal(t, "Müller", first["name"])
+	assert.Equal(t, float64(1850), first["current_dwz"])
+	assert.Equal(t, map[string]interface{}{"name": "Post-SV Ulm", "member_count": float64(42)}, first["current_club"])
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"dwz_new":    float64(1850),
+		"tournament": map[string]interface{}{"name": "Ulmer Sommer-Open"},
+	}}, first["rating_history"])
+	assert.Equal(t, []interface{}{}, players[1].(map[string]interface{})["rating_history"])
+
+	// One batch per level and resource: players, then clubs and rating histories, then tournaments
+	assert.Equal(t, 4, counting.mgets)
+}
+
+func TestQueryBatchesClubRosters(t *testing.T) {
+	server, counting := newTestServer(t, graphql.Limits{MaxDepth: 8, MaxComplexity: 5000})
+
+	data, errs, executed := execute(t, server, `{
+		clubs(ids: ["C0101", "C0102"]) {
+			name
+			players(limit: 1, offset: 1) { name current_dwz }
+		}
+	}`, nil)
+	require.True(t, executed)
+	require.Empty(t, errs)
+
+	clubs := data["clubs"].([]interface{})
+	require.Len(t, clubs, 2)
+	assert.Equal(t, []interface{}{map[string]interface{}{"name": "Schmidt", "current_dwz": float64(1620)}},
+		clubs[0].(map[string]interface{})["players"])
+	assert.Equal(t, []interface{}{}, clubs[1].(map[string]interface{})["players"])
+
+	// One batch for the clubs and one for the rosters of all clubs
+	assert.Equal(t, 2, counting.mgets)
+}
+
+func TestQueryReportsFieldErrors(t *testing.T) {
+	server, _ := newTestServer(t, graphql.Limits{})
+
+	data, errs, executed := execute(t, server, `{ player(id: "invalid") { name } club(id: "C0101") { name } }`, nil)
+	require.True(t, executed)
+	require.Len(t, errs, 1)
+	assert.Equal(t, []interface{}{"player"}, errs[0]["path"])
+	assert.Equal(t, map[string]interface{}{"code": float64(400), "error_code": "INVALID_PLAYER_ID"}, errs[0]["extensions"])
+	assert.Nil(t, data["player"])
+	assert.Equal(t, map[string]interface{}{"name": "Post-SV Ulm"}, data["club"])
+}
+
+func TestQueryDepthLimit(t *testing.T) {
+	server, _ := newTestServer(t, graphql.Limits{MaxDepth: 3})
+
+	_, errs, executed := execute(t, server, `{ club(id: "C0101") { players { current_club { players { name } } } } }`, nil)
+	assert.False(t, executed)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0]["message"], "depth 5 exceeds the maximum of 3")
+
+	// Fragments count towards the depth as well
+	_, errs, executed = execute(t, server, `
+		{ club(id: "C0101") { ...roster } }
+		fragment roster on Club { players { current_club { name } } }`, nil)
+	assert.False(t, executed)
+	assert.Contains(t, errs[0]["message"], "depth 4")
+}
+
+func TestQueryComplexityLimit(t *testing.T) {
+	server, _ := newTestServer(t, graphql.Limits{MaxComplexity: 1000})
+
+	// 1 + 500 * (1 + 1 + 25 * (1 + 1 + 1)): rating histories without limit count as 25 entries
+	_, errs, executed := execute(t, server, `{ search_players(limit: 500) { name rating_history { dwz_new tournament { name } } } }`, nil)
+	assert.False(t, executed)
+	require.Len(t, errs, 1)
+	assert.Contains(t, errs[0]["message"], "complexity 38501 exceeds the maximum of 1000")
+
+	// Limits passed as variables are used as well
+	_, errs, executed = execute(t, server, `query($limit: Int) { search_clubs(limit: $limit) { name } }`,
+		map[string]interface{}{"limit": float64(2000)})
+	assert.False(t, executed)
+	assert.Contains(t, errs[0]["message"], "complexity 2001")
+}
+
+func TestIntrospectionIsNotLimited(t *testing.T) {
+	server, _ := newTestServer(t, graphql.Limits{MaxDepth: 2, MaxComplexity: 10})
+
+	data, errs, executed := execute(t, server, `{ __type(name: "Player") { fields { name type { kind ofType { name } } } } }`, nil)
+	require.True(t, executed)
+	require.Empty(t, errs)
+
+	// Fields are generated from the response model, related resources are added
+	fields := map[string]bool{}
+	for _, field := range data["__type"].(map[string]interface{})["fields"].([]interface{}) {
+		fields[field.(map[string]interface{})["name"].(string)] = true
+	}
+	for _, name := range []string{"id", "name", "club_id", "current_dwz", "birth_year", "current_club", "rating_history"} {
+		assert.True(t, fields[name], name)
+	}
+}
+
+func TestMutationsAreRejected(t *testing.T) {
+	server, _ := newTestServer(t, graphql.Limits{})
+
+	_, errs, executed := execute(t, server, `mutation { deletePlayer(id: "C0101-1014") }`, nil)
+	assert.False(t, executed)
+	require.NotEmpty(t, errs)
+	assert.Contains(t, errs[0]["message"], "read-only")
+}
+
+func TestOperationTokens(t *testing.T) {
+	server, _ := newTestServer(t, graphql.Limits{MaxComplexity: 5000, ComplexityPerToken: 100})
+
+	operation, invalid := server.Prepare(graphql.Request{Query: `{ club(id: "C0101") { name } }`})
+	require.Nil(t, invalid)
+	assert.Equal(t, 2, operation.Complexity)
+	assert.Equal(t, 1, operation.Tokens)
+
+	// 1 + 250 * (1 + 1): one token per started 100 of complexity
+	operation, invalid = server.Prepare(graphql.Request{Query: `{ search_clubs(limit: 250) { id name } }`})
+	require.Nil(t, invalid)
+	assert.Equal(t, 501, operation.Complexity)
+	assert.Equal(t, 6, operation.Tokens)
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"portal64api/internal/api/handlers"
+	"portal64api/internal/config"
+	"portal64api/internal/models"
+	"portal64api/internal/services"
+	"portal64api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testExportGeneration = "20261018T031502Z"
+
+// newExportRouter serves a generation with a players dataset written to a temporary directory
+// It returns the gzip file as stored and the description of the file in the manifest.
+func newExportRouter(t *testing.T) (*gin.Engine, []byte, models.ExportFile) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	dir := t.TempDir()
+	generationDir := filepath.Join(dir, testExportGeneration)
+	require.NoError(t, os.MkdirAll(generationDir, 0755))
+
+	name := "players.ndjson.gz"
+	file, err := utils.CreateTableFile(filepath.Join(generationDir, name), utils.FormatNDJSON, models.PlayerResponse{})
+	require.NoError(t, err)
+	require.NoError(t, file.Write(models.PlayerResponse{ID: "C0101-1014", Name: "Müller", CurrentDWZ: 1850}))
+	require.NoError(t, file.Write(models.PlayerResponse{ID: "C0101-1015", Name: "Schmidt", CurrentDWZ: 1620}))
+	info, err := file.Close()
+	require.NoError(t, err)
+
+	exportFile := models.ExportFile{
+		Dataset:       models.ExportDatasetPlayers,
+		Format:        models.ExportFormatNDJSON,
+		Name:          name,
+		Rows:          info.Rows,
+		Size:          info.Size,
+		SHA256:        info.SHA256,
+		ContentSHA256: info.ContentSHA256,
+	}
+	manifest := models.ExportManifest{
+		Generation:  testExportGeneration,
+		GeneratedAt: time.Date(2026, 10, 18, 3, 15, 2, 0, time.UTC),
+		Files:       []models.ExportFile{exportFile},
+	}
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(generationDir, "manifest.json"), data, 0644))
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	service := services.NewExportService(&config.ExportConfig{Directory: dir, Keep: 2}, nil, log)
+	require.NoError(t, service.Start())
+
+	stored, err := os.ReadFile(filepath.Join(generationDir, name))
+	require.NoError(t, err)
+
+	router := gin.New()
+	router.GET("/api/v1/export/:dataset", handlers.NewExportHandler(service).DownloadDataset)
+	return router, stored, exportFile
+}
+
+func TestExportHandler_DownloadDataset(t *testing.T) {
+	router, stored, file := newExportRouter(t)
+
+	var content bytes.Buffer
+	gz, err := gzip.NewReader(bytes.NewReader(stored))
+	require.NoError(t, err)
+	_, err = io.Copy(&content, gz)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name             string
+		acceptEncoding   string
+		ifNoneMatch      string
+		expectedCode     int
+		expectedEncoding string
+		expectedETag     string
+		expectedBody     []byte
+	}{
+		{
+			name:             "gzip is sent as stored",
+			acceptEncoding:   "gzip, deflate",
+			expectedCode:     http.StatusOK,
+			expectedEncoding: "gzip",
+			expectedETag:     `"` + file.SHA256 + `"`,
+			expectedBody:     stored,
+		},
+		{
+			name:         "identity is decompressed",
+			expectedCode: http.StatusOK,
+			expectedETag: `"` + file.ContentSHA256 + `"`,
+			expectedBody: content.Bytes(),
+		},
+		{
+			name:         "identity not modified",
+			ifNoneMatch:  `"` + file.ContentSHA256 + `"`,
+			expectedCode: http.StatusNotModified,
+			expectedETag: `"` + file.ContentSHA256 + `"`,
+		},
+		{
+			// http.ServeContent drops the Content-Encoding of responses without body
+			name:           "gzip not modified",
+			acceptEncoding: "gzip",
+			ifNoneMatch:    `"` + file.SHA256 + `"`,
+			expectedCode:   http.StatusNotModified,
+			expectedETag:   `"` + file.SHA256 + `"`,
+		},
+		{
+			name:         "checksum of the other encoding does not match",
+			ifNoneMatch:  `"` + file.SHA256 + `"`,
+			expectedCode: http.StatusOK,
+			expectedETag: `"` + file.ContentSHA256 + `"`,
+			expectedBody: content.Bytes(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/export/players", nil)
+			if tt.acceptEncoding != "" {
+				req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			}
+			if tt.ifNoneMatch != "" {
+				req.Header.Set("If-None-Match", tt.ifNoneMatch)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expectedCode, w.Code)
+			assert.Equal(t, tt.expectedEncoding, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, tt.expectedETag, w.Header().Get("ETag"))
+			assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+			assert.Equal(t, testExportGeneration, w.Header().Get("X-Export-Generation"))
+			assert.Equal(t, string(tt.expectedBody), w.Body.String())
+		})
+	}
+}
+
+func TestExportHandler_DownloadDatasetErrors(t *testing.T) {
+	router, _, _ := newExportRouter(t)
+
+	tests := []struct {
+		name         string
+		url          string
+		expectedCode int
+	}{
+		{"unknown format", "/api/v1/export/players?format=xlsx", http.StatusNotAcceptable},
+		{"unknown dataset", "/api/v1/export/games", http.StatusNotFound},
+		{"invalid generation", "/api/v1/export/players?generation=../secrets", http.StatusBadRequest},
+		{"missing generation", "/api/v1/export/players?generation=20250101T000000Z", http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.url, nil))
+			assert.Equal(t, tt.expectedCode, w.Code)
+		})
+	}
+}
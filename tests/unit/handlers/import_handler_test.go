@@ -7,7 +7,9 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"portal64api/internal/api/handlers"
+	"portal64api/internal/events"
 	"portal64api/internal/models"
+	"strings"
 	"testing"
 	"time"
 
@@ -47,6 +49,11 @@ func (m *MockImportService) GetLogs(limit int) []models.ImportLogEntry {
 	return args.Get(0).([]models.ImportLogEntry)
 }
 
+func (m *MockImportService) SubscribeEvents() (<-chan events.Event, func()) {
+	args := m.Called()
+	return args.Get(0).(<-chan events.Event), args.Get(1).(func())
+}
+
 func (m *MockImportService) TestConnection() error {
 	args := m.Called()
 	return args.Error(0)
@@ -774,3 +781,40 @@ func TestImportHandler_ContentType(t *testing.T) {
 		})
 	}
 }
+
+func TestImportHandler_StreamImportEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// A closed channel ends the stream after the buffered events
+	ch := make(chan events.Event, 2)
+	ch <- events.Event{ID: 7, Type: events.TypeStatus, Data: &models.ImportStatus{Status: "running", CurrentStep: "download", Progress: 20}}
+	ch <- events.Event{ID: 8, Type: events.TypeLog, Data: models.ImportLogEntry{Level: "INFO", Step: "download", Message: "Downloading"}}
+	close(ch)
+	unsubscribed := false
+
+	mockService := new(MockImportService)
+	mockService.On("SubscribeEvents").Return((<-chan events.Event)(ch), func() { unsubscribed = true })
+	mockService.On("GetStatus").Return(&models.ImportStatus{Status: "idle"})
+
+	handler := handlers.NewImportHandler(mockService)
+	router := gin.New()
+	router.GET("/api/v1/import/events", handler.StreamImportEvents)
+
+	req, err := http.NewRequest(http.MethodGet, "/api/v1/import/events", nil)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+	assert.True(t, unsubscribed)
+
+	body := w.Body.String()
+	assert.Contains(t, body, "retry: 3000\n\n")
+	// The current status comes first, without an ID
+	assert.Contains(t, body, "event: status\ndata: {\"status\":\"idle\"")
+	assert.Contains(t, body, "id: 7\nevent: status\ndata: {\"status\":\"running\",\"progress\":20")
+	assert.Contains(t, body, "id: 8\nevent: log\ndata: {")
+	assert.Less(t, strings.Index(body, "\"idle\""), strings.Index(body, "id: 7"))
+	mockService.AssertExpectations(t)
+}
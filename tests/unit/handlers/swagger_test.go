@@ -10,6 +10,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestSwaggerEndpoints tests Swagger endpoints without database dependency
@@ -21,7 +22,8 @@ func TestSwaggerEndpoints(t *testing.T) {
 	dbs := &database.Databases{}
 
 	// Setup routes with nil services - Swagger endpoints don't need them
-	router := api.SetupRoutes(dbs, nil, nil)
+	router, err := api.SetupRoutes(api.RouterOptions{Databases: dbs})
+	require.NoError(t, err)
 
 	tests := []struct {
 		name           string
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"log"
 	"os"
+	"portal64api/internal/events"
 	"portal64api/internal/importers"
 	"portal64api/internal/models"
 	"strings"
@@ -596,3 +597,84 @@ func TestStatusTracker_LogRotation(t *testing.T) {
 		assert.Equal(t, expectedMessages[i], log.Message)
 	}
 }
+
+func TestStatusTracker_Metrics(t *testing.T) {
+	logger := log.New(os.Stdout, "TEST: ", log.LstdFlags)
+	tracker := importers.NewStatusTracker(100, logger)
+
+	metrics := tracker.GetMetrics()
+	assert.False(t, metrics.Running)
+	assert.Empty(t, metrics.Outcomes)
+	assert.Nil(t, metrics.LastSuccess)
+
+	// First import: two steps, then success
+	tracker.UpdateStatus(models.StatusRunning, models.StepInitialization, 0)
+	assert.True(t, tracker.GetMetrics().Running)
+	time.Sleep(5 * time.Millisecond)
+	tracker.UpdateProgress(models.StepDownload, 20)
+	time.Sleep(5 * time.Millisecond)
+	tracker.UpdateProgress(models.StepDownload, 40)
+	tracker.MarkSuccess()
+
+	metrics = tracker.GetMetrics()
+	assert.False(t, metrics.Running)
+	assert.Equal(t, int64(1), metrics.Outcomes[models.StatusSuccess])
+	assert.NotNil(t, metrics.LastSuccess)
+	assert.GreaterOrEqual(t, metrics.PhaseDurations[models.StepInitialization], 5*time.Millisecond)
+	assert.GreaterOrEqual(t, metrics.PhaseDurations[models.StepDownload], 5*time.Millisecond)
+	assert.GreaterOrEqual(t, metrics.LastDuration, 10*time.Millisecond)
+
+	// Second import fails in its first step, the step durations start over
+	tracker.UpdateStatus(models.StatusRunning, models.StepInitialization, 0)
+	tracker.MarkFailed(errors.New("connection refused"), models.StepInitialization)
+
+	metrics = tracker.GetMetrics()
+	assert.Equal(t, int64(1), metrics.Outcomes[models.StatusSuccess])
+	assert.Equal(t, int64(1), metrics.Outcomes[models.StatusFailed])
+	assert.NotContains(t, metrics.PhaseDurations, models.StepDownload)
+	assert.Contains(t, metrics.PhaseDurations, models.StepInitialization)
+	assert.Less(t, metrics.LastDuration, 10*time.Millisecond)
+
+	// Skipped scheduled imports are counted without a duration
+	tracker.MarkSkipped("api_under_heavy_load", models.StepInitialization)
+	metrics = tracker.GetMetrics()
+	assert.Equal(t, int64(1), metrics.Outcomes[models.StatusSkipped])
+	assert.Zero(t, metrics.LastDuration)
+}
+
+func TestStatusTracker_Subscribe(t *testing.T) {
+	logger := log.New(os.Stdout, "TEST: ", log.LstdFlags)
+	tracker := importers.NewStatusTracker(100, logger)
+
+	ch, unsubscribe := tracker.Subscribe()
+	defer unsubscribe()
+
+	tracker.UpdateStatus(models.StatusRunning, models.StepDownload, 20)
+
+	// The status change is published before its log entry
+	event := <-ch
+	assert.Equal(t, events.TypeStatus, event.Type)
+	status := event.Data.(*models.ImportStatus)
+	assert.Equal(t, models.StatusRunning, status.Status)
+	assert.Equal(t, models.StepDownload, status.CurrentStep)
+	assert.Equal(t, 20, status.Progress)
+
+	event = <-ch
+	assert.Equal(t, events.TypeLog, event.Type)
+	assert.Equal(t, models.StepDownload, event.Data.(models.ImportLogEntry).Step)
+
+	// Published statuses are copies
+	tracker.UpdateProgress(models.StepExtraction, 40)
+	event = <-ch
+	assert.Equal(t, 40, event.Data.(*models.ImportStatus).Progress)
+	assert.Equal(t, 20, status.Progress)
+
+	tracker.LogWarning(models.StepExtraction, "Slow download")
+	event = <-ch
+	assert.Equal(t, events.TypeLog, event.Type)
+	assert.Equal(t, "WARN", event.Data.(models.ImportLogEntry).Level)
+
+	tracker.MarkFailed(errors.New("extraction failed"), models.StepExtraction)
+	event = <-ch
+	assert.Equal(t, models.StatusFailed, event.Data.(*models.ImportStatus).Status)
+}
@@ -0,0 +1,97 @@
+package metrics
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"portal64api/internal/api/middleware"
+	"portal64api/internal/cache"
+	"portal64api/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrape serves the collectors from a fresh registry and returns the exposition
+func scrape(t *testing.T, collectors ...prometheus.Collector) string {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(collectors...)
+
+	w := httptest.NewRecorder()
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	return w.Body.String()
+}
+
+func TestRegisterReplacesCollectors(t *testing.T) {
+	require.NoError(t, metrics.Register(metrics.NewHTTPMetrics()))
+	// A second router, e.g. in tests, registers the same metrics again
+	require.NoError(t, metrics.Register(metrics.NewHTTPMetrics()))
+
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.NoError(t, err)
+	names := make([]string, 0, len(families))
+	for _, family := range families {
+		names = append(names, family.GetName())
+	}
+	assert.Contains(t, names, "portal64_http_requests_in_flight")
+	assert.Contains(t, names, "go_goroutines")
+	assert.Contains(t, names, "process_start_time_seconds")
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	httpMetrics := metrics.NewHTTPMetrics()
+
+	router := gin.New()
+	router.Use(middleware.Metrics(httpMetrics))
+	router.GET("/api/v1/players/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for _, path := range []string{"/api/v1/players/C0101-1", "/api/v1/players/C0101-2", "/unknown/path"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	output := scrape(t, httpMetrics)
+	assert.Contains(t, output, `portal64_http_request_duration_seconds_count{method="GET",route="/api/v1/players/:id",status="200"} 2`)
+	assert.Contains(t, output, `portal64_http_request_duration_seconds_count{method="GET",route="unmatched",status="404"} 1`)
+	assert.Contains(t, output, "portal64_http_requests_in_flight 0")
+	assert.NotContains(t, output, "C0101-1")
+}
+
+func TestDatabaseCollector(t *testing.T) {
+	output := scrape(t, metrics.DatabaseCollector(func() map[string]sql.DBStats {
+		return map[string]sql.DBStats{
+			"mvdsb":        {MaxOpenConnections: 100, OpenConnections: 3, InUse: 1, Idle: 2},
+			"portal64_bdw": {MaxOpenConnections: 100, WaitCount: 4, WaitDuration: 1500 * time.Millisecond},
+		}
+	}))
+	assert.Contains(t, output, `portal64_db_open_connections{database="mvdsb"} 3`)
+	assert.Contains(t, output, `portal64_db_in_use_connections{database="mvdsb"} 1`)
+	assert.Contains(t, output, `portal64_db_wait_count_total{database="portal64_bdw"} 4`)
+	assert.Contains(t, output, `portal64_db_wait_duration_seconds_total{database="portal64_bdw"} 1.5`)
+}
+
+func TestCacheCollector(t *testing.T) {
+	cacheService := cache.NewMockCacheService(true)
+	ctx := context.Background()
+	keyGen := cache.NewKeyGenerator()
+
+	var dest map[string]string
+	require.NoError(t, cacheService.Set(ctx, keyGen.PlayerKey("C0101-1"), map[string]string{"id": "C0101-1"}, time.Minute))
+	require.NoError(t, cacheService.Get(ctx, keyGen.PlayerKey("C0101-1"), &dest))
+	assert.Error(t, cacheService.Get(ctx, keyGen.PlayerKey("C0101-2"), &dest))
+	assert.Error(t, cacheService.Get(ctx, keyGen.SearchKey("club", "abc"), &dest))
+
+	output := scrape(t, metrics.CacheCollector(cacheService))
+	assert.Contains(t, output, `portal64_cache_requests_total{prefix="player",result="hit"} 1`)
+	assert.Contains(t, output, `portal64_cache_requests_total{prefix="player",result="miss"} 1`)
+	assert.Contains(t, output, `portal64_cache_hit_ratio{prefix="player"} 0.5`)
+	assert.Contains(t, output, `portal64_cache_hit_ratio{prefix="search:club"} 0`)
+}
@@ -0,0 +1,73 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"portal64api/internal/api/middleware"
+	"portal64api/internal/auth"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newProtectedRouter(store *auth.KeyStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/import/start", middleware.RequireScope(store, auth.ScopeAdminImport), func(c *gin.Context) {
+		c.Status(http.StatusAccepted)
+	})
+	return router
+}
+
+func TestRequireScope(t *testing.T) {
+	store, err := auth.NewKeyStore(filepath.Join(t.TempDir(), "keys.json"))
+	require.NoError(t, err)
+	importKey, _, err := store.Create("import", []string{auth.ScopeAdminImport}, nil)
+	require.NoError(t, err)
+	cacheKey, _, err := store.Create("cache", []string{auth.ScopeAdminCache}, nil)
+	require.NoError(t, err)
+
+	router := newProtectedRouter(store)
+
+	tests := []struct {
+		name     string
+		header   string
+		value    string
+		expected int
+	}{
+		{"Missing key", "", "", http.StatusUnauthorized},
+		{"Invalid key", "X-API-Key", "p64_deadbeef_invalid", http.StatusUnauthorized},
+		{"Missing scope", "X-API-Key", cacheKey, http.StatusForbidden},
+		{"X-API-Key header", "X-API-Key", importKey, http.StatusAccepted},
+		{"Bearer token", "Authorization", "Bearer " + importKey, http.StatusAccepted},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/import/start", nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.expected, w.Code)
+			if tt.expected == http.StatusUnauthorized {
+				assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestRequireScopeDisabled(t *testing.T) {
+	router := newProtectedRouter(nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/import/start", nil))
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+}
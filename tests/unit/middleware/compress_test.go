@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"portal64api/internal/api/middleware"
+	"portal64api/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var largeBody = strings.Repeat(`{"name":"Schachclub"},`, 200)
+
+func newCompressedRouter(cfg config.CompressionConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Compression(cfg))
+	router.Use(middleware.ConditionalGet(nil))
+	router.GET("/large", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(largeBody))
+	})
+	router.GET("/small", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(`{"ok":true}`))
+	})
+	router.GET("/image", func(c *gin.Context) {
+		c.Data(http.StatusOK, "image/png", []byte(largeBody))
+	})
+	router.GET("/stream", func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+		c.String(http.StatusOK, "[")
+		c.Writer.Flush()
+		c.String(http.StatusOK, "1]")
+	})
+	return router
+}
+
+var compressionConfig = config.CompressionConfig{
+	Enabled:      true,
+	Level:        5,
+	MinSize:      1024,
+	ContentTypes: []string{"application/json", "text/"},
+}
+
+func compressedRequest(router *gin.Engine, path, acceptEncoding string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if acceptEncoding != "" {
+		req.Header.Set("Accept-Encoding", acceptEncoding)
+	}
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func gunzip(t *testing.T, body io.Reader) string {
+	reader, err := gzip.NewReader(body)
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	return string(data)
+}
+
+func TestCompression(t *testing.T) {
+	router := newCompressedRouter(compressionConfig)
+
+	w := compressedRequest(router, "/large", "gzip, deflate, br")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Empty(t, w.Header().Get("Content-Length"))
+	assert.True(t, strings.HasPrefix(w.Header().Get("ETag"), `W/"`))
+	assert.Less(t, w.Body.Len(), len(largeBody))
+	assert.Equal(t, largeBody, gunzip(t, w.Body))
+
+	// The weak ETag of the compressed response revalidates
+	etag := w.Header().Get("ETag")
+	w = compressedRequest(router, "/large", "gzip", "If-None-Match", etag)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+}
+
+func TestCompressionSkipped(t *testing.T) {
+	router := newCompressedRouter(compressionConfig)
+
+	tests := []struct {
+		name           string
+		path           string
+		acceptEncoding string
+		body           string
+	}{
+		{"gzip not accepted", "/large", "", largeBody},
+		{"gzip refused", "/large", "gzip;q=0, identity", largeBody},
+		{"below minimum size", "/small", "gzip", `{"ok":true}`},
+		{"content type not compressible", "/image", "gzip", largeBody},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := compressedRequest(router, tt.path, tt.acceptEncoding)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Empty(t, w.Header().Get("Content-Encoding"))
+			assert.Equal(t, tt.body, w.Body.String())
+		})
+	}
+
+	disabled := newCompressedRouter(config.CompressionConfig{})
+	w := compressedRequest(disabled, "/large", "gzip")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Empty(t, w.Header().Get("Vary"))
+	assert.Equal(t, largeBody, w.Body.String())
+}
+
+func TestCompressionStreamed(t *testing.T) {
+	router := newCompressedRouter(compressionConfig)
+
+	w := compressedRequest(router, "/stream", "*")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "[1]", gunzip(t, w.Body))
+}
+
+func TestCompressionPanicLeavesResponseToRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ErrorHandlingMiddleware())
+	router.Use(middleware.Compression(compressionConfig))
+	router.Use(middleware.ConditionalGet(nil))
+	router.GET("/panic", func(c *gin.Context) {
+		c.Header("Content-Type", "application/json")
+		c.String(http.StatusOK, "partial")
+		panic("boom")
+	})
+
+	w := compressedRequest(router, "/panic", "gzip")
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{"type":"/api/v1/errors/INTERNAL_ERROR","title":"Internal server error","status":500,
+		"detail":"Internal server error","instance":"/panic","error_code":"INTERNAL_ERROR",
+		"success":false,"error":"Internal server error"}`, w.Body.String())
+}
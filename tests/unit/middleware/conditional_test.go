@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"portal64api/internal/api/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var lastImport = time.Date(2026, 10, 18, 2, 15, 30, 0, time.UTC)
+
+func newConditionalRouter(lastModified middleware.LastModifiedFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ConditionalGet(lastModified))
+	router.GET("/clubs/:id", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+	router.GET("/missing", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "not found"})
+	})
+	router.GET("/stream", func(c *gin.Context) {
+		c.String(http.StatusOK, "[")
+		c.Writer.Flush()
+		c.String(http.StatusOK, "]")
+	})
+	return router
+}
+
+func conditionalRequest(router *gin.Engine, path string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestConditionalGetHeaders(t *testing.T) {
+	router := newConditionalRouter(func() (time.Time, bool) { return lastImport, true })
+
+	w := conditionalRequest(router, "/clubs/C0101", nil)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":"C0101"}`, w.Body.String())
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, w.Header().Get("ETag"))
+	assert.Equal(t, "Sun, 18 Oct 2026 02:15:30 GMT", w.Header().Get("Last-Modified"))
+	assert.Equal(t, "no-cache", w.Header().Get("Cache-Control"))
+
+	// The ETag depends on the body
+	other := conditionalRequest(router, "/clubs/C0102", nil)
+	assert.NotEqual(t, w.Header().Get("ETag"), other.Header().Get("ETag"))
+}
+
+func TestConditionalGetNotModified(t *testing.T) {
+	router := newConditionalRouter(func() (time.Time, bool) { return lastImport, true })
+	etag := conditionalRequest(router, "/clubs/C0101", nil).Header().Get("ETag")
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected int
+	}{
+		{"matching etag", map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"weak etag in list", map[string]string{"If-None-Match": `"other", W/` + etag}, http.StatusNotModified},
+		{"wildcard", map[string]string{"If-None-Match": "*"}, http.StatusNotModified},
+		{"changed etag", map[string]string{"If-None-Match": `"other"`}, http.StatusOK},
+		{"not modified since import", map[string]string{"If-Modified-Since": "Sun, 18 Oct 2026 02:15:30 GMT"}, http.StatusNotModified},
+		{"modified since", map[string]string{"If-Modified-Since": "Sat, 17 Oct 2026 02:15:30 GMT"}, http.StatusOK},
+		{"invalid date", map[string]string{"If-Modified-Since": "yesterday"}, http.StatusOK},
+		{"etag takes precedence", map[string]string{
+			"If-None-Match":     `"other"`,
+			"If-Modified-Since": "Sun, 18 Oct 2026 02:15:30 GMT",
+		}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := conditionalRequest(router, "/clubs/C0101", tt.headers)
+			assert.Equal(t, tt.expected, w.Code)
+			assert.Equal(t, "Sun, 18 Oct 2026 02:15:30 GMT", w.Header().Get("Last-Modified"))
+			if _, ok := tt.headers["If-None-Match"]; ok || tt.expected == http.StatusOK {
+				assert.Equal(t, etag, w.Header().Get("ETag"))
+			}
+			if tt.expected == http.StatusNotModified {
+				assert.Empty(t, w.Body.String())
+				assert.Empty(t, w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestConditionalGetWithoutImportTime(t *testing.T) {
+	router := newConditionalRouter(nil)
+
+	w := conditionalRequest(router, "/clubs/C0101", map[string]string{"If-Modified-Since": "Sun, 18 Oct 2026 02:15:30 GMT"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Header().Get("Last-Modified"))
+	assert.NotEmpty(t, w.Header().Get("ETag"))
+}
+
+func TestConditionalGetPassesThroughOtherResponses(t *testing.T) {
+	router := newConditionalRouter(func() (time.Time, bool) { return lastImport, true })
+
+	w := conditionalRequest(router, "/missing", map[string]string{"If-None-Match": "*"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Empty(t, w.Header().Get("ETag"))
+
+	// Unknown resources are not answered with 304, even if the data did not change
+	w = conditionalRequest(router, "/missing", map[string]string{"If-Modified-Since": "Sun, 18 Oct 2026 03:00:00 GMT"})
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.JSONEq(t, `{"error":"not found"}`, w.Body.String())
+
+	// Streamed responses carry no ETag but are validated against the import time
+	w = conditionalRequest(router, "/stream", map[string]string{"If-None-Match": "*"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "[]", w.Body.String())
+	assert.Empty(t, w.Header().Get("ETag"))
+	assert.Equal(t, "Sun, 18 Oct 2026 02:15:30 GMT", w.Header().Get("Last-Modified"))
+
+	w = conditionalRequest(router, "/stream", map[string]string{"If-Modified-Since": "Sun, 18 Oct 2026 03:00:00 GMT"})
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+}
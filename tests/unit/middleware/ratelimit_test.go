@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"portal64api/internal/api/middleware"
+	"portal64api/internal/auth"
+	"portal64api/internal/ratelimit"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRateLimitedRouter(limiter *ratelimit.Limiter, store *auth.KeyStore) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RateLimit(limiter, store))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/api/v1/players", ok)
+	router.GET("/api/v1/clubs/:id", ok)
+	router.GET("/health", ok)
+	return router
+}
+
+func doRequest(router *gin.Engine, path, apiKey string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+	if apiKey != "" {
+		req.Header.Set("X-API-Key", apiKey)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimit(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Rule{RequestsPerMinute: 60, Burst: 2}, nil)
+	router := newRateLimitedRouter(limiter, nil)
+
+	w := doRequest(router, "/api/v1/players", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "2", w.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "1", w.Header().Get("RateLimit-Reset"))
+	assert.Equal(t, "60;w=60;burst=2", w.Header().Get("RateLimit-Policy"))
+
+	w = doRequest(router, "/api/v1/players", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "0", w.Header().Get("RateLimit-Remaining"))
+
+	w = doRequest(router, "/api/v1/players", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+
+	// Route groups are limited separately, other paths are not limited
+	w = doRequest(router, "/api/v1/clubs/C0101", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	for i := 0; i < 5; i++ {
+		w = doRequest(router, "/health", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestRateLimitPerAPIKey(t *testing.T) {
+	store, err := auth.NewKeyStore(filepath.Join(t.TempDir(), "keys.json"))
+	require.NoError(t, err)
+	key, _, err := store.Create("reader", []string{auth.ScopeRead}, nil)
+	require.NoError(t, err)
+
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Rule{RequestsPerMinute: 60, Burst: 1}, nil)
+	router := newRateLimitedRouter(limiter, store)
+
+	assert.Equal(t, http.StatusOK, doRequest(router, "/api/v1/players", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRequest(router, "/api/v1/players", "").Code)
+
+	// A valid key gets its own bucket even from the same address
+	assert.Equal(t, http.StatusOK, doRequest(router, "/api/v1/players", key).Code)
+	assert.Equal(t, http.StatusTooManyRequests, doRequest(router, "/api/v1/players", key).Code)
+}
+
+func TestRateLimitForwardedFor(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Rule{RequestsPerMinute: 60, Burst: 1}, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	require.NoError(t, router.SetTrustedProxies([]string{"10.0.0.0/8"}))
+	router.Use(middleware.RateLimit(limiter, nil))
+	router.GET("/api/v1/players", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	request := func(remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/players", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Direct clients cannot get a new bucket by spoofing X-Forwarded-For
+	assert.Equal(t, http.StatusOK, request("192.0.2.1:1234", "198.51.100.1"))
+	assert.Equal(t, http.StatusTooManyRequests, request("192.0.2.1:1234", "198.51.100.2"))
+
+	// Behind a trusted proxy every forwarded client has its own bucket
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:1234", "198.51.100.1"))
+	assert.Equal(t, http.StatusOK, request("10.0.0.1:1234", "198.51.100.2"))
+	assert.Equal(t, http.StatusTooManyRequests, request("10.0.0.1:1234", "198.51.100.2"))
+}
+
+func TestRateLimitDisabled(t *testing.T) {
+	router := newRateLimitedRouter(nil, nil)
+	for i := 0; i < 5; i++ {
+		w := doRequest(router, "/api/v1/players", "")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("RateLimit-Limit"))
+	}
+}
+
+func TestRouteGroup(t *testing.T) {
+	assert.Equal(t, "players", middleware.RouteGroup("/api/v1/players"))
+	assert.Equal(t, "players", middleware.RouteGroup("/api/v1/players/C0101-1014/rating-history"))
+	assert.Equal(t, "", middleware.RouteGroup("/health"))
+	assert.Equal(t, "", middleware.RouteGroup("/demo/index.html"))
+}
+
+func TestRateLimitCharge(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Rule{RequestsPerMinute: 60, Burst: 5}, nil)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RateLimit(limiter, nil))
+	router.GET("/api/v1/graphql", func(c *gin.Context) {
+		result, err := ratelimit.ChargeRequest(c.Request.Context(), 3)
+		require.NoError(t, err)
+		if !result.Allowed {
+			c.Status(http.StatusTooManyRequests)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	// The request takes one token, the handler three more from the same bucket
+	w := doRequest(router, "/api/v1/graphql", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// One token is left: the request passes the middleware, the charge is denied
+	w = doRequest(router, "/api/v1/graphql", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	w = doRequest(router, "/api/v1/graphql", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "1", w.Header().Get("Retry-After"))
+}
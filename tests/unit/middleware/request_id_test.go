@@ -0,0 +1,172 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"portal64api/internal/api/middleware"
+	"portal64api/internal/logging"
+	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func newRequestIDRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(middleware.LoggingMiddleware())
+	router.Use(middleware.ErrorHandlingMiddleware())
+	router.GET("/api/v1/players/:id", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		logging.RecordCacheMiss(ctx)
+		logging.RecordDBQuery(ctx, 3*time.Millisecond)
+		utils.SendJSONResponse(c, http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+	router.GET("/api/v1/missing", func(c *gin.Context) {
+		utils.SendJSONResponse(c, http.StatusNotFound, errors.NewNotFoundError("player"))
+	})
+	router.GET("/api/v1/panic", func(c *gin.Context) {
+		panic("boom")
+	})
+	return router
+}
+
+// captureLog redirects the structured logger to a buffer for the duration of a test
+func captureLog(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	out, formatter, level := logging.Log.Out, logging.Log.Formatter, logging.Log.Level
+	logging.Log.SetOutput(&buf)
+	logging.Log.SetFormatter(&logrus.JSONFormatter{})
+	logging.Log.SetLevel(logrus.InfoLevel)
+	t.Cleanup(func() {
+		logging.Log.SetOutput(out)
+		logging.Log.SetFormatter(formatter)
+		logging.Log.SetLevel(level)
+	})
+	return &buf
+}
+
+// logEntries decodes the JSON log lines written to a buffer
+func logEntries(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	captureLog(t)
+	router := newRequestIDRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/players/C0101-123", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Regexp(t, uuidPattern, w.Header().Get(middleware.RequestIDHeader))
+
+	// Each request gets its own ID
+	w2 := httptest.NewRecorder()
+	router.ServeHTTP(w2, httptest.NewRequest(http.MethodGet, "/api/v1/players/C0101-123", nil))
+	assert.NotEqual(t, w.Header().Get(middleware.RequestIDHeader), w2.Header().Get(middleware.RequestIDHeader))
+}
+
+func TestRequestIDAccepted(t *testing.T) {
+	captureLog(t)
+	router := newRequestIDRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/players/C0101-123", nil)
+	req.Header.Set(middleware.RequestIDHeader, "proxy-4711.a:b_c")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, "proxy-4711.a:b_c", w.Header().Get(middleware.RequestIDHeader))
+
+	// Invalid IDs are replaced
+	for _, id := range []string{"with space", "<script>", strings.Repeat("a", 129)} {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/players/C0101-123", nil)
+		req.Header.Set(middleware.RequestIDHeader, id)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Regexp(t, uuidPattern, w.Header().Get(middleware.RequestIDHeader), id)
+	}
+}
+
+func TestRequestIDInErrorResponses(t *testing.T) {
+	captureLog(t)
+	router := newRequestIDRouter()
+
+	for _, path := range []string{"/api/v1/missing", "/api/v1/panic"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(middleware.RequestIDHeader, "req-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		var body map[string]interface{}
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body), path)
+		assert.Equal(t, false, body["success"], path)
+		assert.Equal(t, "req-1", body["request_id"], path)
+	}
+
+	// Successful responses are unchanged
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/players/C0101-123", nil))
+	assert.NotContains(t, w.Body.String(), "request_id")
+}
+
+func TestAccessLog(t *testing.T) {
+	buf := captureLog(t)
+	router := newRequestIDRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/players/C0101-123?format=json", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-2")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	entries := logEntries(t, buf)
+	require.Len(t, entries, 1)
+	entry := entries[0]
+	assert.Equal(t, "info", entry["level"])
+	assert.Equal(t, "req-2", entry["request_id"])
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/api/v1/players/C0101-123", entry["path"])
+	assert.Equal(t, "/api/v1/players/:id", entry["route"])
+	assert.Equal(t, "format=json", entry["query"])
+	assert.EqualValues(t, 200, entry["status"])
+	assert.Contains(t, entry, "latency_ms")
+	assert.EqualValues(t, 0, entry["cache_hits"])
+	assert.EqualValues(t, 1, entry["cache_misses"])
+	assert.EqualValues(t, 1, entry["db_queries"])
+	assert.EqualValues(t, 3, entry["db_time_ms"])
+
+	// Client errors are logged as warnings, panics as errors
+	buf.Reset()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/missing", nil))
+	entries = logEntries(t, buf)
+	require.NotEmpty(t, entries)
+	assert.Equal(t, "warning", entries[len(entries)-1]["level"])
+
+	buf.Reset()
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/panic", nil))
+	entries = logEntries(t, buf)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "Panic recovered", entries[0]["msg"])
+	assert.Equal(t, "error", entries[1]["level"])
+	assert.Equal(t, entries[0]["request_id"], entries[1]["request_id"])
+}
@@ -0,0 +1,96 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"portal64api/internal/api/middleware"
+	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFormatRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.ResponseFormat())
+	router.GET("/clubs", func(c *gin.Context) {
+		c.String(http.StatusOK, string(utils.RequestedFormat(c)))
+	})
+	return router
+}
+
+func TestResponseFormatNegotiation(t *testing.T) {
+	router := newFormatRouter()
+
+	tests := []struct {
+		path   string
+		accept string
+		format utils.Format
+	}{
+		{"/clubs", "", utils.FormatJSON},
+		{"/clubs?format=XLSX", "", utils.FormatXLSX},
+		{"/clubs?format=csv-excel", "application/json", utils.FormatExcelCSV},
+		{"/clubs", "text/csv", utils.FormatCSV},
+		{"/clubs", "application/x-ndjson", utils.FormatNDJSON},
+		{"/clubs", "application/json;q=0.5, application/xml", utils.FormatXML},
+		{"/clubs", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", utils.FormatJSON},
+		{"/clubs", "image/png", utils.FormatJSON},
+	}
+	for _, tt := range tests {
+		w := conditionalRequest(router, tt.path, map[string]string{"Accept": tt.accept})
+		require.Equal(t, http.StatusOK, w.Code, tt.path)
+		assert.Equal(t, string(tt.format), w.Body.String(), "%s Accept: %s", tt.path, tt.accept)
+		assert.Contains(t, w.Header().Values("Vary"), "Accept")
+	}
+}
+
+func TestResponseFormatUnknown(t *testing.T) {
+	w := conditionalRequest(newFormatRouter(), "/clubs?format=xslx", nil)
+
+	assert.Equal(t, http.StatusNotAcceptable, w.Code)
+	assert.Equal(t, errors.ProblemContentType, w.Header().Get("Content-Type"))
+
+	var problem errors.Problem
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, errors.CodeNotAcceptable, problem.ErrorCode)
+	assert.Contains(t, problem.Detail, "xlsx")
+}
+
+func TestResponseFormatPerRoute(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := func(c *gin.Context) {
+		c.String(http.StatusOK, string(utils.RequestedFormat(c)))
+	}
+	router.GET("/clubs", middleware.ResponseFormat(), handler)
+	router.GET("/tournaments/recent", middleware.ResponseFormat(append(utils.DataFormats, utils.FormatICalendar)...), handler)
+	router.GET("/addresses/:region", middleware.ResponseFormat(utils.FormatJSON, utils.FormatCSV, utils.FormatVCard), handler)
+
+	// Formats of other routes are not acceptable
+	for _, path := range []string{"/clubs?format=ics", "/clubs?format=vcf", "/tournaments/recent?format=vcf", "/addresses/C?format=xlsx"} {
+		w := conditionalRequest(router, path, nil)
+		assert.Equal(t, http.StatusNotAcceptable, w.Code, path)
+		assert.Equal(t, errors.ProblemContentType, w.Header().Get("Content-Type"), path)
+	}
+
+	w := conditionalRequest(router, "/tournaments/recent?format=ics", nil)
+	assert.Equal(t, string(utils.FormatICalendar), w.Body.String())
+	w = conditionalRequest(router, "/addresses/C", map[string]string{"Accept": "text/vcard"})
+	assert.Equal(t, string(utils.FormatVCard), w.Body.String())
+
+	// Accept headers without supported media types fall back to JSON
+	w = conditionalRequest(router, "/clubs", map[string]string{"Accept": "text/calendar"})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, string(utils.FormatJSON), w.Body.String())
+
+	var problem errors.Problem
+	w = conditionalRequest(router, "/clubs?format=ics", nil)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &problem))
+	assert.Equal(t, errors.CodeNotAcceptable, problem.ErrorCode)
+	assert.Contains(t, problem.Detail, "ics")
+}
@@ -0,0 +1,165 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"portal64api/internal/ratelimit"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryStoreTake(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	rule := ratelimit.Rule{RequestsPerMinute: 60, Burst: 3}
+	ctx := context.Background()
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	for i := 2; i >= 0; i-- {
+		result, err := store.Take(ctx, "client", rule, 1, now)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	result, err := store.Take(ctx, "client", rule, 1, now)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.Reset)
+
+	// Other clients have their own bucket
+	result, err = store.Take(ctx, "other", rule, 1, now)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// One token is refilled per second
+	result, err = store.Take(ctx, "client", rule, 1, now.Add(time.Second))
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// The bucket never exceeds its capacity
+	result, err = store.Take(ctx, "client", rule, 1, now.Add(time.Hour))
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 2, result.Remaining)
+}
+
+func TestMemoryStoreRequestsPerMinute(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	ctx := context.Background()
+	start := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+
+	for i := 0; i < 120; i++ {
+		require.NoError(t, store.CountRequest(ctx, start.Add(10*time.Second)))
+	}
+
+	rate, err := store.RequestsPerMinute(ctx, start.Add(30*time.Second))
+	require.NoError(t, err)
+	assert.InDelta(t, 120, rate, 0.001)
+
+	// Half way into the next minute half of the previous minute is counted
+	rate, err = store.RequestsPerMinute(ctx, start.Add(90*time.Second))
+	require.NoError(t, err)
+	assert.InDelta(t, 60, rate, 0.001)
+
+	rate, err = store.RequestsPerMinute(ctx, start.Add(3*time.Minute))
+	require.NoError(t, err)
+	assert.Zero(t, rate)
+}
+
+func TestParseRules(t *testing.T) {
+	rules, err := ratelimit.ParseRules([]string{"players=600:100", " addresses = 120 ", ""})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]ratelimit.Rule{
+		"players":   {RequestsPerMinute: 600, Burst: 100},
+		"addresses": {RequestsPerMinute: 120, Burst: 120},
+	}, rules)
+
+	for _, spec := range []string{"players", "=60:10", "players=many", "players=60:x"} {
+		_, err := ratelimit.ParseRules([]string{spec})
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestLimiter(t *testing.T) {
+	store := ratelimit.NewMemoryStore()
+	limiter := ratelimit.NewLimiter(store, ratelimit.Rule{RequestsPerMinute: 60, Burst: 1}, map[string]ratelimit.Rule{
+		"import": {RequestsPerMinute: 0},
+	})
+	ctx := context.Background()
+
+	result, err := limiter.Allow(ctx, "players", "ip:10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	result, err = limiter.Allow(ctx, "players", "ip:10.0.0.1")
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+
+	// Buckets are kept per route group
+	result, err = limiter.Allow(ctx, "clubs", "ip:10.0.0.1")
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// A rule without a rate disables limiting for the group
+	for i := 0; i < 5; i++ {
+		result, err = limiter.Allow(ctx, "import", "ip:10.0.0.1")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+	}
+
+	// Every request counts towards the load, limited or not
+	rate, err := limiter.RequestsPerMinute(ctx)
+	require.NoError(t, err)
+	assert.GreaterOrEqual(t, rate, 8.0)
+}
+
+func TestLimiterCharge(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), ratelimit.Rule{RequestsPerMinute: 60, Burst: 10}, nil)
+	ctx := context.Background()
+
+	result, err := limiter.Allow(ctx, "graphql", "ip:10.0.0.1")
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// Further tokens come from the same bucket
+	result, err = limiter.Charge(ctx, "graphql", "ip:10.0.0.1", 5)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 4, result.Remaining)
+
+	// Too few tokens deny the charge without taking any
+	result, err = limiter.Charge(ctx, "graphql", "ip:10.0.0.1", 6)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 4, result.Remaining)
+	assert.InDelta(t, 2*time.Second, result.RetryAfter, float64(100*time.Millisecond))
+
+	// Charges are capped at the bucket capacity
+	result, err = limiter.Charge(ctx, "graphql", "ip:10.0.0.2", 50)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+}
+
+func TestChargeRequest(t *testing.T) {
+	// Requests that are not rate limited are allowed
+	result, err := ratelimit.ChargeRequest(context.Background(), 5)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	var charged int
+	ctx := ratelimit.WithCharge(context.Background(), func(tokens int) (ratelimit.Result, error) {
+		charged = tokens
+		return ratelimit.Result{Allowed: false}, nil
+	})
+	result, err = ratelimit.ChargeRequest(ctx, 3)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, 3, charged)
+}
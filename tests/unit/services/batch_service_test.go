@@ -0,0 +1,139 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"portal64api/internal/cache"
+	"portal64api/internal/models"
+	"portal64api/internal/repositories"
+	"portal64api/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlayerService_GetPlayersByIDs(t *testing.T) {
+	mockPlayerRepo := new(MockPlayerRepository)
+	cacheService := cache.NewMockCacheService(true)
+	service := services.NewPlayerService(mockPlayerRepo, new(MockClubRepository), new(MockTournamentRepository), cacheService)
+
+	// C0101-1 is cached, C0101-2 is loaded, C0101-3 does not exist
+	require.NoError(t, cacheService.Set(context.Background(), cache.NewKeyGenerator().PlayerKey("C0101-1"),
+		models.PlayerResponse{ID: "C0101-1", Name: "Cached", CurrentDWZ: 1800}, time.Hour))
+
+	mockPlayerRepo.On("GetPlayersByIDs", []repositories.PlayerKey{
+		{VKZ: "C0101", Spielernummer: 2},
+		{VKZ: "C0101", Spielernummer: 3},
+	}).Return(map[repositories.PlayerKey]repositories.PlayerRecord{
+		{VKZ: "C0101", Spielernummer: 2}: {
+			Person:       models.Person{ID: 42, Name: "Loaded", Vorname: "Anna"},
+			Organisation: models.Organisation{ID: 1, Name: "Post-SV Ulm", VKZ: "C0101"},
+			Evaluation:   &models.Evaluation{DWZNew: 1650, DWZNewIndex: 12},
+		},
+	}, nil).Once()
+
+	results, err := service.GetPlayersByIDs([]string{"C0101-1", "C0101-2", "C0101-3", "invalid", "C0101-1"})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	assert.Equal(t, "C0101-1", results[0].ID)
+	require.NotNil(t, results[0].Data)
+	assert.Equal(t, "Cached", results[0].Data.Name)
+	assert.Nil(t, results[0].Error)
+
+	require.NotNil(t, results[1].Data)
+	assert.Equal(t, "Loaded", results[1].Data.Name)
+	assert.Equal(t, "C0101", results[1].Data.ClubID)
+	assert.Equal(t, 1650, results[1].Data.CurrentDWZ)
+
+	assert.Nil(t, results[2].Data)
+	require.NotNil(t, results[2].Error)
+	assert.Equal(t, http.StatusNotFound, results[2].Error.Code)
+
+	assert.Equal(t, "invalid", results[3].ID)
+	require.NotNil(t, results[3].Error)
+	assert.Equal(t, http.StatusBadRequest, results[3].Error.Code)
+
+	// The loaded player was cached, the second lookup does not query the repository
+	results, err = service.GetPlayersByIDs([]string{"C0101-2"})
+	require.NoError(t, err)
+	require.NotNil(t, results[0].Data)
+	assert.Equal(t, "Loaded", results[0].Data.Name)
+	mockPlayerRepo.AssertExpectations(t)
+}
+
+func TestPlayerService_GetPlayersRatingHistories(t *testing.T) {
+	mockPlayerRepo := new(MockPlayerRepository)
+	service := services.NewPlayerService(mockPlayerRepo, new(MockClubRepository), new(MockTournamentRepository),
+		cache.NewMockCacheService(false))
+
+	finished := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	mockPlayerRepo.On("GetPlayersByIDs", mock.Anything).Return(map[repositories.PlayerKey]repositories.PlayerRecord{
+		{VKZ: "C0101", Spielernummer: 1}: {Person: models.Person{ID: 41}},
+		{VKZ: "C0101", Spielernummer: 2}: {Person: models.Person{ID: 42}},
+	}, nil)
+	mockPlayerRepo.On("GetPlayersRatingHistories", mock.Anything).Return(map[uint][]repositories.EvaluationWithTournament{
+		41: {
+			{Evaluation: models.Evaluation{ID: 7, DWZNew: 1500}, TournamentCode: "B403-500-SEM", TournamentFinishedOn: &finished},
+			{Evaluation: models.Evaluation{ID: 6, DWZNew: 1480}}, // No tournament code, skipped
+		},
+	}, nil)
+
+	results, err := service.GetPlayersRatingHistories([]string{"C0101-1", "C0101-2", "C0101-9"})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	require.Len(t, results[0].Data, 1)
+	assert.Equal(t, "B403-500-SEM", results[0].Data[0].TournamentID)
+	assert.Equal(t, &finished, results[0].Data[0].TournamentDate)
+
+	// Players without evaluations have an empty history, not an error
+	assert.NotNil(t, results[1].Data)
+	assert.Empty(t, results[1].Data)
+	assert.Nil(t, results[1].Error)
+
+	require.NotNil(t, results[2].Error)
+	assert.Equal(t, http.StatusNotFound, results[2].Error.Code)
+}
+
+func TestPlayerService_GetPlayersMemberships(t *testing.T) {
+	mockPlayerRepo := new(MockPlayerRepository)
+	service := services.NewPlayerService(mockPlayerRepo, new(MockClubRepository), new(MockTournamentRepository),
+		cache.NewMockCacheService(false))
+
+	from := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	until := time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)
+	mockPlayerRepo.On("GetPlayersByIDs", mock.Anything).Return(map[repositories.PlayerKey]repositories.PlayerRecord{
+		{VKZ: "C0101", Spielernummer: 1}: {Person: models.Person{ID: 41}},
+		{VKZ: "C0101", Spielernummer: 2}: {Person: models.Person{ID: 42}},
+	}, nil)
+	mockPlayerRepo.On("GetPlayersMemberships", mock.Anything).Return(map[uint][]repositories.MembershipWithOrganisation{
+		41: {
+			{Mitgliedschaft: models.Mitgliedschaft{Person: 41, Spielernummer: 1, Von: &from}, ClubVKZ: "C0101", ClubName: "SC Test"},
+			{Mitgliedschaft: models.Mitgliedschaft{Person: 41, Spielernummer: 7, Von: &from, Bis: &until}, ClubVKZ: "C0327", ClubName: "SV Alt"},
+		},
+	}, nil).Once()
+
+	results, err := service.GetPlayersMemberships([]string{"C0101-1", "C0101-2", "C0101-9"})
+	require.NoError(t, err)
+	require.Len(t, results, 3)
+
+	require.Len(t, results[0].Data, 2)
+	assert.Equal(t, "C0101-001", results[0].Data[0].PlayerID)
+	assert.True(t, results[0].Data[0].Current)
+	assert.Equal(t, "C0327-007", results[0].Data[1].PlayerID)
+	assert.False(t, results[0].Data[1].Current)
+
+	// Players without memberships have an empty list, not an error
+	assert.NotNil(t, results[1].Data)
+	assert.Empty(t, results[1].Data)
+	assert.Nil(t, results[1].Error)
+
+	require.NotNil(t, results[2].Error)
+	assert.Equal(t, http.StatusNotFound, results[2].Error.Code)
+	mockPlayerRepo.AssertExpectations(t)
+}
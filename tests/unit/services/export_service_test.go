@@ -0,0 +1,176 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"portal64api/internal/config"
+	"portal64api/internal/database"
+	"portal64api/internal/models"
+	"portal64api/internal/services"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newMockGormDB opens a gorm connection backed by sqlmock
+func newMockGormDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}),
+		&gorm.Config{Logger: logger.Default.LogMode(logger.Silent), SkipDefaultTransaction: true})
+	require.NoError(t, err)
+	return db, mock
+}
+
+// expectExportSnapshot expects the queries of one generation with a club, a player and an evaluation
+func expectExportSnapshot(mvdsb, bdw sqlmock.Sqlmock) {
+	finished := time.Date(2025, 7, 20, 0, 0, 0, 0, time.UTC)
+	joined := time.Date(2010, 1, 1, 0, 0, 0, 0, time.UTC)
+	club := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "vkz", "name", "organisationsart", "status"}).
+			AddRow(1, "C0101", "Post-SV Ulm", 20, 0)
+	}
+
+	mvdsb.ExpectBegin()
+	mvdsb.ExpectQuery("SELECT \\* FROM `organisation` WHERE vkz != ''").WillReturnRows(club())
+	mvdsb.ExpectQuery("SELECT id, uuid FROM `person`").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "uuid"}).AddRow(1, "uuid-1"))
+	mvdsb.ExpectQuery("SELECT \\* FROM `organisation` WHERE status = 0 AND organisationsart = 20").WillReturnRows(club())
+	mvdsb.ExpectQuery("SELECT organisation, COUNT\\(\\*\\) AS count FROM `mitgliedschaft`").
+		WillReturnRows(sqlmock.NewRows([]string{"organisation", "count"}).AddRow(1, 1))
+	mvdsb.ExpectQuery("SELECT p\\.\\*, m\\.organisation, m\\.spielernummer FROM mitgliedschaft m").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "uuid", "name", "vorname", "status", "organisation", "spielernummer"}).
+			AddRow(1, "uuid-1", "Müller", "Hans", 0, 1, 14))
+	mvdsb.ExpectQuery("SELECT m\\.\\*, o\\.name AS club_name, o\\.vkz AS club_vkz, p\\.uuid AS person_uuid FROM mitgliedschaft m").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "person", "organisation", "spielernummer", "von", "club_name", "club_vkz", "person_uuid"}).
+			AddRow(7, 1, 1, 14, joined, "Post-SV Ulm", "C0101", "uuid-1"))
+	mvdsb.ExpectCommit()
+
+	bdw.ExpectBegin()
+	bdw.ExpectQuery("SELECT organisation, AVG\\(latest_dwz\\)").
+		WillReturnRows(sqlmock.NewRows([]string{"organisation", "avg_dwz"}).AddRow(1, 1850.0))
+	bdw.ExpectQuery("SELECT idPerson, dwzNew, dwzNewIndex FROM `evaluation`").
+		WillReturnRows(sqlmock.NewRows([]string{"idPerson", "dwzNew", "dwzNewIndex"}).AddRow(1, 1850, 12))
+	bdw.ExpectQuery("SELECT e\\.\\*, tm\\.tname, tm\\.tcode, tm\\.finishedOn, tm\\.computedOn FROM evaluation e").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "idMaster", "idPerson", "dwzNew", "tname", "tcode", "finishedOn", "computedOn"}).
+			AddRow(10, 5, 1, 1850, "Ulmer Sommer-Open", "C529-K00-HT1", finished, finished))
+	bdw.ExpectCommit()
+}
+
+// sha256File returns the SHA-256 checksums of a gzip file and of its uncompressed content
+func sha256File(t *testing.T, path string) (string, string, int64) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	fileSum := sha256.Sum256(data)
+
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	gz, err := gzip.NewReader(f)
+	require.NoError(t, err)
+	content := sha256.New()
+	_, err = io.Copy(content, gz)
+	require.NoError(t, err)
+
+	return hex.EncodeToString(fileSum[:]), hex.EncodeToString(content.Sum(nil)), int64(len(data))
+}
+
+func TestExportService_Generate(t *testing.T) {
+	mvdsb, mvdsbMock := newMockGormDB(t)
+	bdw, bdwMock := newMockGormDB(t)
+	expectExportSnapshot(mvdsbMock, bdwMock)
+
+	dir := t.TempDir()
+	// Older generations beyond Keep are pruned, the temporary directories of interrupted ones removed
+	for _, old := range []string{"20240101T000000Z", "20250101T000000Z"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, old), 0755))
+	}
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, ".20250601T000000Z.tmp"), 0755))
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	service := services.NewExportService(&config.ExportConfig{Directory: dir, Keep: 2},
+		&database.Databases{MVDSB: mvdsb, Portal64BDW: bdw}, log)
+	require.NoError(t, service.Start())
+	imported := time.Date(2026, 10, 18, 3, 0, 0, 0, time.UTC)
+	service.SetLastImportTime(func() (time.Time, bool) { return imported, true })
+
+	manifest, err := service.Generate(context.Background())
+	require.NoError(t, err)
+	assert.NoError(t, mvdsbMock.ExpectationsWereMet())
+	assert.NoError(t, bdwMock.ExpectationsWereMet())
+	assert.Equal(t, manifest, service.Manifest())
+	assert.Equal(t, &imported, manifest.ImportedAt)
+
+	// Every dataset is written in every format and the manifest describes the files on disk
+	require.Len(t, manifest.Files, 8)
+	rows := make(map[string]int64)
+	for _, file := range manifest.Files {
+		fileSum, contentSum, size := sha256File(t, filepath.Join(dir, manifest.Generation, file.Name))
+		assert.Equal(t, file.SHA256, fileSum, file.Name)
+		assert.Equal(t, file.ContentSHA256, contentSum, file.Name)
+		assert.Equal(t, file.Size, size, file.Name)
+		rows[file.Dataset+"."+file.Format] = file.Rows
+	}
+	for _, dataset := range []string{models.ExportDatasetClubs, models.ExportDatasetPlayers, models.ExportDatasetMemberships, models.ExportDatasetEvaluations} {
+		assert.Equal(t, int64(1), rows[dataset+"."+models.ExportFormatNDJSON], dataset)
+		assert.Equal(t, int64(1), rows[dataset+"."+models.ExportFormatCSV], dataset)
+	}
+
+	stored, err := service.GetManifest(manifest.Generation)
+	require.NoError(t, err)
+	assert.Equal(t, manifest.Files, stored.Files)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{"20250101T000000Z", manifest.Generation}, names)
+}
+
+func TestExportService_GenerateFailureKeepsCurrent(t *testing.T) {
+	mvdsb, mvdsbMock := newMockGormDB(t)
+	bdw, bdwMock := newMockGormDB(t)
+	mvdsbMock.ExpectBegin()
+	bdwMock.ExpectBegin()
+	mvdsbMock.ExpectQuery("SELECT \\* FROM `organisation`").WillReturnError(sql.ErrConnDone)
+	bdwMock.ExpectRollback()
+	mvdsbMock.ExpectRollback()
+
+	dir := t.TempDir()
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	service := services.NewExportService(&config.ExportConfig{Directory: dir, Keep: 2},
+		&database.Databases{MVDSB: mvdsb, Portal64BDW: bdw}, log)
+	require.NoError(t, service.Start())
+
+	_, err := service.Generate(context.Background())
+	assert.Error(t, err)
+	assert.Nil(t, service.Manifest())
+	assert.False(t, service.IsRunning())
+
+	// The temporary directory of the failed generation is removed
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
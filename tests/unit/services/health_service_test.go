@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"portal64api/internal/cache"
+	"portal64api/internal/config"
+	"portal64api/internal/models"
+	"portal64api/internal/services"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthService_Live(t *testing.T) {
+	healthService := services.NewHealthService(nil, nil, nil, nil)
+
+	live := healthService.Live()
+	assert.Equal(t, models.HealthStatusHealthy, live.Status)
+	assert.NotEmpty(t, live.Build.Version)
+	assert.GreaterOrEqual(t, live.UptimeSeconds, int64(0))
+}
+
+func TestHealthService_ReadyWithoutDatabases(t *testing.T) {
+	healthService := services.NewHealthService(nil, cache.NewMockCacheService(true), nil, nil)
+
+	ready := healthService.Ready(context.Background())
+	assert.False(t, ready.Ready)
+	assert.Equal(t, models.HealthStatusUnhealthy, ready.Status)
+
+	for _, name := range []string{services.ComponentMVDSB, services.ComponentPortal64BDW} {
+		component := ready.Components[name]
+		assert.Equal(t, models.ComponentStatusDown, component.Status, name)
+		assert.True(t, component.Critical, name)
+		assert.NotEmpty(t, component.Message, name)
+	}
+	assert.Equal(t, models.ComponentStatusUp, ready.Components[services.ComponentCache].Status)
+	assert.Equal(t, models.ComponentStatusDisabled, ready.Components[services.ComponentImport].Status)
+	assert.Equal(t, models.ComponentStatusDisabled, ready.Components[services.ComponentKaderPlanung].Status)
+}
+
+func TestHealthService_ReadyOptionalComponents(t *testing.T) {
+	binary := filepath.Join(t.TempDir(), "kader-planung")
+	require.NoError(t, os.WriteFile(binary, []byte("#!/bin/sh\n"), 0755))
+
+	kaderPlanungConfig := &config.KaderPlanungConfig{Enabled: true, BinaryPath: binary}
+	kaderPlanungService := services.NewKaderPlanungService(kaderPlanungConfig, logrus.New())
+
+	healthService := services.NewHealthService(nil, cache.NewMockCacheService(false), nil, kaderPlanungService)
+	ready := healthService.Ready(context.Background())
+	assert.Equal(t, models.ComponentStatusDisabled, ready.Components[services.ComponentCache].Status)
+	assert.Equal(t, models.ComponentStatusUp, ready.Components[services.ComponentKaderPlanung].Status)
+	assert.False(t, ready.Components[services.ComponentKaderPlanung].Critical)
+
+	kaderPlanungConfig.BinaryPath = filepath.Join(t.TempDir(), "missing")
+	ready = healthService.Ready(context.Background())
+	assert.Equal(t, models.ComponentStatusDown, ready.Components[services.ComponentKaderPlanung].Status)
+	assert.Contains(t, ready.Components[services.ComponentKaderPlanung].Message, "not available")
+}
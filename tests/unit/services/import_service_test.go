@@ -4,6 +4,7 @@ import (
 	"context"
 	"log"
 	"os"
+	"path/filepath"
 	"portal64api/internal/cache"
 	"portal64api/internal/config"
 	"portal64api/internal/services"
@@ -633,6 +634,32 @@ func TestImportService_GetLogs(t *testing.T) {
 	// For this test, we verify that the GetLogs method works and returns the expected structure
 }
 
+func TestImportService_GetLastImportTime(t *testing.T) {
+	metadataFile := filepath.Join(t.TempDir(), "metadata.json")
+	importConfig := &config.ImportConfig{
+		Storage: config.StorageConfig{
+			TempDir:      t.TempDir(),
+			MetadataFile: metadataFile,
+		},
+	}
+
+	logger := log.New(os.Stdout, "TEST: ", log.LstdFlags)
+	service := services.NewImportService(importConfig, &config.DatabaseConfig{}, new(MockCacheService), logger)
+	require.NotNil(t, service)
+
+	// An unreadable record is read again with the next call
+	require.NoError(t, os.WriteFile(metadataFile, []byte("{"), 0644))
+	_, ok := service.GetLastImportTime()
+	assert.False(t, ok)
+
+	lastImport := time.Date(2026, 10, 1, 3, 0, 0, 0, time.UTC)
+	require.NoError(t, os.WriteFile(metadataFile,
+		[]byte(`{"last_import": {"timestamp": "2026-10-01T03:00:00Z", "success": true}}`), 0644))
+	got, ok := service.GetLastImportTime()
+	assert.True(t, ok)
+	assert.True(t, lastImport.Equal(got))
+}
+
 func TestImportService_LoadDetection(t *testing.T) {
 	tests := []struct {
 		name           string
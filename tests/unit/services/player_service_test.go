@@ -20,11 +20,17 @@ import (
 // MockPlayerRepository is a mock implementation of PlayerRepositoryInterface
 type MockPlayerRepository struct {
 	mock.Mock
+	ctx context.Context // Context of the last WithContext call
 }
 
 // Ensure MockPlayerRepository implements PlayerRepositoryInterface
 var _ interfaces.PlayerRepositoryInterface = (*MockPlayerRepository)(nil)
 
+func (m *MockPlayerRepository) WithContext(ctx context.Context) interfaces.PlayerRepositoryInterface {
+	m.ctx = ctx
+	return m
+}
+
 func (m *MockPlayerRepository) GetPlayerByID(vkz string, personID uint) (*models.Person, *models.Organisation, *models.Evaluation, error) {
 	args := m.Called(vkz, personID)
 	
@@ -71,15 +77,46 @@ func (m *MockPlayerRepository) GetPlayerCurrentMembership(personID uint) (*model
 	return args.Get(0).(*models.Mitgliedschaft), args.Error(1)
 }
 
+func (m *MockPlayerRepository) GetPlayerMemberships(personID uint) ([]repositories.MembershipWithOrganisation, error) {
+	args := m.Called(personID)
+	return args.Get(0).([]repositories.MembershipWithOrganisation), args.Error(1)
+}
+
+func (m *MockPlayerRepository) GetPlayersByIDs(keys []repositories.PlayerKey) (map[repositories.PlayerKey]repositories.PlayerRecord, error) {
+	args := m.Called(keys)
+	return args.Get(0).(map[repositories.PlayerKey]repositories.PlayerRecord), args.Error(1)
+}
+
+func (m *MockPlayerRepository) GetPlayersRatingHistories(personIDs []uint) (map[uint][]repositories.EvaluationWithTournament, error) {
+	args := m.Called(personIDs)
+	return args.Get(0).(map[uint][]repositories.EvaluationWithTournament), args.Error(1)
+}
+
+func (m *MockPlayerRepository) GetPlayersMemberships(personIDs []uint) (map[uint][]repositories.MembershipWithOrganisation, error) {
+	args := m.Called(personIDs)
+	return args.Get(0).(map[uint][]repositories.MembershipWithOrganisation), args.Error(1)
+}
+
+func (m *MockPlayerRepository) GetPlayersByClubs(vkzs []string) (map[repositories.PlayerKey]repositories.PlayerRecord, error) {
+	args := m.Called(vkzs)
+	return args.Get(0).(map[repositories.PlayerKey]repositories.PlayerRecord), args.Error(1)
+}
+
 // MockClubRepository is a mock implementation of ClubRepository
 // MockClubRepository is a mock implementation of ClubRepositoryInterface
 type MockClubRepository struct {
 	mock.Mock
+	ctx context.Context // Context of the last WithContext call
 }
 
 // Ensure MockClubRepository implements ClubRepositoryInterface
 var _ interfaces.ClubRepositoryInterface = (*MockClubRepository)(nil)
 
+func (m *MockClubRepository) WithContext(ctx context.Context) interfaces.ClubRepositoryInterface {
+	m.ctx = ctx
+	return m
+}
+
 func (m *MockClubRepository) GetClubByVKZ(vkz string) (*models.Organisation, error) {
 	args := m.Called(vkz)
 	return args.Get(0).(*models.Organisation), args.Error(1)
@@ -105,14 +142,30 @@ func (m *MockClubRepository) GetAllClubs() ([]models.Organisation, error) {
 	return args.Get(0).([]models.Organisation), args.Error(1)
 }
 
+func (m *MockClubRepository) StreamAllClubs(fn func(models.Organisation) error) error {
+	args := m.Called()
+	for _, club := range args.Get(0).([]models.Organisation) {
+		if err := fn(club); err != nil {
+			return err
+		}
+	}
+	return args.Error(1)
+}
+
 // MockTournamentRepository is a mock implementation of TournamentRepositoryInterface
 type MockTournamentRepository struct {
 	mock.Mock
+	ctx context.Context // Context of the last WithContext call
 }
 
 // Ensure MockTournamentRepository implements TournamentRepositoryInterface
 var _ interfaces.TournamentRepositoryInterface = (*MockTournamentRepository)(nil)
 
+func (m *MockTournamentRepository) WithContext(ctx context.Context) interfaces.TournamentRepositoryInterface {
+	m.ctx = ctx
+	return m
+}
+
 func (m *MockTournamentRepository) GetTournamentByID(tournamentID string) (*models.Tournament, error) {
 	args := m.Called(tournamentID)
 	if args.Get(0) == nil {
@@ -121,8 +174,8 @@ func (m *MockTournamentRepository) GetTournamentByID(tournamentID string) (*mode
 	return args.Get(0).(*models.Tournament), args.Error(1)
 }
 
-func (m *MockTournamentRepository) SearchTournaments(req models.SearchRequest) ([]models.Tournament, int64, error) {
-	args := m.Called(req)
+func (m *MockTournamentRepository) SearchTournaments(req models.SearchRequest, filter models.TournamentSearchFilter) ([]models.Tournament, int64, error) {
+	args := m.Called(req, filter)
 	return args.Get(0).([]models.Tournament), args.Get(1).(int64), args.Error(2)
 }
 
@@ -156,6 +209,50 @@ func (m *MockCacheServiceForPlayer) Ping(ctx context.Context) error { return nil
 func (m *MockCacheServiceForPlayer) GetStats() cache.CacheStats { return cache.CacheStats{} }
 func (m *MockCacheServiceForPlayer) Close() error { return nil }
 
+func TestPlayerService_WithContext(t *testing.T) {
+	type requestKey struct{}
+	mockPlayerRepo := new(MockPlayerRepository)
+	mockClubRepo := new(MockClubRepository)
+	mockTournamentRepo := new(MockTournamentRepository)
+	service := services.NewPlayerService(mockPlayerRepo, mockClubRepo, mockTournamentRepo, &MockCacheServiceForPlayer{})
+
+	// Any repository implementation receives the request context, not only the MySQL ones
+	service.WithContext(context.WithValue(context.Background(), requestKey{}, "req-1"))
+	for _, ctx := range []context.Context{mockPlayerRepo.ctx, mockClubRepo.ctx, mockTournamentRepo.ctx} {
+		if assert.NotNil(t, ctx) {
+			assert.Equal(t, "req-1", ctx.Value(requestKey{}))
+		}
+	}
+}
+
+func TestPlayerService_GetClubsPlayers(t *testing.T) {
+	mockPlayerRepo := new(MockPlayerRepository)
+	service := services.NewPlayerService(mockPlayerRepo, new(MockClubRepository), new(MockTournamentRepository), &MockCacheServiceForPlayer{})
+
+	club := models.Organisation{ID: 1, VKZ: "C0101", Name: "Post-SV Ulm"}
+	mockPlayerRepo.On("GetPlayersByClubs", []string{"c0101", "C0102"}).Return(map[repositories.PlayerKey]repositories.PlayerRecord{
+		{VKZ: "C0101", Spielernummer: 15}: {Person: models.Person{ID: 2, Name: "Schmidt"}, Organisation: club},
+		{VKZ: "C0101", Spielernummer: 14}: {Person: models.Person{ID: 1, Name: "Müller"}, Organisation: club, Evaluation: &models.Evaluation{DWZNew: 1850}},
+	}, nil)
+
+	results, err := service.GetClubsPlayers([]string{"c0101", "C0102", "C01-01"})
+	assert.NoError(t, err)
+	if assert.Len(t, results, 3) {
+		// Highest DWZ first, the club is matched case-insensitively
+		if assert.Len(t, results[0].Data, 2) {
+			assert.Equal(t, "C0101-014", results[0].Data[0].ID)
+			assert.Equal(t, 1850, results[0].Data[0].CurrentDWZ)
+			assert.Equal(t, "C0101-015", results[0].Data[1].ID)
+		}
+		assert.Empty(t, results[1].Data)
+		assert.Nil(t, results[1].Error)
+		if assert.NotNil(t, results[2].Error) {
+			assert.Equal(t, "INVALID_CLUB_ID", results[2].Error.ErrorCode)
+		}
+	}
+	mockPlayerRepo.AssertExpectations(t)
+}
+
 func TestPlayerService_GetPlayerByID(t *testing.T) {
 	// Setup
 	mockPlayerRepo := new(MockPlayerRepository)
@@ -0,0 +1,102 @@
+package services
+
+import (
+	"testing"
+
+	"portal64api/internal/models"
+	"portal64api/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intValue(value int) *int {
+	return &value
+}
+
+func performanceFixture() *models.EnhancedTournamentResponse {
+	participant := func(personID uint, no int, dwz int) models.ParticipantInfo {
+		return models.ParticipantInfo{
+			PersonID: personID,
+			No:       no,
+			FullName: "Spieler " + string(rune('A'+no-1)),
+			Rating:   &models.RatingInfo{DWZOld: intValue(dwz)},
+		}
+	}
+	game := func(white, black uint, whitePoints float64) models.GameInfo {
+		return models.GameInfo{
+			White:       models.PlayerRef{ID: white},
+			Black:       models.PlayerRef{ID: black},
+			Result:      "x",
+			WhitePoints: whitePoints,
+			BlackPoints: 1 - whitePoints,
+		}
+	}
+
+	return &models.EnhancedTournamentResponse{
+		Participants: []models.ParticipantInfo{
+			participant(1, 1, 2300),
+			participant(2, 2, 2200),
+			participant(3, 3, 2100),
+		},
+		Games: []models.RoundInfo{
+			{Round: 1, Games: []models.GameInfo{game(1, 2, 1)}},
+			{Round: 2, Games: []models.GameInfo{game(3, 1, 0.5)}},
+			{Round: 3, Games: []models.GameInfo{game(2, 3, 0)}},
+			// Bye without opponent is ignored
+			{Round: 4, Games: []models.GameInfo{{White: models.PlayerRef{ID: 1}, WhitePoints: 1, Result: "+"}}},
+		},
+		Evaluations: []models.EvaluationInfo{
+			{PersonID: 1, DWZOld: 2300, DWZNew: 2305},
+			{PersonID: 2, DWZOld: 2200, DWZNew: 2180},
+			{PersonID: 3, DWZOld: 2100, DWZNew: 2115},
+		},
+	}
+}
+
+func TestCalculateTournamentPerformance(t *testing.T) {
+	performance := services.CalculateTournamentPerformance(performanceFixture())
+	require.NotNil(t, performance)
+	require.Len(t, performance.Players, 3)
+
+	assert.Equal(t, 2200, performance.RatingAverage)
+	assert.Equal(t, 0, performance.Category)
+
+	top := performance.Players[0]
+	assert.Equal(t, 2, top.Games)
+	assert.Equal(t, 1.5, top.Points)
+	require.NotNil(t, top.AverageOpponentRating)
+	assert.Equal(t, 2150, *top.AverageOpponentRating)
+	require.NotNil(t, top.Performance)
+	assert.Equal(t, 2341, *top.Performance) // 2150 + 400*log10(3)
+	require.NotNil(t, top.ExpectedScore)
+	assert.Equal(t, 1.4, *top.ExpectedScore)
+	require.NotNil(t, top.DWZDelta)
+	assert.Equal(t, 5, *top.DWZDelta)
+
+	// Zero score is capped at -800
+	winless := performance.Players[1]
+	assert.Equal(t, 0.0, winless.Points)
+	require.NotNil(t, winless.Performance)
+	assert.Equal(t, 2200-800, *winless.Performance)
+
+	require.Len(t, performance.TopGainers, 2)
+	assert.Equal(t, uint(3), performance.TopGainers[0].PersonID)
+	require.Len(t, performance.TopLosers, 1)
+	assert.Equal(t, uint(2), performance.TopLosers[0].PersonID)
+}
+
+func TestCalculateTournamentPerformanceCategory(t *testing.T) {
+	tournament := performanceFixture()
+	tournament.Evaluations = nil
+	for i := range tournament.Participants {
+		*tournament.Participants[i].Rating.DWZOld += 300
+	}
+
+	performance := services.CalculateTournamentPerformance(tournament)
+
+	assert.Equal(t, 2500, performance.RatingAverage)
+	assert.Equal(t, 10, performance.Category)
+	assert.Empty(t, performance.TopGainers)
+	assert.Nil(t, performance.Players[0].DWZDelta)
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"testing"
+
+	"portal64api/internal/models"
+	"portal64api/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func teamParticipant(personID uint, vkz, club string) models.ParticipantInfo {
+	return models.ParticipantInfo{
+		PersonID: personID,
+		Club:     &models.ClubInfo{VKZ: vkz, Name: club},
+	}
+}
+
+func teamGame(board int, white, black uint, whitePoints float64) models.GameInfo {
+	return models.GameInfo{
+		Board:       board,
+		White:       models.PlayerRef{ID: white},
+		Black:       models.PlayerRef{ID: black},
+		WhitePoints: whitePoints,
+		BlackPoints: 1 - whitePoints,
+		Result:      "x",
+	}
+}
+
+func TestBuildTeamMatches(t *testing.T) {
+	// Ulm (1, 2), Neu-Ulm (3, 4), Blaubeuren (5, 6)
+	tournament := &models.EnhancedTournamentResponse{
+		ID:              "C531-K01-ML1",
+		Name:            "Kreisliga",
+		TeamCompetition: &models.TeamCompetitionInfo{Boards: 3},
+		Participants: []models.ParticipantInfo{
+			teamParticipant(1, "C0101", "SF Ulm"),
+			teamParticipant(2, "C0101", "SF Ulm"),
+			teamParticipant(3, "C0102", "SC Neu-Ulm"),
+			teamParticipant(4, "C0102", "SC Neu-Ulm"),
+			teamParticipant(5, "C0103", "SV Blaubeuren"),
+			teamParticipant(6, "C0103", "SV Blaubeuren"),
+			teamParticipant(7, "C0102", "SC Neu-Ulm"),
+		},
+		Games: []models.RoundInfo{
+			{
+				Round: 1,
+				Games: []models.GameInfo{
+					teamGame(2, 4, 2, 0.5), // Neu-Ulm white on board 2 -> home
+					teamGame(1, 1, 3, 1),
+				},
+			},
+			{
+				Round: 2,
+				Games: []models.GameInfo{
+					teamGame(1, 3, 5, 0.5), // Neu-Ulm white on board 1 -> away
+					teamGame(2, 6, 4, 0.5),
+					// Forfeit: Blaubeuren did not field board 3
+					{Board: 3, White: models.PlayerRef{ID: 7}, Black: models.PlayerRef{ID: 0}, WhitePoints: 1, Result: "+:-"},
+				},
+			},
+		},
+	}
+
+	response := services.BuildTeamMatches(tournament)
+	require.NotNil(t, response)
+	require.Len(t, response.Rounds, 2)
+
+	round1 := response.Rounds[0].Matches
+	require.Len(t, round1, 1)
+	assert.Equal(t, "C0102", round1[0].Home.ClubID)
+	assert.Equal(t, "C0101", round1[0].Away.ClubID)
+	assert.Equal(t, 0.5, round1[0].HomeBoardPoints)
+	assert.Equal(t, 1.5, round1[0].AwayBoardPoints)
+	assert.Equal(t, 0, round1[0].HomeMatchPoints)
+	assert.Equal(t, 2, round1[0].AwayMatchPoints)
+	assert.Equal(t, 1, round1[0].Boards[0].Board)
+
+	round2 := response.Rounds[1].Matches
+	require.Len(t, round2, 1)
+	assert.Len(t, round2[0].Boards, 3)
+	assert.Equal(t, "C0103", round2[0].Home.ClubID)
+	assert.Equal(t, "C0102", round2[0].Away.ClubID)
+	assert.Equal(t, 1.0, round2[0].HomeBoardPoints)
+	assert.Equal(t, 2.0, round2[0].AwayBoardPoints)
+
+	// Equal match points are ranked by board points
+	require.Len(t, response.Table, 3)
+	assert.Equal(t, "C0102", response.Table[0].Team.ClubID)
+	assert.Equal(t, 1, response.Table[0].Rank)
+	assert.Equal(t, 2, response.Table[0].MatchPoints)
+	assert.Equal(t, 2.5, response.Table[0].BoardPoints)
+	assert.Equal(t, 1, response.Table[0].Wins)
+	assert.Equal(t, 1, response.Table[0].Losses)
+	assert.Equal(t, "C0101", response.Table[1].Team.ClubID)
+	assert.Equal(t, 2, response.Table[1].Rank)
+	assert.Equal(t, 1.5, response.Table[1].BoardPoints)
+	assert.Equal(t, "C0103", response.Table[2].Team.ClubID)
+	assert.Equal(t, 0, response.Table[2].MatchPoints)
+}
+
+func TestBuildTeamMatchesSeveralTeamsOfOneClub(t *testing.T) {
+	// Ulm (1, 2), Ulm 2 (3, 4), Neu-Ulm (5, 6), Blaubeuren (7, 8)
+	participants := []models.ParticipantInfo{
+		teamParticipant(1, "C0101", "SF Ulm"),
+		teamParticipant(2, "C0101", "SF Ulm"),
+		teamParticipant(3, "C0101", "SF Ulm"),
+		teamParticipant(4, "C0101", "SF Ulm"),
+		teamParticipant(5, "C0102", "SC Neu-Ulm"),
+		teamParticipant(6, "C0102", "SC Neu-Ulm"),
+		teamParticipant(7, "C0103", "SV Blaubeuren"),
+		teamParticipant(8, "C0103", "SV Blaubeuren"),
+	}
+	for i := range participants {
+		participants[i].No = i + 1
+	}
+
+	tournament := &models.EnhancedTournamentResponse{
+		Participants:    participants,
+		TeamCompetition: &models.TeamCompetitionInfo{Boards: 2},
+		Games: []models.RoundInfo{
+			{
+				Round: 1,
+				Games: []models.GameInfo{
+					// Ulm - Ulm 2
+					teamGame(1, 3, 1, 0),
+					teamGame(2, 2, 4, 1),
+					// Blaubeuren - Neu-Ulm
+					teamGame(1, 5, 7, 0.5),
+					teamGame(2, 8, 6, 0.5),
+				},
+			},
+			{
+				Round: 2,
+				Games: []models.GameInfo{
+					// Ulm - Neu-Ulm
+					teamGame(1, 5, 1, 0.5),
+					teamGame(2, 2, 6, 0.5),
+					// Ulm 2 - Blaubeuren
+					teamGame(1, 7, 3, 0),
+					teamGame(2, 4, 8, 1),
+				},
+			},
+		},
+	}
+
+	response := services.BuildTeamMatches(tournament)
+	require.NotNil(t, response)
+
+	round1 := response.Rounds[0].Matches
+	require.Len(t, round1, 2)
+	assert.Equal(t, models.TeamInfo{ID: "C0101-1", ClubID: "C0101", Name: "SF Ulm"}, round1[0].Home)
+	assert.Equal(t, models.TeamInfo{ID: "C0101-2", ClubID: "C0101", Name: "SF Ulm 2"}, round1[0].Away)
+	assert.Equal(t, 2.0, round1[0].HomeBoardPoints)
+
+	round2 := response.Rounds[1].Matches
+	require.Len(t, round2, 2)
+	assert.Equal(t, "C0101-1", round2[0].Home.ID)
+	assert.Equal(t, "C0102-1", round2[0].Away.ID)
+	assert.Equal(t, "C0101-2", round2[1].Home.ID)
+	assert.Equal(t, "C0103-1", round2[1].Away.ID)
+
+	require.Len(t, response.Table, 4)
+	assert.Equal(t, "C0101-1", response.Table[0].Team.ID)
+	assert.Equal(t, 3, response.Table[0].MatchPoints)
+	assert.Equal(t, "C0102-1", response.Table[1].Team.ID)
+	assert.Equal(t, 2, response.Table[1].Rank)
+	assert.Equal(t, "C0101-2", response.Table[2].Team.ID)
+	assert.Equal(t, 2, response.Table[2].Rank)
+	assert.Equal(t, "C0103-1", response.Table[3].Team.ID)
+	assert.Equal(t, 4, response.Table[3].Rank)
+}
+
+func TestBuildTeamMatchesIndividualTournament(t *testing.T) {
+	// Swiss pairings between players of the same clubs look like a match on boards 1 and 2
+	tournament := &models.EnhancedTournamentResponse{
+		Participants: []models.ParticipantInfo{
+			teamParticipant(1, "C0101", "SF Ulm"),
+			teamParticipant(2, "C0102", "SC Neu-Ulm"),
+			teamParticipant(3, "C0101", "SF Ulm"),
+			teamParticipant(4, "C0102", "SC Neu-Ulm"),
+		},
+		Games: []models.RoundInfo{
+			{Round: 1, Games: []models.GameInfo{teamGame(1, 1, 2, 1), teamGame(2, 4, 3, 0)}},
+		},
+	}
+
+	assert.Nil(t, services.BuildTeamMatches(tournament))
+}
+
+func TestBuildTeamMatchesSameClubsTwice(t *testing.T) {
+	// Ulm (1, 2) - Neu-Ulm (5, 6) and Ulm 2 (3, 4) - Neu-Ulm 2 (7, 8) in the same round
+	participants := []models.ParticipantInfo{
+		teamParticipant(1, "C0101", "SF Ulm"),
+		teamParticipant(2, "C0101", "SF Ulm"),
+		teamParticipant(3, "C0101", "SF Ulm"),
+		teamParticipant(4, "C0101", "SF Ulm"),
+		teamParticipant(5, "C0102", "SC Neu-Ulm"),
+		teamParticipant(6, "C0102", "SC Neu-Ulm"),
+		teamParticipant(7, "C0102", "SC Neu-Ulm"),
+		teamParticipant(8, "C0102", "SC Neu-Ulm"),
+	}
+	for i := range participants {
+		participants[i].No = i + 1
+	}
+
+	tests := []struct {
+		name  string
+		games []models.GameInfo
+	}{
+		{
+			name: "boards numbered per match",
+			games: []models.GameInfo{
+				teamGame(1, 5, 1, 0),
+				teamGame(2, 2, 6, 1),
+				teamGame(1, 7, 3, 1),
+				teamGame(2, 4, 8, 0.5),
+			},
+		},
+		{
+			name: "boards numbered through the round",
+			games: []models.GameInfo{
+				teamGame(1, 5, 1, 0),
+				teamGame(2, 2, 6, 1),
+				teamGame(3, 7, 3, 1),
+				teamGame(4, 4, 8, 0.5),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response := services.BuildTeamMatches(&models.EnhancedTournamentResponse{
+				Participants:    participants,
+				TeamCompetition: &models.TeamCompetitionInfo{Boards: 2},
+				Games:           []models.RoundInfo{{Round: 1, Games: tt.games}},
+			})
+			require.NotNil(t, response)
+
+			matches := response.Rounds[0].Matches
+			require.Len(t, matches, 2)
+			assert.Len(t, matches[0].Boards, 2)
+			assert.Equal(t, "C0101-1", matches[0].Home.ID)
+			assert.Equal(t, "C0102-1", matches[0].Away.ID)
+			assert.Equal(t, 2.0, matches[0].HomeBoardPoints)
+			assert.Len(t, matches[1].Boards, 2)
+			assert.Equal(t, "C0101-2", matches[1].Home.ID)
+			assert.Equal(t, "C0102-2", matches[1].Away.ID)
+			assert.Equal(t, 0.5, matches[1].HomeBoardPoints)
+			assert.Equal(t, 1.5, matches[1].AwayBoardPoints)
+		})
+	}
+}
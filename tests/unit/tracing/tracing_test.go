@@ -0,0 +1,151 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"portal64api/internal/api/middleware"
+	"portal64api/internal/config"
+	"portal64api/internal/tracing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+const incomingTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// installRecorder sets up the propagator and a tracer provider recording spans in memory
+func installRecorder(t *testing.T) *tracetest.InMemoryExporter {
+	shutdown, err := tracing.Setup(config.TracingConfig{Enabled: false})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+	return exporter
+}
+
+func newTracingRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Tracing())
+	router.GET("/api/v1/players/:id", func(c *gin.Context) {
+		_, span := tracing.Start(c.Request.Context(), "PlayerService.GetPlayerByID")
+		span.End()
+		c.JSON(http.StatusOK, gin.H{"id": c.Param("id")})
+	})
+	router.GET("/api/v1/broken", func(c *gin.Context) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "broken"})
+	})
+	return router
+}
+
+func spanNamed(spans tracetest.SpanStubs, name string) *tracetest.SpanStub {
+	for i := range spans {
+		if spans[i].Name == name {
+			return &spans[i]
+		}
+	}
+	return nil
+}
+
+func attributeValue(attrs []attribute.KeyValue, key string) (attribute.Value, bool) {
+	for _, attr := range attrs {
+		if string(attr.Key) == key {
+			return attr.Value, true
+		}
+	}
+	return attribute.Value{}, false
+}
+
+func TestTracing_ServerSpanWithRouteAndChildren(t *testing.T) {
+	exporter := installRecorder(t)
+	router := newTracingRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/players/C0101-1014", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	spans := exporter.GetSpans()
+	server := spanNamed(spans, "GET /api/v1/players/:id")
+	require.NotNil(t, server)
+	child := spanNamed(spans, "PlayerService.GetPlayerByID")
+	require.NotNil(t, child)
+
+	assert.Equal(t, server.SpanContext.TraceID(), child.SpanContext.TraceID())
+	assert.Equal(t, server.SpanContext.SpanID(), child.Parent.SpanID())
+	assert.False(t, server.Parent.IsValid(), "a request without traceparent starts a new trace")
+
+	route, ok := attributeValue(server.Attributes, "http.route")
+	require.True(t, ok)
+	assert.Equal(t, "/api/v1/players/:id", route.AsString())
+	status, ok := attributeValue(server.Attributes, "http.response.status_code")
+	require.True(t, ok)
+	assert.Equal(t, int64(http.StatusOK), status.AsInt64())
+	requestID, ok := attributeValue(server.Attributes, "request.id")
+	require.True(t, ok)
+	assert.Equal(t, w.Header().Get("X-Request-ID"), requestID.AsString())
+}
+
+func TestTracing_ContinuesIncomingTraceParent(t *testing.T) {
+	exporter := installRecorder(t)
+	router := newTracingRouter()
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/players/C0101-1014", nil)
+	req.Header.Set("traceparent", incomingTraceParent)
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	server := spanNamed(exporter.GetSpans(), "GET /api/v1/players/:id")
+	require.NotNil(t, server)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", server.SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", server.Parent.SpanID().String())
+	assert.True(t, server.Parent.IsRemote())
+}
+
+func TestTracing_ServerErrorMarksSpan(t *testing.T) {
+	exporter := installRecorder(t)
+	router := newTracingRouter()
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/v1/broken", nil))
+
+	server := spanNamed(exporter.GetSpans(), "GET /api/v1/broken")
+	require.NotNil(t, server)
+	assert.Equal(t, codes.Error, server.Status.Code)
+}
+
+func TestTraceParent(t *testing.T) {
+	installRecorder(t)
+
+	assert.Empty(t, tracing.TraceParent(context.Background()))
+
+	ctx, span := tracing.Start(context.Background(), "KaderPlanung.run")
+	defer span.End()
+
+	traceParent := tracing.TraceParent(ctx)
+	parts := strings.Split(traceParent, "-")
+	require.Len(t, parts, 4)
+	assert.Equal(t, "00", parts[0])
+	assert.Equal(t, span.SpanContext().TraceID().String(), parts[1])
+	assert.Equal(t, span.SpanContext().SpanID().String(), parts[2])
+}
+
+func TestSetup_UnknownExporter(t *testing.T) {
+	_, err := tracing.Setup(config.TracingConfig{Enabled: true, Exporter: "zipkin", ServiceName: "portal64api"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"portal64api/internal/models"
+	"portal64api/pkg/errors"
+	"portal64api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func samplePlayers(n int) []models.PlayerResponse {
+	players := make([]models.PlayerResponse, n)
+	for i := range players {
+		players[i] = models.PlayerResponse{
+			ID:         utils.GeneratePlayerID("C0101", uint(i+1)),
+			Name:       "Müller",
+			Firstname:  "Anna",
+			ClubID:     "C0101",
+			CurrentDWZ: 1500 + i,
+		}
+	}
+	return players
+}
+
+func newFieldsContext(t *testing.T, target string) (*gin.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return c, w
+}
+
+func TestSelectFieldsResource(t *testing.T) {
+	selected, err := utils.SelectFields(&samplePlayers(1)[0], []string{"current_dwz", "id", "name"})
+	require.NoError(t, err)
+
+	encoded, err := json.Marshal(selected)
+	require.NoError(t, err)
+	assert.Equal(t, `{"current_dwz":1500,"id":"C0101-001","name":"Müller"}`, string(encoded))
+}
+
+func TestSelectFieldsPaginatedResponse(t *testing.T) {
+	response := struct {
+		Data []models.PlayerResponse `json:"data"`
+		Meta interface{}             `json:"meta"`
+	}{
+		Data: samplePlayers(2),
+		Meta: models.Meta{Total: 2, Limit: 20},
+	}
+
+	selected, err := utils.SelectFields(response, []string{"id", "current_dwz"})
+	require.NoError(t, err)
+
+	encoded, err := json.Marshal(selected)
+	require.NoError(t, err)
+	var decoded struct {
+		Data []map[string]interface{} `json:"data"`
+		Meta map[string]interface{}   `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.Len(t, decoded.Data, 2)
+	assert.Equal(t, map[string]interface{}{"id": "C0101-002", "current_dwz": float64(1501)}, decoded.Data[1])
+	assert.Equal(t, float64(2), decoded.Meta["total"])
+}
+
+func TestSelectFieldsKeepsIncluded(t *testing.T) {
+	player := models.PlayerWithIncludes{
+		PlayerResponse: samplePlayers(1)[0],
+		Included: &models.PlayerIncludes{
+			Club: &models.ClubResponse{ID: "C0101", Name: "SC Test"},
+		},
+	}
+
+	selected, err := utils.SelectFields(player, []string{"id"})
+	require.NoError(t, err)
+
+	encoded, err := json.Marshal(selected)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"id":"C0101-001","included":{"club":{"id":"C0101","name":"SC Test","short_name":"","region":"","district":"","founding_date":null,"member_count":0,"average_dwz":0,"status":""}}}`, string(encoded))
+}
+
+func TestSelectFieldsUnknownField(t *testing.T) {
+	_, err := utils.SelectFields(samplePlayers(1), []string{"id", "dwz"})
+	require.Error(t, err)
+	apiErr, ok := err.(errors.APIError)
+	require.True(t, ok)
+	assert.Equal(t, http.StatusBadRequest, apiErr.Code)
+	assert.Contains(t, apiErr.Message, "dwz")
+
+	// Empty lists are validated against the element type
+	_, err = utils.SelectFields([]models.PlayerResponse{}, []string{"dwz"})
+	assert.Error(t, err)
+
+	// Without fields the data is returned unchanged
+	players := samplePlayers(1)
+	selected, err := utils.SelectFields(players, nil)
+	require.NoError(t, err)
+	assert.Equal(t, players, selected)
+}
+
+func TestParseIncludes(t *testing.T) {
+	c, _ := newFieldsContext(t, "/api/v1/players/C0101-1?include=club,%20Rating_History,")
+	includes, err := utils.ParseIncludes(c, models.IncludeClub, models.IncludeRatingHistory)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"club", "rating_history"}, includes)
+	assert.True(t, utils.HasInclude(c, "rating_history"))
+
+	c, _ = newFieldsContext(t, "/api/v1/players/C0101-1?include=club,games")
+	_, err = utils.ParseIncludes(c, models.IncludeClub, models.IncludeRatingHistory)
+	require.Error(t, err)
+	assert.Contains(t, err.(errors.APIError).Message, "games")
+
+	c, _ = newFieldsContext(t, "/api/v1/players/C0101-1")
+	includes, err = utils.ParseIncludes(c, models.IncludeClub)
+	require.NoError(t, err)
+	assert.Empty(t, includes)
+}
+
+func TestHandleResponseFieldsCSV(t *testing.T) {
+	c, w := newFieldsContext(t, "/api/v1/clubs/C0101/players?format=csv&fields=name,id")
+	response := struct {
+		Data []models.PlayerResponse `json:"data"`
+		Meta interface{}             `json:"meta"`
+	}{Data: samplePlayers(2)}
+
+	utils.HandleResponse(c, response, "club_players.csv")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	assert.Equal(t, []string{"name;id", "Müller;C0101-001", "Müller;C0101-002"}, lines)
+}
+
+func TestHandleResponseEmbeddedCSV(t *testing.T) {
+	c, w := newFieldsContext(t, "/api/v1/players/C0101-001?format=csv")
+	player := models.PlayerWithIncludes{
+		PlayerResponse: samplePlayers(1)[0],
+		Included:       &models.PlayerIncludes{Memberships: []models.MembershipResponse{{ClubID: "C0101", Current: true}}},
+	}
+
+	utils.HandleResponse(c, player, "player.csv")
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.True(t, strings.HasPrefix(lines[0], "id;pkz;name;firstname;"), lines[0])
+	assert.True(t, strings.HasSuffix(lines[0], ";status;included"), lines[0])
+	assert.Contains(t, lines[1], `"club_id"`)
+}
+
+func TestHandleResponseUnknownField(t *testing.T) {
+	c, w := newFieldsContext(t, "/api/v1/players?fields=id,unknown")
+	utils.HandleResponse(c, samplePlayers(1), "players.csv")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "unknown")
+}
+
+func TestSendJSONResponseStreamsSelectedFields(t *testing.T) {
+	players := samplePlayers(utils.StreamMinItems + 1)
+	selected, err := utils.SelectFields(players, []string{"id", "current_dwz"})
+	require.NoError(t, err)
+	assert.True(t, utils.ShouldStreamJSON(selected))
+
+	c, w := newFieldsContext(t, "/api/v1/clubs/all")
+	utils.SendJSONResponse(c, http.StatusOK, selected)
+
+	expected, err := json.Marshal(models.Response{Success: true, Data: selected})
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), w.Body.String())
+}
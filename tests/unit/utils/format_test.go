@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"portal64api/internal/models"
+	"portal64api/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/xuri/excelize/v2"
+)
+
+type clubStatistics struct {
+	ClubID     string     `json:"club_id"`
+	AverageDWZ float64    `json:"average_dwz"`
+	Founded    *time.Time `json:"founded"`
+}
+
+func TestHandleResponseExcelCSV(t *testing.T) {
+	c, w := newFieldsContext(t, "/api/v1/clubs/C0101/profile?format=csv-excel")
+	utils.HandleResponse(c, []clubStatistics{{ClubID: "C0101", AverageDWZ: 1612.5}}, "club_profile.csv")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "\uFEFFclub_id;average_dwz;founded\r\nC0101;1612,5;\r\n", w.Body.String())
+}
+
+func TestHandleResponseNDJSON(t *testing.T) {
+	c, w := newFieldsContext(t, "/api/v1/clubs/C0101/players?format=ndjson&fields=id,current_dwz")
+	response := struct {
+		Data []models.PlayerResponse `json:"data"`
+		Meta interface{}             `json:"meta"`
+	}{Data: samplePlayers(2)}
+
+	utils.HandleResponse(c, response, "club_players.csv")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "club_players.ndjson")
+	assert.Equal(t, `{"id":"C0101-001","current_dwz":1500}`+"\n"+`{"id":"C0101-002","current_dwz":1501}`+"\n",
+		w.Body.String())
+}
+
+func TestHandleResponseXML(t *testing.T) {
+	c, w := newFieldsContext(t, "/api/v1/players/C0101-001")
+	c.Request.Header.Set("Accept", "application/xml")
+	player := samplePlayers(1)[0]
+	player.Name = "Müller & Söhne"
+
+	utils.HandleResponse(c, player, "player.csv")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/xml; charset=utf-8", w.Header().Get("Content-Type"))
+	body := w.Body.String()
+	assert.True(t, strings.HasPrefix(body, `<?xml version="1.0" encoding="UTF-8"?>`+"\n<response><success>true</success><data><id>C0101-001</id><pkz></pkz>"), body)
+	assert.Contains(t, body, "<name>Müller &amp; Söhne</name>")
+	assert.Contains(t, body, "<birth_year></birth_year>")
+}
+
+func TestJSONToXML(t *testing.T) {
+	document, err := utils.JSONToXML([]byte(`{"years":{"2024":[1,2.5]},"active":false}`))
+	require.NoError(t, err)
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>`+"\n"+
+		`<response><years><entry key="2024"><item>1</item><item>2.5</item></entry></years><active>false</active></response>`,
+		string(document))
+}
+
+func TestHandleResponseXLSX(t *testing.T) {
+	c, w := newFieldsContext(t, "/api/v1/clubs/C0101/players?format=xlsx&fields=name,current_dwz")
+	response := struct {
+		Data []models.PlayerResponse `json:"data"`
+		Meta interface{}             `json:"meta"`
+	}{Data: samplePlayers(2)}
+
+	utils.HandleResponse(c, response, "club_players.csv")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, utils.FormatXLSX.ContentType(), w.Header().Get("Content-Type"))
+	assert.Contains(t, w.Header().Get("Content-Disposition"), "club_players.xlsx")
+
+	file, err := excelize.OpenReader(bytes.NewReader(w.Body.Bytes()))
+	require.NoError(t, err)
+	defer file.Close()
+
+	assert.Equal(t, []string{"club_players"}, file.GetSheetList())
+	rows, err := file.GetRows("club_players")
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"name", "current_dwz"}, {"Müller", "1500"}, {"Müller", "1501"}}, rows)
+
+	cellType, err := file.GetCellType("club_players", "B2")
+	require.NoError(t, err)
+	assert.NotEqual(t, excelize.CellTypeInlineString, cellType)
+	assert.NotEqual(t, excelize.CellTypeSharedString, cellType)
+}
+
+func TestHandleResponseXLSXDates(t *testing.T) {
+	c, w := newFieldsContext(t, "/api/v1/clubs/C0101/profile?format=xlsx")
+	founded := time.Date(1920, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	utils.HandleResponse(c, clubStatistics{ClubID: "C0101", AverageDWZ: 1612.5, Founded: &founded}, "club_profile.csv")
+
+	file, err := excelize.OpenReader(bytes.NewReader(w.Body.Bytes()))
+	require.NoError(t, err)
+	defer file.Close()
+
+	value, err := file.GetCellValue("club_profile", "B2", excelize.Options{RawCellValue: true})
+	require.NoError(t, err)
+	assert.Equal(t, "1612.5", value)
+	value, err = file.GetCellValue("club_profile", "C2")
+	require.NoError(t, err)
+	assert.Equal(t, "03-01-20", value)
+}
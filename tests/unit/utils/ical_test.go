@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"portal64api/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildICalendar(t *testing.T) {
+	stamp := time.Date(2025, 7, 20, 12, 30, 0, 0, time.UTC)
+	events := []utils.ICalEvent{
+		{
+			UID:         "C529-K00-HT1@portal64",
+			Summary:     "Stadtmeisterschaft Ulm, Gruppe A; Senioren",
+			Description: "Code: C529-K00-HT1\nRunden: 7",
+			Location:    "SF Ulm",
+			URL:         "http://localhost:8080/api/v1/tournaments/C529-K00-HT1",
+			Start:       time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC),
+			End:         time.Date(2025, 7, 20, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	ics := utils.BuildICalendar("Portal64 Turniere", events, stamp)
+
+	assert.True(t, strings.HasPrefix(ics, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(ics, "END:VCALENDAR\r\n"))
+	assert.Contains(t, ics, "UID:C529-K00-HT1@portal64\r\n")
+	assert.Contains(t, ics, "DTSTAMP:20250720T123000Z\r\n")
+	assert.Contains(t, ics, "DTSTART;VALUE=DATE:20250714\r\n")
+	assert.Contains(t, ics, "DTEND;VALUE=DATE:20250721\r\n") // exclusive end
+	assert.Contains(t, ics, "SUMMARY:Stadtmeisterschaft Ulm\\, Gruppe A\\; Senioren\r\n")
+	assert.Contains(t, ics, "DESCRIPTION:Code: C529-K00-HT1\\nRunden: 7\r\n")
+	assert.Contains(t, ics, "URL:http://localhost:8080/api/v1/tournaments/C529-K00-HT1\r\n")
+}
+
+func TestBuildICalendarFoldsLongLines(t *testing.T) {
+	events := []utils.ICalEvent{
+		{
+			UID:     "long@portal64",
+			Summary: strings.Repeat("Schachturnier Württemberg ", 10),
+			Start:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	ics := utils.BuildICalendar("Test", events, time.Now())
+
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+	// Unfolding restores the original value
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	assert.Contains(t, unfolded, "SUMMARY:"+strings.Repeat("Schachturnier Württemberg ", 10))
+}
@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"portal64api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromTrustedProxy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	require.NoError(t, router.SetTrustedProxies([]string{"10.0.0.0/8"}))
+	router.GET("/", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"trusted": utils.FromTrustedProxy(c), "client": c.ClientIP()})
+	})
+
+	request := func(remoteAddr string) string {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		req.Header.Set("X-Forwarded-For", "198.51.100.1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Body.String()
+	}
+
+	assert.JSONEq(t, `{"trusted": true, "client": "198.51.100.1"}`, request("10.0.0.1:1234"))
+	assert.JSONEq(t, `{"trusted": false, "client": "192.0.2.1"}`, request("192.0.2.1:1234"))
+}
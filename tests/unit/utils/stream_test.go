@@ -0,0 +1,137 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"portal64api/internal/models"
+	"portal64api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type streamRound struct {
+	Round int      `json:"round"`
+	Games []string `json:"games,omitempty"`
+}
+
+type streamRoster struct {
+	ID        string               `json:"id"`
+	Note      string               `json:"note,omitempty"`
+	Updated   time.Time            `json:"updated"`
+	Club      *models.ClubResponse `json:"club"`
+	Members   []int                `json:"members"`
+	Rounds    []streamRound        `json:"rounds"`
+	Hidden    string               `json:"-"`
+	Untagged  bool
+	internal  int
+	Threshold float64 `json:"threshold,omitempty"`
+}
+
+func largeRosters() []streamRoster {
+	rosters := make([]streamRoster, utils.StreamMinItems+10)
+	for i := range rosters {
+		rosters[i] = streamRoster{
+			ID:       "C0101",
+			Updated:  time.Date(2026, 10, 18, 2, 15, 30, 0, time.UTC),
+			Members:  []int{i},
+			Rounds:   []streamRound{{Round: 1, Games: []string{"<a & b>"}}, {Round: 2}},
+			Hidden:   "secret",
+			Untagged: true,
+			internal: 1,
+		}
+	}
+	return rosters
+}
+
+func TestShouldStreamJSON(t *testing.T) {
+	rosters := largeRosters()
+	assert.True(t, utils.ShouldStreamJSON(rosters))
+	assert.True(t, utils.ShouldStreamJSON(&rosters))
+	assert.True(t, utils.ShouldStreamJSON(make([]models.ClubResponse, utils.StreamMinItems)))
+	assert.False(t, utils.ShouldStreamJSON(make([]models.ClubResponse, utils.StreamMinItems-1)))
+	assert.False(t, utils.ShouldStreamJSON(make([]byte, 10*utils.StreamMinItems)))
+	assert.False(t, utils.ShouldStreamJSON(nil))
+	// Only the top-level array is streamed
+	assert.False(t, utils.ShouldStreamJSON([][]int{make([]int, utils.StreamMinItems)}))
+	assert.False(t, utils.ShouldStreamJSON(map[string][]int{"ids": make([]int, utils.StreamMinItems)}))
+}
+
+func TestStreamJSONResponseMatchesEncodingJSON(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rosters := largeRosters()
+
+	for _, data := range []interface{}{rosters, &rosters, []int{1, 2}, []string{}} {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+
+		utils.StreamJSONResponse(c, http.StatusOK, data)
+
+		expected, err := json.Marshal(models.Response{Success: true, Data: data})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		assert.Equal(t, string(expected), w.Body.String())
+		assert.True(t, w.Flushed)
+	}
+}
+
+func TestSendJSONResponseStreamsLargeArrays(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clubs := make([]models.ClubResponse, utils.StreamMinItems)
+	for i := range clubs {
+		clubs[i] = models.ClubResponse{ID: "C0101", Name: "SC <Test>"}
+	}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	utils.SendJSONResponse(c, http.StatusOK, clubs)
+
+	expected, err := json.Marshal(models.Response{Success: true, Data: clubs})
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), w.Body.String())
+	assert.True(t, w.Flushed)
+}
+
+func TestStreamJSONRows(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	clubs := []models.ClubResponse{{ID: "C0101", Name: "SC <Test>"}, {ID: "C0102"}}
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	err := utils.StreamJSONRows(c, http.StatusOK, func(emit func(interface{}) error) error {
+		for _, club := range clubs {
+			if err := emit(club); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	expected, err := json.Marshal(models.Response{Success: true, Data: clubs})
+	require.NoError(t, err)
+	assert.Equal(t, string(expected), w.Body.String())
+	assert.True(t, w.Flushed)
+}
+
+func TestStreamJSONRowsReturnsErrorBeforeFirstElement(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	failure := errors.New("connection lost")
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	err := utils.StreamJSONRows(c, http.StatusOK, func(emit func(interface{}) error) error {
+		return failure
+	})
+
+	assert.Equal(t, failure, err)
+	assert.False(t, c.Writer.Written())
+	assert.Empty(t, w.Body.String())
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"portal64api/internal/models"
+	"portal64api/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type exportRow struct {
+	models.MembershipResponse
+	PersonUUID string `json:"person_uuid"`
+}
+
+func writeTableFile(t *testing.T, format utils.Format, rows []exportRow) (string, utils.TableFileInfo) {
+	path := filepath.Join(t.TempDir(), "memberships."+string(format)+".gz")
+	file, err := utils.CreateTableFile(path, format, exportRow{})
+	require.NoError(t, err)
+	for _, row := range rows {
+		require.NoError(t, file.Write(row))
+	}
+	info, err := file.Close()
+	require.NoError(t, err)
+	return path, info
+}
+
+func readGzip(t *testing.T, path string) ([]byte, []byte) {
+	compressed, err := os.ReadFile(path)
+	require.NoError(t, err)
+	gz, err := gzip.NewReader(bytes.NewReader(compressed))
+	require.NoError(t, err)
+	content, err := io.ReadAll(gz)
+	require.NoError(t, err)
+	return compressed, content
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestTableFileNDJSON(t *testing.T) {
+	rows := []exportRow{
+		{MembershipResponse: models.MembershipResponse{PlayerID: "C0101-1014", ClubID: "C0101", Club: "Post SV Ulm", Current: true}, PersonUUID: "a1"},
+		{MembershipResponse: models.MembershipResponse{PlayerID: "C0327-297", ClubID: "C0327", Club: "SF Ulm"}, PersonUUID: "a1"},
+	}
+	path, info := writeTableFile(t, utils.FormatNDJSON, rows)
+
+	compressed, content := readGzip(t, path)
+	assert.Equal(t, int64(2), info.Rows)
+	assert.Equal(t, int64(len(compressed)), info.Size)
+	assert.Equal(t, sha256Hex(compressed), info.SHA256)
+	assert.Equal(t, sha256Hex(content), info.ContentSHA256)
+
+	lines := bytes.Split(bytes.TrimSuffix(content, []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.Contains(t, string(lines[0]), `"player_id":"C0101-1014"`)
+	assert.Contains(t, string(lines[0]), `"person_uuid":"a1"`)
+}
+
+func TestTableFileCSV(t *testing.T) {
+	rows := []exportRow{
+		{MembershipResponse: models.MembershipResponse{PlayerID: "C0101-1014", ClubID: "C0101", Club: "Post SV Ulm", Current: true}, PersonUUID: "a1"},
+	}
+	path, info := writeTableFile(t, utils.FormatCSV, rows)
+
+	_, content := readGzip(t, path)
+	lines := bytes.Split(bytes.TrimSuffix(content, []byte("\n")), []byte("\n"))
+	require.Len(t, lines, 2)
+	assert.Contains(t, string(lines[0]), "player_id;club_id;club")
+	assert.Contains(t, string(lines[0]), ";person_uuid")
+	assert.Contains(t, string(lines[1]), "C0101-1014;C0101;Post SV Ulm")
+	assert.Equal(t, int64(1), info.Rows)
+}
+
+func TestTableFileEmptyCSVHasHeader(t *testing.T) {
+	path, info := writeTableFile(t, utils.FormatCSV, nil)
+
+	_, content := readGzip(t, path)
+	assert.Contains(t, string(content), "player_id;club_id")
+	assert.Equal(t, int64(0), info.Rows)
+}
+
+func TestTableFileRejectsOtherFormats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "clubs.xlsx.gz")
+	_, err := utils.CreateTableFile(path, utils.FormatXLSX, exportRow{})
+	assert.Error(t, err)
+	assert.NoFileExists(t, path)
+}
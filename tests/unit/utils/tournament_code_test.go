@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"portal64api/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeTournamentCode(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        string
+		year        int
+		week        int
+		startDate   time.Time
+		subCode     string
+		typeAcronym string
+	}{
+		{
+			name:        "2020s code",
+			code:        "C529-K00-HT1",
+			year:        2025,
+			week:        29,
+			startDate:   time.Date(2025, 7, 14, 0, 0, 0, 0, time.UTC),
+			subCode:     "K00",
+			typeAcronym: "HT1",
+		},
+		{
+			name:        "2010s code",
+			code:        "B718-A08-BEL",
+			year:        2017,
+			week:        18,
+			startDate:   time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC),
+			subCode:     "A08",
+			typeAcronym: "BEL",
+		},
+		{
+			name:        "Week 1 starting in previous year",
+			code:        "C501-612-DSV",
+			year:        2025,
+			week:        1,
+			startDate:   time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC),
+			subCode:     "612",
+			typeAcronym: "DSV",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := utils.DecodeTournamentCode(tt.code)
+			require.NoError(t, err)
+			assert.Equal(t, tt.year, info.Year)
+			assert.Equal(t, tt.week, info.Week)
+			require.NotNil(t, info.ApproxStartDate)
+			assert.Equal(t, tt.startDate, *info.ApproxStartDate)
+			assert.Equal(t, tt.subCode, info.SubCode)
+			assert.Equal(t, tt.typeAcronym, info.TypeAcronym)
+		})
+	}
+}
+
+func TestDecodeTournamentCodeInvalid(t *testing.T) {
+	codes := []string{
+		"",             // empty
+		"T117893",      // sequential code without date
+		"C599-K00-HT1", // week out of range
+		"C5-K00-HT1",   // missing week
+		"invalid",      // not a tournament code
+	}
+
+	for _, code := range codes {
+		_, err := utils.DecodeTournamentCode(code)
+		assert.Error(t, err, code)
+	}
+}
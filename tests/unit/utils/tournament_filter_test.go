@@ -0,0 +1,68 @@
+package utils
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"portal64api/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newQueryContext(query string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(nil)
+	parsedURL, _ := url.Parse("http://example.com?" + query)
+	c.Request = &http.Request{URL: parsedURL}
+	return c
+}
+
+func TestParseTournamentSearchFilter(t *testing.T) {
+	c := newQueryContext("region=c03&computed=false&type=ht1&rounds=7&assessor_id=42&organisation_id=17&finished_from=2025-01-01&finished_to=2025-06-01")
+
+	filter, err := utils.ParseTournamentSearchFilter(c)
+	require.NoError(t, err)
+
+	assert.Equal(t, "C03", filter.Region)
+	assert.Equal(t, "HT1", filter.TypeAcronym)
+	assert.Equal(t, 7, filter.Rounds)
+	assert.Equal(t, uint(42), filter.AssessorID)
+	assert.Equal(t, uint(17), filter.OrganisationID)
+	require.NotNil(t, filter.Computed)
+	assert.False(t, *filter.Computed)
+	require.NotNil(t, filter.FinishedFrom)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), *filter.FinishedFrom)
+	require.NotNil(t, filter.FinishedTo)
+	assert.Equal(t, time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), *filter.FinishedTo)
+}
+
+func TestParseTournamentSearchFilterEmpty(t *testing.T) {
+	filter, err := utils.ParseTournamentSearchFilter(newQueryContext(""))
+	require.NoError(t, err)
+
+	assert.Empty(t, filter.Region)
+	assert.Nil(t, filter.Computed)
+	assert.Nil(t, filter.FinishedFrom)
+	assert.Nil(t, filter.FinishedTo)
+}
+
+func TestParseTournamentSearchFilterInvalid(t *testing.T) {
+	queries := []string{
+		"region=C0%25",
+		"computed=maybe",
+		"rounds=0",
+		"assessor_id=abc",
+		"organisation_id=-1",
+		"finished_from=01.01.2025",
+		"finished_from=2025-06-01&finished_to=2025-01-01",
+	}
+
+	for _, query := range queries {
+		_, err := utils.ParseTournamentSearchFilter(newQueryContext(query))
+		assert.Error(t, err, query)
+	}
+}
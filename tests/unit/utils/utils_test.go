@@ -2,6 +2,7 @@ package utils
 
 import (
 	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"testing"
 
@@ -173,3 +174,99 @@ func TestParsePlayerID(t *testing.T) {
 		})
 	}
 }
+
+func TestFoldUmlauts(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Müller", "mueller"},
+		{"Mueller", "mueller"},
+		{" Jugendwart ", "jugendwart"},
+		{"Schachfreunde Göppingen-Süßen", "schachfreunde goeppingen-suessen"},
+		{"ÄÖÜ", "aeoeue"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, utils.FoldUmlauts(tt.input))
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Müller", "Müller"},
+		{"100%", `100\%`},
+		{"C0_01", `C0\_01`},
+		{`a\b`, `a\\b`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, utils.EscapeLike(tt.input))
+		})
+	}
+}
+
+func TestValidatePersonUUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		uuid    string
+		wantErr bool
+	}{
+		{"Valid lowercase", "0b9f7c1e-5a1d-4c7e-9a55-3f1f2c6d8e01", false},
+		{"Valid uppercase", "0B9F7C1E-5A1D-4C7E-9A55-3F1F2C6D8E01", false},
+		{"Empty", "", true},
+		{"Too short", "0b9f7c1e-5a1d-4c7e-9a55", true},
+		{"Misplaced dash", "0b9f7c1e5-a1d-4c7e-9a55-3f1f2c6d8e01", true},
+		{"Non-hex character", "0b9f7c1e-5a1d-4c7e-9a55-3f1f2c6d8e0x", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := utils.ValidatePersonUUID(tt.uuid)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSendJSONResponseProblemDetails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/v1/players/:id", func(c *gin.Context) {
+		utils.SendJSONResponse(c, http.StatusBadRequest, utils.ValidatePlayerID(c.Param("id")))
+	})
+	router.GET("/api/v1/failure", func(c *gin.Context) {
+		utils.SendJSONResponse(c, http.StatusInternalServerError, "plain error")
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/players/C0101", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "/api/v1/errors/INVALID_PLAYER_ID",
+		"title": "Invalid player ID",
+		"status": 400,
+		"detail": "Invalid player ID format (expected: CLUBID-PERSONID)",
+		"instance": "/api/v1/players/C0101",
+		"error_code": "INVALID_PLAYER_ID",
+		"success": false,
+		"error": "Invalid player ID format (expected: CLUBID-PERSONID)"
+	}`, w.Body.String())
+
+	// Other errors get the generic code of the status
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/failure", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Contains(t, w.Body.String(), `"error_code":"INTERNAL_ERROR"`)
+	assert.Contains(t, w.Body.String(), `"detail":"plain error"`)
+}
@@ -0,0 +1,65 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"portal64api/pkg/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildVCards(t *testing.T) {
+	cards := []utils.VCard{
+		{
+			UID:          "urn:uuid:0b9f7c1e-5a1d-4c7e-9a55-3f1f2c6d8e01",
+			Kind:         "individual",
+			FullName:     "Müller, Hans",
+			FamilyName:   "Müller",
+			GivenName:    "Hans",
+			Organization: "Schachverband Württemberg",
+			Title:        "Präsident",
+			Street:       "Hauptstraße 1",
+			PostalCode:   "89073",
+			Locality:     "Ulm",
+			Country:      "Deutschland",
+			Phones:       []string{"+49 (0)731 / 12 34 56"},
+			Faxes:        []string{"0731-123457"},
+			Emails:       []string{"praesident@svw.info", "hans@example.org"},
+			URLs:         []string{"https://www.svw.info"},
+			Note:         "Sprechzeiten: Mo; Mi\nnach Vereinbarung",
+			Categories:   []string{"Präsidium"},
+		},
+		{FullName: "Geschäftsstelle", Kind: "org"},
+	}
+
+	vcf := utils.BuildVCards(cards)
+
+	assert.Equal(t, 2, strings.Count(vcf, "BEGIN:VCARD\r\nVERSION:4.0\r\n"))
+	assert.Equal(t, 2, strings.Count(vcf, "END:VCARD\r\n"))
+	assert.Contains(t, vcf, "FN:Müller\\, Hans\r\n")
+	assert.Contains(t, vcf, "N:Müller;Hans;;;\r\n")
+	assert.Contains(t, vcf, "ORG:Schachverband Württemberg\r\n")
+	assert.Contains(t, vcf, "TITLE:Präsident\r\n")
+	assert.Contains(t, vcf, "ADR;TYPE=work:;;Hauptstraße 1;Ulm;;89073;Deutschland\r\n")
+	assert.Contains(t, vcf, "TEL;TYPE=work,voice;VALUE=uri:tel:+49-731-12-34-56\r\n")
+	assert.Contains(t, vcf, "TEL;TYPE=work,fax;VALUE=uri:tel:0731-123457\r\n")
+	assert.Contains(t, vcf, "EMAIL;TYPE=work;PREF=1:praesident@svw.info\r\n")
+	assert.Contains(t, vcf, "EMAIL;TYPE=work:hans@example.org\r\n")
+	assert.Contains(t, vcf, "URL:https://www.svw.info\r\n")
+	assert.Contains(t, vcf, "NOTE:Sprechzeiten: Mo\\; Mi\\nnach Vereinbarung\r\n")
+	assert.Contains(t, vcf, "CATEGORIES:Präsidium\r\n")
+	assert.Contains(t, vcf, "UID:urn:uuid:0b9f7c1e-5a1d-4c7e-9a55-3f1f2c6d8e01\r\n")
+	assert.Contains(t, vcf, "KIND:org\r\nFN:Geschäftsstelle\r\nEND:VCARD\r\n")
+}
+
+func TestBuildVCardsFoldsLongLines(t *testing.T) {
+	note := strings.Repeat("Übungsabend freitags ab 19 Uhr im Vereinsheim. ", 5)
+	vcf := utils.BuildVCards([]utils.VCard{{FullName: "Test", Note: note}})
+
+	for _, line := range strings.Split(strings.TrimSuffix(vcf, "\r\n"), "\r\n") {
+		assert.LessOrEqual(t, len(line), 75, line)
+	}
+	unfolded := strings.ReplaceAll(vcf, "\r\n ", "")
+	assert.Contains(t, unfolded, "NOTE:"+note+"\r\n")
+}
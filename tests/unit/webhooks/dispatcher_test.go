@@ -0,0 +1,234 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"portal64api/internal/webhooks"
+
+	"github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDispatcher creates a dispatcher delivering to the loopback test servers
+func newDispatcher(t *testing.T, maxAttempts int) *webhooks.Dispatcher {
+	return startDispatcher(t, maxAttempts, true)
+}
+
+func startDispatcher(t *testing.T, maxAttempts int, allowPrivateNetworks bool) *webhooks.Dispatcher {
+	logger := logrus.New()
+	logger.SetOutput(io.Discard)
+
+	dispatcher := webhooks.NewDispatcher(webhooks.Options{
+		MaxAttempts: maxAttempts,
+		Backoff:     10 * time.Millisecond,
+		Timeout:     time.Second,
+		Workers:     2,
+		LogSize:     10,
+
+		AllowPrivateNetworks: allowPrivateNetworks,
+	}, logger)
+	dispatcher.Start()
+	t.Cleanup(dispatcher.Stop)
+	return dispatcher
+}
+
+// waitForStatus waits until the delivery has a final status
+func waitForStatus(t *testing.T, dispatcher *webhooks.Dispatcher, subscriptionID string) webhooks.Delivery {
+	var delivery webhooks.Delivery
+	require.Eventually(t, func() bool {
+		deliveries := dispatcher.Deliveries(subscriptionID, 1)
+		if len(deliveries) == 0 {
+			return false
+		}
+		delivery = deliveries[0]
+		return delivery.Status == webhooks.DeliverySucceeded || delivery.Status == webhooks.DeliveryFailed
+	}, 2*time.Second, 5*time.Millisecond)
+	return delivery
+}
+
+func TestSign(t *testing.T) {
+	// HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000.{}"))
+	assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), webhooks.Sign("secret", 1700000000, []byte("{}")))
+	assert.NotEqual(t, webhooks.Sign("secret", 1700000000, []byte("{}")), webhooks.Sign("secret", 1700000001, []byte("{}")))
+	assert.NotEqual(t, webhooks.Sign("secret", 1700000000, []byte("{}")), webhooks.Sign("other", 1700000000, []byte("{}")))
+}
+
+func TestDispatchSignsEvent(t *testing.T) {
+	subscription := webhooks.Subscription{ID: "wh_1", Secret: "s3cret", Events: []string{webhooks.EventImportCompleted}}
+
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	subscription.URL = server.URL
+
+	dispatcher := newDispatcher(t, 3)
+	event := webhooks.NewEvent(webhooks.EventImportCompleted, map[string]string{"status": "success"})
+	_, err := dispatcher.Dispatch(subscription, event)
+	require.NoError(t, err)
+
+	request, body := <-received, <-bodies
+	assert.Equal(t, webhooks.EventImportCompleted, request.Header.Get(webhooks.HeaderEvent))
+	timestamp, err := strconv.ParseInt(request.Header.Get(webhooks.HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, webhooks.Sign("s3cret", timestamp, body), request.Header.Get(webhooks.HeaderSignature))
+
+	var payload webhooks.Event
+	require.NoError(t, json.Unmarshal(body, &payload))
+	assert.Equal(t, event.ID, payload.ID)
+	assert.Equal(t, webhooks.EventImportCompleted, payload.Type)
+
+	delivery := waitForStatus(t, dispatcher, subscription.ID)
+	assert.Equal(t, webhooks.DeliverySucceeded, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusNoContent, delivery.StatusCode)
+	assert.Equal(t, request.Header.Get(webhooks.HeaderDelivery), delivery.ID)
+}
+
+func TestDispatchRetriesServerErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	dispatcher := newDispatcher(t, 5)
+	subscription := webhooks.Subscription{ID: "wh_2", URL: server.URL, Secret: "s"}
+	_, err := dispatcher.Dispatch(subscription, webhooks.NewEvent(webhooks.EventPing, nil))
+	require.NoError(t, err)
+
+	delivery := waitForStatus(t, dispatcher, subscription.ID)
+	assert.Equal(t, webhooks.DeliverySucceeded, delivery.Status)
+	assert.Equal(t, 3, delivery.Attempts)
+	assert.Empty(t, delivery.Error)
+}
+
+func TestDispatchGivesUp(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	dispatcher := newDispatcher(t, 2)
+	subscription := webhooks.Subscription{ID: "wh_3", URL: server.URL, Secret: "s"}
+	_, err := dispatcher.Dispatch(subscription, webhooks.NewEvent(webhooks.EventPing, nil))
+	require.NoError(t, err)
+
+	delivery := waitForStatus(t, dispatcher, subscription.ID)
+	assert.Equal(t, webhooks.DeliveryFailed, delivery.Status)
+	assert.Equal(t, 2, delivery.Attempts)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Contains(t, delivery.Error, "500")
+}
+
+func TestDispatchDoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	dispatcher := newDispatcher(t, 5)
+	subscription := webhooks.Subscription{ID: "wh_4", URL: server.URL, Secret: "s"}
+	_, err := dispatcher.Dispatch(subscription, webhooks.NewEvent(webhooks.EventPing, nil))
+	require.NoError(t, err)
+
+	delivery := waitForStatus(t, dispatcher, subscription.ID)
+	assert.Equal(t, webhooks.DeliveryFailed, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusGone, delivery.StatusCode)
+}
+
+func TestDeliveryLogIsBounded(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	dispatcher := newDispatcher(t, 1)
+	subscription := webhooks.Subscription{ID: "wh_5", URL: server.URL, Secret: "s"}
+	for i := 0; i < 15; i++ {
+		_, err := dispatcher.Dispatch(subscription, webhooks.NewEvent(webhooks.EventPing, nil))
+		require.NoError(t, err)
+	}
+
+	assert.Len(t, dispatcher.Deliveries("", 0), 10)
+	assert.Len(t, dispatcher.Deliveries(subscription.ID, 3), 3)
+	assert.Empty(t, dispatcher.Deliveries("wh_other", 0))
+}
+
+func TestDispatchRejectsNonPublicAddresses(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	dispatcher := startDispatcher(t, 5, false)
+	urls := []string{
+		server.URL,
+		"http://localhost:" + server.URL[strings.LastIndex(server.URL, ":")+1:],
+		"http://10.0.0.1/hook",
+		"http://192.168.1.1/hook",
+		"http://169.254.169.254/latest/meta-data/",
+		"http://[::1]:9/hook",
+	}
+	for i, url := range urls {
+		subscription := webhooks.Subscription{ID: "wh_blocked_" + strconv.Itoa(i), URL: url, Secret: "s"}
+		_, err := dispatcher.Dispatch(subscription, webhooks.NewEvent(webhooks.EventPing, nil))
+		require.NoError(t, err)
+
+		delivery := waitForStatus(t, dispatcher, subscription.ID)
+		assert.Equal(t, webhooks.DeliveryFailed, delivery.Status, url)
+		assert.Equal(t, 1, delivery.Attempts, url)
+		assert.Contains(t, delivery.Error, webhooks.ErrBlockedAddress.Error(), url)
+	}
+	assert.Zero(t, atomic.LoadInt32(&calls))
+}
+
+func TestDispatchDoesNotFollowRedirects(t *testing.T) {
+	var redirected int32
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&redirected, 1)
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL, http.StatusTemporaryRedirect)
+	}))
+	defer server.Close()
+
+	dispatcher := newDispatcher(t, 5)
+	subscription := webhooks.Subscription{ID: "wh_6", URL: server.URL, Secret: "s"}
+	_, err := dispatcher.Dispatch(subscription, webhooks.NewEvent(webhooks.EventPing, nil))
+	require.NoError(t, err)
+
+	delivery := waitForStatus(t, dispatcher, subscription.ID)
+	assert.Equal(t, webhooks.DeliveryFailed, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusTemporaryRedirect, delivery.StatusCode)
+	assert.Zero(t, atomic.LoadInt32(&redirected))
+}
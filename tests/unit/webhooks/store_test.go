@@ -0,0 +1,97 @@
+package webhooks
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"portal64api/internal/webhooks"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStore(t *testing.T) (*webhooks.Store, string) {
+	path := filepath.Join(t.TempDir(), "webhooks", "subscriptions.json")
+	store, err := webhooks.NewStore(path)
+	require.NoError(t, err)
+	return store, path
+}
+
+func TestStoreCreatePersists(t *testing.T) {
+	store, path := newStore(t)
+
+	created, err := store.Create(webhooks.Subscription{
+		URL:     "https://example.org/hook",
+		Events:  []string{webhooks.EventImportCompleted, webhooks.EventPlayerRatingChanged, webhooks.EventImportCompleted},
+		Filters: webhooks.Filters{ClubIDs: []string{" c0101 ", ""}},
+	})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(created.ID, "wh_"))
+	assert.True(t, strings.HasPrefix(created.Secret, "whsec_"), "a secret is generated")
+	assert.Equal(t, []string{webhooks.EventImportCompleted, webhooks.EventPlayerRatingChanged}, created.Events)
+	assert.Equal(t, []string{"C0101"}, created.Filters.ClubIDs)
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm(), "the file holds secrets")
+
+	reopened, err := webhooks.NewStore(path)
+	require.NoError(t, err)
+	loaded, err := reopened.Get(created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, created.Secret, loaded.Secret)
+	assert.Len(t, reopened.Subscribers(webhooks.EventPlayerRatingChanged), 1)
+	assert.Empty(t, reopened.Subscribers(webhooks.EventImportFailed))
+}
+
+func TestStoreKeepsGivenSecret(t *testing.T) {
+	store, _ := newStore(t)
+
+	created, err := store.Create(webhooks.Subscription{URL: "http://localhost:9000/", Secret: "s3cret", Events: []string{webhooks.EventImportFailed}})
+	require.NoError(t, err)
+	assert.Equal(t, "s3cret", created.Secret)
+}
+
+func TestStoreRejectsInvalidSubscriptions(t *testing.T) {
+	store, _ := newStore(t)
+
+	invalid := []webhooks.Subscription{
+		{URL: "ftp://example.org/hook", Events: []string{webhooks.EventImportCompleted}},
+		{URL: "/relative", Events: []string{webhooks.EventImportCompleted}},
+		{URL: "https://example.org/hook"},
+		{URL: "https://example.org/hook", Events: []string{"player.created"}},
+		{URL: "https://example.org/hook", Events: []string{webhooks.EventPing}},
+	}
+	for _, subscription := range invalid {
+		_, err := store.Create(subscription)
+		assert.ErrorIs(t, err, webhooks.ErrInvalidSubscription, subscription)
+	}
+	assert.Empty(t, store.List())
+}
+
+func TestStoreDelete(t *testing.T) {
+	store, path := newStore(t)
+
+	created, err := store.Create(webhooks.Subscription{URL: "https://example.org/hook", Events: []string{webhooks.EventAnalysisCompleted}})
+	require.NoError(t, err)
+
+	require.NoError(t, store.Delete(created.ID))
+	assert.ErrorIs(t, store.Delete(created.ID), webhooks.ErrSubscriptionNotFound)
+	_, err = store.Get(created.ID)
+	assert.ErrorIs(t, err, webhooks.ErrSubscriptionNotFound)
+
+	reopened, err := webhooks.NewStore(path)
+	require.NoError(t, err)
+	assert.Empty(t, reopened.List())
+}
+
+func TestFiltersMatch(t *testing.T) {
+	assert.True(t, webhooks.Filters{}.Matches("C0101", "C0101-1014"), "no filters pass everything")
+
+	filters := webhooks.Filters{ClubIDs: []string{"C0101"}, PlayerIDs: []string{"C0327-297"}}
+	assert.True(t, filters.Matches("c0101", "C0101-1014"))
+	assert.True(t, filters.Matches("C0327", "C0327-297"))
+	assert.False(t, filters.Matches("C0327", "C0327-298"))
+}